@@ -0,0 +1,4 @@
+// Command gcclean maintains note media in storage: it deletes objects that no
+// database row references and can backfill recorded object sizes and image
+// dimensions.
+package main
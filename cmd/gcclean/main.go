@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/logger"
+	"github.com/icco/etu-backend/internal/media"
+	"github.com/icco/etu-backend/internal/storage"
+)
+
+// bucket is the subset of storage.Client used by the cleanup job.
+type bucket interface {
+	List(ctx context.Context, prefix string) ([]storage.ObjectInfo, error)
+	Stat(ctx context.Context, objectName string) (storage.ObjectInfo, error)
+	DeleteImage(ctx context.Context, objectName string) error
+}
+
+// referenceSource reports which object names are still in use.
+type referenceSource interface {
+	GetReferencedObjectNames(ctx context.Context) (map[string]bool, error)
+}
+
+// sizeStore reads and records media object sizes for the backfill.
+type sizeStore interface {
+	GetImagesWithoutSize(ctx context.Context) ([]db.NoteImage, error)
+	UpdateImageSize(ctx context.Context, imageID string, sizeBytes int64) error
+	GetAudiosWithoutSize(ctx context.Context) ([]db.NoteAudio, error)
+	UpdateAudioSize(ctx context.Context, audioID string, sizeBytes int64) error
+}
+
+// objectReader downloads stored objects for the dimension backfill.
+type objectReader interface {
+	GetImage(ctx context.Context, objectName string) ([]byte, error)
+}
+
+// dimensionStore reads and records image dimensions for the backfill.
+type dimensionStore interface {
+	GetImagesWithoutDimensions(ctx context.Context) ([]db.NoteImage, error)
+	UpdateImageDimensions(ctx context.Context, imageID string, width, height int) error
+}
+
+// CleanResult holds the results of a cleanup run
+type CleanResult struct {
+	Scanned  int
+	Orphaned int
+	Deleted  int
+	Errors   int
+}
+
+// BackfillResult holds the results of a size backfill run
+type BackfillResult struct {
+	Images int
+	Audios int
+	Errors int
+}
+
+// DimensionResult holds the results of a dimension backfill run
+type DimensionResult struct {
+	Images     int
+	Unmeasured int // images in a format whose size can't be read, such as HEIC
+	Errors     int
+}
+
+func main() {
+	log := logger.New()
+
+	prefix := flag.String("prefix", "notes/", "Only consider objects whose name starts with this prefix")
+	grace := flag.Duration("grace", 24*time.Hour, "Only delete orphans older than this, so uploads still being saved are left alone")
+	dryRun := flag.Bool("dry-run", false, "Report changes without deleting objects or updating the database")
+	backfill := flag.Bool("backfill-sizes", false, "Instead of cleaning up, record the stored size of images and audio files uploaded before sizes were tracked")
+	backfillDims := flag.Bool("backfill-dimensions", false, "Instead of cleaning up, record the pixel dimensions of images uploaded before dimensions were tracked")
+	flag.Parse()
+
+	gcsBucket := os.Getenv("GCS_BUCKET")
+	if gcsBucket == "" {
+		log.Error("GCS_BUCKET environment variable not set")
+		os.Exit(1)
+	}
+
+	log.Info("starting orphaned object cleanup",
+		"bucket", gcsBucket,
+		"prefix", *prefix,
+		"grace", grace.String(),
+		"dry_run", *dryRun)
+
+	ctx := context.Background()
+	storageClient, err := storage.New(ctx, gcsBucket)
+	if err != nil {
+		log.Error("failed to initialize storage client", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := storageClient.Close(); err != nil {
+			log.Error("error closing storage client", "error", err)
+		}
+	}()
+
+	database, err := db.New()
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := database.Close(); err != nil {
+			log.Error("error closing database", "error", err)
+		}
+	}()
+
+	if *backfill || *backfillDims {
+		failed := false
+		if *backfill {
+			result, err := backfillSizes(ctx, log, storageClient, database, *dryRun)
+			if err != nil {
+				log.Error("size backfill failed", "error", err)
+				os.Exit(1)
+			}
+			log.Info("size backfill completed",
+				"images", result.Images,
+				"audios", result.Audios,
+				"errors", result.Errors,
+				"dry_run", *dryRun)
+			failed = result.Errors > 0
+		}
+		if *backfillDims {
+			result, err := backfillDimensions(ctx, log, storageClient, database, *dryRun)
+			if err != nil {
+				log.Error("dimension backfill failed", "error", err)
+				os.Exit(1)
+			}
+			log.Info("dimension backfill completed",
+				"images", result.Images,
+				"unmeasured", result.Unmeasured,
+				"errors", result.Errors,
+				"dry_run", *dryRun)
+			failed = failed || result.Errors > 0
+		}
+		if failed {
+			os.Exit(1)
+		}
+		return
+	}
+
+	result, err := clean(ctx, log, storageClient, database, *prefix, *grace, *dryRun, time.Now())
+	if err != nil {
+		log.Error("cleanup failed", "error", err)
+		os.Exit(1)
+	}
+
+	log.Info("cleanup completed",
+		"scanned", result.Scanned,
+		"orphaned", result.Orphaned,
+		"deleted", result.Deleted,
+		"errors", result.Errors,
+		"dry_run", *dryRun)
+
+	if result.Errors > 0 {
+		os.Exit(1)
+	}
+}
+
+// clean deletes objects under prefix that no database row references and
+// that were created more than grace before now. Objects are listed before
+// references are loaded so an upload that is saved in between is never
+// mistaken for an orphan.
+func clean(ctx context.Context, log *slog.Logger, b bucket, refs referenceSource, prefix string, grace time.Duration, dryRun bool, now time.Time) (CleanResult, error) {
+	var result CleanResult
+
+	objects, err := b.List(ctx, prefix)
+	if err != nil {
+		return result, err
+	}
+	result.Scanned = len(objects)
+
+	referenced, err := refs.GetReferencedObjectNames(ctx)
+	if err != nil {
+		return result, err
+	}
+
+	orphans := findOrphans(objects, referenced, now.Add(-grace))
+	result.Orphaned = len(orphans)
+
+	for _, obj := range orphans {
+		if dryRun {
+			log.Info("would delete orphaned object", "object_name", obj.Name, "size", obj.Size, "created", obj.Created)
+			continue
+		}
+
+		if err := b.DeleteImage(ctx, obj.Name); err != nil {
+			log.Error("failed to delete orphaned object", "object_name", obj.Name, "error", err)
+			result.Errors++
+			continue
+		}
+		log.Info("deleted orphaned object", "object_name", obj.Name, "size", obj.Size)
+		result.Deleted++
+	}
+
+	return result, nil
+}
+
+// findOrphans returns the objects that are not referenced and were created
+// before cutoff.
+func findOrphans(objects []storage.ObjectInfo, referenced map[string]bool, cutoff time.Time) []storage.ObjectInfo {
+	var orphans []storage.ObjectInfo
+	for _, obj := range objects {
+		if referenced[obj.Name] || !obj.Created.Before(cutoff) {
+			continue
+		}
+		orphans = append(orphans, obj)
+	}
+	return orphans
+}
+
+// backfillSizes records the stored size of media rows created before sizes
+// were captured at upload time, by reading each object's attributes.
+func backfillSizes(ctx context.Context, log *slog.Logger, b bucket, store sizeStore, dryRun bool) (BackfillResult, error) {
+	var result BackfillResult
+
+	images, err := store.GetImagesWithoutSize(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, img := range images {
+		size, ok := objectSize(ctx, log, b, img.GCSObjectName)
+		if !ok {
+			result.Errors++
+			continue
+		}
+		if !dryRun {
+			if err := store.UpdateImageSize(ctx, img.ID, size); err != nil {
+				log.Error("failed to update image size", "image_id", img.ID, "error", err)
+				result.Errors++
+				continue
+			}
+		}
+		log.Info("recorded image size", "image_id", img.ID, "size", size, "dry_run", dryRun)
+		result.Images++
+	}
+
+	audios, err := store.GetAudiosWithoutSize(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, aud := range audios {
+		size, ok := objectSize(ctx, log, b, aud.GCSObjectName)
+		if !ok {
+			result.Errors++
+			continue
+		}
+		if !dryRun {
+			if err := store.UpdateAudioSize(ctx, aud.ID, size); err != nil {
+				log.Error("failed to update audio size", "audio_id", aud.ID, "error", err)
+				result.Errors++
+				continue
+			}
+		}
+		log.Info("recorded audio size", "audio_id", aud.ID, "size", size, "dry_run", dryRun)
+		result.Audios++
+	}
+
+	return result, nil
+}
+
+// objectSize looks up the stored size of an object, logging failures.
+func objectSize(ctx context.Context, log *slog.Logger, b bucket, objectName string) (int64, bool) {
+	info, err := b.Stat(ctx, objectName)
+	if err != nil {
+		log.Error("failed to stat object", "object_name", objectName, "error", err)
+		return 0, false
+	}
+	return info.Size, true
+}
+
+// backfillDimensions records the pixel dimensions of images uploaded before
+// dimensions were captured, by downloading each one and reading its header.
+// Images in a format that can't be measured are left at zero.
+func backfillDimensions(ctx context.Context, log *slog.Logger, r objectReader, store dimensionStore, dryRun bool) (DimensionResult, error) {
+	var result DimensionResult
+
+	images, err := store.GetImagesWithoutDimensions(ctx)
+	if err != nil {
+		return result, err
+	}
+	for _, img := range images {
+		data, err := r.GetImage(ctx, img.GCSObjectName)
+		if err != nil {
+			log.Error("failed to download image", "image_id", img.ID, "object_name", img.GCSObjectName, "error", err)
+			result.Errors++
+			continue
+		}
+
+		width, height := media.ImageDimensions(data)
+		if width == 0 || height == 0 {
+			log.Info("could not read image dimensions", "image_id", img.ID, "mime_type", img.MimeType)
+			result.Unmeasured++
+			continue
+		}
+
+		if !dryRun {
+			if err := store.UpdateImageDimensions(ctx, img.ID, width, height); err != nil {
+				log.Error("failed to update image dimensions", "image_id", img.ID, "error", err)
+				result.Errors++
+				continue
+			}
+		}
+		log.Info("recorded image dimensions", "image_id", img.ID, "width", width, "height", height, "dry_run", dryRun)
+		result.Images++
+	}
+
+	return result, nil
+}
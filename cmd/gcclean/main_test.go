@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/storage"
+)
+
+// fakeBucket is an in-memory bucket that records deletions.
+type fakeBucket struct {
+	objects   []storage.ObjectInfo
+	deleted   []string
+	deleteErr map[string]error
+}
+
+func (b *fakeBucket) List(ctx context.Context, prefix string) ([]storage.ObjectInfo, error) {
+	var out []storage.ObjectInfo
+	for _, obj := range b.objects {
+		if strings.HasPrefix(obj.Name, prefix) {
+			out = append(out, obj)
+		}
+	}
+	return out, nil
+}
+
+func (b *fakeBucket) Stat(ctx context.Context, objectName string) (storage.ObjectInfo, error) {
+	for _, obj := range b.objects {
+		if obj.Name == objectName {
+			return obj, nil
+		}
+	}
+	return storage.ObjectInfo{}, errors.New("object not found")
+}
+
+func (b *fakeBucket) DeleteImage(ctx context.Context, objectName string) error {
+	if err := b.deleteErr[objectName]; err != nil {
+		return err
+	}
+	b.deleted = append(b.deleted, objectName)
+	return nil
+}
+
+// fakeRefs returns a fixed set of referenced object names.
+type fakeRefs map[string]bool
+
+func (r fakeRefs) GetReferencedObjectNames(ctx context.Context) (map[string]bool, error) {
+	return r, nil
+}
+
+func TestClean(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := now.Add(-48 * time.Hour)
+	recent := now.Add(-time.Hour)
+
+	objects := []storage.ObjectInfo{
+		{Name: "notes/n1/img1", Created: old},      // referenced
+		{Name: "notes/n1/orphan", Created: old},    // orphan, past grace
+		{Name: "notes/n2/aud1", Created: old},      // referenced
+		{Name: "notes/n3/fresh", Created: recent},  // orphan, within grace
+		{Name: "profiles/u1/avatar", Created: old}, // outside prefix
+	}
+	refs := fakeRefs{"notes/n1/img1": true, "notes/n2/aud1": true}
+	log := slog.New(slog.DiscardHandler)
+
+	tests := []struct {
+		name        string
+		dryRun      bool
+		wantDeleted []string
+		want        CleanResult
+	}{
+		{
+			name:        "deletes old orphans",
+			wantDeleted: []string{"notes/n1/orphan"},
+			want:        CleanResult{Scanned: 4, Orphaned: 1, Deleted: 1},
+		},
+		{
+			name:   "dry run deletes nothing",
+			dryRun: true,
+			want:   CleanResult{Scanned: 4, Orphaned: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &fakeBucket{objects: objects}
+			got, err := clean(context.Background(), log, b, refs, "notes/", 24*time.Hour, tt.dryRun, now)
+			if err != nil {
+				t.Fatalf("clean: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+			if !slices.Equal(b.deleted, tt.wantDeleted) {
+				t.Errorf("deleted = %v, want %v", b.deleted, tt.wantDeleted)
+			}
+		})
+	}
+}
+
+func TestClean_DeleteError(t *testing.T) {
+	now := time.Now()
+	b := &fakeBucket{
+		objects: []storage.ObjectInfo{
+			{Name: "notes/a", Created: now.Add(-48 * time.Hour)},
+			{Name: "notes/b", Created: now.Add(-48 * time.Hour)},
+		},
+		deleteErr: map[string]error{"notes/a": errors.New("permission denied")},
+	}
+
+	got, err := clean(context.Background(), slog.New(slog.DiscardHandler), b, fakeRefs{}, "notes/", time.Hour, false, now)
+	if err != nil {
+		t.Fatalf("clean: %v", err)
+	}
+	if diff := cmp.Diff(CleanResult{Scanned: 2, Orphaned: 2, Deleted: 1, Errors: 1}, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// fakeSizeStore records size updates in memory.
+type fakeSizeStore struct {
+	images  []db.NoteImage
+	audios  []db.NoteAudio
+	updated map[string]int64
+}
+
+func (f *fakeSizeStore) GetImagesWithoutSize(ctx context.Context) ([]db.NoteImage, error) {
+	return f.images, nil
+}
+
+func (f *fakeSizeStore) UpdateImageSize(ctx context.Context, imageID string, sizeBytes int64) error {
+	f.updated[imageID] = sizeBytes
+	return nil
+}
+
+func (f *fakeSizeStore) GetAudiosWithoutSize(ctx context.Context) ([]db.NoteAudio, error) {
+	return f.audios, nil
+}
+
+func (f *fakeSizeStore) UpdateAudioSize(ctx context.Context, audioID string, sizeBytes int64) error {
+	f.updated[audioID] = sizeBytes
+	return nil
+}
+
+func TestBackfillSizes(t *testing.T) {
+	b := &fakeBucket{objects: []storage.ObjectInfo{
+		{Name: "notes/n1/img1", Size: 1200},
+		{Name: "notes/n1/aud1", Size: 45000},
+	}}
+	newStore := func() *fakeSizeStore {
+		return &fakeSizeStore{
+			images: []db.NoteImage{
+				{ID: "img1", GCSObjectName: "notes/n1/img1"},
+				{ID: "img2", GCSObjectName: "notes/n1/missing"},
+			},
+			audios:  []db.NoteAudio{{ID: "aud1", GCSObjectName: "notes/n1/aud1"}},
+			updated: map[string]int64{},
+		}
+	}
+	log := slog.New(slog.DiscardHandler)
+
+	store := newStore()
+	got, err := backfillSizes(context.Background(), log, b, store, false)
+	if err != nil {
+		t.Fatalf("backfillSizes: %v", err)
+	}
+	if diff := cmp.Diff(BackfillResult{Images: 1, Audios: 1, Errors: 1}, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string]int64{"img1": 1200, "aud1": 45000}, store.updated); diff != "" {
+		t.Errorf("updated sizes mismatch (-want +got):\n%s", diff)
+	}
+
+	store = newStore()
+	if _, err := backfillSizes(context.Background(), log, b, store, true); err != nil {
+		t.Fatalf("backfillSizes dry run: %v", err)
+	}
+	if len(store.updated) != 0 {
+		t.Errorf("dry run updated sizes: %v", store.updated)
+	}
+}
+
+// fakeReader serves object contents from memory.
+type fakeReader map[string][]byte
+
+func (r fakeReader) GetImage(ctx context.Context, objectName string) ([]byte, error) {
+	data, ok := r[objectName]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return data, nil
+}
+
+// fakeDimensionStore records dimension updates in memory.
+type fakeDimensionStore struct {
+	images  []db.NoteImage
+	updated map[string][2]int
+}
+
+func (f *fakeDimensionStore) GetImagesWithoutDimensions(ctx context.Context) ([]db.NoteImage, error) {
+	return f.images, nil
+}
+
+func (f *fakeDimensionStore) UpdateImageDimensions(ctx context.Context, imageID string, width, height int) error {
+	f.updated[imageID] = [2]int{width, height}
+	return nil
+}
+
+func TestBackfillDimensions(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 30, 20))); err != nil {
+		t.Fatalf("failed to encode fixture: %v", err)
+	}
+	r := fakeReader{
+		"notes/n1/img1": pngData.Bytes(),
+		"notes/n1/heic": []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"),
+	}
+	newStore := func() *fakeDimensionStore {
+		return &fakeDimensionStore{
+			images: []db.NoteImage{
+				{ID: "img1", GCSObjectName: "notes/n1/img1"},
+				{ID: "heic", GCSObjectName: "notes/n1/heic"},
+				{ID: "gone", GCSObjectName: "notes/n1/missing"},
+			},
+			updated: map[string][2]int{},
+		}
+	}
+	log := slog.New(slog.DiscardHandler)
+
+	store := newStore()
+	got, err := backfillDimensions(context.Background(), log, r, store, false)
+	if err != nil {
+		t.Fatalf("backfillDimensions: %v", err)
+	}
+	if diff := cmp.Diff(DimensionResult{Images: 1, Unmeasured: 1, Errors: 1}, got); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(map[string][2]int{"img1": {30, 20}}, store.updated); diff != "" {
+		t.Errorf("updated dimensions mismatch (-want +got):\n%s", diff)
+	}
+
+	store = newStore()
+	if _, err := backfillDimensions(context.Background(), log, r, store, true); err != nil {
+		t.Fatalf("backfillDimensions dry run: %v", err)
+	}
+	if len(store.updated) != 0 {
+		t.Errorf("dry run updated dimensions: %v", store.updated)
+	}
+}
@@ -0,0 +1,4 @@
+// Command import creates notes for a user from a CSV or JSON export. Each row
+// gets an idempotency key derived from its content and creation date, so a
+// file can be imported again without duplicating notes that already exist.
+package main
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/logger"
+	"github.com/icco/etu-backend/internal/models"
+)
+
+// noteStore is the subset of db.DB used by the import.
+type noteStore interface {
+	IdempotencyKeyInUse(ctx context.Context, userID, key string) (bool, error)
+	CreateNote(ctx context.Context, userID, content, color string, tagNames []string, idempotencyKey string, createdAt time.Time) (*db.Note, error)
+}
+
+// ImportResult holds the results of an import run
+type ImportResult struct {
+	Created int
+	Skipped int // malformed rows and rows imported by an earlier run
+	Failed  int
+}
+
+func main() {
+	log := logger.New()
+
+	userID := flag.String("user", "", "User ID to create the notes for (required)")
+	file := flag.String("file", "", "CSV or JSON file to import, or - for stdin (required)")
+	format := flag.String("format", "", "File format: csv or json (default: from the file extension)")
+	dryRun := flag.Bool("dry-run", false, "Validate the file and report what would be created without writing notes")
+	flag.Parse()
+
+	if *userID == "" || *file == "" {
+		log.Error("-user and -file are required")
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	maxContent := models.DefaultMaxContentLength
+	if value := os.Getenv("MAX_CONTENT_LENGTH"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			log.Error("invalid MAX_CONTENT_LENGTH value", "value", value, "error", err)
+			os.Exit(1)
+		}
+		maxContent = n
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Error("failed to open import file", "file", *file, "error", err)
+			os.Exit(1)
+		}
+		defer func() {
+			if err := f.Close(); err != nil {
+				log.Error("error closing import file", "error", err)
+			}
+		}()
+		input = f
+	}
+
+	rows, invalid, err := parser{now: time.Now(), maxContent: maxContent}.parse(input, *format)
+	if err != nil {
+		log.Error("failed to parse import file", "file", *file, "error", err)
+		os.Exit(1)
+	}
+
+	log.Info("starting import",
+		"user_id", *userID,
+		"file", *file,
+		"format", *format,
+		"rows", len(rows)+len(invalid),
+		"dry_run", *dryRun)
+
+	database, err := db.New()
+	if err != nil {
+		log.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer func() {
+		if err := database.Close(); err != nil {
+			log.Error("error closing database", "error", err)
+		}
+	}()
+
+	ctx := context.Background()
+	user, err := database.GetUser(ctx, *userID)
+	if err != nil {
+		log.Error("failed to look up user", "user_id", *userID, "error", err)
+		os.Exit(1)
+	}
+	if user == nil {
+		log.Error("user not found", "user_id", *userID)
+		os.Exit(1)
+	}
+
+	result := importNotes(ctx, log, database, *userID, rows, invalid, *dryRun)
+
+	log.Info("import completed",
+		"created", result.Created,
+		"skipped", result.Skipped,
+		"failed", result.Failed,
+		"dry_run", *dryRun)
+
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// importNotes creates a note for each row whose idempotency key isn't in use
+// yet. Invalid rows are logged and counted as skipped, as are rows a previous
+// run already imported.
+func importNotes(ctx context.Context, log *slog.Logger, store noteStore, userID string, rows []importRow, invalid []rowError, dryRun bool) ImportResult {
+	var result ImportResult
+
+	for _, rowErr := range invalid {
+		log.Warn("skipping malformed row", "row", rowErr.Line, "error", rowErr.Err)
+		result.Skipped++
+	}
+
+	for _, row := range rows {
+		key := importKey(row)
+
+		inUse, err := store.IdempotencyKeyInUse(ctx, userID, key)
+		if err != nil {
+			log.Error("failed to check for existing note", "row", row.Line, "error", err)
+			result.Failed++
+			continue
+		}
+		if inUse {
+			log.Info("skipping row imported by an earlier run", "row", row.Line)
+			result.Skipped++
+			continue
+		}
+
+		if dryRun {
+			log.Info("would create note", "row", row.Line, "tags", row.Tags, "created_at", row.CreatedAt)
+			continue
+		}
+
+		note, err := store.CreateNote(ctx, userID, row.Content, "", row.Tags, key, row.CreatedAt)
+		if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+			log.Info("skipping row imported concurrently", "row", row.Line)
+			result.Skipped++
+			continue
+		}
+		if err != nil {
+			log.Error("failed to create note", "row", row.Line, "error", err)
+			result.Failed++
+			continue
+		}
+		log.Info("created note", "row", row.Line, "note_id", note.ID)
+		result.Created++
+	}
+
+	return result
+}
+
+// importKey derives a row's idempotency key from its creation date and
+// content, so re-running an import finds the notes an earlier run created
+// even if the file's tags were edited in between.
+func importKey(row importRow) string {
+	var created string
+	if !row.CreatedAt.IsZero() {
+		created = row.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+	sum := sha256.Sum256([]byte(created + "\x00" + row.Content))
+	return "import-" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/internal/db"
+)
+
+// fakeNoteStore records created notes by idempotency key.
+type fakeNoteStore struct {
+	keys      map[string]bool
+	created   []string
+	createErr map[string]error
+}
+
+func (s *fakeNoteStore) IdempotencyKeyInUse(ctx context.Context, userID, key string) (bool, error) {
+	return s.keys[key], nil
+}
+
+func (s *fakeNoteStore) CreateNote(ctx context.Context, userID, content, color string, tagNames []string, idempotencyKey string, createdAt time.Time) (*db.Note, error) {
+	if err := s.createErr[content]; err != nil {
+		return nil, err
+	}
+	if s.keys == nil {
+		s.keys = make(map[string]bool)
+	}
+	s.keys[idempotencyKey] = true
+	s.created = append(s.created, content)
+	return &db.Note{ID: fmt.Sprintf("note%d", len(s.created))}, nil
+}
+
+func TestImportNotes(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	ctx := context.Background()
+	rows := []importRow{
+		{Line: 2, Content: "one", CreatedAt: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Line: 3, Content: "two", Tags: []string{"work"}},
+		{Line: 4, Content: "broken"},
+		{Line: 5, Content: "raced"},
+	}
+	invalid := []rowError{{Line: 6, Err: errors.New("content is empty")}}
+	store := &fakeNoteStore{createErr: map[string]error{
+		"broken": errors.New("connection reset"),
+		"raced":  db.ErrIdempotencyKeyConflict,
+	}}
+
+	got := importNotes(ctx, log, store, "user1", rows, invalid, false)
+	want := ImportResult{Created: 2, Skipped: 2, Failed: 1}
+	if got != want {
+		t.Errorf("first run = %+v, want %+v", got, want)
+	}
+
+	// A second run over the same rows creates nothing new.
+	store.createErr = nil
+	got = importNotes(ctx, log, store, "user1", rows[:2], nil, false)
+	want = ImportResult{Skipped: 2}
+	if got != want {
+		t.Errorf("second run = %+v, want %+v", got, want)
+	}
+	if len(store.created) != 2 {
+		t.Errorf("created %v, want two notes", store.created)
+	}
+}
+
+func TestImportNotes_DryRun(t *testing.T) {
+	store := &fakeNoteStore{}
+	rows := []importRow{{Line: 2, Content: "one"}}
+
+	got := importNotes(context.Background(), slog.New(slog.DiscardHandler), store, "user1", rows, nil, true)
+	if got != (ImportResult{}) {
+		t.Errorf("result = %+v, want all zero", got)
+	}
+	if len(store.created) != 0 {
+		t.Errorf("dry run created %v", store.created)
+	}
+}
+
+func TestImportKey(t *testing.T) {
+	base := importRow{Content: "hello", CreatedAt: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)}
+
+	sameInstant := base
+	sameInstant.CreatedAt = base.CreatedAt.In(time.FixedZone("EST", -5*60*60))
+	sameInstant.Tags = []string{"edited"}
+	if importKey(base) != importKey(sameInstant) {
+		t.Error("key changed with time zone or tags")
+	}
+
+	otherDate := base
+	otherDate.CreatedAt = base.CreatedAt.Add(time.Second)
+	if importKey(base) == importKey(otherDate) {
+		t.Error("key did not change with created date")
+	}
+
+	otherContent := base
+	otherContent.Content = "hello!"
+	if importKey(base) == importKey(otherContent) {
+		t.Error("key did not change with content")
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/icco/etu-backend/internal/models"
+)
+
+// maxFutureSkew is how far past the current time a row's creation date may be,
+// to allow for clock differences on the machine that produced the export.
+const maxFutureSkew = 5 * time.Minute
+
+// dateLayouts are the creation date formats accepted in import files. Layouts
+// without a zone are read as UTC.
+var dateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// columnAliases maps accepted CSV header names to the field they fill.
+var columnAliases = map[string]string{
+	"content":    "content",
+	"text":       "content",
+	"tags":       "tags",
+	"tag":        "tags",
+	"created_at": "created_at",
+	"createdat":  "created_at",
+	"created":    "created_at",
+	"date":       "created_at",
+}
+
+// importRow is a validated note ready to be created.
+type importRow struct {
+	Line      int // CSV line number, or 1-based position in a JSON array
+	Content   string
+	Tags      []string
+	CreatedAt time.Time // zero when the file has no date, meaning "now"
+}
+
+// rowError describes a row that was skipped because it could not be parsed
+// or failed validation.
+type rowError struct {
+	Line int
+	Err  error
+}
+
+func (e rowError) Error() string {
+	return fmt.Sprintf("row %d: %v", e.Line, e.Err)
+}
+
+// parser validates rows against the same limits the API enforces.
+type parser struct {
+	now        time.Time
+	maxContent int
+}
+
+// parse reads rows in format ("csv" or "json"). Malformed rows are returned
+// as rowErrors rather than aborting; err is only set when the file as a whole
+// can't be read.
+func (p parser) parse(r io.Reader, format string) ([]importRow, []rowError, error) {
+	switch format {
+	case "csv":
+		return p.parseCSV(r)
+	case "json":
+		return p.parseJSON(r)
+	default:
+		return nil, nil, fmt.Errorf("unsupported format %q: must be csv or json", format)
+	}
+}
+
+// parseCSV reads a CSV file with a header row. A content column is required;
+// tags (separated by commas or semicolons) and created_at are optional.
+func (p parser) parseCSV(r io.Reader) ([]importRow, []rowError, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		// Spreadsheet exports often start with a UTF-8 byte order mark.
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := columnAliases[name]; ok {
+			if _, dup := columns[field]; !dup {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["content"]; !ok {
+		return nil, nil, errors.New("CSV header has no content column")
+	}
+
+	cell := func(record []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	var rows []importRow
+	var invalid []rowError
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return rows, invalid, fmt.Errorf("failed to read CSV: %w", err)
+			}
+			invalid = append(invalid, rowError{Line: parseErr.StartLine, Err: parseErr.Err})
+			continue
+		}
+
+		line, _ := reader.FieldPos(0)
+		tags := strings.FieldsFunc(cell(record, "tags"), func(r rune) bool { return r == ',' || r == ';' })
+		row, err := p.newRow(line, cell(record, "content"), tags, cell(record, "created_at"))
+		if err != nil {
+			invalid = append(invalid, rowError{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, invalid, nil
+}
+
+// jsonNote is one element of a JSON import file.
+type jsonNote struct {
+	Content   string   `json:"content"`
+	Tags      []string `json:"tags"`
+	CreatedAt string   `json:"created_at"`
+}
+
+// parseJSON reads a JSON array of {"content", "tags", "created_at"} objects.
+// Each element is decoded on its own so one bad element doesn't reject the
+// whole file.
+func (p parser) parseJSON(r io.Reader) ([]importRow, []rowError, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		if err == io.EOF {
+			return nil, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to read JSON array: %w", err)
+	}
+
+	var rows []importRow
+	var invalid []rowError
+	for i, element := range elements {
+		line := i + 1
+		var note jsonNote
+		if err := json.Unmarshal(element, &note); err != nil {
+			invalid = append(invalid, rowError{Line: line, Err: err})
+			continue
+		}
+		row, err := p.newRow(line, note.Content, note.Tags, note.CreatedAt)
+		if err != nil {
+			invalid = append(invalid, rowError{Line: line, Err: err})
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return rows, invalid, nil
+}
+
+// newRow validates one row's fields. Content is trimmed of trailing
+// whitespace like CreateNote does, and tags are normalized.
+func (p parser) newRow(line int, content string, tags []string, createdAt string) (importRow, error) {
+	content = strings.TrimRightFunc(content, unicode.IsSpace)
+	if strings.TrimSpace(content) == "" {
+		return importRow{}, errors.New("content is empty")
+	}
+	if n := utf8.RuneCountInString(content); n > p.maxContent {
+		return importRow{}, fmt.Errorf("content is %d characters, over the limit of %d", n, p.maxContent)
+	}
+
+	created, err := parseDate(createdAt)
+	if err != nil {
+		return importRow{}, err
+	}
+	if created.After(p.now.Add(maxFutureSkew)) {
+		return importRow{}, fmt.Errorf("created date %s is in the future", created.Format(time.RFC3339))
+	}
+
+	return importRow{
+		Line:      line,
+		Content:   content,
+		Tags:      models.NormalizeTags(tags),
+		CreatedAt: created,
+	}, nil
+}
+
+// parseDate parses value with the first matching layout in dateLayouts. A
+// blank value returns the zero time.
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized created date %q", value)
+}
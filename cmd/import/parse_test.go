@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+var testNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func testParser() parser {
+	return parser{now: testNow, maxContent: 20}
+}
+
+func TestParseCSV(t *testing.T) {
+	input := "\ufeffDate,Content,Tags\n" +
+		"2024-01-02,first note,\"Work, #ideas\"\n" +
+		"2024-01-03T08:30:00Z,\"multi\nline\",a;b;a\n" +
+		",no date,\n" +
+		"2024-01-04,,empty\n" +
+		"not-a-date,bad date,\n" +
+		"2030-01-01,from the future,\n" +
+		"2024-01-05,this content is far too long,\n" +
+		"2024-01-06 09:15:00,  trailing space  \n"
+
+	rows, invalid, err := testParser().parse(strings.NewReader(input), "csv")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	wantRows := []importRow{
+		{Line: 2, Content: "first note", Tags: []string{"work", "ideas"}, CreatedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{Line: 3, Content: "multi\nline", Tags: []string{"a", "b"}, CreatedAt: time.Date(2024, 1, 3, 8, 30, 0, 0, time.UTC)},
+		{Line: 5, Content: "no date"},
+		{Line: 10, Content: "  trailing space", CreatedAt: time.Date(2024, 1, 6, 9, 15, 0, 0, time.UTC)},
+	}
+	if diff := cmp.Diff(wantRows, rows); diff != "" {
+		t.Errorf("rows mismatch (-want +got):\n%s", diff)
+	}
+
+	var lines []int
+	for _, e := range invalid {
+		lines = append(lines, e.Line)
+	}
+	if diff := cmp.Diff([]int{6, 7, 8, 9}, lines); diff != "" {
+		t.Errorf("invalid rows mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParseCSV_MalformedRecordContinues(t *testing.T) {
+	input := "content\nok one\nbad \"quote\nok two\n"
+
+	rows, invalid, err := testParser().parse(strings.NewReader(input), "csv")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(rows) != 2 || rows[0].Content != "ok one" || rows[1].Content != "ok two" {
+		t.Errorf("rows = %+v, want ok one and ok two", rows)
+	}
+	if len(invalid) != 1 || invalid[0].Line != 3 {
+		t.Errorf("invalid = %v, want one error on row 3", invalid)
+	}
+}
+
+func TestParseCSV_MissingContentColumn(t *testing.T) {
+	_, _, err := testParser().parse(strings.NewReader("title,tags\nx,y\n"), "csv")
+	if err == nil {
+		t.Fatal("expected an error for a header without a content column")
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	input := `[
+		{"content": "hello", "tags": ["Work"], "created_at": "2024-02-03T04:05:06+02:00"},
+		{"content": 42},
+		{"content": "   "},
+		{"content": "plain"}
+	]`
+
+	rows, invalid, err := testParser().parse(strings.NewReader(input), "json")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	wantRows := []importRow{
+		{Line: 1, Content: "hello", Tags: []string{"work"}, CreatedAt: time.Date(2024, 2, 3, 2, 5, 6, 0, time.UTC)},
+		{Line: 4, Content: "plain"},
+	}
+	if diff := cmp.Diff(wantRows, rows, cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })); diff != "" {
+		t.Errorf("rows mismatch (-want +got):\n%s", diff)
+	}
+	if len(invalid) != 2 || invalid[0].Line != 2 || invalid[1].Line != 3 {
+		t.Errorf("invalid = %v, want rows 2 and 3", invalid)
+	}
+}
+
+func TestParseJSON_NotAnArray(t *testing.T) {
+	_, _, err := testParser().parse(strings.NewReader(`{"content": "x"}`), "json")
+	if err == nil {
+		t.Fatal("expected an error for a JSON object instead of an array")
+	}
+}
+
+func TestParse_UnsupportedFormat(t *testing.T) {
+	_, _, err := testParser().parse(strings.NewReader(""), "xml")
+	if err == nil {
+		t.Fatal("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/icco/etu-backend/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeServerStream is a server stream carrying only a context.
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (f *fakeServerStream) Context() context.Context { return f.ctx }
+
+func TestStreamAuthInterceptor(t *testing.T) {
+	t.Setenv("GRPC_API_KEYS", "test-m2m-token")
+	log := slog.New(slog.DiscardHandler)
+	interceptor := streamAuthInterceptor(nil, auth.NewM2MConfig(log), nil, nil, log)
+	info := &grpc.StreamServerInfo{FullMethod: "/etu.NotesService/WatchNotes", IsServerStream: true}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{name: "authorized", md: metadata.Pairs("authorization", "test-m2m-token"), wantCode: codes.OK},
+		{name: "missing authorization", md: metadata.MD{}, wantCode: codes.Unauthenticated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			var authType string
+			err := interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(srv interface{}, stream grpc.ServerStream) error {
+				authType = auth.GetAuthType(stream.Context())
+				return nil
+			})
+
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("got %v, want %v", err, tt.wantCode)
+			}
+			if tt.wantCode == codes.OK && authType != "m2m" {
+				t.Errorf("handler saw auth type %q, want m2m", authType)
+			}
+		})
+	}
+}
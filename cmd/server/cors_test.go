@@ -0,0 +1,94 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+)
+
+func TestWithCORS(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name        string
+		origins     []string
+		method      string
+		origin      string
+		preflight   bool
+		wantStatus  int
+		wantAllowed string
+	}{
+		{name: "disabled", origins: nil, method: http.MethodGet, origin: "https://app.example.com", wantStatus: http.StatusOK},
+		{name: "allowed request", origins: []string{"https://app.example.com"}, method: http.MethodGet, origin: "https://app.example.com", wantStatus: http.StatusOK, wantAllowed: "https://app.example.com"},
+		{name: "other origin", origins: []string{"https://app.example.com"}, method: http.MethodGet, origin: "https://evil.example.com", wantStatus: http.StatusOK},
+		{name: "same origin", origins: []string{"https://app.example.com"}, method: http.MethodGet, wantStatus: http.StatusOK},
+		{name: "wildcard", origins: []string{"*"}, method: http.MethodPost, origin: "https://any.example.com", wantStatus: http.StatusOK, wantAllowed: "*"},
+		{name: "allowed preflight", origins: []string{"https://app.example.com"}, method: http.MethodOptions, origin: "https://app.example.com", preflight: true, wantStatus: http.StatusNoContent, wantAllowed: "https://app.example.com"},
+		{name: "rejected preflight", origins: []string{"https://app.example.com"}, method: http.MethodOptions, origin: "https://evil.example.com", preflight: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/health", nil)
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.preflight {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "authorization,content-type")
+			}
+			rec := httptest.NewRecorder()
+
+			withCORS(next, tt.origins).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantAllowed {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantAllowed)
+			}
+			isPreflightAnswer := rec.Header().Get("Access-Control-Allow-Methods") != ""
+			if wantPreflight := tt.preflight && tt.wantAllowed != ""; isPreflightAnswer != wantPreflight {
+				t.Errorf("preflight headers set = %v, want %v", isPreflightAnswer, wantPreflight)
+			}
+		})
+	}
+}
+
+func TestWithCORS_HealthEndpoint(t *testing.T) {
+	handler := withCORS(newHTTPHandler(newHealthHandler(slog.New(slog.DiscardHandler)), http.NotFoundHandler()), []string{"https://app.example.com"})
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want 200", rec.Code)
+	}
+	if got := rec.Header().Get("Access-Control-Allow-Origin"); got != "https://app.example.com" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if !slices.Contains(rec.Header().Values("Vary"), "Origin") {
+		t.Errorf("Vary = %v, want it to include Origin", rec.Header().Values("Vary"))
+	}
+}
+
+func TestCORSOriginsFromEnv(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	if got := corsOriginsFromEnv(log); len(got) != 0 {
+		t.Errorf("unset = %v, want none", got)
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", " https://a.example.com/, ,https://b.example.com")
+	want := []string{"https://a.example.com", "https://b.example.com"}
+	if got := corsOriginsFromEnv(log); !slices.Equal(got, want) {
+		t.Errorf("corsOriginsFromEnv = %v, want %v", got, want)
+	}
+}
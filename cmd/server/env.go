@@ -0,0 +1,38 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"time"
+)
+
+// positiveIntFromEnv reads a positive integer from envVar, logging and using
+// defaultValue when it is unset or invalid
+func positiveIntFromEnv(log *slog.Logger, envVar string, defaultValue int) int {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		log.Error("invalid value, using default", "env", envVar, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+// positiveDurationFromEnv reads a positive duration ("30s", "2m") from
+// envVar, logging and using defaultValue when it is unset or invalid
+func positiveDurationFromEnv(log *slog.Logger, envVar string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Error("invalid duration, using default", "env", envVar, "value", value, "default", defaultValue)
+		return defaultValue
+	}
+	return d
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestPositiveIntFromEnv(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{value: "", want: 7},
+		{value: "20", want: 20},
+		{value: "0", want: 7},
+		{value: "-1", want: 7},
+		{value: "lots", want: 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("AUTH_RATE_LIMIT", tt.value)
+			if got := positiveIntFromEnv(log, "AUTH_RATE_LIMIT", 7); got != tt.want {
+				t.Errorf("positiveIntFromEnv(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPositiveDurationFromEnv(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: time.Minute},
+		{value: "90s", want: 90 * time.Second},
+		{value: "0s", want: time.Minute},
+		{value: "-5s", want: time.Minute},
+		{value: "60", want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("GRPC_REQUEST_TIMEOUT", tt.value)
+			if got := positiveDurationFromEnv(log, "GRPC_REQUEST_TIMEOUT", time.Minute); got != tt.want {
+				t.Errorf("positiveDurationFromEnv(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
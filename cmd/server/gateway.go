@@ -0,0 +1,55 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// gatewayPrefixes are the HTTP path prefixes served by the JSON gateway.
+// Routes mirror gRPC method names, e.g. POST /etu.NotesService/ListNotes.
+var gatewayPrefixes = []string{
+	"/etu.NotesService/",
+	"/etu.TagsService/",
+	"/etu.TemplatesService/",
+	"/etu.SyncService/",
+}
+
+// newGatewayHandler creates an HTTP/JSON handler that proxies requests to the
+// gRPC server at grpcAddr. Requests go through the gRPC server rather than
+// calling the services directly, so the auth interceptor applies unchanged and
+// the Authorization header is forwarded as "authorization" metadata.
+func newGatewayHandler(ctx context.Context, grpcAddr string) (http.Handler, error) {
+	mux := runtime.NewServeMux()
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+
+	if err := pb.RegisterNotesServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, fmt.Errorf("failed to register notes gateway: %w", err)
+	}
+	if err := pb.RegisterTagsServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, fmt.Errorf("failed to register tags gateway: %w", err)
+	}
+	if err := pb.RegisterTemplatesServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, fmt.Errorf("failed to register templates gateway: %w", err)
+	}
+	if err := pb.RegisterSyncServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, fmt.Errorf("failed to register sync gateway: %w", err)
+	}
+
+	return mux, nil
+}
+
+// newHTTPHandler combines the health endpoints with the JSON gateway.
+func newHTTPHandler(health, gateway http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", health)
+	for _, prefix := range gatewayPrefixes {
+		mux.Handle(prefix, gateway)
+	}
+	return mux
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc"
+)
+
+// gatewayTagsService is a minimal TagsService used to exercise the gateway.
+type gatewayTagsService struct {
+	pb.UnimplementedTagsServiceServer
+}
+
+func (s *gatewayTagsService) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+	return &pb.ListTagsResponse{
+		Tags: []*pb.Tag{{Id: "tag1", Name: req.UserId + "-work", Count: 2}},
+	}, nil
+}
+
+func TestGatewayProxiesThroughAuthInterceptor(t *testing.T) {
+	t.Setenv("GRPC_API_KEYS", "test-m2m-token")
+	log := slog.New(slog.DiscardHandler)
+
+	server := grpc.NewServer(grpc.UnaryInterceptor(authInterceptor(nil, auth.NewM2MConfig(log), nil, nil, log)))
+	pb.RegisterTagsServiceServer(server, &gatewayTagsService{})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	gateway, err := newGatewayHandler(ctx, lis.Addr().String())
+	if err != nil {
+		t.Fatalf("newGatewayHandler: %v", err)
+	}
+	handler := newHTTPHandler(newHealthHandler(log), gateway)
+
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+	}{
+		{name: "authorized", token: "test-m2m-token", wantStatus: http.StatusOK},
+		{name: "missing authorization", token: "", wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/etu.TagsService/ListTags", strings.NewReader(`{"userId":"user1"}`))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (body: %s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp struct {
+				Tags []struct {
+					Name string `json:"name"`
+				} `json:"tags"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Tags) != 1 || resp.Tags[0].Name != "user1-work" {
+				t.Errorf("unexpected tags: %+v", resp.Tags)
+			}
+		})
+	}
+}
+
+func TestHTTPHandlerKeepsHealthEndpoints(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	handler := newHTTPHandler(newHealthHandler(log), http.NotFoundHandler())
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/health status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
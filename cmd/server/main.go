@@ -13,8 +13,10 @@ import (
 
 	"github.com/icco/etu-backend/internal/ai"
 	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/crypto"
 	"github.com/icco/etu-backend/internal/db"
 	"github.com/icco/etu-backend/internal/logger"
+	"github.com/icco/etu-backend/internal/models"
 	"github.com/icco/etu-backend/internal/service"
 	"github.com/icco/etu-backend/internal/storage"
 	pb "github.com/icco/etu-backend/proto"
@@ -25,8 +27,10 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// Build metadata, set with -ldflags "-X main.CommitSHA=... -X main.BuildTime=..."
 var (
 	CommitSHA = "unknown"
+	BuildTime = "unknown"
 )
 
 func main() {
@@ -57,14 +61,21 @@ func main() {
 	}()
 
 	// Run database migrations
-	if err := database.AutoMigrate(); err != nil {
+	if err := database.Migrate(context.Background()); err != nil {
 		log.Error("failed to run database migrations", "error", err)
 		os.Exit(1)
 	}
 	log.Info("database initialized and migrations completed")
 
+	// API keys are stored as HMACs when a secret is set, bcrypt otherwise
+	apiKeyHasher, err := crypto.APIKeyHasherFromEnv()
+	if err != nil {
+		log.Error("invalid API_KEY_HMAC_SECRET", "error", err)
+		os.Exit(1)
+	}
+
 	// Initialize authenticator
-	authenticator, err := auth.New()
+	authenticator, err := auth.New(apiKeyHasher)
 	if err != nil {
 		log.Error("failed to initialize authenticator", "error", err)
 		os.Exit(1)
@@ -97,13 +108,15 @@ func main() {
 	}
 
 	// Get Gemini API key for AI operations (optional)
-	var aiClient *ai.Client
+	// Left as a nil interface when unavailable so services can check for nil
+	var aiClient ai.Generator
 	geminiAPIKey := os.Getenv("GEMINI_API_KEY")
 	if geminiAPIKey != "" {
-		aiClient, err = ai.NewClient(geminiAPIKey)
+		client, err := ai.NewClient(geminiAPIKey)
 		if err != nil {
 			log.Warn("failed to initialize AI client", "error", err)
 		} else {
+			aiClient = client
 			log.Info("AI client initialized (OCR, transcription enabled)")
 		}
 	} else {
@@ -121,18 +134,36 @@ func main() {
 	// Initialize M2M authentication configuration
 	m2mConfig := auth.NewM2MConfig(log)
 
-	// Create gRPC server with authentication interceptor
+	// Throttle the public Register and Authenticate methods per client IP
+	authLimiter := newIPRateLimiterFromEnv(log)
+
+	// Bound how long shutdown waits for in-flight gRPC calls
+	shutdownTimeout := positiveDurationFromEnv(log, "GRPC_SHUTDOWN_TIMEOUT", DefaultShutdownTimeout)
+
+	// Create gRPC server with panic recovery wrapping the authentication
+	// interceptors, so panics anywhere in a call are caught
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(authInterceptor(authenticator, m2mConfig, log)),
+		grpc.MaxRecvMsgSize(service.MaxRequestSize(log)),
+		grpc.ChainUnaryInterceptor(
+			recoveryInterceptor(log),
+			timeoutInterceptor(positiveDurationFromEnv(log, "GRPC_REQUEST_TIMEOUT", DefaultRequestTimeout)),
+			authInterceptor(authenticator, m2mConfig, database, authLimiter, log),
+		),
+		grpc.ChainStreamInterceptor(
+			streamRecoveryInterceptor(log),
+			streamAuthInterceptor(authenticator, m2mConfig, database, authLimiter, log),
+		),
 	)
 
 	// Register services
 	notesService := service.NewNotesService(database, storageClient, aiClient, imgixDomain)
-	tagsService := service.NewTagsService(database)
+	tagsService := service.NewTagsService(database, notesService)
 	authService := service.NewAuthService(database)
-	apiKeysService := service.NewApiKeysService(database)
+	apiKeysService := service.NewApiKeysService(database, authenticator, authenticator, apiKeyHasher)
 	userSettingsService := service.NewUserSettingsService(database, storageClient, imgixDomain)
 	statsService := service.NewStatsService(database)
+	templatesService := service.NewTemplatesService(database, notesService)
+	syncService := service.NewSyncService(database)
 
 	pb.RegisterNotesServiceServer(server, notesService)
 	pb.RegisterTagsServiceServer(server, tagsService)
@@ -140,6 +171,8 @@ func main() {
 	pb.RegisterApiKeysServiceServer(server, apiKeysService)
 	pb.RegisterUserSettingsServiceServer(server, userSettingsService)
 	pb.RegisterStatsServiceServer(server, statsService)
+	pb.RegisterTemplatesServiceServer(server, templatesService)
+	pb.RegisterSyncServiceServer(server, syncService)
 
 	// Enable reflection for development/debugging
 	reflection.Register(server)
@@ -151,17 +184,33 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Create HTTP server for health checks
+	// Create HTTP/JSON gateway backed by the local gRPC server
+	gatewayCtx, cancelGateway := context.WithCancel(context.Background())
+	defer cancelGateway()
+	gateway, err := newGatewayHandler(gatewayCtx, "localhost:"+grpcPort)
+	if err != nil {
+		log.Error("failed to create HTTP gateway", "error", err)
+		os.Exit(1)
+	}
+
+	// Serve Stripe subscription webhooks when a signing secret is configured
+	httpHandler := newHTTPHandler(newHealthHandler(log), gateway)
+	if secret := os.Getenv("STRIPE_WEBHOOK_SECRET"); secret != "" {
+		httpHandler = withStripeWebhook(httpHandler, newStripeWebhookHandler(secret, database, log))
+		log.Info("stripe webhook enabled", "path", stripeWebhookPath)
+	}
+
+	// Create HTTP server for health checks and the JSON gateway
 	httpServer := &http.Server{
 		Addr:         ":" + httpPort,
-		Handler:      newHealthHandler(log),
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: 5 * time.Second,
+		Handler:      withCORS(httpHandler, corsOriginsFromEnv(log)),
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
 	}
 
 	// Start HTTP server in goroutine
 	go func() {
-		log.Info("HTTP health server listening", "port", httpPort)
+		log.Info("HTTP server listening", "port", httpPort)
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Error("HTTP server error", "error", err)
 			os.Exit(1)
@@ -191,10 +240,16 @@ func main() {
 		log.Error("HTTP server shutdown error", "error", err)
 	}
 
-	// Gracefully stop gRPC server
-	server.GracefulStop()
+	// Gracefully stop gRPC server, ending WatchNotes streams first since
+	// they would otherwise keep it open. Calls still running after the grace
+	// period are cut off.
+	notesService.StopWatchers()
+	stopGRPCServer(server, shutdownTimeout, log)
+
+	// With no requests left to queue uploads, stop background OCR
+	notesService.StopMediaWorker()
 
-	log.Info("servers stopped gracefully")
+	log.Info("servers stopped")
 }
 
 // newHealthHandler creates an HTTP handler for health check endpoints
@@ -229,6 +284,9 @@ func newHealthHandler(log *slog.Logger) http.Handler {
 		}
 	})
 
+	// Build info for correlating deployed versions
+	mux.HandleFunc("/version", newVersionHandler(log))
+
 	// Readiness check (could add DB checks here if needed)
 	mux.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -244,7 +302,64 @@ func newHealthHandler(log *slog.Logger) http.Handler {
 }
 
 // authInterceptor creates a gRPC interceptor that validates API keys and M2M tokens
-func authInterceptor(authenticator *auth.Authenticator, m2mConfig *auth.M2MConfig, log *slog.Logger) grpc.UnaryServerInterceptor {
+func authInterceptor(authenticator *auth.Authenticator, m2mConfig *auth.M2MConfig, database *db.DB, limiter *ipRateLimiter, log *slog.Logger) grpc.UnaryServerInterceptor {
+	authenticate := newAuthenticateFunc(authenticator, m2mConfig, database, limiter, log)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// streamAuthInterceptor is authInterceptor for streaming RPCs such as
+// WatchNotes.
+func streamAuthInterceptor(authenticator *auth.Authenticator, m2mConfig *auth.M2MConfig, database *db.DB, limiter *ipRateLimiter, log *slog.Logger) grpc.StreamServerInterceptor {
+	authenticate := newAuthenticateFunc(authenticator, m2mConfig, database, limiter, log)
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticatedStream overrides a stream's context with the authenticated one.
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// hasM2MToken reports whether the call's authorization metadata carries a
+// valid M2M token.
+func hasM2MToken(ctx context.Context, m2mConfig *auth.M2MConfig) bool {
+	if !m2mConfig.IsEnabled() {
+		return false
+	}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	authHeaders := md.Get("authorization")
+	if len(authHeaders) == 0 {
+		return false
+	}
+	valid, _ := m2mConfig.ValidateToken(authHeaders[0])
+	return valid
+}
+
+// newAuthenticateFunc returns a function that checks the authorization
+// metadata for a call to method and returns a context carrying the caller.
+// API key checks that miss the key cache are written to database's login
+// audit log when database is non-nil, and rateLimitedMethods are throttled per client IP
+// when limiter is non-nil.
+func newAuthenticateFunc(authenticator *auth.Authenticator, m2mConfig *auth.M2MConfig, database *db.DB, limiter *ipRateLimiter, log *slog.Logger) func(ctx context.Context, method string) (context.Context, error) {
 	// Methods that don't require authentication
 	publicMethods := map[string]bool{
 		"/etu.AuthService/Register":        true,
@@ -252,11 +367,20 @@ func authInterceptor(authenticator *auth.Authenticator, m2mConfig *auth.M2MConfi
 		"/etu.ApiKeysService/VerifyApiKey": true,
 	}
 
-	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	return func(ctx context.Context, method string) (context.Context, error) {
 		// Skip auth for public methods
-		if publicMethods[info.FullMethod] {
-			log.Info("public request", "method", info.FullMethod)
-			return handler(ctx, req)
+		if publicMethods[method] {
+			// etu-web calls these server to server for all of its users, so
+			// M2M calls share its few IPs and must not be throttled by them
+			if limiter != nil && rateLimitedMethods[method] && !hasM2MToken(ctx, m2mConfig) {
+				ip := auth.RemoteIP(ctx)
+				if !limiter.Allow(ip) {
+					log.Warn("rate limited public request", "method", method, "ip", ip)
+					return nil, status.Error(codes.ResourceExhausted, "too many requests, try again later")
+				}
+			}
+			log.Info("public request", "method", method)
+			return ctx, nil
 		}
 
 		// Extract metadata from context
@@ -277,25 +401,33 @@ func authInterceptor(authenticator *auth.Authenticator, m2mConfig *auth.M2MConfi
 		if m2mConfig.IsEnabled() {
 			if valid, tokenIndex := m2mConfig.ValidateToken(token); valid {
 				// M2M authentication successful - no user context
-				ctx = auth.SetAuthContext(ctx, "m2m", "m2m")
-				m2mConfig.LogAuthentication(info.FullMethod, tokenIndex)
-				return handler(ctx, req)
+				m2mConfig.LogAuthentication(method, tokenIndex)
+				return auth.SetAuthContext(ctx, "m2m", "m2m"), nil
 			}
 		}
 
 		// Fall back to API key verification
-		userID, err := authenticator.VerifyAPIKey(ctx, token)
+		userID, checked, err := authenticator.VerifyAPIKey(ctx, token)
 		if err != nil {
-			log.Warn("authentication failed", "method", info.FullMethod, "error", err.Error())
+			log.Warn("authentication failed", "method", method, "error", err.Error())
 			return nil, status.Errorf(codes.Unauthenticated, "invalid API key: %v", err)
 		}
 
-		// Add user ID to context for use by handlers
-		ctx = auth.SetAuthContext(ctx, userID, "apikey")
-
 		// Log the authenticated request
-		log.Info("authenticated request", "method", info.FullMethod, "user_id", userID, "auth_type", "apikey")
+		log.Info("authenticated request", "method", method, "user_id", userID, "auth_type", "apikey")
+
+		// Audit the login when the key was actually checked rather than served
+		// from the cache, so the log doesn't grow by a row per request. The
+		// IP comes from the connection, since x-forwarded-for can be forged.
+		// A logging failure must not reject the request.
+		if database != nil && checked {
+			_, userAgent := auth.ClientInfo(ctx)
+			if err := database.RecordLoginEvent(ctx, userID, true, models.LoginAuthAPIKey, auth.RemoteIP(ctx), userAgent); err != nil {
+				log.Error("failed to record login event", "user_id", userID, "error", err)
+			}
+		}
 
-		return handler(ctx, req)
+		// Add user ID to context for use by handlers
+		return auth.SetAuthContext(ctx, userID, "apikey"), nil
 	}
 }
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+const (
+	// DefaultAuthRateLimit is how many Register and Authenticate calls one IP
+	// may make per minute once its burst is spent.
+	DefaultAuthRateLimit = 10
+	// DefaultAuthRateBurst is how many calls one IP may make back to back.
+	DefaultAuthRateBurst = 5
+
+	// ipLimiterIdleTTL is how long an IP's limiter is kept after its last
+	// call. A limiter idle this long has refilled, so dropping it loses
+	// nothing.
+	ipLimiterIdleTTL = 10 * time.Minute
+)
+
+// rateLimitedMethods are the public methods throttled per client IP
+var rateLimitedMethods = map[string]bool{
+	"/etu.AuthService/Register":     true,
+	"/etu.AuthService/Authenticate": true,
+}
+
+// ipRateLimiter keeps a token bucket per client IP
+type ipRateLimiter struct {
+	mu        sync.Mutex
+	limiters  map[string]*ipLimiter
+	limit     rate.Limit
+	burst     int
+	lastSweep time.Time
+}
+
+type ipLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// newIPRateLimiter allows perMinute calls per IP with bursts of up to burst
+func newIPRateLimiter(perMinute, burst int) *ipRateLimiter {
+	return &ipRateLimiter{
+		limiters:  make(map[string]*ipLimiter),
+		limit:     rate.Limit(float64(perMinute) / 60),
+		burst:     burst,
+		lastSweep: time.Now(),
+	}
+}
+
+// newIPRateLimiterFromEnv reads AUTH_RATE_LIMIT (calls per minute) and
+// AUTH_RATE_BURST, falling back to the defaults for unset or invalid values
+func newIPRateLimiterFromEnv(log *slog.Logger) *ipRateLimiter {
+	perMinute := positiveIntFromEnv(log, "AUTH_RATE_LIMIT", DefaultAuthRateLimit)
+	burst := positiveIntFromEnv(log, "AUTH_RATE_BURST", DefaultAuthRateBurst)
+	return newIPRateLimiter(perMinute, burst)
+}
+
+// Allow reports whether ip may make another call now
+func (l *ipRateLimiter) Allow(ip string) bool {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > ipLimiterIdleTTL {
+		for key, entry := range l.limiters {
+			if now.Sub(entry.lastSeen) > ipLimiterIdleTTL {
+				delete(l.limiters, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	entry, ok := l.limiters[ip]
+	if !ok {
+		entry = &ipLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[ip] = entry
+	}
+	entry.lastSeen = now
+	return entry.limiter.AllowN(now, 1)
+}
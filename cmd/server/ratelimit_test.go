@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/icco/etu-backend/internal/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+func peerContext(ip string) context.Context {
+	return peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP(ip), Port: 40000},
+	})
+}
+
+func TestAuthInterceptor_RateLimitsRegister(t *testing.T) {
+	t.Setenv("GRPC_API_KEYS", "test-m2m-token")
+	log := slog.New(slog.DiscardHandler)
+	const burst = 3
+	interceptor := authInterceptor(nil, auth.NewM2MConfig(log), nil, newIPRateLimiter(1, burst), log)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	register := &grpc.UnaryServerInfo{FullMethod: "/etu.AuthService/Register"}
+
+	for i := 0; i < burst; i++ {
+		if _, err := interceptor(peerContext("203.0.113.7"), nil, register, handler); err != nil {
+			t.Fatalf("Register call %d: %v", i+1, err)
+		}
+	}
+
+	_, err := interceptor(peerContext("203.0.113.7"), nil, register, handler)
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Register past the limit: got %v, want ResourceExhausted", err)
+	}
+
+	// Other IPs have their own budget
+	if _, err := interceptor(peerContext("198.51.100.2"), nil, register, handler); err != nil {
+		t.Errorf("Register from another IP: %v", err)
+	}
+
+	// Authenticated calls from the throttled IP are unaffected
+	ctx := metadata.NewIncomingContext(peerContext("203.0.113.7"), metadata.Pairs("authorization", "test-m2m-token"))
+	listTags := &grpc.UnaryServerInfo{FullMethod: "/etu.TagsService/ListTags"}
+	for i := 0; i < burst+1; i++ {
+		if _, err := interceptor(ctx, nil, listTags, handler); err != nil {
+			t.Fatalf("M2M call %d: %v", i+1, err)
+		}
+	}
+}
+
+func TestAuthInterceptor_M2MAuthenticateNotRateLimited(t *testing.T) {
+	t.Setenv("GRPC_API_KEYS", "test-m2m-token")
+	log := slog.New(slog.DiscardHandler)
+	const burst = 3
+	interceptor := authInterceptor(nil, auth.NewM2MConfig(log), nil, newIPRateLimiter(1, burst), log)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) { return "ok", nil }
+	authenticate := &grpc.UnaryServerInfo{FullMethod: "/etu.AuthService/Authenticate"}
+
+	// etu-web logs in every user from the same IP
+	ctx := metadata.NewIncomingContext(peerContext("203.0.113.7"), metadata.Pairs("authorization", "test-m2m-token"))
+	for i := 0; i < burst*3; i++ {
+		if _, err := interceptor(ctx, nil, authenticate, handler); err != nil {
+			t.Fatalf("M2M Authenticate call %d: %v", i+1, err)
+		}
+	}
+
+	// An invalid token is still throttled
+	bad := metadata.NewIncomingContext(peerContext("203.0.113.7"), metadata.Pairs("authorization", "wrong-token"))
+	for i := 0; i < burst; i++ {
+		if _, err := interceptor(bad, nil, authenticate, handler); err != nil {
+			t.Fatalf("Authenticate call %d with a bad token: %v", i+1, err)
+		}
+	}
+	if _, err := interceptor(bad, nil, authenticate, handler); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Authenticate past the limit with a bad token: got %v, want ResourceExhausted", err)
+	}
+}
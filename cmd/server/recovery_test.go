@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// panickingTagsService panics in GetTag and works in ListTags
+type panickingTagsService struct {
+	pb.UnimplementedTagsServiceServer
+}
+
+func (s *panickingTagsService) GetTag(ctx context.Context, req *pb.GetTagRequest) (*pb.GetTagResponse, error) {
+	panic("secret internal detail")
+}
+
+func (s *panickingTagsService) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*pb.ListTagsResponse, error) {
+	return &pb.ListTagsResponse{}, nil
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	t.Setenv("GRPC_API_KEYS", "test-m2m-token")
+	log := slog.New(slog.DiscardHandler)
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		recoveryInterceptor(log),
+		authInterceptor(nil, auth.NewM2MConfig(log), nil, nil, log),
+	))
+	pb.RegisterTagsServiceServer(server, &panickingTagsService{})
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	client := pb.NewTagsServiceClient(conn)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "test-m2m-token")
+
+	_, err = client.GetTag(ctx, &pb.GetTagRequest{UserId: "user1", Id: "tag1"})
+	if status.Code(err) != codes.Internal {
+		t.Fatalf("GetTag: got %v, want Internal", err)
+	}
+	if strings.Contains(status.Convert(err).Message(), "secret") {
+		t.Errorf("GetTag leaked the panic value: %q", status.Convert(err).Message())
+	}
+
+	// The server survives and keeps serving
+	if _, err := client.ListTags(ctx, &pb.ListTagsRequest{UserId: "user1"}); err != nil {
+		t.Errorf("ListTags after panic: %v", err)
+	}
+}
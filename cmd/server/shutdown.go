@@ -0,0 +1,42 @@
+package main
+
+import (
+	"log/slog"
+	"time"
+)
+
+// DefaultShutdownTimeout bounds how long graceful shutdown waits for
+// in-flight calls to finish
+const DefaultShutdownTimeout = 30 * time.Second
+
+// grpcStopper is the part of *grpc.Server used during shutdown
+type grpcStopper interface {
+	GracefulStop()
+	Stop()
+}
+
+// stopGRPCServer stops server gracefully, waiting for in-flight calls to
+// finish, but forces it closed if they are still running after timeout. It
+// reports whether the graceful path completed.
+func stopGRPCServer(server grpcStopper, timeout time.Duration, log *slog.Logger) bool {
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		log.Info("gRPC server stopped gracefully")
+		return true
+	case <-timer.C:
+		log.Warn("gRPC graceful stop timed out, closing remaining connections", "timeout", timeout)
+		// Stop also unblocks the pending GracefulStop
+		server.Stop()
+		<-done
+		return false
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// fakeStopper blocks GracefulStop until Stop is called, or returns at once
+// when hung is false
+type fakeStopper struct {
+	hung    bool
+	stopped chan struct{}
+	forced  bool
+}
+
+func newFakeStopper(hung bool) *fakeStopper {
+	return &fakeStopper{hung: hung, stopped: make(chan struct{})}
+}
+
+func (f *fakeStopper) GracefulStop() {
+	if f.hung {
+		<-f.stopped
+	}
+}
+
+func (f *fakeStopper) Stop() {
+	f.forced = true
+	close(f.stopped)
+}
+
+func TestStopGRPCServer(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	t.Run("graceful", func(t *testing.T) {
+		server := newFakeStopper(false)
+		if !stopGRPCServer(server, time.Second, log) {
+			t.Error("stopGRPCServer reported a forced stop")
+		}
+		if server.forced {
+			t.Error("Stop was called although GracefulStop finished")
+		}
+	})
+
+	t.Run("hung call", func(t *testing.T) {
+		server := newFakeStopper(true)
+		start := time.Now()
+		if stopGRPCServer(server, 20*time.Millisecond, log) {
+			t.Error("stopGRPCServer reported a graceful stop")
+		}
+		if !server.forced {
+			t.Error("Stop was not called after the timeout")
+		}
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("shutdown took %v, want it bounded by the timeout", elapsed)
+		}
+	})
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/icco/etu-backend/internal/db"
+)
+
+const (
+	// stripeWebhookPath is where Stripe delivers events
+	stripeWebhookPath = "/webhooks/stripe"
+
+	// stripeSignatureTolerance is how far a signed timestamp may be from
+	// now, matching Stripe's libraries. It limits replays of old events.
+	stripeSignatureTolerance = 5 * time.Minute
+
+	// maxStripePayloadSize bounds the webhook body; Stripe events are small
+	maxStripePayloadSize = 64 * 1024
+)
+
+var errBadStripeSignature = errors.New("invalid stripe signature")
+
+// stripeStore is the data access the Stripe webhook needs. *db.DB implements it.
+type stripeStore interface {
+	GetUserByStripeCustomerID(ctx context.Context, stripeCustomerID string) (*db.User, error)
+	ApplyStripeSubscriptionEvent(ctx context.Context, eventID, eventType, userID, subscriptionStatus string, subscriptionEnd *time.Time) (bool, error)
+}
+
+// stripeEvent is the part of a Stripe event the webhook reads
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+// stripeSubscription is the part of a Stripe subscription object the webhook
+// reads. Newer API versions report the period end on each item instead of
+// the subscription.
+type stripeSubscription struct {
+	Customer         string `json:"customer"`
+	Status           string `json:"status"`
+	CurrentPeriodEnd int64  `json:"current_period_end"`
+	Items            struct {
+		Data []struct {
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// periodEnd returns when the subscription's current period ends, or nil if
+// the event doesn't say
+func (s *stripeSubscription) periodEnd() *time.Time {
+	end := s.CurrentPeriodEnd
+	for _, item := range s.Items.Data {
+		end = max(end, item.CurrentPeriodEnd)
+	}
+	if end == 0 {
+		return nil
+	}
+	t := time.Unix(end, 0).UTC()
+	return &t
+}
+
+// withStripeWebhook serves the Stripe webhook at stripeWebhookPath and
+// everything else with next
+func withStripeWebhook(next, webhook http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", next)
+	mux.Handle(stripeWebhookPath, webhook)
+	return mux
+}
+
+// newStripeWebhookHandler updates users' subscriptions from Stripe
+// customer.subscription events signed with secret. Each event is applied at
+// most once, however often Stripe delivers it. Other event types, and events
+// for customers with no user, are acknowledged and ignored so Stripe doesn't
+// retry them.
+func newStripeWebhookHandler(secret string, store stripeStore, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxStripePayloadSize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if err := verifyStripeSignature(payload, r.Header.Get("Stripe-Signature"), secret, time.Now()); err != nil {
+			log.Warn("rejected stripe webhook", "error", err)
+			http.Error(w, "invalid signature", http.StatusBadRequest)
+			return
+		}
+
+		var event stripeEvent
+		if err := json.Unmarshal(payload, &event); err != nil || event.ID == "" {
+			http.Error(w, "invalid event", http.StatusBadRequest)
+			return
+		}
+
+		switch event.Type {
+		case "customer.subscription.created", "customer.subscription.updated", "customer.subscription.deleted":
+		default:
+			log.Debug("ignoring stripe event", "event_id", event.ID, "type", event.Type)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		var sub stripeSubscription
+		if err := json.Unmarshal(event.Data.Object, &sub); err != nil || sub.Customer == "" || sub.Status == "" {
+			http.Error(w, "invalid subscription", http.StatusBadRequest)
+			return
+		}
+
+		ctx := r.Context()
+		user, err := store.GetUserByStripeCustomerID(ctx, sub.Customer)
+		if err != nil {
+			log.Error("failed to look up stripe customer", "event_id", event.ID, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if user == nil {
+			log.Warn("ignoring stripe event for unknown customer", "event_id", event.ID, "customer", sub.Customer)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		applied, err := store.ApplyStripeSubscriptionEvent(ctx, event.ID, event.Type, user.ID, sub.Status, sub.periodEnd())
+		if err != nil {
+			log.Error("failed to update subscription", "event_id", event.ID, "user_id", user.ID, "error", err)
+			http.Error(w, "internal error", http.StatusInternalServerError)
+			return
+		}
+		if !applied {
+			log.Info("ignoring already processed stripe event", "event_id", event.ID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		log.Info("updated subscription from stripe", "event_id", event.ID, "type", event.Type, "user_id", user.ID, "status", sub.Status)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// verifyStripeSignature checks a Stripe-Signature header ("t=<unix>,v1=<hex>",
+// with one v1 per active secret) against payload. The signature is an
+// HMAC-SHA256 of "<t>.<payload>" keyed with the endpoint's signing secret.
+func verifyStripeSignature(payload []byte, header, secret string, now time.Time) error {
+	var timestamp string
+	var signatures [][]byte
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			if sig, err := hex.DecodeString(value); err == nil {
+				signatures = append(signatures, sig)
+			}
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("%w: missing timestamp or v1 signature", errBadStripeSignature)
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: bad timestamp %q", errBadStripeSignature, timestamp)
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return fmt.Errorf("%w: timestamp outside tolerance", errBadStripeSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		if hmac.Equal(sig, expected) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no matching signature", errBadStripeSignature)
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/internal/db"
+)
+
+const testStripeSecret = "whsec_test"
+
+// fakeStripeStore records subscription updates for one known customer
+type fakeStripeStore struct {
+	lookupErr error
+	processed map[string]bool
+	updates   []stripeUpdate
+}
+
+type stripeUpdate struct {
+	userID string
+	status string
+	end    *time.Time
+}
+
+func (f *fakeStripeStore) GetUserByStripeCustomerID(ctx context.Context, stripeCustomerID string) (*db.User, error) {
+	if f.lookupErr != nil {
+		return nil, f.lookupErr
+	}
+	if stripeCustomerID != "cus_123" {
+		return nil, nil
+	}
+	return &db.User{ID: "user1", StripeCustomerID: &stripeCustomerID}, nil
+}
+
+func (f *fakeStripeStore) ApplyStripeSubscriptionEvent(ctx context.Context, eventID, eventType, userID, subscriptionStatus string, subscriptionEnd *time.Time) (bool, error) {
+	if f.processed[eventID] {
+		return false, nil
+	}
+	if f.processed == nil {
+		f.processed = make(map[string]bool)
+	}
+	f.processed[eventID] = true
+	f.updates = append(f.updates, stripeUpdate{userID: userID, status: subscriptionStatus, end: subscriptionEnd})
+	return true, nil
+}
+
+// signStripePayload builds a Stripe-Signature header the way Stripe does
+func signStripePayload(payload, secret string, at time.Time) string {
+	timestamp := fmt.Sprint(at.Unix())
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "." + payload))
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func subscriptionEvent(eventType, customer, status string, periodEnd int64) string {
+	return fmt.Sprintf(`{"id":"evt_1","type":%q,"data":{"object":{"id":"sub_1","object":"subscription","customer":%q,"status":%q,"current_period_end":%d}}}`,
+		eventType, customer, status, periodEnd)
+}
+
+func postStripeWebhook(t *testing.T, store stripeStore, payload, signature string) *httptest.ResponseRecorder {
+	t.Helper()
+	handler := withStripeWebhook(http.NotFoundHandler(), newStripeWebhookHandler(testStripeSecret, store, slog.New(slog.DiscardHandler)))
+	req := httptest.NewRequest(http.MethodPost, stripeWebhookPath, strings.NewReader(payload))
+	if signature != "" {
+		req.Header.Set("Stripe-Signature", signature)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestStripeWebhook_UpdatesSubscription(t *testing.T) {
+	store := &fakeStripeStore{}
+	periodEnd := time.Date(2026, 11, 16, 0, 0, 0, 0, time.UTC)
+	payload := subscriptionEvent("customer.subscription.updated", "cus_123", "active", periodEnd.Unix())
+
+	rec := postStripeWebhook(t, store, payload, signStripePayload(payload, testStripeSecret, time.Now()))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if len(store.updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(store.updates))
+	}
+	got := store.updates[0]
+	if got.userID != "user1" || got.status != "active" || got.end == nil || !got.end.Equal(periodEnd) {
+		t.Errorf("update = %+v, want user1 active until %v", got, periodEnd)
+	}
+}
+
+func TestStripeWebhook_RedeliveryAppliedOnce(t *testing.T) {
+	store := &fakeStripeStore{}
+	payload := subscriptionEvent("customer.subscription.updated", "cus_123", "active", 0)
+
+	for i := range 2 {
+		rec := postStripeWebhook(t, store, payload, signStripePayload(payload, testStripeSecret, time.Now()))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("delivery %d: status = %d, want 200", i+1, rec.Code)
+		}
+	}
+	if len(store.updates) != 1 {
+		t.Errorf("got %d updates, want 1", len(store.updates))
+	}
+}
+
+func TestStripeWebhook_ItemPeriodEnd(t *testing.T) {
+	store := &fakeStripeStore{}
+	periodEnd := time.Date(2026, 11, 16, 0, 0, 0, 0, time.UTC)
+	payload := fmt.Sprintf(`{"id":"evt_1","type":"customer.subscription.deleted","data":{"object":{"customer":"cus_123","status":"canceled","items":{"data":[{"current_period_end":%d}]}}}}`, periodEnd.Unix())
+
+	rec := postStripeWebhook(t, store, payload, signStripePayload(payload, testStripeSecret, time.Now()))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if len(store.updates) != 1 || store.updates[0].status != "canceled" || store.updates[0].end == nil || !store.updates[0].end.Equal(periodEnd) {
+		t.Errorf("updates = %+v, want canceled until %v", store.updates, periodEnd)
+	}
+}
+
+func TestStripeWebhook_BadSignature(t *testing.T) {
+	payload := subscriptionEvent("customer.subscription.updated", "cus_123", "active", 0)
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		signature string
+	}{
+		{name: "missing header", signature: ""},
+		{name: "wrong secret", signature: signStripePayload(payload, "whsec_other", now)},
+		{name: "tampered payload", signature: signStripePayload(strings.Replace(payload, "active", "trialing", 1), testStripeSecret, now)},
+		{name: "stale timestamp", signature: signStripePayload(payload, testStripeSecret, now.Add(-10*time.Minute))},
+		{name: "no v1 signature", signature: fmt.Sprintf("t=%d", now.Unix())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStripeStore{}
+			rec := postStripeWebhook(t, store, payload, tt.signature)
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want 400", rec.Code)
+			}
+			if len(store.updates) != 0 {
+				t.Errorf("got %d updates, want none", len(store.updates))
+			}
+		})
+	}
+}
+
+func TestStripeWebhook_Ignored(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+	}{
+		{name: "other event type", payload: `{"id":"evt_1","type":"invoice.paid","data":{"object":{"customer":"cus_123"}}}`},
+		{name: "unknown customer", payload: subscriptionEvent("customer.subscription.updated", "cus_unknown", "active", 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeStripeStore{}
+			rec := postStripeWebhook(t, store, tt.payload, signStripePayload(tt.payload, testStripeSecret, time.Now()))
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want 200", rec.Code)
+			}
+			if len(store.updates) != 0 {
+				t.Errorf("got %d updates, want none", len(store.updates))
+			}
+		})
+	}
+}
+
+func TestStripeWebhook_LookupErrorIsRetried(t *testing.T) {
+	store := &fakeStripeStore{lookupErr: errors.New("db down")}
+	payload := subscriptionEvent("customer.subscription.updated", "cus_123", "active", 0)
+
+	rec := postStripeWebhook(t, store, payload, signStripePayload(payload, testStripeSecret, time.Now()))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500 so Stripe retries", rec.Code)
+	}
+}
+
+func TestStripeWebhook_MountedAlongsideHealth(t *testing.T) {
+	handler := withStripeWebhook(newHTTPHandler(newHealthHandler(slog.New(slog.DiscardHandler)), http.NotFoundHandler()),
+		newStripeWebhookHandler(testStripeSecret, &fakeStripeStore{}, slog.New(slog.DiscardHandler)))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/health status = %d, want 200", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, stripeWebhookPath, nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("GET %s status = %d, want 405", stripeWebhookPath, rec.Code)
+	}
+}
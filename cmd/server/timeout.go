@@ -0,0 +1,30 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// DefaultRequestTimeout bounds how long a unary call may run
+const DefaultRequestTimeout = 60 * time.Second
+
+// noTimeoutMethods are unary methods exempt from the request timeout.
+// Streaming RPCs such as WatchNotes are long-lived by design and are never
+// given one, since timeoutInterceptor only wraps unary calls.
+var noTimeoutMethods = map[string]bool{}
+
+// timeoutInterceptor gives each unary call a context that is cancelled after
+// timeout, so a hung handler releases its resources. A shorter deadline set
+// by the client still applies.
+func timeoutInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if noTimeoutMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestTimeoutInterceptor(t *testing.T) {
+	interceptor := timeoutInterceptor(50 * time.Millisecond)
+
+	var deadline time.Time
+	var hasDeadline bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		deadline, hasDeadline = ctx.Deadline()
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	start := time.Now()
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/etu.NotesService/ListNotes"}, handler)
+	if err != context.DeadlineExceeded {
+		t.Fatalf("handler error = %v, want DeadlineExceeded", err)
+	}
+	if !hasDeadline || deadline.Sub(start) > time.Second {
+		t.Errorf("handler deadline = %v (set %v), want about 50ms", deadline.Sub(start), hasDeadline)
+	}
+}
+
+func TestTimeoutInterceptor_KeepsShorterClientDeadline(t *testing.T) {
+	interceptor := timeoutInterceptor(time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	want, _ := ctx.Deadline()
+
+	_, _ = interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/etu.NotesService/ListNotes"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if got, _ := ctx.Deadline(); !got.Equal(want) {
+			t.Errorf("handler deadline = %v, want client deadline %v", got, want)
+		}
+		return nil, nil
+	})
+}
+
+func TestTimeoutInterceptor_OptOut(t *testing.T) {
+	noTimeoutMethods["/etu.NotesService/Slow"] = true
+	defer delete(noTimeoutMethods, "/etu.NotesService/Slow")
+
+	interceptor := timeoutInterceptor(time.Millisecond)
+	_, _ = interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/etu.NotesService/Slow"}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("opted-out method was given a deadline")
+		}
+		return nil, nil
+	})
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+)
+
+// serviceName identifies this binary in the /version response.
+const serviceName = "etu-backend"
+
+// versionInfo describes the running build.
+type versionInfo struct {
+	Service   string `json:"service"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+// buildVersion combines the values set with -ldflags with the module build
+// info. VCS settings fill in the commit and build time when the binary was
+// built without ldflags, e.g. with go run or go install.
+func buildVersion() versionInfo {
+	info := versionInfo{
+		Service:   serviceName,
+		Commit:    CommitSHA,
+		BuildTime: BuildTime,
+		GoVersion: "unknown",
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = bi.GoVersion
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if info.Commit == "unknown" && setting.Value != "" {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if info.BuildTime == "unknown" && setting.Value != "" {
+				info.BuildTime = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// newVersionHandler serves the build info of the running binary.
+func newVersionHandler(log *slog.Logger) http.HandlerFunc {
+	info := buildVersion()
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(info); err != nil {
+			log.Error("error encoding version response", "error", err)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestVersionEndpoint(t *testing.T) {
+	oldCommit, oldBuildTime := CommitSHA, BuildTime
+	CommitSHA, BuildTime = "abc1234", "2026-01-02T03:04:05Z"
+	t.Cleanup(func() { CommitSHA, BuildTime = oldCommit, oldBuildTime })
+
+	handler := newHTTPHandler(newHealthHandler(slog.New(slog.DiscardHandler)), http.NotFoundHandler())
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("/version status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var got versionInfo
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	want := versionInfo{
+		Service:   serviceName,
+		Commit:    "abc1234",
+		BuildTime: "2026-01-02T03:04:05Z",
+		GoVersion: runtime.Version(),
+	}
+	if got != want {
+		t.Errorf("/version = %+v, want %+v", got, want)
+	}
+}
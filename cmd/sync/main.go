@@ -6,22 +6,97 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"slices"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/icco/etu-backend/internal/logger"
 	"github.com/icco/etu-backend/internal/notion"
+	"github.com/icco/etu-backend/internal/storage"
 	"github.com/icco/etu-backend/internal/sync"
 	"github.com/icco/etu-backend/internal/syncdb"
 )
 
+// SyncSummary aggregates the results of syncing every user in one run.
+type SyncSummary struct {
+	Users     int // Users with a Notion key
+	Succeeded int
+	Failed    int
+	Skipped   int // Synced within their own interval
+	Created   int
+	Updated   int
+	Unchanged int
+	Archived  int
+	Pruned    int
+	Errors    int // Per-note errors across all users
+	Duration  time.Duration
+}
+
+// addFromNotion adds the counts of a Notion-to-database sync.
+func (s *SyncSummary) addFromNotion(r *sync.SyncResult) {
+	s.Created += r.Created
+	s.Updated += r.Updated
+	s.Unchanged += r.Unchanged
+	s.Pruned += r.Pruned
+	s.Errors += r.Errors
+}
+
+// addToNotion adds the counts of a database-to-Notion sync.
+func (s *SyncSummary) addToNotion(r *sync.SyncToNotionResult) {
+	s.Created += r.Created
+	s.Updated += r.Updated
+	s.Archived += r.Archived
+	s.Errors += r.Errors
+}
+
+// add accumulates another run's summary, for totals across continuous runs.
+func (s *SyncSummary) add(o SyncSummary) {
+	s.Users += o.Users
+	s.Succeeded += o.Succeeded
+	s.Failed += o.Failed
+	s.Skipped += o.Skipped
+	s.Created += o.Created
+	s.Updated += o.Updated
+	s.Unchanged += o.Unchanged
+	s.Archived += o.Archived
+	s.Pruned += o.Pruned
+	s.Errors += o.Errors
+	s.Duration += o.Duration
+}
+
 func main() {
 	log := logger.New()
 
+	// Deferred first so it runs after every other deferred cleanup
+	exitCode := 0
+	defer func() {
+		if exitCode != 0 {
+			os.Exit(exitCode)
+		}
+	}()
+
+	// SYNC_INCREMENTAL_BUFFER sets the default for -incremental-buffer
+	incrementalBufferDefault := sync.DefaultIncrementalBuffer
+	if value := os.Getenv("SYNC_INCREMENTAL_BUFFER"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d <= 0 {
+			log.Error("invalid SYNC_INCREMENTAL_BUFFER value", "value", value, "error", err)
+			os.Exit(1)
+		}
+		incrementalBufferDefault = d
+	}
+
 	// Parse command line flags
 	fullSync := flag.Bool("full", false, "Perform a full sync instead of incremental")
 	direction := flag.String("direction", "from-notion", "Sync direction: from-notion, to-notion, or bidirectional")
 	interval := flag.Duration("interval", 0, "Run continuously with this interval (e.g., 1h). If not set, runs once and exits.")
+	dryRun := flag.Bool("dry-run", false, "Log what would be created, updated, or archived without writing to the database or Notion")
+	toNotionSince := flag.String("to-notion-since", "", "Only push notes modified at or after this RFC 3339 time to Notion (e.g., 2024-01-02T15:04:05Z)")
+	syncTags := flag.String("sync-tags", string(sync.TagSyncBoth), "Which way tags are synced: both, from-notion, to-notion, or none")
+	prune := flag.Bool("prune", false, "Delete notes whose Notion page is gone, on full syncs that list every page")
+	maxPruneFraction := flag.Float64("max-prune-fraction", sync.DefaultMaxPruneFraction, "Largest share (0-1] of a user's Notion-linked notes one prune may delete; larger prunes are refused")
+	incrementalBuffer := flag.Duration("incremental-buffer", incrementalBufferDefault, "How far before the last sync an incremental sync looks for edited Notion pages")
 	flag.Parse()
 
 	// Validate direction flag
@@ -35,6 +110,47 @@ func main() {
 		os.Exit(1)
 	}
 
+	if !slices.Contains(sync.TagSyncModes, sync.TagSync(*syncTags)) {
+		log.Error("invalid sync-tags value", "sync_tags", *syncTags, "valid_options", sync.TagSyncModes)
+		os.Exit(1)
+	}
+
+	var since time.Time
+	if *toNotionSince != "" {
+		parsed, err := time.Parse(time.RFC3339, *toNotionSince)
+		if err != nil {
+			log.Error("invalid to-notion-since value", "value", *toNotionSince, "error", err)
+			os.Exit(1)
+		}
+		since = parsed
+	}
+
+	if *incrementalBuffer <= 0 {
+		log.Error("invalid incremental-buffer value", "value", incrementalBuffer.String())
+		os.Exit(1)
+	}
+
+	if *maxPruneFraction <= 0 || *maxPruneFraction > 1 {
+		log.Error("invalid max-prune-fraction value", "value", *maxPruneFraction)
+		os.Exit(1)
+	}
+
+	var maxContentLength int
+	if value := os.Getenv("MAX_CONTENT_LENGTH"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			log.Error("invalid MAX_CONTENT_LENGTH value", "value", value, "error", err)
+			os.Exit(1)
+		}
+		maxContentLength = n
+	}
+
+	retryConfig, err := notion.RetryConfigFromEnv()
+	if err != nil {
+		log.Error("invalid Notion retry configuration", "error", err)
+		os.Exit(1)
+	}
+
 	intervalStr := "once"
 	if *interval > 0 {
 		intervalStr = interval.String()
@@ -44,7 +160,13 @@ func main() {
 		"direction", *direction,
 		"full_sync", *fullSync,
 		"continuous", *interval > 0,
-		"interval", intervalStr)
+		"interval", intervalStr,
+		"dry_run", *dryRun,
+		"prune", *prune,
+		"max_prune_fraction", *maxPruneFraction,
+		"sync_tags", *syncTags,
+		"incremental_buffer", incrementalBuffer.String(),
+		"notion_max_retries", retryConfig.MaxRetries)
 
 	// Initialize database with GORM
 	database, err := syncdb.New()
@@ -78,22 +200,63 @@ func main() {
 		cancel()
 	}()
 
+	// Images pushed to Notion load from imgix when configured, otherwise from
+	// signed GCS URLs, which expire
+	opts := sync.Options{
+		DryRun:            *dryRun,
+		ImgixDomain:       os.Getenv("IMGIX_DOMAIN"),
+		ToNotionSince:     since,
+		MaxContentLength:  maxContentLength,
+		IncrementalBuffer: *incrementalBuffer,
+		Prune:             *prune,
+		MaxPruneFraction:  *maxPruneFraction,
+		SyncTags:          sync.TagSync(*syncTags),
+	}
+	// Pruning also needs storage, to delete the pruned notes' media
+	if gcsBucket := os.Getenv("GCS_BUCKET"); gcsBucket != "" && (opts.ImgixDomain == "" || opts.Prune) {
+		storageClient, err := storage.New(ctx, gcsBucket)
+		if err != nil {
+			log.Warn("failed to initialize GCS storage client, using stored image URLs and leaving pruned media", "error", err, "bucket", gcsBucket)
+		} else {
+			defer func() {
+				if err := storageClient.Close(); err != nil {
+					log.Error("error closing storage client", "error", err)
+				}
+			}()
+			if opts.ImgixDomain == "" {
+				opts.Signer = storageClient
+			}
+			opts.Storage = storageClient
+		}
+	}
 	if *interval > 0 {
 		// Run continuously
-		runContinuously(ctx, log, database, *fullSync, *direction, *interval)
+		runContinuously(ctx, log, database, *fullSync, *direction, opts, retryConfig, *interval)
 	} else {
-		// Run once
-		runOnce(ctx, log, database, *fullSync, *direction)
+		// Run once, exiting non-zero if any user failed so schedulers notice
+		summary, err := runOnce(ctx, log, database, *fullSync, *direction, opts, retryConfig)
+		if err != nil || summary.Failed > 0 {
+			exitCode = 1
+		}
 	}
 }
 
-func runOnce(ctx context.Context, log *slog.Logger, database *syncdb.DB, fullSync bool, syncMode string) {
-	syncAllUsers(ctx, log, database, fullSync, syncMode)
+func runOnce(ctx context.Context, log *slog.Logger, database *syncdb.DB, fullSync bool, syncMode string, opts sync.Options, retry notion.RetryConfig) (SyncSummary, error) {
+	return syncAllUsers(ctx, log, database, fullSync, syncMode, opts, retry)
 }
 
-func runContinuously(ctx context.Context, log *slog.Logger, database *syncdb.DB, fullSync bool, syncMode string, interval time.Duration) {
+func runContinuously(ctx context.Context, log *slog.Logger, database *syncdb.DB, fullSync bool, syncMode string, opts sync.Options, retry notion.RetryConfig, interval time.Duration) {
+	var totals SyncSummary
+	runs := 0
+	run := func() {
+		// A failed run is already logged; keep going and try again next tick
+		summary, _ := syncAllUsers(ctx, log, database, fullSync, syncMode, opts, retry)
+		totals.add(summary)
+		runs++
+	}
+
 	// Run immediately on start
-	syncAllUsers(ctx, log, database, fullSync, syncMode)
+	run()
 
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
@@ -101,64 +264,120 @@ func runContinuously(ctx context.Context, log *slog.Logger, database *syncdb.DB,
 	for {
 		select {
 		case <-ctx.Done():
-			log.Info("shutting down sync job")
+			log.Info("shutting down sync job",
+				"runs", runs,
+				"succeeded", totals.Succeeded,
+				"failed", totals.Failed,
+				"created", totals.Created,
+				"updated", totals.Updated,
+				"archived", totals.Archived,
+				"pruned", totals.Pruned,
+				"errors", totals.Errors,
+				"duration", totals.Duration.String())
 			return
 		case <-ticker.C:
 			// After the first run, always do incremental syncs unless --full was specified
-			syncAllUsers(ctx, log, database, fullSync, syncMode)
+			run()
 		}
 	}
 }
 
-func syncAllUsers(ctx context.Context, log *slog.Logger, database *syncdb.DB, fullSync bool, syncMode string) {
-	log.Info("starting sync for all users", "timestamp", time.Now().Format(time.RFC3339))
+// syncAllUsers syncs every user with a Notion key and returns the combined
+// results. It only returns an error if the users couldn't be loaded; per-user
+// failures are counted in the summary.
+func syncAllUsers(ctx context.Context, log *slog.Logger, database *syncdb.DB, fullSync bool, syncMode string, opts sync.Options, retry notion.RetryConfig) (SyncSummary, error) {
+	start := time.Now()
+	var summary SyncSummary
+	log.Info("starting sync for all users", "timestamp", start.Format(time.RFC3339))
+
+	// Drop watermarks of users who removed their Notion key, so re-adding
+	// one starts a full sync. Failing to clean up doesn't stop the sync.
+	if !opts.DryRun {
+		if removed, err := database.DeleteStaleSyncState(ctx); err != nil {
+			log.Warn("failed to delete stale sync state", "error", err)
+		} else if removed > 0 {
+			log.Info("deleted stale sync state", "count", removed)
+		}
+	}
 
 	// Get all users with Notion keys
 	users, err := database.GetUsersWithNotionKeys(ctx)
 	if err != nil {
 		log.Error("failed to get users with Notion keys", "error", err)
-		return
+		return summary, err
 	}
 
 	if len(users) == 0 {
 		log.Info("no users with Notion API keys configured")
-		return
+		return summary, nil
 	}
 
 	log.Info("found users with Notion keys", "count", len(users))
-
-	successCount := 0
-	failureCount := 0
+	summary.Users = len(users)
 
 	for _, user := range users {
 		if user.NotionKey == nil || *user.NotionKey == "" {
 			continue
 		}
 
+		// Users with their own interval are skipped until it has elapsed;
+		// everyone else is synced on every run.
+		if user.SyncIntervalMinutes != nil && *user.SyncIntervalMinutes > 0 {
+			userInterval := time.Duration(*user.SyncIntervalMinutes) * time.Minute
+			lastSync, err := database.GetLastSyncTime(user.ID)
+			if err != nil {
+				log.Warn("failed to get last sync time, syncing anyway", "user_id", user.ID, "error", err)
+			} else if !dueForSync(lastSync, userInterval, time.Now()) {
+				log.Info("skipping user, synced within their interval",
+					"user_id", user.ID,
+					"last_sync", lastSync.Format(time.RFC3339),
+					"sync_interval", userInterval.String())
+				summary.Skipped++
+				continue
+			}
+		}
+
 		// Create Notion client with user's API key and optional database name
 		databaseName := notion.DefaultDatabaseName
 		if user.NotionDatabaseName != nil && *user.NotionDatabaseName != "" {
 			databaseName = *user.NotionDatabaseName
 		}
-		notionClient := notion.NewClientWithKey(*user.NotionKey, databaseName)
-		syncer := sync.NewSyncer(database, notionClient)
+		notionClient := notion.NewClientWithConfig(*user.NotionKey, databaseName, retry)
+		syncer := sync.NewSyncer(database, notionClient, opts)
 
 		// Try to sync and track success/failure
-		syncResult := performSyncWithResult(ctx, log, syncer, user.ID, fullSync, syncMode)
+		syncResult := performSyncWithResult(ctx, log, syncer, user.ID, fullSync, syncMode, &summary)
 		if syncResult {
-			successCount++
+			summary.Succeeded++
 		} else {
-			failureCount++
+			summary.Failed++
 		}
 	}
 
+	summary.Duration = time.Since(start)
 	log.Info("completed sync for all users",
-		"succeeded", successCount,
-		"failed", failureCount,
-		"total", len(users))
+		"succeeded", summary.Succeeded,
+		"failed", summary.Failed,
+		"skipped", summary.Skipped,
+		"total", summary.Users,
+		"created", summary.Created,
+		"updated", summary.Updated,
+		"unchanged", summary.Unchanged,
+		"archived", summary.Archived,
+		"pruned", summary.Pruned,
+		"errors", summary.Errors,
+		"duration", summary.Duration.String())
+	return summary, nil
+}
+
+// dueForSync reports whether a user last synced at lastSync should be synced
+// again at now under their per-user interval. A user who has never synced is
+// always due.
+func dueForSync(lastSync *time.Time, interval time.Duration, now time.Time) bool {
+	return lastSync == nil || !now.Before(lastSync.Add(interval))
 }
 
-func performSyncWithResult(ctx context.Context, log *slog.Logger, syncer *sync.Syncer, userID string, fullSync bool, syncMode string) bool {
+func performSyncWithResult(ctx context.Context, log *slog.Logger, syncer *sync.Syncer, userID string, fullSync bool, syncMode string, summary *SyncSummary) bool {
 	switch syncMode {
 	case "to-notion":
 		result, err := syncer.SyncUserToNotion(ctx, userID)
@@ -177,6 +396,7 @@ func performSyncWithResult(ctx context.Context, log *slog.Logger, syncer *sync.S
 			"updated", result.Updated,
 			"archived", result.Archived,
 			"errors", result.Errors)
+		summary.addToNotion(result)
 		return result.Errors == 0
 
 	case "bidirectional":
@@ -195,12 +415,15 @@ func performSyncWithResult(ctx context.Context, log *slog.Logger, syncer *sync.S
 			"from_notion_created", fromResult.Created,
 			"from_notion_updated", fromResult.Updated,
 			"from_notion_unchanged", fromResult.Unchanged,
+			"from_notion_pruned", fromResult.Pruned,
 			"from_notion_errors", fromResult.Errors,
 			"to_notion_duration", toResult.Duration.String(),
 			"to_notion_created", toResult.Created,
 			"to_notion_updated", toResult.Updated,
 			"to_notion_archived", toResult.Archived,
 			"to_notion_errors", toResult.Errors)
+		summary.addFromNotion(fromResult)
+		summary.addToNotion(toResult)
 		return fromResult.Errors == 0 && toResult.Errors == 0
 
 	default: // from-notion
@@ -219,7 +442,9 @@ func performSyncWithResult(ctx context.Context, log *slog.Logger, syncer *sync.S
 			"created", result.Created,
 			"updated", result.Updated,
 			"unchanged", result.Unchanged,
+			"pruned", result.Pruned,
 			"errors", result.Errors)
+		summary.addFromNotion(result)
 		return result.Errors == 0
 	}
 }
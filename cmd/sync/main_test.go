@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/icco/etu-backend/internal/sync"
+	"github.com/icco/etu-backend/internal/syncdb"
+)
+
+func TestDueForSync(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		ts := now.Add(-d)
+		return &ts
+	}
+
+	tests := []struct {
+		name     string
+		lastSync *time.Time
+		interval time.Duration
+		want     bool
+	}{
+		{name: "never synced", lastSync: nil, interval: time.Hour, want: true},
+		{name: "within interval", lastSync: at(30 * time.Minute), interval: time.Hour, want: false},
+		{name: "interval just elapsed", lastSync: at(time.Hour), interval: time.Hour, want: true},
+		{name: "past interval", lastSync: at(2 * time.Hour), interval: time.Hour, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dueForSync(tt.lastSync, tt.interval, now); got != tt.want {
+				t.Errorf("dueForSync() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncSummary(t *testing.T) {
+	var run SyncSummary
+	run.addFromNotion(&sync.SyncResult{Created: 2, Updated: 1, Unchanged: 5, Pruned: 1, Errors: 1})
+	run.addToNotion(&sync.SyncToNotionResult{Created: 1, Updated: 3, Archived: 2})
+	run.Succeeded, run.Failed, run.Users = 1, 1, 2
+
+	var totals SyncSummary
+	totals.add(run)
+	totals.add(run)
+
+	want := SyncSummary{Users: 4, Succeeded: 2, Failed: 2, Created: 6, Updated: 8, Unchanged: 10, Archived: 4, Pruned: 2, Errors: 2}
+	if diff := cmp.Diff(want, totals); diff != "" {
+		t.Errorf("totals mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPerformSyncWithResult_EscapesErrors(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	database, err := syncdb.NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// An error message with quotes and a newline used to break the
+	// hand-built JSON log lines
+	dbErr := errors.New(`relation "SyncState" does not exist` + "\nHINT: run \"migrate\"")
+	mock.ExpectQuery(`SELECT (.+) FROM "SyncState"`).WillReturnError(dbErr)
+
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, nil))
+	syncer := sync.NewSyncer(database, nil, sync.Options{})
+
+	if performSyncWithResult(context.Background(), log, syncer, "user-1", false, "from-notion", &SyncSummary{}) {
+		t.Fatal("performSyncWithResult() = true, want false")
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d log lines, want 1:\n%s", len(lines), buf.String())
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("log line is not valid JSON: %v\n%s", err, lines[0])
+	}
+	if entry["user_id"] != "user-1" || entry["direction"] != "from-notion" {
+		t.Errorf("entry = %v, want user_id and direction fields", entry)
+	}
+	if msg, _ := entry["error"].(string); !strings.Contains(msg, dbErr.Error()) {
+		t.Errorf("error = %q, want it to contain %q", msg, dbErr.Error())
+	}
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
+	"fmt"
 	"log/slog"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -13,6 +16,7 @@ import (
 	"github.com/icco/etu-backend/internal/ai"
 	"github.com/icco/etu-backend/internal/db"
 	"github.com/icco/etu-backend/internal/logger"
+	"github.com/icco/etu-backend/internal/models"
 	"github.com/icco/etu-backend/internal/storage"
 	"github.com/icco/etu-backend/internal/tagging"
 	"golang.org/x/time/rate"
@@ -24,8 +28,38 @@ func main() {
 	// Parse command line flags
 	interval := flag.Duration("interval", 0, "Run continuously with this interval (e.g., 1h). If not set, runs once and exits.")
 	dryRun := flag.Bool("dry-run", false, "Run without actually adding tags (for testing)")
+	tasksFlag := flag.String("tasks", strings.Join(defaultTasks, ","), "Comma-separated list of tasks to run (tags, ocr, transcribe, summaries, moods)")
+	maxAttempts := flag.Int("max-attempts", 5, "Skip images and audio files that have failed processing this many times")
+	shutdownGrace := flag.Duration("shutdown-grace", 30*time.Second, "How long in-flight AI calls may run after a shutdown signal before being cancelled")
+	maxTags := flag.Int("max-tags", ai.DefaultMaxTags, fmt.Sprintf("Give notes with fewer than this many tags up to this many (1-%d)", ai.MaxTagsLimit))
+	temperature := flag.Float64("temperature", float64(ai.DefaultTagTemperature), "Sampling temperature for tag generation, clamped to 0-2")
+	tagGuidance := flag.String("tag-guidance", "", "Extra instructions for tag generation, e.g. a team's preferred vocabulary")
+	tagLanguage := flag.String("tag-language", "", "Language to write generated tags in (default: follow each note's language)")
+	summaryMinWords := flag.Int("summary-min-words", 100, "Only summarize notes with at least this many words")
+	userID := flag.String("user", "", "Only run OCR and transcription on this user's notes (default: all users)")
 	flag.Parse()
 
+	tasks, err := parseTasks(*tasksFlag)
+	if err != nil {
+		log.Error("invalid -tasks flag", "error", err)
+		os.Exit(1)
+	}
+
+	tagOpts := ai.TagGenOptions{
+		MaxTags:       *maxTags,
+		Temperature:   float32(*temperature),
+		ExtraGuidance: *tagGuidance,
+		Language:      *tagLanguage,
+	}
+	if *maxTags < 1 {
+		log.Error("invalid -max-tags flag", "error", fmt.Errorf("must be between 1 and %d, got %d", ai.MaxTagsLimit, *maxTags))
+		os.Exit(1)
+	}
+	if err := tagOpts.Validate(); err != nil {
+		log.Error("invalid tag generation flags", "error", err)
+		os.Exit(1)
+	}
+
 	geminiKey := os.Getenv("GEMINI_API_KEY")
 	if geminiKey == "" {
 		log.Error("GEMINI_API_KEY environment variable not set")
@@ -63,8 +97,12 @@ func main() {
 		intervalStr = interval.String()
 	}
 
-	log.Info("starting AI processing job (tag generation, OCR, audio transcription)",
+	log.Info("starting AI processing job (tag generation, OCR, audio transcription, summaries, moods)",
 		"dry_run", *dryRun,
+		"tasks", tasks.names(),
+		"max_attempts", *maxAttempts,
+		"max_tags", tagOpts.MaxTags,
+		"user", *userID,
 		"continuous", *interval > 0,
 		"interval", intervalStr)
 
@@ -81,17 +119,26 @@ func main() {
 	}()
 	log.Info("database connected")
 
-	// Handle graceful shutdown
+	// Handle graceful shutdown. A signal first closes stopCh so no new items
+	// are started; the in-flight item keeps running on processCtx until it
+	// finishes or the grace period expires.
 	processCtx, cancel := context.WithCancel(context.Background())
 	defer cancel()
+	stopCh := make(chan struct{})
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
 	go func() {
 		sig := <-sigCh
-		log.Info("received shutdown signal, stopping", "signal", sig.String())
-		cancel()
+		log.Info("received shutdown signal, draining in-flight work", "signal", sig.String(), "grace", shutdownGrace.String())
+		close(stopCh)
+		select {
+		case <-time.After(*shutdownGrace):
+			log.Warn("shutdown grace period expired, cancelling in-flight work")
+			cancel()
+		case <-processCtx.Done():
+		}
 	}()
 
 	// Create rate limiter: 1 API call per second shared across all tasks
@@ -103,25 +150,25 @@ func main() {
 		defer ticker.Stop()
 
 		// Run immediately on start
-		processOnce(processCtx, log, database, aiClient, storageClient, *dryRun, rateLimiter)
+		processOnce(processCtx, stopCh, log, database, aiClient, storageClient, tasks, tagOpts, *userID, *summaryMinWords, *maxAttempts, *dryRun, rateLimiter)
 
 		for {
 			select {
-			case <-processCtx.Done():
+			case <-stopCh:
 				log.Info("shutting down AI processing job")
 				return
 			case <-ticker.C:
-				processOnce(processCtx, log, database, aiClient, storageClient, *dryRun, rateLimiter)
+				processOnce(processCtx, stopCh, log, database, aiClient, storageClient, tasks, tagOpts, *userID, *summaryMinWords, *maxAttempts, *dryRun, rateLimiter)
 			}
 		}
 	} else {
 		// Run once and exit
-		processOnce(processCtx, log, database, aiClient, storageClient, *dryRun, rateLimiter)
+		processOnce(processCtx, stopCh, log, database, aiClient, storageClient, tasks, tagOpts, *userID, *summaryMinWords, *maxAttempts, *dryRun, rateLimiter)
 	}
 }
 
-func processOnce(ctx context.Context, log *slog.Logger, database *db.DB, aiClient *ai.Client, storageClient *storage.Client, dryRun bool, rateLimiter *rate.Limiter) {
-	result, err := processAllTasks(ctx, log, database, aiClient, storageClient, dryRun, rateLimiter)
+func processOnce(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, aiClient *ai.Client, storageClient *storage.Client, tasks taskSet, tagOpts ai.TagGenOptions, userID string, summaryMinWords, maxAttempts int, dryRun bool, rateLimiter *rate.Limiter) {
+	result, err := processAllTasks(ctx, stop, log, database, aiClient, storageClient, tasks, tagOpts, userID, summaryMinWords, maxAttempts, dryRun, rateLimiter)
 	if err != nil {
 		log.Error("AI processing failed", "error", err)
 		return
@@ -129,72 +176,123 @@ func processOnce(ctx context.Context, log *slog.Logger, database *db.DB, aiClien
 
 	log.Info("AI processing completed",
 		"duration", result.Duration.String(),
+		"tasks", result.TasksRun,
 		"users_processed", result.UsersProcessed,
 		"notes_processed", result.NotesProcessed,
 		"tags_added", result.TagsAdded,
 		"images_processed", result.ImagesProcessed,
 		"audios_processed", result.AudiosProcessed,
+		"summaries_added", result.SummariesAdded,
+		"moods_added", result.MoodsAdded,
 		"errors", result.Errors)
 }
 
+// stopping reports whether the loop should stop starting new items, either
+// because shutdown was requested or because in-flight work was cancelled.
+func stopping(ctx context.Context, stop <-chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
+
 // ProcessResult holds the results of processing run
 type ProcessResult struct {
+	TasksRun        []string
 	UsersProcessed  int
 	NotesProcessed  int
 	TagsAdded       int
 	ImagesProcessed int
 	AudiosProcessed int
+	SummariesAdded  int
+	MoodsAdded      int
 	Errors          int
 	Duration        time.Duration
 }
 
-// processAllTasks runs all AI processing tasks in parallel: tag generation, OCR, and audio transcription
-func processAllTasks(ctx context.Context, log *slog.Logger, database *db.DB, aiClient *ai.Client, storageClient *storage.Client, dryRun bool, rateLimiter *rate.Limiter) (*ProcessResult, error) {
+// processAllTasks runs the selected AI processing tasks in parallel: tag generation, OCR, audio transcription, summaries, and moods
+func processAllTasks(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, aiClient *ai.Client, storageClient *storage.Client, tasks taskSet, tagOpts ai.TagGenOptions, userID string, summaryMinWords, maxAttempts int, dryRun bool, rateLimiter *rate.Limiter) (*ProcessResult, error) {
 	start := time.Now()
-	result := &ProcessResult{}
+	result := &ProcessResult{TasksRun: tasks.names()}
 
 	var wg sync.WaitGroup
 	var mu sync.Mutex // Protect result updates
 
 	// Task 1: Generate tags for notes
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		tagResult, err := generateTagsForAllUsers(ctx, log, database, aiClient, dryRun, rateLimiter)
-		mu.Lock()
-		defer mu.Unlock()
-		if err != nil {
-			log.Error("tag generation failed", "error", err)
-			result.Errors++
-		} else {
-			result.UsersProcessed = tagResult.UsersProcessed
-			result.NotesProcessed = tagResult.NotesProcessed
-			result.TagsAdded = tagResult.TagsAdded
-			result.Errors += tagResult.Errors
-		}
-	}()
+	if tasks[taskTags] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tagResult, err := generateTagsForAllUsers(ctx, stop, log, database, aiClient, tagOpts, dryRun, rateLimiter)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Error("tag generation failed", "error", err)
+				result.Errors++
+			} else {
+				result.UsersProcessed = tagResult.UsersProcessed
+				result.NotesProcessed = tagResult.NotesProcessed
+				result.TagsAdded = tagResult.TagsAdded
+				result.Errors += tagResult.Errors
+			}
+		}()
+	}
 
 	// Task 2: Process images without extracted text
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		imagesProcessed, imageErrors := processImagesWithoutText(ctx, log, database, aiClient, storageClient, dryRun, rateLimiter)
-		mu.Lock()
-		defer mu.Unlock()
-		result.ImagesProcessed = imagesProcessed
-		result.Errors += imageErrors
-	}()
+	if tasks[taskOCR] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			imagesProcessed, imageErrors := processImagesWithoutText(ctx, stop, log, database, aiClient, storageClient, userID, maxAttempts, dryRun, rateLimiter)
+			mu.Lock()
+			defer mu.Unlock()
+			result.ImagesProcessed = imagesProcessed
+			result.Errors += imageErrors
+		}()
+	}
 
 	// Task 3: Process audio files without transcription
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		audiosProcessed, audioErrors := processAudiosWithoutTranscription(ctx, log, database, aiClient, storageClient, dryRun, rateLimiter)
-		mu.Lock()
-		defer mu.Unlock()
-		result.AudiosProcessed = audiosProcessed
-		result.Errors += audioErrors
-	}()
+	if tasks[taskTranscribe] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			audiosProcessed, audioErrors := processAudiosWithoutTranscription(ctx, stop, log, database, aiClient, storageClient, userID, maxAttempts, dryRun, rateLimiter)
+			mu.Lock()
+			defer mu.Unlock()
+			result.AudiosProcessed = audiosProcessed
+			result.Errors += audioErrors
+		}()
+	}
+
+	// Task 4: Summarize long notes without a summary
+	if tasks[taskSummaries] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			summariesAdded, summaryErrors := summarizeNotesWithoutSummary(ctx, stop, log, database, aiClient, summaryMinWords, dryRun, rateLimiter)
+			mu.Lock()
+			defer mu.Unlock()
+			result.SummariesAdded = summariesAdded
+			result.Errors += summaryErrors
+		}()
+	}
+
+	// Task 5: Analyze the mood of notes without one
+	if tasks[taskMoods] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			moodsAdded, moodErrors := analyzeNotesWithoutMood(ctx, stop, log, database, aiClient, dryRun, rateLimiter)
+			mu.Lock()
+			defer mu.Unlock()
+			result.MoodsAdded = moodsAdded
+			result.Errors += moodErrors
+		}()
+	}
 
 	// Wait for all tasks to complete
 	wg.Wait()
@@ -203,24 +301,33 @@ func processAllTasks(ctx context.Context, log *slog.Logger, database *db.DB, aiC
 	return result, nil
 }
 
-// processImagesWithoutText processes all images that don't have extracted text yet
-func processImagesWithoutText(ctx context.Context, log *slog.Logger, database *db.DB, aiClient *ai.Client, storageClient *storage.Client, dryRun bool, limiter *rate.Limiter) (int, int) {
-	images, err := database.GetImagesWithoutExtractedText(ctx)
+// processImagesWithoutText processes all images that don't have extracted text
+// yet, only in userID's notes if it is set
+func processImagesWithoutText(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, aiClient ai.Generator, storageClient *storage.Client, userID string, maxAttempts int, dryRun bool, limiter *rate.Limiter) (int, int) {
+	images, err := database.GetImagesWithoutExtractedText(ctx, userID)
 	if err != nil {
 		log.Error("failed to get images without extracted text", "error", err)
 		return 0, 1
 	}
 
-	log.Info("found images without extracted text", "count", len(images))
+	exhausted, err := database.GetExhaustedResourceIDs(ctx, models.ResourceTypeImage, maxAttempts)
+	if err != nil {
+		log.Error("failed to get failed images", "error", err)
+		return 0, 1
+	}
+
+	log.Info("found images without extracted text", "count", len(images), "skipped_failed", len(exhausted))
 
 	processed := 0
 	errors := 0
 
 	for _, image := range images {
-		select {
-		case <-ctx.Done():
+		if stopping(ctx, stop) {
 			return processed, errors
-		default:
+		}
+
+		if exhausted[image.ID] {
+			continue
 		}
 
 		log.Info("processing image for OCR", "image_id", image.ID, "note_id", image.NoteID)
@@ -237,14 +344,19 @@ func processImagesWithoutText(ctx context.Context, log *slog.Logger, database *d
 		imageData, err := storageClient.GetImage(ctx, image.GCSObjectName)
 		if err != nil {
 			log.Error("failed to download image", "image_id", image.ID, "error", err)
+			recordFailure(ctx, log, database, dryRun, models.ResourceTypeImage, image.ID, err)
 			errors++
 			continue
 		}
 
 		// Extract text from image
 		extractedText, err := aiClient.ExtractTextFromImage(ctx, imageData, image.MimeType)
+		if backOff(log, "ocr", err) {
+			return processed, errors
+		}
 		if err != nil {
 			log.Error("failed to extract text from image", "image_id", image.ID, "error", err)
+			recordFailure(ctx, log, database, dryRun, models.ResourceTypeImage, image.ID, err)
 			errors++
 			continue
 		}
@@ -258,6 +370,7 @@ func processImagesWithoutText(ctx context.Context, log *slog.Logger, database *d
 				errors++
 				continue
 			}
+			clearFailure(ctx, log, database, models.ResourceTypeImage, image.ID)
 		}
 
 		processed++
@@ -266,24 +379,33 @@ func processImagesWithoutText(ctx context.Context, log *slog.Logger, database *d
 	return processed, errors
 }
 
-// processAudiosWithoutTranscription processes all audio files that don't have transcribed text yet
-func processAudiosWithoutTranscription(ctx context.Context, log *slog.Logger, database *db.DB, aiClient *ai.Client, storageClient *storage.Client, dryRun bool, limiter *rate.Limiter) (int, int) {
-	audios, err := database.GetAudiosWithoutTranscription(ctx)
+// processAudiosWithoutTranscription processes all audio files that don't have
+// transcribed text yet, only in userID's notes if it is set
+func processAudiosWithoutTranscription(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, aiClient ai.Generator, storageClient *storage.Client, userID string, maxAttempts int, dryRun bool, limiter *rate.Limiter) (int, int) {
+	audios, err := database.GetAudiosWithoutTranscription(ctx, userID)
 	if err != nil {
 		log.Error("failed to get audios without transcription", "error", err)
 		return 0, 1
 	}
 
-	log.Info("found audios without transcription", "count", len(audios))
+	exhausted, err := database.GetExhaustedResourceIDs(ctx, models.ResourceTypeAudio, maxAttempts)
+	if err != nil {
+		log.Error("failed to get failed audios", "error", err)
+		return 0, 1
+	}
+
+	log.Info("found audios without transcription", "count", len(audios), "skipped_failed", len(exhausted))
 
 	processed := 0
 	errors := 0
 
 	for _, audio := range audios {
-		select {
-		case <-ctx.Done():
+		if stopping(ctx, stop) {
 			return processed, errors
-		default:
+		}
+
+		if exhausted[audio.ID] {
+			continue
 		}
 
 		log.Info("processing audio for transcription", "audio_id", audio.ID, "note_id", audio.NoteID)
@@ -300,14 +422,19 @@ func processAudiosWithoutTranscription(ctx context.Context, log *slog.Logger, da
 		audioData, err := storageClient.GetImage(ctx, audio.GCSObjectName)
 		if err != nil {
 			log.Error("failed to download audio", "audio_id", audio.ID, "error", err)
+			recordFailure(ctx, log, database, dryRun, models.ResourceTypeAudio, audio.ID, err)
 			errors++
 			continue
 		}
 
 		// Transcribe audio
 		transcribedText, err := aiClient.TranscribeAudio(ctx, audioData, audio.MimeType)
+		if backOff(log, "transcription", err) {
+			return processed, errors
+		}
 		if err != nil {
 			log.Error("failed to transcribe audio", "audio_id", audio.ID, "error", err)
+			recordFailure(ctx, log, database, dryRun, models.ResourceTypeAudio, audio.ID, err)
 			errors++
 			continue
 		}
@@ -321,6 +448,7 @@ func processAudiosWithoutTranscription(ctx context.Context, log *slog.Logger, da
 				errors++
 				continue
 			}
+			clearFailure(ctx, log, database, models.ResourceTypeAudio, audio.ID)
 		}
 
 		processed++
@@ -329,8 +457,157 @@ func processAudiosWithoutTranscription(ctx context.Context, log *slog.Logger, da
 	return processed, errors
 }
 
+// summarizeNotesWithoutSummary writes a summary for each note of at least
+// minWords words that doesn't have one yet
+func summarizeNotesWithoutSummary(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, aiClient *ai.Client, minWords int, dryRun bool, limiter *rate.Limiter) (int, int) {
+	notes, err := database.GetNotesWithoutSummary(ctx, minWords)
+	if err != nil {
+		log.Error("failed to get notes without summary", "error", err)
+		return 0, 1
+	}
+
+	log.Info("found notes without summary", "count", len(notes), "min_words", minWords)
+
+	added := 0
+	errors := 0
+
+	for _, note := range notes {
+		if stopping(ctx, stop) {
+			return added, errors
+		}
+
+		// Wait for rate limiter before making API call
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				log.Error("rate limiter error", "error", err)
+				return added, errors
+			}
+		}
+
+		summary, err := aiClient.SummarizeNote(ctx, note.Content)
+		if backOff(log, "summaries", err) {
+			return added, errors
+		}
+		if err != nil {
+			log.Error("failed to summarize note", "note_id", note.ID, "error", err)
+			errors++
+			continue
+		}
+		if summary == "" {
+			continue
+		}
+
+		log.Info("summarized note", "note_id", note.ID, "summary_length", len(summary), "dry_run", dryRun)
+
+		if !dryRun {
+			updated, err := database.UpdateNoteSummary(ctx, note.ID, note.ContentHash, summary)
+			if err != nil {
+				log.Error("failed to update note summary", "note_id", note.ID, "error", err)
+				errors++
+				continue
+			}
+			if !updated {
+				// Edited or deleted meanwhile; the next run picks it up again
+				log.Info("note changed while summarizing, skipping", "note_id", note.ID)
+				continue
+			}
+		}
+
+		added++
+	}
+
+	return added, errors
+}
+
+// analyzeNotesWithoutMood records a mood for each note that doesn't have one yet
+func analyzeNotesWithoutMood(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, aiClient *ai.Client, dryRun bool, limiter *rate.Limiter) (int, int) {
+	notes, err := database.GetNotesWithoutMood(ctx)
+	if err != nil {
+		log.Error("failed to get notes without mood", "error", err)
+		return 0, 1
+	}
+
+	log.Info("found notes without mood", "count", len(notes))
+
+	added := 0
+	errors := 0
+
+	for _, note := range notes {
+		if stopping(ctx, stop) {
+			return added, errors
+		}
+
+		// Wait for rate limiter before making API call
+		if limiter != nil {
+			if err := limiter.Wait(ctx); err != nil {
+				log.Error("rate limiter error", "error", err)
+				return added, errors
+			}
+		}
+
+		mood, err := aiClient.AnalyzeMood(ctx, note.Content)
+		if backOff(log, "moods", err) {
+			return added, errors
+		}
+		if err != nil {
+			log.Error("failed to analyze note mood", "note_id", note.ID, "error", err)
+			errors++
+			continue
+		}
+
+		log.Info("analyzed note mood", "note_id", note.ID, "mood", mood, "dry_run", dryRun)
+
+		if !dryRun {
+			updated, err := database.UpdateNoteMood(ctx, note.ID, note.ContentHash, mood)
+			if err != nil {
+				log.Error("failed to update note mood", "note_id", note.ID, "error", err)
+				errors++
+				continue
+			}
+			if !updated {
+				// Edited or deleted meanwhile; the next run picks it up again
+				log.Info("note changed while analyzing mood, skipping", "note_id", note.ID)
+				continue
+			}
+		}
+
+		added++
+	}
+
+	return added, errors
+}
+
+// recordFailure stores a processing failure so the resource is skipped once it
+// reaches the max attempt threshold. Failures are not recorded in dry-run mode.
+func recordFailure(ctx context.Context, log *slog.Logger, database *db.DB, dryRun bool, resourceType, resourceID string, cause error) {
+	if dryRun {
+		return
+	}
+	if err := database.RecordProcessingFailure(ctx, resourceType, resourceID, cause.Error()); err != nil {
+		log.Error("failed to record processing failure", "resource_type", resourceType, "resource_id", resourceID, "error", err)
+	}
+}
+
+// backOff reports whether err means Gemini is rate limiting us. The rest of
+// the batch would fail the same way, so callers stop until the next run
+// instead of recording a failure against the resource.
+func backOff(log *slog.Logger, task string, err error) bool {
+	if !errors.Is(err, ai.ErrRateLimited) {
+		return false
+	}
+	log.Warn("gemini rate limited, deferring remaining work to the next run", "task", task, "error", err)
+	return true
+}
+
+// clearFailure removes any failure record for a resource that was processed successfully
+func clearFailure(ctx context.Context, log *slog.Logger, database *db.DB, resourceType, resourceID string) {
+	if _, err := database.ResetProcessingFailure(ctx, resourceType, resourceID); err != nil {
+		log.Error("failed to clear processing failure", "resource_type", resourceType, "resource_id", resourceID, "error", err)
+	}
+}
+
 // generateTagsForAllUsers generates tags for all users in the database
-func generateTagsForAllUsers(ctx context.Context, log *slog.Logger, database *db.DB, aiClient *ai.Client, dryRun bool, limiter *rate.Limiter) (*TagGenResult, error) {
+func generateTagsForAllUsers(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, aiClient ai.Generator, tagOpts ai.TagGenOptions, dryRun bool, limiter *rate.Limiter) (*TagGenResult, error) {
 	start := time.Now()
 	result := &TagGenResult{}
 
@@ -343,14 +620,16 @@ func generateTagsForAllUsers(ctx context.Context, log *slog.Logger, database *db
 	log.Info("found users to process", "count", len(users))
 
 	for _, user := range users {
-		select {
-		case <-ctx.Done():
+		if stopping(ctx, stop) {
 			result.Duration = time.Since(start)
 			return result, ctx.Err()
-		default:
 		}
 
-		userResult, err := generateTagsForUser(ctx, log, database, user.ID, aiClient, dryRun, limiter)
+		userResult, err := generateTagsForUser(ctx, stop, log, database, user.ID, aiClient, tagOpts, dryRun, limiter)
+		if errors.Is(err, ai.ErrRateLimited) {
+			// Remaining users would be throttled too
+			break
+		}
 		if err != nil {
 			log.Error("failed to generate tags for user", "user_id", user.ID, "error", err)
 			result.Errors++
@@ -376,7 +655,7 @@ type TagGenResult struct {
 	Duration       time.Duration
 }
 
-func generateTagsForUser(ctx context.Context, log *slog.Logger, database *db.DB, userID string, aiClient *ai.Client, dryRun bool, limiter *rate.Limiter) (*TagGenResult, error) {
+func generateTagsForUser(ctx context.Context, stop <-chan struct{}, log *slog.Logger, database *db.DB, userID string, aiClient ai.Generator, tagOpts ai.TagGenOptions, dryRun bool, limiter *rate.Limiter) (*TagGenResult, error) {
 	result := &TagGenResult{}
 
 	// Fetch all existing tags for the user to prefer reusing them
@@ -391,8 +670,8 @@ func generateTagsForUser(ctx context.Context, log *slog.Logger, database *db.DB,
 	}
 	existingTagNames, existingTagList := tagging.BuildExistingTagContext(existingTagValues)
 
-	// Fetch notes with less than 3 tags
-	notes, err := database.GetNotesWithFewTags(ctx, userID, 3)
+	// Fetch notes with fewer tags than the per-note target
+	notes, err := database.GetNotesWithFewTags(ctx, userID, tagOpts.MaxTags)
 	if err != nil {
 		return nil, err
 	}
@@ -403,11 +682,15 @@ func generateTagsForUser(ctx context.Context, log *slog.Logger, database *db.DB,
 		"existing_tags", len(existingTags))
 
 	for _, note := range notes {
+		if stopping(ctx, stop) {
+			return result, nil
+		}
+
 		result.NotesProcessed++
 
 		// Calculate how many tags we can add
 		currentTagCount := len(note.Tags)
-		maxNewTags := 3 - currentTagCount
+		maxNewTags := tagOpts.MaxTags - currentTagCount
 
 		if maxNewTags <= 0 {
 			continue
@@ -452,7 +735,10 @@ func generateTagsForUser(ctx context.Context, log *slog.Logger, database *db.DB,
 		}
 
 		// Generate tags using Gemini, passing existing tags
-		generatedTags, err := aiClient.GenerateTags(ctx, note.Content, existingTagList)
+		generatedTags, err := aiClient.GenerateTags(ctx, note.Content, existingTagList, tagOpts)
+		if backOff(log, "tags", err) {
+			return result, err
+		}
 		if err != nil {
 			log.Error("failed to generate tags for note", "note_id", note.ID, "error", err)
 			result.Errors++
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+
+	"github.com/icco/etu-backend/internal/ai"
+)
+
+func TestStopping(t *testing.T) {
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	closedStop := make(chan struct{})
+	close(closedStop)
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		stop chan struct{}
+		want bool
+	}{
+		{name: "running", ctx: context.Background(), stop: make(chan struct{}), want: false},
+		{name: "stop requested", ctx: context.Background(), stop: closedStop, want: true},
+		{name: "hard cancel", ctx: cancelledCtx, stop: make(chan struct{}), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := stopping(tt.ctx, tt.stop); got != tt.want {
+				t.Errorf("stopping() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackOff(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "success", err: nil, want: false},
+		{name: "rate limited", err: fmt.Errorf("failed to generate tags: %w", ai.ErrRateLimited), want: true},
+		{name: "invalid input", err: fmt.Errorf("failed to generate tags: %w", ai.ErrInvalidInput), want: false},
+		{name: "other", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := backOff(log, "tags", tt.err); got != tt.want {
+				t.Errorf("backOff(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Task names accepted by the -tasks flag.
+const (
+	taskTags       = "tags"
+	taskOCR        = "ocr"
+	taskTranscribe = "transcribe"
+	taskSummaries  = "summaries"
+	taskMoods      = "moods"
+)
+
+// allTasks lists every task in the order they are reported.
+var allTasks = []string{taskTags, taskOCR, taskTranscribe, taskSummaries, taskMoods}
+
+// defaultTasks are run when -tasks isn't given. Summaries and moods are opt-in.
+var defaultTasks = []string{taskTags, taskOCR, taskTranscribe}
+
+// taskSet records which tasks are enabled for a run.
+type taskSet map[string]bool
+
+// parseTasks parses a comma-separated list of task names.
+func parseTasks(value string) (taskSet, error) {
+	tasks := taskSet{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !slices.Contains(allTasks, name) {
+			return nil, fmt.Errorf("unknown task %q (valid tasks: %s)", name, strings.Join(allTasks, ", "))
+		}
+		tasks[name] = true
+	}
+
+	if len(tasks) == 0 {
+		return nil, fmt.Errorf("at least one task is required (valid tasks: %s)", strings.Join(allTasks, ", "))
+	}
+
+	return tasks, nil
+}
+
+// names returns the enabled task names in a stable order.
+func (t taskSet) names() []string {
+	names := make([]string, 0, len(t))
+	for _, name := range allTasks {
+		if t[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTasks(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "default", value: "tags,ocr,transcribe", want: []string{"tags", "ocr", "transcribe"}},
+		{name: "single task", value: "ocr", want: []string{"ocr"}},
+		{name: "summaries", value: "summaries,tags", want: []string{"tags", "summaries"}},
+		{name: "moods", value: "moods,summaries", want: []string{"summaries", "moods"}},
+		{name: "whitespace and case", value: " Transcribe , TAGS ", want: []string{"tags", "transcribe"}},
+		{name: "duplicates", value: "ocr,ocr", want: []string{"ocr"}},
+		{name: "unknown task", value: "tags,bogus", wantErr: true},
+		{name: "empty", value: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTasks(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseTasks(%q) expected error, got %v", tt.value, got.names())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTasks(%q): %v", tt.value, err)
+			}
+			if diff := cmp.Diff(tt.want, got.names()); diff != "" {
+				t.Errorf("parseTasks(%q) mismatch (-want +got):\n%s", tt.value, diff)
+			}
+		})
+	}
+}
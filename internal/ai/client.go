@@ -2,26 +2,83 @@ package ai
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
+	"time"
 
 	"google.golang.org/genai"
 )
 
+// geminiModel is the model used for every AI call
+const geminiModel = "gemini-2.0-flash"
+
+// Default per-call timeouts. Text-only prompts are quick; media calls upload
+// the file and scale with its length.
+const (
+	DefaultTextTimeout       = 30 * time.Second
+	DefaultImageTimeout      = 60 * time.Second
+	DefaultTranscribeTimeout = 5 * time.Minute
+)
+
+// contentGenerator is the part of the Gemini API the client uses
+type contentGenerator interface {
+	GenerateContent(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error)
+}
+
 // Client wraps the Gemini API client with shared configuration
 type Client struct {
 	apiKey string
+	// models replaces the Gemini API in tests
+	models contentGenerator
+
+	textTimeout       time.Duration // tags, summaries, moods
+	imageTimeout      time.Duration // OCR
+	transcribeTimeout time.Duration // audio transcription
 }
 
-// NewClient creates a new AI client with the provided API key
+// Generator is the set of Gemini calls services make on user content. It is
+// implemented by *Client; depend on it rather than *Client so tests can
+// substitute a fake.
+type Generator interface {
+	GenerateTags(ctx context.Context, text string, existingTags []string, opts TagGenOptions) ([]string, error)
+	ExtractTextFromImage(ctx context.Context, imageData []byte, mimeType string) (string, error)
+	TranscribeAudio(ctx context.Context, audioData []byte, mimeType string) (string, error)
+}
+
+var _ Generator = (*Client)(nil)
+
+// NewClient creates a new AI client with the provided API key. Per-call
+// timeouts are read from AI_TEXT_TIMEOUT, AI_IMAGE_TIMEOUT, and
+// AI_TRANSCRIBE_TIMEOUT.
 func NewClient(apiKey string) (*Client, error) {
 	if apiKey == "" {
 		return nil, fmt.Errorf("API key is required")
 	}
 	return &Client{
-		apiKey: apiKey,
+		apiKey:            apiKey,
+		textTimeout:       timeoutFromEnv("AI_TEXT_TIMEOUT", DefaultTextTimeout),
+		imageTimeout:      timeoutFromEnv("AI_IMAGE_TIMEOUT", DefaultImageTimeout),
+		transcribeTimeout: timeoutFromEnv("AI_TRANSCRIBE_TIMEOUT", DefaultTranscribeTimeout),
 	}, nil
 }
 
+// timeoutFromEnv reads a positive duration from envVar, falling back to the
+// default when unset or invalid
+func timeoutFromEnv(envVar string, defaultTimeout time.Duration) time.Duration {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultTimeout
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		slog.Default().Error("invalid AI timeout, using default", "env", envVar, "value", value, "default", defaultTimeout)
+		return defaultTimeout
+	}
+	return d
+}
+
 // newGenaiClient creates a new Gemini API client
 // Note: Creates a new client for each call. If performance becomes an issue,
 // consider caching the client in the Client struct. However, the genai library
@@ -35,3 +92,32 @@ func (c *Client) newGenaiClient(ctx context.Context) (*genai.Client, error) {
 	}
 	return client, nil
 }
+
+// generateContent sends one request to Gemini, giving up after timeout so a
+// stuck call cannot block its caller indefinitely. Failures and refusals are
+// wrapped with one of the categories in errors.go.
+func (c *Client) generateContent(ctx context.Context, timeout time.Duration, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+	models := c.models
+	if models == nil {
+		client, err := c.newGenaiClient(ctx)
+		if err != nil {
+			return nil, err
+		}
+		models = client.Models
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	resp, err := models.GenerateContent(callCtx, geminiModel, contents, config)
+	if err != nil {
+		if errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w after %s", ErrTimeout, timeout)
+		}
+		return nil, categorize(err)
+	}
+	if err := refusal(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
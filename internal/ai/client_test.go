@@ -0,0 +1,108 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// fakeGenerator answers every request with text after delay, or fails with
+// the context's error if the context ends first
+type fakeGenerator struct {
+	delay time.Duration
+	text  string
+}
+
+func (f *fakeGenerator) GenerateContent(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &genai.GenerateContentResponse{
+		Candidates: []*genai.Candidate{{Content: genai.NewContentFromText(f.text, genai.RoleModel)}},
+	}, nil
+}
+
+func newFakeClient(gen contentGenerator, timeout time.Duration) *Client {
+	return &Client{
+		models:            gen,
+		textTimeout:       timeout,
+		imageTimeout:      timeout,
+		transcribeTimeout: timeout,
+	}
+}
+
+func TestGenerateContent_Timeout(t *testing.T) {
+	c := newFakeClient(&fakeGenerator{delay: time.Minute}, 20*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	_, err := c.GenerateTags(ctx, "a note about hiking", nil, DefaultTagGenOptions())
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("GenerateTags error = %v, want ErrTimeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("GenerateTags took %v, want it cut off at the timeout", elapsed)
+	}
+
+	_, err = c.TranscribeAudio(ctx, []byte("audio"), "audio/mpeg")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("TranscribeAudio error = %v, want ErrTimeout", err)
+	}
+	_, err = c.ExtractTextFromImage(ctx, []byte("image"), "image/png")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("ExtractTextFromImage error = %v, want ErrTimeout", err)
+	}
+}
+
+func TestGenerateContent_WithinTimeout(t *testing.T) {
+	c := newFakeClient(&fakeGenerator{text: `["hiking", "outdoors"]`}, time.Second)
+
+	tags, err := c.GenerateTags(context.Background(), "a note about hiking", nil, DefaultTagGenOptions())
+	if err != nil {
+		t.Fatalf("GenerateTags: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != "hiking" {
+		t.Errorf("GenerateTags = %v, want [hiking outdoors]", tags)
+	}
+}
+
+func TestGenerateContent_CallerCancel(t *testing.T) {
+	c := newFakeClient(&fakeGenerator{delay: time.Minute}, time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.SummarizeNote(ctx, "some note content")
+	if errors.Is(err, ErrTimeout) {
+		t.Errorf("SummarizeNote error = %v, cancellation is not a timeout", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("SummarizeNote error = %v, want context.Canceled", err)
+	}
+}
+
+func TestTimeoutFromEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{value: "", want: DefaultTextTimeout},
+		{value: "2m", want: 2 * time.Minute},
+		{value: "0s", want: DefaultTextTimeout},
+		{value: "slow", want: DefaultTextTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("AI_TEXT_TIMEOUT", tt.value)
+			if got := timeoutFromEnv("AI_TEXT_TIMEOUT", DefaultTextTimeout); got != tt.want {
+				t.Errorf("timeoutFromEnv(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/genai"
+)
+
+// Failure categories. Every error returned by an AI method wraps one of
+// these, so callers can tell with errors.Is whether to back off, skip the
+// input, or retry later.
+var (
+	// ErrRateLimited means Gemini rejected the call for quota or rate
+	// reasons; retrying immediately will fail again
+	ErrRateLimited = errors.New("gemini rate limit exceeded")
+	// ErrInvalidInput means the input was rejected, either locally or by
+	// Gemini, or the model refused to answer; retrying will not help
+	ErrInvalidInput = errors.New("invalid input for gemini")
+	// ErrTimeout means a Gemini call exceeded its timeout
+	ErrTimeout = errors.New("gemini request timed out")
+	// ErrInternal covers everything else: server errors, auth problems, and
+	// unusable responses
+	ErrInternal = errors.New("gemini request failed")
+)
+
+// categorize wraps an error from the Gemini API with its failure category.
+// Cancellation by the caller is returned unchanged since it is not a failure
+// of the call itself.
+func categorize(err error) error {
+	if errors.Is(err, context.Canceled) {
+		return err
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	}
+
+	var apiErr genai.APIError
+	if !errors.As(err, &apiErr) {
+		return fmt.Errorf("%w: %w", ErrInternal, err)
+	}
+	switch apiErr.Code {
+	case http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return fmt.Errorf("%w: %w", ErrTimeout, err)
+	case http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity:
+		return fmt.Errorf("%w: %w", ErrInvalidInput, err)
+	default:
+		return fmt.Errorf("%w: %w", ErrInternal, err)
+	}
+}
+
+// refusal reports a response in which Gemini blocked the prompt or stopped
+// for a content policy reason, which is not worth retrying
+func refusal(resp *genai.GenerateContentResponse) error {
+	if resp == nil {
+		return nil
+	}
+	if fb := resp.PromptFeedback; fb != nil && fb.BlockReason != "" && fb.BlockReason != genai.BlockedReasonUnspecified {
+		return fmt.Errorf("%w: prompt blocked (%s)", ErrInvalidInput, fb.BlockReason)
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0] == nil {
+		return nil
+	}
+	switch reason := resp.Candidates[0].FinishReason; reason {
+	case genai.FinishReasonSafety, genai.FinishReasonRecitation, genai.FinishReasonBlocklist,
+		genai.FinishReasonProhibitedContent, genai.FinishReasonSPII:
+		return fmt.Errorf("%w: model refused (%s)", ErrInvalidInput, reason)
+	}
+	return nil
+}
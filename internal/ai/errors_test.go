@@ -0,0 +1,112 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genai"
+)
+
+// stubGenerator returns a fixed response and error for every request
+type stubGenerator struct {
+	resp *genai.GenerateContentResponse
+	err  error
+}
+
+func (s *stubGenerator) GenerateContent(ctx context.Context, model string, contents []*genai.Content, config *genai.GenerateContentConfig) (*genai.GenerateContentResponse, error) {
+	return s.resp, s.err
+}
+
+func TestAIErrorCategories(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  *stubGenerator
+		want error
+	}{
+		{
+			name: "rate limited",
+			gen:  &stubGenerator{err: genai.APIError{Code: 429, Status: "RESOURCE_EXHAUSTED"}},
+			want: ErrRateLimited,
+		},
+		{
+			name: "bad request",
+			gen:  &stubGenerator{err: genai.APIError{Code: 400, Status: "INVALID_ARGUMENT"}},
+			want: ErrInvalidInput,
+		},
+		{
+			name: "server error",
+			gen:  &stubGenerator{err: genai.APIError{Code: 503, Status: "UNAVAILABLE"}},
+			want: ErrInternal,
+		},
+		{
+			name: "gateway timeout",
+			gen:  &stubGenerator{err: genai.APIError{Code: 504, Status: "DEADLINE_EXCEEDED"}},
+			want: ErrTimeout,
+		},
+		{
+			name: "network error",
+			gen:  &stubGenerator{err: errors.New("connection reset by peer")},
+			want: ErrInternal,
+		},
+		{
+			name: "prompt blocked",
+			gen: &stubGenerator{resp: &genai.GenerateContentResponse{
+				PromptFeedback: &genai.GenerateContentResponsePromptFeedback{BlockReason: genai.BlockedReasonSafety},
+			}},
+			want: ErrInvalidInput,
+		},
+		{
+			name: "model refused",
+			gen: &stubGenerator{resp: &genai.GenerateContentResponse{
+				Candidates: []*genai.Candidate{{FinishReason: genai.FinishReasonSafety}},
+			}},
+			want: ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newFakeClient(tt.gen, time.Second)
+			ctx := context.Background()
+
+			_, err := c.GenerateTags(ctx, "a note about hiking", nil, DefaultTagGenOptions())
+			if !errors.Is(err, tt.want) {
+				t.Errorf("GenerateTags error = %v, want %v", err, tt.want)
+			}
+			_, err = c.ExtractTextFromImage(ctx, []byte("image"), "image/png")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("ExtractTextFromImage error = %v, want %v", err, tt.want)
+			}
+			_, err = c.TranscribeAudio(ctx, []byte("audio"), "audio/mpeg")
+			if !errors.Is(err, tt.want) {
+				t.Errorf("TranscribeAudio error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAIErrorCategories_LocalValidation(t *testing.T) {
+	c := newFakeClient(&stubGenerator{}, time.Second)
+	ctx := context.Background()
+
+	if _, err := c.ExtractTextFromImage(ctx, []byte("image"), "image/tiff"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("ExtractTextFromImage error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := c.TranscribeAudio(ctx, nil, "audio/mpeg"); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("TranscribeAudio error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := c.GenerateTags(ctx, "text", nil, TagGenOptions{MaxTags: MaxTagsLimit + 1}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("GenerateTags error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestAIErrorCategories_EmptyTagResponse(t *testing.T) {
+	c := newFakeClient(&stubGenerator{resp: &genai.GenerateContentResponse{}}, time.Second)
+
+	_, err := c.GenerateTags(context.Background(), "a note about hiking", nil, DefaultTagGenOptions())
+	if !errors.Is(err, ErrInternal) {
+		t.Errorf("GenerateTags error = %v, want ErrInternal", err)
+	}
+}
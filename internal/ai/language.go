@@ -0,0 +1,65 @@
+package ai
+
+import (
+	"unicode"
+)
+
+// tagScripts are the non-Latin scripts detectScript recognizes, in the order
+// ties are broken. Names are what the prompt shows the model.
+var tagScripts = []struct {
+	name  string
+	table *unicode.RangeTable
+}{
+	{"Cyrillic", unicode.Cyrillic},
+	{"Greek", unicode.Greek},
+	{"Arabic", unicode.Arabic},
+	{"Hebrew", unicode.Hebrew},
+	{"Devanagari", unicode.Devanagari},
+	{"Bengali", unicode.Bengali},
+	{"Tamil", unicode.Tamil},
+	{"Thai", unicode.Thai},
+	{"Georgian", unicode.Georgian},
+	{"Armenian", unicode.Armenian},
+	{"Hangul", unicode.Hangul},
+	{"Hiragana", unicode.Hiragana},
+	{"Katakana", unicode.Katakana},
+	{"Han", unicode.Han},
+}
+
+// detectScript returns the name of the script most of text's letters are
+// written in, or "" when most are Latin or there are none. Japanese text mixes
+// kana with Han characters, so any kana makes the result Japanese.
+func detectScript(text string) string {
+	counts := make([]int, len(tagScripts))
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for i, s := range tagScripts {
+			if unicode.Is(s.table, r) {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	best, bestCount, nonLatin, kana := "", 0, 0, 0
+	for i, s := range tagScripts {
+		nonLatin += counts[i]
+		if s.table == unicode.Hiragana || s.table == unicode.Katakana {
+			kana += counts[i]
+		}
+		if counts[i] > bestCount {
+			best, bestCount = s.name, counts[i]
+		}
+	}
+	if letters == 0 || nonLatin*2 <= letters {
+		return ""
+	}
+	if kana > 0 {
+		return "Japanese"
+	}
+	return best
+}
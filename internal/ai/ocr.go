@@ -13,17 +13,12 @@ import (
 // Returns the extracted text, or an empty string if no text is found.
 func (c *Client) ExtractTextFromImage(ctx context.Context, imageData []byte, mimeType string) (string, error) {
 	if len(imageData) == 0 {
-		return "", fmt.Errorf("image data is empty")
+		return "", fmt.Errorf("%w: image data is empty", ErrInvalidInput)
 	}
 
 	// Validate mime type
 	if !IsValidImageMimeType(mimeType) {
-		return "", fmt.Errorf("unsupported image MIME type: %s", mimeType)
-	}
-
-	client, err := c.newGenaiClient(ctx)
-	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: unsupported image MIME type: %s", ErrInvalidInput, mimeType)
 	}
 
 	// Create content with both text prompt and image
@@ -56,7 +51,7 @@ Return ONLY the extracted text, nothing else.`
 		},
 	}
 
-	resp, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", []*genai.Content{content}, &genai.GenerateContentConfig{
+	resp, err := c.generateContent(ctx, c.imageTimeout, []*genai.Content{content}, &genai.GenerateContentConfig{
 		Temperature: genai.Ptr(float32(0.1)), // Very low temperature for accurate extraction
 	})
 	if err != nil {
@@ -13,17 +13,12 @@ import (
 // Returns the transcribed text, or an empty string if transcription fails.
 func (c *Client) TranscribeAudio(ctx context.Context, audioData []byte, mimeType string) (string, error) {
 	if len(audioData) == 0 {
-		return "", fmt.Errorf("audio data is empty")
+		return "", fmt.Errorf("%w: audio data is empty", ErrInvalidInput)
 	}
 
 	// Validate mime type
 	if !IsValidAudioMimeType(mimeType) {
-		return "", fmt.Errorf("unsupported audio MIME type: %s", mimeType)
-	}
-
-	client, err := c.newGenaiClient(ctx)
-	if err != nil {
-		return "", err
+		return "", fmt.Errorf("%w: unsupported audio MIME type: %s", ErrInvalidInput, mimeType)
 	}
 
 	// Create content with both text prompt and audio
@@ -56,7 +51,7 @@ Return ONLY the transcribed text, nothing else.`
 		},
 	}
 
-	resp, err := client.Models.GenerateContent(ctx, "gemini-2.0-flash", []*genai.Content{content}, &genai.GenerateContentConfig{
+	resp, err := c.generateContent(ctx, c.transcribeTimeout, []*genai.Content{content}, &genai.GenerateContentConfig{
 		Temperature: genai.Ptr(float32(0.1)), // Very low temperature for accurate transcription
 	})
 	if err != nil {
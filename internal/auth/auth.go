@@ -7,10 +7,9 @@ import (
 	"log/slog"
 	"os"
 	"strings"
-	"time"
 
+	"github.com/icco/etu-backend/internal/crypto"
 	_ "github.com/lib/pq"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // ContextKey is the type used for context keys in authentication.
@@ -56,12 +55,16 @@ func SetAuthContext(ctx context.Context, userID, authType string) context.Contex
 
 // Authenticator handles API key authentication
 type Authenticator struct {
-	db  *sql.DB
-	log *slog.Logger
+	db     *sql.DB
+	log    *slog.Logger
+	cache  *keyCache
+	usage  *usageRecorder
+	hasher *crypto.APIKeyHasher
 }
 
-// New creates a new Authenticator
-func New() (*Authenticator, error) {
+// New creates a new Authenticator. Keys are checked with hasher, and bcrypt
+// hashes are replaced with HMACs when hasher has a secret.
+func New(hasher *crypto.APIKeyHasher) (*Authenticator, error) {
 	connStr := os.Getenv("DATABASE_URL")
 	if connStr == "" {
 		return nil, fmt.Errorf("DATABASE_URL environment variable not set")
@@ -76,23 +79,36 @@ func New() (*Authenticator, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
+	log := slog.Default()
 	return &Authenticator{
-		db:  conn,
-		log: slog.Default(),
+		db:     conn,
+		log:    log,
+		cache:  newKeyCacheFromEnv(log),
+		usage:  newUsageRecorder(conn, log, usageFlushInterval),
+		hasher: hasher,
 	}, nil
 }
 
-// Close closes the database connection
+// Close writes buffered API key usage and closes the database connection
 func (a *Authenticator) Close() error {
+	a.usage.close()
 	return a.db.Close()
 }
 
 // VerifyAPIKey verifies an API key and returns the associated user ID
 // API keys have the format: etu_<64 hex characters>
-func (a *Authenticator) VerifyAPIKey(ctx context.Context, apiKey string) (string, error) {
+// checked is false when the key was recently verified and served from the
+// cache, so callers can audit actual key checks rather than every request.
+func (a *Authenticator) VerifyAPIKey(ctx context.Context, apiKey string) (userID string, checked bool, err error) {
 	// Validate key format
 	if !strings.HasPrefix(apiKey, "etu_") {
-		return "", fmt.Errorf("invalid API key format")
+		return "", false, fmt.Errorf("invalid API key format")
+	}
+
+	// Recently verified keys skip the database and hash check
+	if userID, keyID, ok := a.cache.get(apiKey); ok {
+		a.usage.record(keyID)
+		return userID, false, nil
 	}
 
 	// Extract prefix for lookup (first 12 chars of the key)
@@ -105,7 +121,7 @@ func (a *Authenticator) VerifyAPIKey(ctx context.Context, apiKey string) (string
 		WHERE "keyPrefix" = $1
 	`, keyPrefix)
 	if err != nil {
-		return "", fmt.Errorf("failed to query API keys: %w", err)
+		return "", false, fmt.Errorf("failed to query API keys: %w", err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -117,30 +133,49 @@ func (a *Authenticator) VerifyAPIKey(ctx context.Context, apiKey string) (string
 	for rows.Next() {
 		var id, keyHash, userID string
 		if err := rows.Scan(&id, &keyHash, &userID); err != nil {
-			return "", fmt.Errorf("failed to scan API key: %w", err)
+			return "", false, fmt.Errorf("failed to scan API key: %w", err)
 		}
 
 		// Compare the full key against the hash
-		if err := bcrypt.CompareHashAndPassword([]byte(keyHash), []byte(apiKey)); err == nil {
-			// Update last used timestamp
-			go a.updateLastUsed(id)
-			return userID, nil
+		if match, rehash := a.hasher.Verify(keyHash, apiKey); match {
+			if rehash {
+				a.rehash(ctx, id, keyHash, apiKey)
+			}
+			a.cache.put(apiKey, userID, id)
+			a.usage.record(id)
+			return userID, true, nil
 		}
 	}
 
 	if err := rows.Err(); err != nil {
-		return "", fmt.Errorf("error iterating API keys: %w", err)
+		return "", false, fmt.Errorf("error iterating API keys: %w", err)
 	}
 
-	return "", fmt.Errorf("invalid API key")
+	return "", false, fmt.Errorf("invalid API key")
+}
+
+// rehash replaces a key's bcrypt hash with an HMAC so later checks are
+// cheap. Failures are logged; the key keeps working and is retried next use.
+func (a *Authenticator) rehash(ctx context.Context, keyID, oldHash, apiKey string) {
+	newHash, err := a.hasher.Hash(apiKey)
+	if err == nil {
+		_, err = a.db.ExecContext(ctx, `UPDATE "ApiKey" SET "keyHash" = $1 WHERE id = $2 AND "keyHash" = $3`, newHash, keyID, oldHash)
+	}
+	if err != nil {
+		a.log.Warn("failed to rehash API key", "key_id", keyID, "error", err)
+	}
 }
 
-// updateLastUsed updates the lastUsed timestamp for an API key
-func (a *Authenticator) updateLastUsed(keyID string) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+// RecordAPIKeyUse counts a use of the API key with the given record ID that
+// was verified somewhere other than VerifyAPIKey, such as the VerifyApiKey
+// RPC. It is written out with the next batch of usage.
+func (a *Authenticator) RecordAPIKeyUse(keyID string) {
+	a.usage.record(keyID)
+}
 
-	_, _ = a.db.ExecContext(ctx, `
-		UPDATE "ApiKey" SET "lastUsed" = $1 WHERE id = $2
-	`, time.Now(), keyID)
+// InvalidateAPIKey stops trusting a cached verification of the API key with
+// the given record ID. Call it when a key is deleted so it stops working on
+// this instance immediately rather than when its cache entry expires.
+func (a *Authenticator) InvalidateAPIKey(keyID string) {
+	a.cache.invalidate(keyID)
 }
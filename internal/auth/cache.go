@@ -0,0 +1,148 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultKeyCacheTTL is how long a verified API key is trusted without
+	// going back to the database. It bounds how long a key deleted on another
+	// server instance keeps working there.
+	DefaultKeyCacheTTL = time.Minute
+	// DefaultKeyCacheSize is the most verified keys kept in memory
+	DefaultKeyCacheSize = 10000
+)
+
+// keyCache remembers recently verified API keys so the hot path can skip the
+// database lookup and bcrypt compare. Entries are keyed by the SHA-256 of the
+// raw key, so the raw key is never held in memory after the call.
+type keyCache struct {
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]keyCacheEntry
+	ttl     time.Duration
+	size    int
+	now     func() time.Time
+}
+
+type keyCacheEntry struct {
+	userID  string
+	keyID   string
+	expires time.Time
+}
+
+// newKeyCache returns a cache holding up to size keys for ttl each, or nil
+// (caching disabled) when either is not positive
+func newKeyCache(ttl time.Duration, size int) *keyCache {
+	if ttl <= 0 || size <= 0 {
+		return nil
+	}
+	return &keyCache{
+		entries: make(map[[sha256.Size]byte]keyCacheEntry),
+		ttl:     ttl,
+		size:    size,
+		now:     time.Now,
+	}
+}
+
+// newKeyCacheFromEnv reads API_KEY_CACHE_TTL (a duration, "0" disables the
+// cache) and API_KEY_CACHE_SIZE, falling back to the defaults when unset or
+// invalid
+func newKeyCacheFromEnv(log *slog.Logger) *keyCache {
+	ttl := DefaultKeyCacheTTL
+	if value := os.Getenv("API_KEY_CACHE_TTL"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			log.Error("invalid API key cache TTL, using default", "env", "API_KEY_CACHE_TTL", "value", value, "default", DefaultKeyCacheTTL)
+		} else {
+			ttl = d
+		}
+	}
+
+	size := DefaultKeyCacheSize
+	if value := os.Getenv("API_KEY_CACHE_SIZE"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n <= 0 {
+			log.Error("invalid API key cache size, using default", "env", "API_KEY_CACHE_SIZE", "value", value, "default", DefaultKeyCacheSize)
+		} else {
+			size = n
+		}
+	}
+
+	return newKeyCache(ttl, size)
+}
+
+// get returns the user and key ID for a cached, unexpired raw key
+func (c *keyCache) get(apiKey string) (userID, keyID string, ok bool) {
+	if c == nil {
+		return "", "", false
+	}
+	hash := sha256.Sum256([]byte(apiKey))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[hash]
+	if !ok {
+		return "", "", false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, hash)
+		return "", "", false
+	}
+	return entry.userID, entry.keyID, true
+}
+
+// put caches a raw key that bcrypt has just verified
+func (c *keyCache) put(apiKey, userID, keyID string) {
+	if c == nil {
+		return
+	}
+	hash := sha256.Sum256([]byte(apiKey))
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[hash]; !exists && len(c.entries) >= c.size {
+		c.evict(now)
+	}
+	c.entries[hash] = keyCacheEntry{userID: userID, keyID: keyID, expires: now.Add(c.ttl)}
+}
+
+// evict makes room for one entry, dropping expired entries first and an
+// arbitrary live one if none have expired. Callers must hold c.mu.
+func (c *keyCache) evict(now time.Time) {
+	for hash, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, hash)
+		}
+	}
+	if len(c.entries) < c.size {
+		return
+	}
+	for hash := range c.entries {
+		delete(c.entries, hash)
+		return
+	}
+}
+
+// invalidate drops every cached entry for an API key record
+func (c *keyCache) invalidate(keyID string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for hash, entry := range c.entries {
+		if entry.keyID == keyID {
+			delete(c.entries, hash)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package auth
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/crypto"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testAPIKey = "etu_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestKeyCache_TTLExpiry(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newKeyCache(time.Minute, 10)
+	cache.now = func() time.Time { return now }
+
+	cache.put(testAPIKey, "user1", "key1")
+	if userID, _, ok := cache.get(testAPIKey); !ok || userID != "user1" {
+		t.Fatalf("get() = %q, %v, want user1, true", userID, ok)
+	}
+
+	now = now.Add(59 * time.Second)
+	if _, _, ok := cache.get(testAPIKey); !ok {
+		t.Error("entry expired before its TTL")
+	}
+
+	now = now.Add(time.Second)
+	if _, _, ok := cache.get(testAPIKey); ok {
+		t.Error("entry still cached after its TTL")
+	}
+	if len(cache.entries) != 0 {
+		t.Errorf("expired entry not removed, %d entries left", len(cache.entries))
+	}
+}
+
+func TestKeyCache_Size(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newKeyCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.put("etu_a", "user1", "key1")
+	now = now.Add(2 * time.Minute)
+	cache.put("etu_b", "user2", "key2")
+	cache.put("etu_c", "user3", "key3")
+
+	// The expired entry is evicted first
+	if _, _, ok := cache.get("etu_a"); ok {
+		t.Error("expired entry survived eviction")
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("cache holds %d entries, want 2", len(cache.entries))
+	}
+
+	cache.put("etu_d", "user4", "key4")
+	if len(cache.entries) != 2 {
+		t.Errorf("cache holds %d entries after a full put, want 2", len(cache.entries))
+	}
+	if _, _, ok := cache.get("etu_d"); !ok {
+		t.Error("newest entry missing")
+	}
+}
+
+func TestKeyCache_Invalidate(t *testing.T) {
+	cache := newKeyCache(time.Minute, 10)
+	cache.put(testAPIKey, "user1", "key1")
+	cache.put("etu_other", "user1", "key2")
+
+	cache.invalidate("key1")
+
+	if _, _, ok := cache.get(testAPIKey); ok {
+		t.Error("invalidated key still cached")
+	}
+	if _, _, ok := cache.get("etu_other"); !ok {
+		t.Error("unrelated key was invalidated")
+	}
+}
+
+func TestNewKeyCacheFromEnv(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	t.Setenv("API_KEY_CACHE_TTL", "0")
+	if cache := newKeyCacheFromEnv(log); cache != nil {
+		t.Error("API_KEY_CACHE_TTL=0 should disable the cache")
+	}
+
+	t.Setenv("API_KEY_CACHE_TTL", "30s")
+	t.Setenv("API_KEY_CACHE_SIZE", "5")
+	cache := newKeyCacheFromEnv(log)
+	if cache == nil || cache.ttl != 30*time.Second || cache.size != 5 {
+		t.Errorf("newKeyCacheFromEnv() = %+v, want ttl 30s, size 5", cache)
+	}
+
+	t.Setenv("API_KEY_CACHE_TTL", "soon")
+	t.Setenv("API_KEY_CACHE_SIZE", "-1")
+	cache = newKeyCacheFromEnv(log)
+	if cache == nil || cache.ttl != DefaultKeyCacheTTL || cache.size != DefaultKeyCacheSize {
+		t.Errorf("newKeyCacheFromEnv() = %+v, want defaults", cache)
+	}
+}
+
+// newTestAuthenticator returns an Authenticator backed by sqlmock whose
+// ApiKey table holds testAPIKey for user1. It records no usage.
+func newTestAuthenticator(tb testing.TB, cache *keyCache) (*Authenticator, sqlmock.Sqlmock, string) {
+	tb.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		tb.Fatalf("sqlmock.New: %v", err)
+	}
+	tb.Cleanup(func() { _ = sqlDB.Close() })
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(testAPIKey), bcrypt.DefaultCost)
+	if err != nil {
+		tb.Fatalf("GenerateFromPassword: %v", err)
+	}
+
+	return &Authenticator{db: sqlDB, log: slog.New(slog.DiscardHandler), cache: cache}, mock, string(hash)
+}
+
+func expectKeyLookup(mock sqlmock.Sqlmock, hash string) {
+	mock.ExpectQuery(`SELECT id, "keyHash", "userId"\s+FROM "ApiKey"`).
+		WithArgs(testAPIKey[:12]).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "keyHash", "userId"}).AddRow("key1", hash, "user1"))
+}
+
+func TestVerifyAPIKey_Cached(t *testing.T) {
+	a, mock, hash := newTestAuthenticator(t, newKeyCache(time.Minute, 10))
+	a.usage = &usageRecorder{counts: make(map[string]int64)}
+	ctx := context.Background()
+
+	// Only the first call and the call after invalidation hit the database
+	expectKeyLookup(mock, hash)
+	expectKeyLookup(mock, hash)
+
+	for i := 0; i < 3; i++ {
+		userID, checked, err := a.VerifyAPIKey(ctx, testAPIKey)
+		if err != nil || userID != "user1" {
+			t.Fatalf("VerifyAPIKey call %d = %q, %v, want user1", i+1, userID, err)
+		}
+		if want := i == 0; checked != want {
+			t.Errorf("VerifyAPIKey call %d checked = %v, want %v", i+1, checked, want)
+		}
+	}
+
+	a.InvalidateAPIKey("key1")
+	if _, checked, err := a.VerifyAPIKey(ctx, testAPIKey); err != nil || !checked {
+		t.Fatalf("VerifyAPIKey after invalidation = checked %v, %v, want a fresh check", checked, err)
+	}
+
+	// Cache hits still count as uses
+	if got := a.usage.counts["key1"]; got != 4 {
+		t.Errorf("recorded %d uses of key1, want 4", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestVerifyAPIKey_RehashesBcrypt(t *testing.T) {
+	a, mock, hash := newTestAuthenticator(t, nil)
+	a.usage = &usageRecorder{counts: make(map[string]int64)}
+	hasher, err := crypto.NewAPIKeyHasher([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewAPIKeyHasher: %v", err)
+	}
+	a.hasher = hasher
+	wantHash, err := hasher.Hash(testAPIKey)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	// The bcrypt hash is swapped for the HMAC, then the HMAC verifies
+	expectKeyLookup(mock, hash)
+	mock.ExpectExec(`UPDATE "ApiKey" SET "keyHash" = \$1 WHERE id = \$2 AND "keyHash" = \$3`).
+		WithArgs(wantHash, "key1", hash).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectKeyLookup(mock, wantHash)
+
+	for i := 0; i < 2; i++ {
+		userID, _, err := a.VerifyAPIKey(context.Background(), testAPIKey)
+		if err != nil || userID != "user1" {
+			t.Fatalf("VerifyAPIKey call %d = %q, %v, want user1", i+1, userID, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+// BenchmarkVerifyAPIKey compares the cost of verifying a key on every call
+// (a database query plus a bcrypt or HMAC compare) with serving it from the
+// cache.
+func BenchmarkVerifyAPIKey(b *testing.B) {
+	ctx := context.Background()
+
+	b.Run("uncached", func(b *testing.B) {
+		a, mock, hash := newTestAuthenticator(b, nil)
+		for i := 0; i < b.N; i++ {
+			expectKeyLookup(mock, hash)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := a.VerifyAPIKey(ctx, testAPIKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached hmac", func(b *testing.B) {
+		a, mock, _ := newTestAuthenticator(b, nil)
+		hasher, err := crypto.NewAPIKeyHasher([]byte("0123456789abcdef0123456789abcdef"))
+		if err != nil {
+			b.Fatal(err)
+		}
+		a.hasher = hasher
+		hash, err := hasher.Hash(testAPIKey)
+		if err != nil {
+			b.Fatal(err)
+		}
+		for i := 0; i < b.N; i++ {
+			expectKeyLookup(mock, hash)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := a.VerifyAPIKey(ctx, testAPIKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		a, mock, hash := newTestAuthenticator(b, newKeyCache(time.Hour, 10))
+		expectKeyLookup(mock, hash)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, _, err := a.VerifyAPIKey(ctx, testAPIKey); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
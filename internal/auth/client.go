@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ClientInfo returns the caller's IP address and user agent for a gRPC
+// request. Requests proxied by the HTTP gateway carry the original client in
+// the x-forwarded-for and grpcgateway-user-agent metadata, so those take
+// precedence over the connection peer. Either value is empty when unknown.
+func ClientInfo(ctx context.Context) (ip, userAgent string) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+		ip = strings.TrimSpace(strings.Split(forwarded[0], ",")[0])
+	}
+	if ip == "" {
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			ip = p.Addr.String()
+			if host, _, err := net.SplitHostPort(ip); err == nil {
+				ip = host
+			}
+		}
+	}
+
+	if agents := md.Get("grpcgateway-user-agent"); len(agents) > 0 {
+		userAgent = agents[0]
+	} else if agents := md.Get("user-agent"); len(agents) > 0 {
+		userAgent = agents[0]
+	}
+
+	return ip, userAgent
+}
+
+// RemoteIP returns the address of the connection that sent a gRPC request.
+// For requests relayed by the in-process HTTP gateway, which arrive over
+// loopback, it returns the HTTP client address the gateway appended to
+// x-forwarded-for. Unlike ClientInfo it ignores forwarded addresses a client
+// could set itself, so it is safe to key rate limits on.
+func RemoteIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	ip := p.Addr.String()
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.IsLoopback() {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if forwarded := md.Get("x-forwarded-for"); len(forwarded) > 0 {
+			hops := strings.Split(forwarded[len(forwarded)-1], ",")
+			if last := strings.TrimSpace(hops[len(hops)-1]); last != "" {
+				return last
+			}
+		}
+	}
+
+	return ip
+}
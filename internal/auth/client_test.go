@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestClientInfo(t *testing.T) {
+	peerCtx := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.5"), Port: 51234},
+	})
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		wantIP string
+		wantUA string
+	}{
+		{
+			name:   "peer only",
+			ctx:    metadata.NewIncomingContext(peerCtx, metadata.Pairs("user-agent", "grpc-go/1.0")),
+			wantIP: "10.0.0.5",
+			wantUA: "grpc-go/1.0",
+		},
+		{
+			name: "via gateway",
+			ctx: metadata.NewIncomingContext(peerCtx, metadata.Pairs(
+				"x-forwarded-for", "203.0.113.7, 10.0.0.1",
+				"grpcgateway-user-agent", "Mozilla/5.0",
+				"user-agent", "grpc-go/1.0",
+			)),
+			wantIP: "203.0.113.7",
+			wantUA: "Mozilla/5.0",
+		},
+		{
+			name: "nothing known",
+			ctx:  context.Background(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ip, ua := ClientInfo(tt.ctx)
+			if ip != tt.wantIP || ua != tt.wantUA {
+				t.Errorf("ClientInfo() = (%q, %q), want (%q, %q)", ip, ua, tt.wantIP, tt.wantUA)
+			}
+		})
+	}
+}
+
+func TestRemoteIP(t *testing.T) {
+	remote := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("198.51.100.2"), Port: 51234},
+	})
+	loopback := peer.NewContext(context.Background(), &peer.Peer{
+		Addr: &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 51234},
+	})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{name: "direct", ctx: remote, want: "198.51.100.2"},
+		{
+			name: "direct ignores forged header",
+			ctx:  metadata.NewIncomingContext(remote, metadata.Pairs("x-forwarded-for", "203.0.113.7")),
+			want: "198.51.100.2",
+		},
+		{
+			name: "via gateway uses the hop it appended",
+			ctx:  metadata.NewIncomingContext(loopback, metadata.Pairs("x-forwarded-for", "203.0.113.7, 192.0.2.9")),
+			want: "192.0.2.9",
+		},
+		{name: "loopback without gateway", ctx: loopback, want: "127.0.0.1"},
+		{name: "no peer", ctx: context.Background(), want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemoteIP(tt.ctx); got != tt.want {
+				t.Errorf("RemoteIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// usageFlushInterval is how often buffered API key usage is written out
+const usageFlushInterval = 30 * time.Second
+
+// usageRecorder counts API key uses in memory and writes them in batches, so
+// authenticating a request does not cost a database write. Counts buffered
+// when the process dies are lost.
+type usageRecorder struct {
+	db  *sql.DB
+	log *slog.Logger
+
+	mu     sync.Mutex
+	counts map[string]int64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newUsageRecorder returns a recorder that flushes every interval until
+// close is called
+func newUsageRecorder(db *sql.DB, log *slog.Logger, interval time.Duration) *usageRecorder {
+	r := &usageRecorder{
+		db:     db,
+		log:    log,
+		counts: make(map[string]int64),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run(interval)
+	return r
+}
+
+// record counts one use of an API key
+func (r *usageRecorder) record(keyID string) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	r.counts[keyID]++
+	r.mu.Unlock()
+}
+
+func (r *usageRecorder) run(interval time.Duration) {
+	defer close(r.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.flush()
+		case <-r.stop:
+			r.flush()
+			return
+		}
+	}
+}
+
+// flush writes the buffered counts and lastUsed for every key used since the
+// previous flush. Counts that fail to write are dropped rather than retried,
+// since usage is informational.
+func (r *usageRecorder) flush() {
+	r.mu.Lock()
+	counts := r.counts
+	r.counts = make(map[string]int64)
+	r.mu.Unlock()
+
+	if len(counts) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	for keyID, uses := range counts {
+		_, err := r.db.ExecContext(ctx, `
+			UPDATE "ApiKey" SET "lastUsed" = $1, "usageCount" = "usageCount" + $2 WHERE id = $3
+		`, now, uses, keyID)
+		if err != nil {
+			r.log.Error("failed to record API key usage", "key_id", keyID, "uses", uses, "error", err)
+		}
+	}
+}
+
+// close stops the flush loop after writing any buffered counts
+func (r *usageRecorder) close() {
+	if r == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+}
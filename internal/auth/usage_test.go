@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestUsageRecorder_BatchesCounts(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+	mock.MatchExpectationsInOrder(false)
+
+	// An hour-long interval so only close flushes
+	r := newUsageRecorder(sqlDB, slog.New(slog.DiscardHandler), time.Hour)
+
+	mock.ExpectExec(`UPDATE "ApiKey" SET "lastUsed" = \$1, "usageCount" = "usageCount" \+ \$2 WHERE id = \$3`).
+		WithArgs(sqlmock.AnyArg(), int64(3), "key1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "ApiKey" SET "lastUsed" = \$1, "usageCount" = "usageCount" \+ \$2 WHERE id = \$3`).
+		WithArgs(sqlmock.AnyArg(), int64(1), "key2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	for i := 0; i < 3; i++ {
+		r.record("key1")
+	}
+	r.record("key2")
+	r.close()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestUsageRecorder_FlushResetsCounts(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	r := newUsageRecorder(sqlDB, slog.New(slog.DiscardHandler), time.Hour)
+	defer r.close()
+
+	mock.ExpectExec(`UPDATE "ApiKey"`).
+		WithArgs(sqlmock.AnyArg(), int64(2), "key1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	r.record("key1")
+	r.record("key1")
+	r.flush()
+
+	// Nothing new was recorded, so the next flush writes nothing
+	r.flush()
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	testAPIKey   = "etu_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	wrongTestKey = "etu_ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+	testHMACKey  = "0123456789abcdef0123456789abcdef"
+	otherHMACKey = "fedcba9876543210fedcba9876543210"
+)
+
+func mustHasher(t testing.TB, secret string) *APIKeyHasher {
+	t.Helper()
+	h, err := NewAPIKeyHasher([]byte(secret))
+	if err != nil {
+		t.Fatalf("NewAPIKeyHasher: %v", err)
+	}
+	return h
+}
+
+func bcryptHash(t testing.TB, key string) string {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(key), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	return string(hash)
+}
+
+func TestNewAPIKeyHasher_ShortSecret(t *testing.T) {
+	if _, err := NewAPIKeyHasher([]byte("too-short")); err == nil {
+		t.Error("expected error for a secret shorter than 32 bytes")
+	}
+}
+
+func TestAPIKeyHasher_HMAC(t *testing.T) {
+	h := mustHasher(t, testHMACKey)
+
+	hash, err := h.Hash(testAPIKey)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "hmac-sha256:") || strings.Contains(hash, testAPIKey) {
+		t.Fatalf("Hash = %q, want an hmac-sha256 hash", hash)
+	}
+
+	tests := []struct {
+		name      string
+		hasher    *APIKeyHasher
+		hash      string
+		key       string
+		wantMatch bool
+	}{
+		{name: "right key", hasher: h, hash: hash, key: testAPIKey, wantMatch: true},
+		{name: "wrong key", hasher: h, hash: hash, key: wrongTestKey},
+		{name: "other secret", hasher: mustHasher(t, otherHMACKey), hash: hash, key: testAPIKey},
+		{name: "no secret", hasher: nil, hash: hash, key: testAPIKey},
+		{name: "corrupt hash", hasher: h, hash: "hmac-sha256:zz", key: testAPIKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, rehash := tt.hasher.Verify(tt.hash, tt.key)
+			if match != tt.wantMatch || rehash {
+				t.Errorf("Verify = %v, %v, want %v, false", match, rehash, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestAPIKeyHasher_Bcrypt(t *testing.T) {
+	t.Setenv("BCRYPT_COST", "4")
+	hash := bcryptHash(t, testAPIKey)
+
+	tests := []struct {
+		name       string
+		hasher     *APIKeyHasher
+		key        string
+		wantMatch  bool
+		wantRehash bool
+	}{
+		{name: "no secret", hasher: mustHasher(t, ""), key: testAPIKey, wantMatch: true},
+		{name: "nil hasher", hasher: nil, key: testAPIKey, wantMatch: true},
+		{name: "with secret rehashes", hasher: mustHasher(t, testHMACKey), key: testAPIKey, wantMatch: true, wantRehash: true},
+		{name: "wrong key", hasher: mustHasher(t, testHMACKey), key: wrongTestKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, rehash := tt.hasher.Verify(hash, tt.key)
+			if match != tt.wantMatch || rehash != tt.wantRehash {
+				t.Errorf("Verify = %v, %v, want %v, %v", match, rehash, tt.wantMatch, tt.wantRehash)
+			}
+		})
+	}
+
+	// Without a secret new keys are still hashed with bcrypt
+	newHash, err := mustHasher(t, "").Hash(testAPIKey)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if cost, err := bcrypt.Cost([]byte(newHash)); err != nil || cost != 4 {
+		t.Errorf("bcrypt.Cost(Hash()) = %d, %v, want 4", cost, err)
+	}
+}
+
+// BenchmarkAPIKeyVerify compares checking a key against a bcrypt hash at the
+// default cost with checking it against an HMAC
+func BenchmarkAPIKeyVerify(b *testing.B) {
+	h := mustHasher(b, testHMACKey)
+
+	b.Run("bcrypt", func(b *testing.B) {
+		hash, err := bcrypt.GenerateFromPassword([]byte(testAPIKey), bcrypt.DefaultCost)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if match, _ := h.Verify(string(hash), testAPIKey); !match {
+				b.Fatal("no match")
+			}
+		}
+	})
+
+	b.Run("hmac", func(b *testing.B) {
+		hash, err := h.Hash(testAPIKey)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if match, _ := h.Verify(hash, testAPIKey); !match {
+				b.Fatal("no match")
+			}
+		}
+	})
+}
@@ -0,0 +1,25 @@
+package crypto
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// BcryptCost returns the bcrypt cost for hashing passwords and API keys,
+// read from BCRYPT_COST. Values outside bcrypt's range are clamped to it;
+// an unset or non-numeric value gives bcrypt.DefaultCost. Raising the cost
+// only affects new hashes, and existing hashes keep verifying.
+func BcryptCost() int {
+	value := strings.TrimSpace(os.Getenv("BCRYPT_COST"))
+	if value == "" {
+		return bcrypt.DefaultCost
+	}
+	cost, err := strconv.Atoi(value)
+	if err != nil {
+		return bcrypt.DefaultCost
+	}
+	return min(max(cost, bcrypt.MinCost), bcrypt.MaxCost)
+}
@@ -0,0 +1,31 @@
+package crypto
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestBcryptCost(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{value: "", want: bcrypt.DefaultCost},
+		{value: "12", want: 12},
+		{value: " 6 ", want: 6},
+		{value: "1", want: bcrypt.MinCost},
+		{value: "-3", want: bcrypt.MinCost},
+		{value: "99", want: bcrypt.MaxCost},
+		{value: "high", want: bcrypt.DefaultCost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv("BCRYPT_COST", tt.value)
+			if got := BcryptCost(); got != tt.want {
+				t.Errorf("BcryptCost() with BCRYPT_COST=%q = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MediaCopy describes a copy of one of a note's attachments that the caller
+// has already written to storage, for DuplicateNote to record
+type MediaCopy struct {
+	SourceID      string // ID of the image or audio file that was copied
+	ID            string // ID for the new attachment row
+	URL           string
+	GCSObjectName string
+	SizeBytes     int64
+}
+
+// DuplicateNote copies one of the user's notes to a new note with ID
+// newNoteID in one transaction. Content, color, summary, mood, and tags are
+// copied; the copy is unpinned, gets fresh timestamps, and has no Notion link,
+// so sync treats it as a new page. Images and audio files are copied with
+// their text, caption, dimensions, and position, pointing at the objects in
+// media. Attachments with no entry in media are left off the copy.
+//
+// Returns a nil note if the source does not exist or belongs to another user.
+func (db *DB) DuplicateNote(ctx context.Context, userID, noteID, newNoteID string, media []MediaCopy) (*Note, error) {
+	copies := make(map[string]MediaCopy, len(media))
+	for _, m := range media {
+		copies[m.SourceID] = m
+	}
+
+	found := false
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var source Note
+		result := tx.Where(`id = ? AND "userId" = ?`, noteID, userID).First(&source)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to get note: %w", result.Error)
+		}
+		found = true
+
+		now := time.Now()
+		note := Note{
+			ID:        newNoteID,
+			Content:   source.Content,
+			WordCount: source.WordCount,
+			Color:     source.Color,
+			Summary:   source.Summary,
+			Mood:      source.Mood,
+			CreatedAt: now,
+			UpdatedAt: now,
+			UserID:    userID,
+		}
+		if err := tx.Create(&note).Error; err != nil {
+			return fmt.Errorf("failed to insert note: %w", err)
+		}
+
+		err := tx.Exec(`INSERT INTO "NoteTag" ("noteId", "tagId") SELECT ?, "tagId" FROM "NoteTag" WHERE "noteId" = ?`, newNoteID, noteID).Error
+		if err != nil {
+			return fmt.Errorf("failed to copy tags: %w", err)
+		}
+
+		var images []NoteImage
+		if err := tx.Where(`"noteId" = ?`, noteID).Find(&images).Error; err != nil {
+			return fmt.Errorf("failed to get images: %w", err)
+		}
+		for _, img := range images {
+			c, ok := copies[img.ID]
+			if !ok {
+				continue
+			}
+			img.ID, img.NoteID, img.URL, img.GCSObjectName, img.SizeBytes, img.CreatedAt = c.ID, newNoteID, c.URL, c.GCSObjectName, c.SizeBytes, now
+			if err := tx.Create(&img).Error; err != nil {
+				return fmt.Errorf("failed to copy image %s: %w", c.SourceID, err)
+			}
+		}
+
+		var audios []NoteAudio
+		if err := tx.Where(`"noteId" = ?`, noteID).Find(&audios).Error; err != nil {
+			return fmt.Errorf("failed to get audio files: %w", err)
+		}
+		for _, aud := range audios {
+			c, ok := copies[aud.ID]
+			if !ok {
+				continue
+			}
+			aud.ID, aud.NoteID, aud.URL, aud.GCSObjectName, aud.SizeBytes, aud.CreatedAt = c.ID, newNoteID, c.URL, c.GCSObjectName, c.SizeBytes, now
+			if err := tx.Create(&aud).Error; err != nil {
+				return fmt.Errorf("failed to copy audio %s: %w", c.SourceID, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+
+	note, err := db.GetNoteFromPrimary(ctx, userID, newNoteID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload copied note: %w", err)
+	}
+	return note, nil
+}
@@ -0,0 +1,121 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDuplicateNote_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "wordCount", "createdAt", "updatedAt", "userId", "externalId", "pinned", "color", "summary", "mood"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("source", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).
+			AddRow("source", "- [ ] milk", 3, now.Add(-time.Hour), now, "user-1", "notion-page", true, "blue", "Shopping list", "neutral"))
+
+	// The copy keeps content and labels but not the pin or the Notion link
+	mock.ExpectExec(`INSERT INTO "Note"`).
+		WithArgs(
+			"copy", "- [ ] milk", 3, sqlmock.AnyArg(), sqlmock.AnyArg(), "user-1",
+			nil, nil, nil, false, "blue", "Shopping list", "neutral", nil, nil,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO "NoteTag" \("noteId", "tagId"\) SELECT \$1, "tagId" FROM "NoteTag" WHERE "noteId" = \$2`).
+		WithArgs("copy", "source").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	// img-2 has no stored copy, so it is left off
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage" WHERE "noteId" = \$1`).
+		WithArgs("source").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "extractedText", "caption", "mimeType", "sizeBytes", "width", "height", "position"}).
+			AddRow("img-1", "source", "https://old", "notes/source/img-1", "MILK", "list", "image/png", 10, 4, 3, 1).
+			AddRow("img-2", "source", "https://old2", "notes/source/img-2", "", "", "image/png", 10, 0, 0, 2))
+	mock.ExpectExec(`INSERT INTO "NoteImage"`).
+		WithArgs("img-copy", "copy", "https://new", "notes/copy/img-copy", "MILK", "list", "image/png", int64(12), 4, 3, 1, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio" WHERE "noteId" = \$1`).
+		WithArgs("source").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "transcribedText", "mimeType", "sizeBytes", "position"}).
+			AddRow("aud-1", "source", "https://old3", "notes/source/aud-1", "buy milk", "audio/mpeg", 20, 0))
+	mock.ExpectExec(`INSERT INTO "NoteAudio"`).
+		WithArgs("aud-copy", "copy", "https://new2", "notes/copy/aud-copy", "buy milk", "audio/mpeg", int64(20), 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Reload
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("copy", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).AddRow("copy", "- [ ] milk", now, now, "user-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("copy").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}).AddRow("tag-1", "shopping", now, "user-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("copy").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "gcsObjectName"}).AddRow("img-copy", "copy", "notes/copy/img-copy"))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("copy").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	media := []MediaCopy{
+		{SourceID: "img-1", ID: "img-copy", URL: "https://new", GCSObjectName: "notes/copy/img-copy", SizeBytes: 12},
+		{SourceID: "aud-1", ID: "aud-copy", URL: "https://new2", GCSObjectName: "notes/copy/aud-copy", SizeBytes: 20},
+	}
+	note, err := db.DuplicateNote(context.Background(), "user-1", "source", "copy", media)
+	if err != nil {
+		t.Fatalf("DuplicateNote: %v", err)
+	}
+	if note == nil || note.ID != "copy" || len(note.Tags) != 1 || len(note.Images) != 1 {
+		t.Errorf("got %+v, want the reloaded copy with its tag and image", note)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestDuplicateNote_NotFound(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("missing", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	note, err := db.DuplicateNote(context.Background(), "user-1", "missing", "copy", nil)
+	if err != nil {
+		t.Fatalf("DuplicateNote: %v", err)
+	}
+	if note != nil {
+		t.Errorf("got %+v, want nil for a missing note", note)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
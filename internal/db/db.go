@@ -3,10 +3,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,13 +18,26 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	gormlogger "gorm.io/gorm/logger"
 )
 
 // DB wraps the GORM database connection
 type DB struct {
-	conn *gorm.DB
-	log  *slog.Logger
+	conn    *gorm.DB
+	replica *gorm.DB // Optional read replica, nil when not configured
+	log     *slog.Logger
+}
+
+// readConn returns a connection for read-only queries that can tolerate
+// replication lag. It uses the replica when one is configured and the primary
+// otherwise. Transactions and reads that must see the caller's own writes
+// should use db.conn instead.
+func (db *DB) readConn(ctx context.Context) *gorm.DB {
+	if db.replica != nil {
+		return db.replica.WithContext(ctx)
+	}
+	return db.conn.WithContext(ctx)
 }
 
 // Re-export models for backwards compatibility
@@ -32,6 +47,13 @@ type User = models.User
 type ApiKey = models.ApiKey
 type NoteImage = models.NoteImage
 type NoteAudio = models.NoteAudio
+type ProcessingFailure = models.ProcessingFailure
+type StorageReservation = models.StorageReservation
+type SyncState = models.SyncState
+type ProcessedStripeEvent = models.ProcessedStripeEvent
+type SchemaMigration = models.SchemaMigration
+type LoginEvent = models.LoginEvent
+type NoteTemplate = models.NoteTemplate
 
 // encryptNotionKey encrypts a Notion API key if encryption is available.
 // If ENCRYPTION_KEY is not set, it logs a warning and returns the plaintext.
@@ -87,13 +109,39 @@ func New() (*DB, error) {
 		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	poolConfig, err := PoolConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection pool settings: %w", err)
+	}
+	poolConfig.Apply(sqlDB)
+
+	log := logger.New()
+	log.Info("database connection pool configured",
+		"max_open_conns", poolConfig.MaxOpenConns,
+		"max_idle_conns", poolConfig.MaxIdleConns,
+		"conn_max_lifetime", poolConfig.ConnMaxLifetime.String())
+
+	// Optional read replica for queries that tolerate replication lag
+	var replica *gorm.DB
+	if replicaConnStr := os.Getenv("DATABASE_REPLICA_URL"); replicaConnStr != "" {
+		replica, err = gorm.Open(postgres.Open(replicaConnStr), &gorm.Config{
+			Logger: gormlogger.Default.LogMode(gormlogger.Warn),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to open replica connection: %w", err)
+		}
+		replicaDB, err := replica.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get underlying replica sql.DB: %w", err)
+		}
+		poolConfig.Apply(replicaDB)
+		log.Info("database read replica configured")
+	}
 
 	return &DB{
-		conn: conn,
-		log:  logger.New(),
+		conn:    conn,
+		replica: replica,
+		log:     log,
 	}, nil
 }
 
@@ -114,6 +162,16 @@ func NewFromConn(sqlDB *sql.DB) (*DB, error) {
 
 // Close closes the database connection
 func (db *DB) Close() error {
+	if db.replica != nil {
+		replicaDB, err := db.replica.DB()
+		if err != nil {
+			return err
+		}
+		if err := replicaDB.Close(); err != nil {
+			return err
+		}
+	}
+
 	sqlDB, err := db.conn.DB()
 	if err != nil {
 		return err
@@ -132,48 +190,121 @@ func (db *DB) AutoMigrate() error {
 		&models.SyncState{},
 		&models.NoteImage{},
 		&models.NoteAudio{},
+		&models.ProcessingFailure{},
+		&models.StorageReservation{},
+		&models.SchemaMigration{},
+		&models.LoginEvent{},
+		&models.NoteTemplate{},
+		&models.ProcessedStripeEvent{},
 	)
 }
 
-// ListNotes retrieves notes for a user with optional filtering
-func (db *DB) ListNotes(ctx context.Context, userID, search string, tags []string, startDate, endDate string, limit, offset int) ([]Note, int, error) {
-	var notes []Note
-	var total int64
+// NoteInclude selects the associations GetNote and ListNotes load. Skipping
+// one saves a query; the note's field for it is left nil.
+type NoteInclude struct {
+	Tags   bool
+	Images bool
+	Audios bool
+}
 
-	query := db.conn.WithContext(ctx).Model(&Note{}).Where(`"userId" = ?`, userID)
+// DefaultNoteInclude loads every association
+var DefaultNoteInclude = NoteInclude{Tags: true, Images: true, Audios: true}
+
+// NoteFilter holds the optional filters for ListNotes and CountNotes
+type NoteFilter struct {
+	Search         string   // Free text, may include tag: filters
+	Tags           []string // Tag names the note must have one of
+	StartDate      string   // Inclusive lower bound on createdAt
+	EndDate        string   // Inclusive upper bound on createdAt
+	SearchCaptions bool     // Also match Search against image captions
+	SearchMedia    bool     // Also match Search against image OCR text and audio transcripts
+	Color          string   // Only notes with this label color, if set
+}
 
-	// Parse tag: syntax from search string
-	searchTags, remainingSearch := parseTagSearch(search)
-	allTags := normalizeTagNames(append(tags, searchTags...))
+// IsEmpty reports whether the filter matches all of a user's notes
+func (f NoteFilter) IsEmpty() bool {
+	searchTags, remainingSearch := parseTagSearch(f.Search)
+	allTags := models.NormalizeTags(append(slices.Clone(f.Tags), searchTags...))
+	return len(allTags) == 0 && remainingSearch == "" && f.StartDate == "" && f.EndDate == "" && f.Color == ""
+}
 
-	// Tag filtering
-	if len(allTags) > 0 {
-		query = query.Joins(`JOIN "NoteTag" ON "Note".id = "NoteTag"."noteId"`).
-			Joins(`JOIN "Tag" ON "NoteTag"."tagId" = "Tag".id`).
-			Where(`LOWER("Tag".name) IN ?`, allTags).
-			Distinct()
+// Fields ListNotes can sort by
+const (
+	SortByCreatedAt = "created_at"
+	SortByUpdatedAt = "updated_at"
+	SortByWordCount = "word_count"
+)
+
+// Directions ListNotes can sort in
+const (
+	SortDirDesc = "desc"
+	SortDirAsc  = "asc"
+)
+
+// noteSortColumns maps each allowed sort field to its column. Only these
+// columns are ever interpolated into ORDER BY.
+var noteSortColumns = map[string]string{
+	SortByCreatedAt: `"createdAt"`,
+	SortByUpdatedAt: `"updatedAt"`,
+	SortByWordCount: `"wordCount"`,
+}
+
+// NoteSort orders ListNotes results. The zero value sorts newest first.
+type NoteSort struct {
+	By  string // One of the SortBy constants; empty means SortByCreatedAt
+	Dir string // SortDirAsc or SortDirDesc; empty means SortDirDesc
+}
+
+// Validate reports an error if By or Dir is not an allowed value
+func (s NoteSort) Validate() error {
+	if _, ok := noteSortColumns[s.By]; s.By != "" && !ok {
+		return fmt.Errorf("sort field must be one of %s, %s, %s", SortByCreatedAt, SortByUpdatedAt, SortByWordCount)
+	}
+	if s.Dir != "" && s.Dir != SortDirAsc && s.Dir != SortDirDesc {
+		return fmt.Errorf("sort direction must be %s or %s", SortDirAsc, SortDirDesc)
 	}
+	return nil
+}
 
-	// Search filter (remaining text after tag: extraction)
-	if remainingSearch != "" {
-		query = query.Where("content ILIKE ?", "%"+remainingSearch+"%")
+// orderClause returns the ORDER BY clause for a validated sort. Pinned notes
+// always come first, and notes tied on another field fall back to newest
+// first so pages stay stable.
+func (s NoteSort) orderClause() string {
+	by := s.By
+	if by == "" {
+		by = SortByCreatedAt
+	}
+	dir := "DESC"
+	if s.Dir == SortDirAsc {
+		dir = "ASC"
 	}
 
-	// Date filters
-	if startDate != "" {
-		query = query.Where(`"createdAt" >= ?`, startDate)
+	clause := "pinned DESC, " + noteSortColumns[by] + " " + dir
+	if by != SortByCreatedAt {
+		clause += `, "createdAt" DESC`
 	}
-	if endDate != "" {
-		query = query.Where(`"createdAt" <= ?`, endDate)
+	return clause
+}
+
+// ListNotes retrieves notes for a user with optional filtering, ordered by sort
+func (db *DB) ListNotes(ctx context.Context, userID string, filter NoteFilter, sort NoteSort, include NoteInclude, limit, offset int) ([]Note, int, error) {
+	if err := sort.Validate(); err != nil {
+		return nil, 0, err
 	}
 
+	var notes []Note
+	var total int64
+
+	conn := db.readConn(ctx)
+	query := db.buildNotesQuery(ctx, userID, filter)
+
 	// Get total count
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to count notes: %w", err)
 	}
 
 	// Get paginated results
-	if err := query.Order(`"createdAt" DESC`).Limit(limit).Offset(offset).Find(&notes).Error; err != nil {
+	if err := query.Order(sort.orderClause()).Limit(limit).Offset(offset).Find(&notes).Error; err != nil {
 		return nil, 0, fmt.Errorf("failed to query notes: %w", err)
 	}
 
@@ -181,37 +312,79 @@ func (db *DB) ListNotes(ctx context.Context, userID, search string, tags []strin
 		return notes, int(total), nil
 	}
 
-	// Collect note IDs for batch fetching
-	noteIDs := make([]string, len(notes))
-	for i, n := range notes {
-		noteIDs[i] = n.ID
+	if err := loadNoteAssociations(conn, notes, include); err != nil {
+		return nil, 0, err
 	}
 
-	// Batch fetch tags for all notes
-	tagsByNoteID, err := db.getTagsForNotes(ctx, noteIDs)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to batch fetch tags: %w", err)
+	return notes, int(total), nil
+}
+
+// CountNotes returns how many of a user's notes match filter, without
+// loading them
+func (db *DB) CountNotes(ctx context.Context, userID string, filter NoteFilter) (int, error) {
+	var total int64
+	if err := db.buildNotesQuery(ctx, userID, filter).Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to count notes: %w", err)
 	}
+	return int(total), nil
+}
 
-	// Batch fetch images for all notes
-	imagesByNoteID, err := db.getImagesForNotes(ctx, noteIDs)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to batch fetch images: %w", err)
+// buildNotesQuery returns a read query over the user's notes matching
+// filter. ListNotes and CountNotes both start from it so their results always
+// agree. It only adds WHERE clauses, never joins, so each note appears once
+// and counts need no DISTINCT.
+func (db *DB) buildNotesQuery(ctx context.Context, userID string, filter NoteFilter) *gorm.DB {
+	query := db.readConn(ctx).Model(&Note{}).Where(`"userId" = ?`, userID)
+
+	// Parse tag: syntax from search string
+	searchTags, remainingSearch := parseTagSearch(filter.Search)
+	allTags := models.NormalizeTags(append(filter.Tags, searchTags...))
+
+	// Tag filtering: notes with any of the tags
+	if len(allTags) > 0 {
+		query = query.Where(`EXISTS (SELECT 1 FROM "NoteTag" JOIN "Tag" ON "NoteTag"."tagId" = "Tag".id WHERE "NoteTag"."noteId" = "Note".id AND LOWER("Tag".name) IN ?)`, allTags)
 	}
 
-	// Assign tags and images to notes
-	for i := range notes {
-		notes[i].Tags = tagsByNoteID[notes[i].ID]
-		notes[i].Images = imagesByNoteID[notes[i].ID]
+	// Search filter (remaining text after tag: extraction)
+	// Attachments are matched with subqueries rather than joins so a note
+	// with several matching attachments is still returned once.
+	if remainingSearch != "" {
+		pattern := "%" + remainingSearch + "%"
+		predicates := []string{"content ILIKE @pattern"}
+		if filter.SearchCaptions {
+			predicates = append(predicates, `EXISTS (SELECT 1 FROM "NoteImage" WHERE "NoteImage"."noteId" = "Note".id AND "NoteImage".caption ILIKE @pattern)`)
+		}
+		if filter.SearchMedia {
+			// Uncorrelated IN subqueries let Postgres answer each one with
+			// a single scan of the trigram index from migration 9 rather
+			// than probing every note's attachments.
+			predicates = append(predicates,
+				`"Note".id IN (SELECT "noteId" FROM "NoteImage" WHERE "extractedText" ILIKE @pattern)`,
+				`"Note".id IN (SELECT "noteId" FROM "NoteAudio" WHERE "transcribedText" ILIKE @pattern)`,
+			)
+		}
+		query = query.Where(strings.Join(predicates, " OR "), sql.Named("pattern", pattern))
 	}
 
-	return notes, int(total), nil
+	// Date filters
+	if filter.StartDate != "" {
+		query = query.Where(`"createdAt" >= ?`, filter.StartDate)
+	}
+	if filter.EndDate != "" {
+		query = query.Where(`"createdAt" <= ?`, filter.EndDate)
+	}
+
+	if filter.Color != "" {
+		query = query.Where(`color = ?`, filter.Color)
+	}
+
+	return query
 }
 
-// getNoteTags retrieves tags for a note
-func (db *DB) getNoteTags(ctx context.Context, noteID string) ([]Tag, error) {
+// getNoteTags retrieves tags for a note using conn
+func getNoteTags(conn *gorm.DB, noteID string) ([]Tag, error) {
 	var tags []Tag
-	err := db.conn.WithContext(ctx).
+	err := conn.
 		Joins(`JOIN "NoteTag" ON "Tag".id = "NoteTag"."tagId"`).
 		Where(`"NoteTag"."noteId" = ?`, noteID).
 		Order(`"Tag".name`).
@@ -219,27 +392,37 @@ func (db *DB) getNoteTags(ctx context.Context, noteID string) ([]Tag, error) {
 	return tags, err
 }
 
-// getNoteImages retrieves images for a note
-func (db *DB) getNoteImages(ctx context.Context, noteID string) ([]NoteImage, error) {
+// getNoteImages retrieves images for a note using conn
+func getNoteImages(conn *gorm.DB, noteID string) ([]NoteImage, error) {
 	var images []NoteImage
-	err := db.conn.WithContext(ctx).
+	err := conn.
 		Where(`"noteId" = ?`, noteID).
-		Order(`"createdAt" ASC`).
+		Order(`position ASC, "createdAt" ASC`).
 		Find(&images).Error
 	return images, err
 }
 
+// getNoteAudios retrieves audio files for a note using conn
+func getNoteAudios(conn *gorm.DB, noteID string) ([]NoteAudio, error) {
+	var audios []NoteAudio
+	err := conn.
+		Where(`"noteId" = ?`, noteID).
+		Order(`position ASC, "createdAt" ASC`).
+		Find(&audios).Error
+	return audios, err
+}
+
 // noteTagResult is used for batch fetching tags with their note associations
 type noteTagResult struct {
 	NoteID string
 	Tag
 }
 
-// getTagsForNotes batch fetches tags for multiple notes
-func (db *DB) getTagsForNotes(ctx context.Context, noteIDs []string) (map[string][]Tag, error) {
+// getTagsForNotes batch fetches tags for multiple notes using conn
+func getTagsForNotes(conn *gorm.DB, noteIDs []string) (map[string][]Tag, error) {
 	var results []noteTagResult
 
-	err := db.conn.WithContext(ctx).
+	err := conn.
 		Table(`"Tag"`).
 		Select(`"NoteTag"."noteId" as note_id, "Tag".*`).
 		Joins(`JOIN "NoteTag" ON "Tag".id = "NoteTag"."tagId"`).
@@ -263,13 +446,13 @@ func (db *DB) getTagsForNotes(ctx context.Context, noteIDs []string) (map[string
 	return tagsByNoteID, nil
 }
 
-// getImagesForNotes batch fetches images for multiple notes
-func (db *DB) getImagesForNotes(ctx context.Context, noteIDs []string) (map[string][]NoteImage, error) {
+// getImagesForNotes batch fetches images for multiple notes using conn
+func getImagesForNotes(conn *gorm.DB, noteIDs []string) (map[string][]NoteImage, error) {
 	var images []NoteImage
 
-	err := db.conn.WithContext(ctx).
+	err := conn.
 		Where(`"noteId" IN ?`, noteIDs).
-		Order(`"createdAt" ASC`).
+		Order(`position ASC, "createdAt" ASC`).
 		Find(&images).Error
 
 	if err != nil {
@@ -288,10 +471,96 @@ func (db *DB) getImagesForNotes(ctx context.Context, noteIDs []string) (map[stri
 	return imagesByNoteID, nil
 }
 
-// GetNote retrieves a single note by ID for a user
-func (db *DB) GetNote(ctx context.Context, userID, noteID string) (*Note, error) {
+// loadNoteAssociations batch fetches the associations in include for notes
+// using conn, one query per association
+func loadNoteAssociations(conn *gorm.DB, notes []Note, include NoteInclude) error {
+	if len(notes) == 0 {
+		return nil
+	}
+
+	// Collect note IDs for batch fetching
+	noteIDs := make([]string, len(notes))
+	for i, n := range notes {
+		noteIDs[i] = n.ID
+	}
+
+	// Batch fetch tags for all notes
+	if include.Tags {
+		tagsByNoteID, err := getTagsForNotes(conn, noteIDs)
+		if err != nil {
+			return fmt.Errorf("failed to batch fetch tags: %w", err)
+		}
+		for i := range notes {
+			notes[i].Tags = tagsByNoteID[notes[i].ID]
+		}
+	}
+
+	// Batch fetch images for all notes
+	if include.Images {
+		imagesByNoteID, err := getImagesForNotes(conn, noteIDs)
+		if err != nil {
+			return fmt.Errorf("failed to batch fetch images: %w", err)
+		}
+		for i := range notes {
+			notes[i].Images = imagesByNoteID[notes[i].ID]
+		}
+	}
+
+	// Batch fetch audios for all notes
+	if include.Audios {
+		audiosByNoteID, err := getAudiosForNotes(conn, noteIDs)
+		if err != nil {
+			return fmt.Errorf("failed to batch fetch audios: %w", err)
+		}
+		for i := range notes {
+			notes[i].Audios = audiosByNoteID[notes[i].ID]
+		}
+	}
+
+	return nil
+}
+
+// getAudiosForNotes batch fetches audio files for multiple notes using conn
+func getAudiosForNotes(conn *gorm.DB, noteIDs []string) (map[string][]NoteAudio, error) {
+	var audios []NoteAudio
+
+	err := conn.
+		Where(`"noteId" IN ?`, noteIDs).
+		Order(`position ASC, "createdAt" ASC`).
+		Find(&audios).Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Group audios by note ID
+	audiosByNoteID := make(map[string][]NoteAudio)
+	for _, noteID := range noteIDs {
+		audiosByNoteID[noteID] = []NoteAudio{} // Initialize empty slice for notes with no audios
+	}
+	for _, aud := range audios {
+		audiosByNoteID[aud.NoteID] = append(audiosByNoteID[aud.NoteID], aud)
+	}
+
+	return audiosByNoteID, nil
+}
+
+// GetNote retrieves a single note by ID for a user, reading from the replica
+// if one is configured
+func (db *DB) GetNote(ctx context.Context, userID, noteID string, include NoteInclude) (*Note, error) {
+	return getNote(db.readConn(ctx), userID, noteID, include)
+}
+
+// GetNoteFromPrimary is GetNote for callers that just wrote to the note and
+// must not see a stale copy from the replica
+func (db *DB) GetNoteFromPrimary(ctx context.Context, userID, noteID string) (*Note, error) {
+	return getNote(db.conn.WithContext(ctx), userID, noteID, DefaultNoteInclude)
+}
+
+// getNote retrieves a single note with the associations in include using conn
+func getNote(conn *gorm.DB, userID, noteID string, include NoteInclude) (*Note, error) {
 	var note Note
-	result := db.conn.WithContext(ctx).Where(`id = ? AND "userId" = ?`, noteID, userID).First(&note)
+	result := conn.Where(`id = ? AND "userId" = ?`, noteID, userID).First(&note)
 	if result.Error == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -299,60 +568,76 @@ func (db *DB) GetNote(ctx context.Context, userID, noteID string) (*Note, error)
 		return nil, fmt.Errorf("failed to get note: %w", result.Error)
 	}
 
-	tags, err := db.getNoteTags(ctx, note.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tags for note: %w", err)
+	if include.Tags {
+		tags, err := getNoteTags(conn, note.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tags for note: %w", err)
+		}
+		note.Tags = tags
 	}
-	note.Tags = tags
 
-	images, err := db.getNoteImages(ctx, note.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get images for note: %w", err)
+	if include.Images {
+		images, err := getNoteImages(conn, note.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get images for note: %w", err)
+		}
+		note.Images = images
+	}
+
+	if include.Audios {
+		audios, err := getNoteAudios(conn, note.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get audios for note: %w", err)
+		}
+		note.Audios = audios
 	}
-	note.Images = images
 
 	return &note, nil
 }
 
-// CreateNote creates a new note with optional tags
-func (db *DB) CreateNote(ctx context.Context, userID, content string, tagNames []string) (*Note, error) {
+// CreateNote creates a new note with an optional label color and tags. If
+// idempotencyKey is set and another note already holds it, an error wrapping
+// ErrIdempotencyKeyConflict is returned and nothing is created.
+// createdAt backdates the note, e.g. for imports; the zero time means now.
+// updatedAt is always now, so the note is still picked up by sync.
+func (db *DB) CreateNote(ctx context.Context, userID, content, color string, tagNames []string, idempotencyKey string, createdAt time.Time) (*Note, error) {
 	var note Note
 
 	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		now := time.Now()
+		if createdAt.IsZero() {
+			createdAt = now
+		}
 		note = Note{
 			ID:        models.GenerateCUID(),
 			Content:   content,
-			CreatedAt: now,
+			WordCount: int(CountWords(content)),
+			Color:     color,
+			CreatedAt: createdAt,
 			UpdatedAt: now,
 			UserID:    userID,
 		}
 
+		if idempotencyKey != "" {
+			if err := clearExpiredIdempotencyKey(tx, userID, idempotencyKey, now); err != nil {
+				return err
+			}
+			note.IdempotencyKey = &idempotencyKey
+			note.IdempotencyKeyAt = &now
+		}
+
 		if err := tx.Create(&note).Error; err != nil {
+			if idempotencyKey != "" && isUniqueViolation(err) {
+				return fmt.Errorf("%w: %q", ErrIdempotencyKeyConflict, idempotencyKey)
+			}
 			return fmt.Errorf("failed to insert note: %w", err)
 		}
 
 		// Create tags and link them
-		for _, tagName := range tagNames {
-			tagName = strings.ToLower(strings.TrimSpace(tagName))
-			if tagName == "" {
-				continue
-			}
-
-			var tag models.Tag
-			result := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, tagName).First(&tag)
-			if result.Error == gorm.ErrRecordNotFound {
-				tag = models.Tag{
-					ID:        models.GenerateCUID(),
-					Name:      tagName,
-					CreatedAt: now,
-					UserID:    userID,
-				}
-				if err := tx.Create(&tag).Error; err != nil {
-					return fmt.Errorf("failed to create tag: %w", err)
-				}
-			} else if result.Error != nil {
-				return result.Error
+		for _, tagName := range models.NormalizeTags(tagNames) {
+			tag, err := findOrCreateTag(tx, userID, tagName, now)
+			if err != nil {
+				return err
 			}
 
 			// Link note to tag
@@ -369,24 +654,31 @@ func (db *DB) CreateNote(ctx context.Context, userID, content string, tagNames [
 		return nil, err
 	}
 
-	// Reload tags and images
-	tags, err := db.getNoteTags(ctx, note.ID)
+	// Reload tags, images, and audios
+	tags, err := getNoteTags(db.conn.WithContext(ctx), note.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags for note: %w", err)
 	}
 	note.Tags = tags
 
-	images, err := db.getNoteImages(ctx, note.ID)
+	images, err := getNoteImages(db.conn.WithContext(ctx), note.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get images for note: %w", err)
 	}
 	note.Images = images
 
+	audios, err := getNoteAudios(db.conn.WithContext(ctx), note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audios for note: %w", err)
+	}
+	note.Audios = audios
+
 	return &note, nil
 }
 
-// UpdateNote updates an existing note
-func (db *DB) UpdateNote(ctx context.Context, userID, noteID string, content *string, tagNames []string, updateTags bool) (*Note, error) {
+// UpdateNote updates an existing note. Content and color are left alone when
+// nil.
+func (db *DB) UpdateNote(ctx context.Context, userID, noteID string, content, color *string, tagNames []string, updateTags bool) (*Note, error) {
 	var note Note
 
 	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -401,7 +693,17 @@ func (db *DB) UpdateNote(ctx context.Context, userID, noteID string, content *st
 
 		now := time.Now()
 		if content != nil {
+			// A summary or mood of the old content is stale; taggen
+			// writes new ones
+			if *content != note.Content {
+				note.Summary = ""
+				note.Mood = ""
+			}
 			note.Content = *content
+			note.WordCount = int(CountWords(*content))
+		}
+		if color != nil {
+			note.Color = *color
 		}
 		note.UpdatedAt = now
 
@@ -417,26 +719,10 @@ func (db *DB) UpdateNote(ctx context.Context, userID, noteID string, content *st
 			}
 
 			// Add new tags
-			for _, tagName := range tagNames {
-				tagName = strings.ToLower(strings.TrimSpace(tagName))
-				if tagName == "" {
-					continue
-				}
-
-				var tag models.Tag
-				result := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, tagName).First(&tag)
-				if result.Error == gorm.ErrRecordNotFound {
-					tag = models.Tag{
-						ID:        models.GenerateCUID(),
-						Name:      tagName,
-						CreatedAt: now,
-						UserID:    userID,
-					}
-					if err := tx.Create(&tag).Error; err != nil {
-						return fmt.Errorf("failed to create tag: %w", err)
-					}
-				} else if result.Error != nil {
-					return result.Error
+			for _, tagName := range models.NormalizeTags(tagNames) {
+				tag, err := findOrCreateTag(tx, userID, tagName, now)
+				if err != nil {
+					return err
 				}
 
 				noteTag := models.NoteTag{NoteID: noteID, TagID: tag.ID}
@@ -457,19 +743,25 @@ func (db *DB) UpdateNote(ctx context.Context, userID, noteID string, content *st
 		return nil, nil
 	}
 
-	// Reload tags and images
-	tags, err := db.getNoteTags(ctx, note.ID)
+	// Reload tags, images, and audios
+	tags, err := getNoteTags(db.conn.WithContext(ctx), note.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get tags for note: %w", err)
 	}
 	note.Tags = tags
 
-	images, err := db.getNoteImages(ctx, note.ID)
+	images, err := getNoteImages(db.conn.WithContext(ctx), note.ID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get images for note: %w", err)
 	}
 	note.Images = images
 
+	audios, err := getNoteAudios(db.conn.WithContext(ctx), note.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audios for note: %w", err)
+	}
+	note.Audios = audios
+
 	return &note, nil
 }
 
@@ -482,18 +774,107 @@ func (db *DB) DeleteNote(ctx context.Context, userID, noteID string) (bool, erro
 	return result.RowsAffected > 0, nil
 }
 
-// AddImageToNote adds an image to a note
+// AddImageToNote adds an image to a note, positioned after its existing images
 func (db *DB) AddImageToNote(ctx context.Context, noteID string, image *NoteImage) error {
 	image.NoteID = noteID
 	if image.CreatedAt.IsZero() {
 		image.CreatedAt = time.Now()
 	}
+	position, err := nextPosition(db.conn.WithContext(ctx), &NoteImage{}, noteID)
+	if err != nil {
+		return fmt.Errorf("failed to add image to note: %w", err)
+	}
+	image.Position = position
 	if err := db.conn.WithContext(ctx).Create(image).Error; err != nil {
 		return fmt.Errorf("failed to add image to note: %w", err)
 	}
 	return nil
 }
 
+// nextPosition returns the position that appends a new attachment of model's
+// type after the note's existing ones, using conn, which may be a transaction
+func nextPosition(conn *gorm.DB, model interface{}, noteID string) (int, error) {
+	var position int
+	err := conn.Model(model).
+		Select("COALESCE(MAX(position) + 1, 0)").
+		Where(`"noteId" = ?`, noteID).
+		Scan(&position).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next position: %w", err)
+	}
+	return position, nil
+}
+
+// SetNotePinned pins or unpins one of the user's notes. It doesn't touch
+// updatedAt, so pinning alone doesn't queue the note for a Notion push.
+// Returns false if the note does not exist or belongs to another user.
+func (db *DB) SetNotePinned(ctx context.Context, userID, noteID string, pinned bool) (bool, error) {
+	result := db.conn.WithContext(ctx).Model(&Note{}).
+		Where(`id = ? AND "userId" = ?`, noteID, userID).
+		UpdateColumn("pinned", pinned)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to set note pinned: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ErrInvalidImageOrder is returned by ReorderNoteImages when the given IDs are
+// not exactly the note's images
+var ErrInvalidImageOrder = errors.New("image IDs must list each of the note's images exactly once")
+
+// ReorderNoteImages sets the display order of a note's images to match
+// orderedIDs. Returns false if the note does not exist or belongs to another
+// user, and an error wrapping ErrInvalidImageOrder if orderedIDs is not a
+// permutation of the note's image IDs.
+func (db *DB) ReorderNoteImages(ctx context.Context, userID, noteID string, orderedIDs []string) (bool, error) {
+	found := false
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Verify note ownership
+		var note Note
+		result := tx.Where(`id = ? AND "userId" = ?`, noteID, userID).First(&note)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to verify note ownership: %w", result.Error)
+		}
+		found = true
+
+		var imageIDs []string
+		if err := tx.Model(&NoteImage{}).Where(`"noteId" = ?`, noteID).Pluck("id", &imageIDs).Error; err != nil {
+			return fmt.Errorf("failed to get images: %w", err)
+		}
+
+		existing := make(map[string]bool, len(imageIDs))
+		for _, id := range imageIDs {
+			existing[id] = true
+		}
+		if len(orderedIDs) != len(imageIDs) {
+			return fmt.Errorf("%w: got %d IDs for %d images", ErrInvalidImageOrder, len(orderedIDs), len(imageIDs))
+		}
+		for _, id := range orderedIDs {
+			if !existing[id] {
+				return fmt.Errorf("%w: %q is missing, repeated, or not on this note", ErrInvalidImageOrder, id)
+			}
+			delete(existing, id)
+		}
+
+		for position, id := range orderedIDs {
+			if err := tx.Model(&NoteImage{}).Where("id = ?", id).Update("position", position).Error; err != nil {
+				return fmt.Errorf("failed to update image position: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
 // RemoveImageFromNote removes an image from a note and returns the GCS object name for cleanup
 func (db *DB) RemoveImageFromNote(ctx context.Context, userID, noteID, imageID string) (string, error) {
 	// First verify the note belongs to the user
@@ -524,9 +905,31 @@ func (db *DB) RemoveImageFromNote(ctx context.Context, userID, noteID, imageID s
 	return image.GCSObjectName, nil
 }
 
+// UpdateImageCaption sets the caption of an image on one of the user's notes.
+// Returns nil if the image does not exist or belongs to another user.
+func (db *DB) UpdateImageCaption(ctx context.Context, userID, imageID, caption string) (*NoteImage, error) {
+	var image NoteImage
+	result := db.conn.WithContext(ctx).
+		Joins(`JOIN "Note" ON "Note".id = "NoteImage"."noteId"`).
+		Where(`"NoteImage".id = ? AND "Note"."userId" = ?`, imageID, userID).
+		First(&image)
+	if result.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get image: %w", result.Error)
+	}
+
+	if err := db.conn.WithContext(ctx).Model(&image).Update("caption", caption).Error; err != nil {
+		return nil, fmt.Errorf("failed to update image caption: %w", err)
+	}
+
+	return &image, nil
+}
+
 // GetNoteImages retrieves all images for a note (public version)
 func (db *DB) GetNoteImages(ctx context.Context, noteID string) ([]NoteImage, error) {
-	return db.getNoteImages(ctx, noteID)
+	return getNoteImages(db.conn.WithContext(ctx), noteID)
 }
 
 // GetImagesByNoteID retrieves images for a note for deletion purposes
@@ -539,12 +942,17 @@ func (db *DB) GetImagesByNoteID(ctx context.Context, noteID string) ([]NoteImage
 	return images, nil
 }
 
-// AddAudioToNote adds an audio file to a note
+// AddAudioToNote adds an audio file to a note, positioned after its existing audio files
 func (db *DB) AddAudioToNote(ctx context.Context, noteID string, audio *NoteAudio) error {
 	audio.NoteID = noteID
 	if audio.CreatedAt.IsZero() {
 		audio.CreatedAt = time.Now()
 	}
+	position, err := nextPosition(db.conn.WithContext(ctx), &NoteAudio{}, noteID)
+	if err != nil {
+		return fmt.Errorf("failed to add audio to note: %w", err)
+	}
+	audio.Position = position
 	if err := db.conn.WithContext(ctx).Create(audio).Error; err != nil {
 		return fmt.Errorf("failed to add audio to note: %w", err)
 	}
@@ -584,17 +992,53 @@ func (db *DB) RemoveAudioFromNote(ctx context.Context, userID, noteID, audioID s
 // GetAudiosByNoteID retrieves audio files for a note for deletion purposes
 func (db *DB) GetAudiosByNoteID(ctx context.Context, noteID string) ([]NoteAudio, error) {
 	var audios []NoteAudio
-	err := db.conn.WithContext(ctx).Where(`"noteId" = ?`, noteID).Find(&audios).Error
+	err := db.conn.WithContext(ctx).Where(`"noteId" = ?`, noteID).Order(`position ASC, "createdAt" ASC`).Find(&audios).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get audios: %w", err)
 	}
 	return audios, nil
 }
 
+// GetReferencedObjectNames returns the set of storage object names referenced
+// by any image, audio file, or profile image
+func (db *DB) GetReferencedObjectNames(ctx context.Context) (map[string]bool, error) {
+	referenced := make(map[string]bool)
+
+	sources := []struct {
+		model  interface{}
+		column string
+	}{
+		{&NoteImage{}, `"gcsObjectName"`},
+		{&NoteAudio{}, `"gcsObjectName"`},
+		{&User{}, `"profileImageGCSObject"`},
+	}
+	for _, src := range sources {
+		var names []string
+		err := db.conn.WithContext(ctx).Model(src.model).
+			Where(src.column+" IS NOT NULL AND "+src.column+" <> ''").
+			Pluck(src.column, &names).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to get referenced object names: %w", err)
+		}
+		for _, name := range names {
+			referenced[name] = true
+		}
+	}
+
+	return referenced, nil
+}
+
 // GetImagesWithoutExtractedText returns all images that don't have extracted text yet
-func (db *DB) GetImagesWithoutExtractedText(ctx context.Context) ([]NoteImage, error) {
+// If userID is empty, returns images for all users
+func (db *DB) GetImagesWithoutExtractedText(ctx context.Context, userID string) ([]NoteImage, error) {
 	var images []NoteImage
-	err := db.conn.WithContext(ctx).Where(`"extractedText" = ?`, "").Find(&images).Error
+	query := db.conn.WithContext(ctx).Where(`"extractedText" = ?`, "")
+	if userID != "" {
+		query = query.Select(`"NoteImage".*`).
+			Joins(`JOIN "Note" ON "Note".id = "NoteImage"."noteId"`).
+			Where(`"Note"."userId" = ?`, userID)
+	}
+	err := query.Find(&images).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get images without extracted text: %w", err)
 	}
@@ -614,9 +1058,16 @@ func (db *DB) UpdateImageExtractedText(ctx context.Context, imageID string, extr
 }
 
 // GetAudiosWithoutTranscription returns all audio files that don't have transcribed text yet
-func (db *DB) GetAudiosWithoutTranscription(ctx context.Context) ([]NoteAudio, error) {
+// If userID is empty, returns audio files for all users
+func (db *DB) GetAudiosWithoutTranscription(ctx context.Context, userID string) ([]NoteAudio, error) {
 	var audios []NoteAudio
-	err := db.conn.WithContext(ctx).Where(`"transcribedText" = ?`, "").Find(&audios).Error
+	query := db.conn.WithContext(ctx).Where(`"transcribedText" = ?`, "")
+	if userID != "" {
+		query = query.Select(`"NoteAudio".*`).
+			Joins(`JOIN "Note" ON "Note".id = "NoteAudio"."noteId"`).
+			Where(`"Note"."userId" = ?`, userID)
+	}
+	err := query.Find(&audios).Error
 	if err != nil {
 		return nil, fmt.Errorf("failed to get audios without transcription: %w", err)
 	}
@@ -635,10 +1086,152 @@ func (db *DB) UpdateAudioTranscribedText(ctx context.Context, audioID string, tr
 	return nil
 }
 
+// GetImagesWithoutSize returns images whose stored size has not been recorded
+func (db *DB) GetImagesWithoutSize(ctx context.Context) ([]NoteImage, error) {
+	var images []NoteImage
+	err := db.conn.WithContext(ctx).Where(`"sizeBytes" = ?`, 0).Find(&images).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images without size: %w", err)
+	}
+	return images, nil
+}
+
+// UpdateImageSize records the stored size of an image
+func (db *DB) UpdateImageSize(ctx context.Context, imageID string, sizeBytes int64) error {
+	result := db.conn.WithContext(ctx).Model(&NoteImage{}).Where("id = ?", imageID).Update("sizeBytes", sizeBytes)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update image size: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("image not found")
+	}
+	return nil
+}
+
+// GetImagesWithoutDimensions returns images whose pixel dimensions have not
+// been recorded. Images in formats that can't be measured stay in this list.
+func (db *DB) GetImagesWithoutDimensions(ctx context.Context) ([]NoteImage, error) {
+	var images []NoteImage
+	err := db.conn.WithContext(ctx).Where(`"width" = ? OR "height" = ?`, 0, 0).Find(&images).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get images without dimensions: %w", err)
+	}
+	return images, nil
+}
+
+// UpdateImageDimensions records the pixel dimensions of an image
+func (db *DB) UpdateImageDimensions(ctx context.Context, imageID string, width, height int) error {
+	result := db.conn.WithContext(ctx).Model(&NoteImage{}).Where("id = ?", imageID).
+		Updates(map[string]interface{}{"width": width, "height": height})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update image dimensions: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("image not found")
+	}
+	return nil
+}
+
+// GetAudiosWithoutSize returns audio files whose stored size has not been recorded
+func (db *DB) GetAudiosWithoutSize(ctx context.Context) ([]NoteAudio, error) {
+	var audios []NoteAudio
+	err := db.conn.WithContext(ctx).Where(`"sizeBytes" = ?`, 0).Find(&audios).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get audios without size: %w", err)
+	}
+	return audios, nil
+}
+
+// UpdateAudioSize records the stored size of an audio file
+func (db *DB) UpdateAudioSize(ctx context.Context, audioID string, sizeBytes int64) error {
+	result := db.conn.WithContext(ctx).Model(&NoteAudio{}).Where("id = ?", audioID).Update("sizeBytes", sizeBytes)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update audio size: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("audio not found")
+	}
+	return nil
+}
+
+// ReprocessOptions selects which AI-derived data ReprocessNote clears
+type ReprocessOptions struct {
+	ExtractedText   bool
+	TranscribedText bool
+	Tags            bool
+}
+
+// ReprocessNote clears the selected AI-derived data for a note so the taggen
+// job picks it up again. Failure records for the cleared media are reset so
+// items that previously exhausted their retries are attempted again.
+// Returns false if the note does not exist or belongs to another user.
+func (db *DB) ReprocessNote(ctx context.Context, userID, noteID string, opts ReprocessOptions) (bool, error) {
+	found := false
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Verify note ownership
+		var note Note
+		result := tx.Where(`id = ? AND "userId" = ?`, noteID, userID).First(&note)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to verify note ownership: %w", result.Error)
+		}
+		found = true
+
+		if opts.ExtractedText {
+			var imageIDs []string
+			if err := tx.Model(&NoteImage{}).Where(`"noteId" = ?`, noteID).Pluck("id", &imageIDs).Error; err != nil {
+				return fmt.Errorf("failed to get images: %w", err)
+			}
+			if len(imageIDs) > 0 {
+				if err := tx.Model(&NoteImage{}).Where("id IN ?", imageIDs).Update("extractedText", "").Error; err != nil {
+					return fmt.Errorf("failed to clear extracted text: %w", err)
+				}
+				if err := tx.Where(`"resourceType" = ? AND "resourceId" IN ?`, models.ResourceTypeImage, imageIDs).Delete(&ProcessingFailure{}).Error; err != nil {
+					return fmt.Errorf("failed to reset image processing failures: %w", err)
+				}
+			}
+		}
+
+		if opts.TranscribedText {
+			var audioIDs []string
+			if err := tx.Model(&NoteAudio{}).Where(`"noteId" = ?`, noteID).Pluck("id", &audioIDs).Error; err != nil {
+				return fmt.Errorf("failed to get audios: %w", err)
+			}
+			if len(audioIDs) > 0 {
+				if err := tx.Model(&NoteAudio{}).Where("id IN ?", audioIDs).Update("transcribedText", "").Error; err != nil {
+					return fmt.Errorf("failed to clear transcribed text: %w", err)
+				}
+				if err := tx.Where(`"resourceType" = ? AND "resourceId" IN ?`, models.ResourceTypeAudio, audioIDs).Delete(&ProcessingFailure{}).Error; err != nil {
+					return fmt.Errorf("failed to reset audio processing failures: %w", err)
+				}
+			}
+		}
+
+		if opts.Tags {
+			if err := tx.Where(`"noteId" = ?`, noteID).Delete(&models.NoteTag{}).Error; err != nil {
+				return fmt.Errorf("failed to remove tag links: %w", err)
+			}
+			if err := tx.Model(&note).Update("updatedAt", time.Now()).Error; err != nil {
+				return fmt.Errorf("failed to update note timestamp: %w", err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return found, nil
+}
+
 // ListTags retrieves all tags for a user with usage counts
 func (db *DB) ListTags(ctx context.Context, userID string) ([]Tag, error) {
 	var tags []Tag
-	err := db.conn.WithContext(ctx).
+	err := db.readConn(ctx).
 		Select(`"Tag".*, COUNT("NoteTag"."noteId") as count`).
 		Joins(`LEFT JOIN "NoteTag" ON "Tag".id = "NoteTag"."tagId"`).
 		Where(`"Tag"."userId" = ?`, userID).
@@ -651,6 +1244,80 @@ func (db *DB) ListTags(ctx context.Context, userID string) ([]Tag, error) {
 	return tags, nil
 }
 
+// GetTagCounts returns a user's tags with the number of notes using each one,
+// counting only notes created within the optional startDate and endDate
+// bounds. Tags with no notes in the range are omitted. Results are ordered by
+// count, highest first.
+func (db *DB) GetTagCounts(ctx context.Context, userID, startDate, endDate string) ([]Tag, error) {
+	query := db.conn.WithContext(ctx).
+		Select(`"Tag".*, COUNT("Note".id) as count`).
+		Joins(`JOIN "NoteTag" ON "Tag".id = "NoteTag"."tagId"`).
+		Joins(`JOIN "Note" ON "Note".id = "NoteTag"."noteId"`).
+		Where(`"Tag"."userId" = ?`, userID)
+	if startDate != "" {
+		query = query.Where(`"Note"."createdAt" >= ?`, startDate)
+	}
+	if endDate != "" {
+		query = query.Where(`"Note"."createdAt" <= ?`, endDate)
+	}
+
+	var tags []Tag
+	err := query.
+		Group(`"Tag".id`).
+		Order(`count DESC, "Tag".name`).
+		Find(&tags).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetRelatedTags returns up to limit of a user's other tags that appear on the
+// same notes as tagName, with Count set to the number of notes they share.
+// tagName is normalized like stored tags. Results are ordered by count,
+// highest first; an unknown or unshared tag returns none.
+func (db *DB) GetRelatedTags(ctx context.Context, userID, tagName string, limit int) ([]Tag, error) {
+	name, ok := models.NormalizeTag(tagName)
+	if !ok {
+		return []Tag{}, nil
+	}
+
+	var tags []Tag
+	err := db.readConn(ctx).
+		Select(`"Tag".*, COUNT(DISTINCT "NoteTag"."noteId") as count`).
+		Joins(`JOIN "NoteTag" ON "Tag".id = "NoteTag"."tagId"`).
+		Joins(`JOIN "NoteTag" AS source_note_tag ON source_note_tag."noteId" = "NoteTag"."noteId" AND source_note_tag."tagId" <> "NoteTag"."tagId"`).
+		Joins(`JOIN "Tag" AS source_tag ON source_tag.id = source_note_tag."tagId"`).
+		Where(`"Tag"."userId" = ? AND source_tag."userId" = ? AND LOWER(source_tag.name) = ?`, userID, userID, name).
+		Group(`"Tag".id`).
+		Order(`count DESC, "Tag".name`).
+		Limit(limit).
+		Find(&tags).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to query related tags: %w", err)
+	}
+	return tags, nil
+}
+
+// GetTag retrieves one of a user's tags with its usage count. Returns nil if
+// the tag does not exist or belongs to another user.
+func (db *DB) GetTag(ctx context.Context, userID, tagID string) (*Tag, error) {
+	var tag Tag
+	result := db.conn.WithContext(ctx).
+		Select(`"Tag".*, COUNT("NoteTag"."noteId") as count`).
+		Joins(`LEFT JOIN "NoteTag" ON "Tag".id = "NoteTag"."tagId"`).
+		Where(`"Tag".id = ? AND "Tag"."userId" = ?`, tagID, userID).
+		Group(`"Tag".id`).
+		Take(&tag)
+	if result.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get tag: %w", result.Error)
+	}
+	return &tag, nil
+}
+
 // CreateUser creates a new user with email and password
 func (db *DB) CreateUser(ctx context.Context, email, passwordHash string) (*User, error) {
 	now := time.Now()
@@ -731,6 +1398,20 @@ func (db *DB) GetUserByStripeCustomerID(ctx context.Context, stripeCustomerID st
 
 // UpdateUserSubscription updates a user's subscription information
 func (db *DB) UpdateUserSubscription(ctx context.Context, userID, subscriptionStatus string, stripeCustomerID *string, subscriptionEnd *time.Time) (*User, error) {
+	updated, err := updateUserSubscription(db.conn.WithContext(ctx), userID, subscriptionStatus, stripeCustomerID, subscriptionEnd)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, nil
+	}
+
+	return db.GetUser(ctx, userID)
+}
+
+// updateUserSubscription sets a user's subscription fields, leaving the
+// customer ID and end unchanged when nil. Reports false if there is no such user.
+func updateUserSubscription(tx *gorm.DB, userID, subscriptionStatus string, stripeCustomerID *string, subscriptionEnd *time.Time) (bool, error) {
 	updates := map[string]interface{}{
 		"subscriptionStatus": subscriptionStatus,
 	}
@@ -741,15 +1422,11 @@ func (db *DB) UpdateUserSubscription(ctx context.Context, userID, subscriptionSt
 		updates["subscriptionEnd"] = *subscriptionEnd
 	}
 
-	result := db.conn.WithContext(ctx).Model(&User{}).Where("id = ?", userID).Updates(updates)
+	result := tx.Model(&User{}).Where("id = ?", userID).Updates(updates)
 	if result.Error != nil {
-		return nil, fmt.Errorf("failed to update user subscription: %w", result.Error)
-	}
-	if result.RowsAffected == 0 {
-		return nil, nil
+		return false, fmt.Errorf("failed to update user subscription: %w", result.Error)
 	}
-
-	return db.GetUser(ctx, userID)
+	return result.RowsAffected > 0, nil
 }
 
 // IsAccountLocked checks if a user account is locked or disabled
@@ -813,6 +1490,25 @@ func (db *DB) RecordSuccessfulLogin(ctx context.Context, userID string) error {
 	return nil
 }
 
+// UnlockAccount clears the failed login count that locks an account. Unlike
+// RecordSuccessfulLogin it reports a missing user.
+func (db *DB) UnlockAccount(ctx context.Context, userID string) error {
+	updates := map[string]interface{}{
+		"failedLoginAttempts": 0,
+		"lastFailedLogin":     nil,
+	}
+
+	result := db.conn.WithContext(ctx).Model(&User{}).Where("id = ?", userID).Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to unlock account: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
 // DisableUser disables a user account with a reason
 func (db *DB) DisableUser(ctx context.Context, userID string, reason string) error {
 	updates := map[string]interface{}{
@@ -872,7 +1568,7 @@ func (db *DB) CreateApiKey(ctx context.Context, userID, name, keyPrefix, keyHash
 func (db *DB) ListApiKeys(ctx context.Context, userID string) ([]ApiKey, error) {
 	var keys []ApiKey
 	err := db.conn.WithContext(ctx).
-		Select(`id, name, "keyPrefix", "createdAt", "lastUsed", "userId"`).
+		Select(`id, name, "keyPrefix", "createdAt", "lastUsed", "userId", "usageCount"`).
 		Where(`"userId" = ?`, userID).
 		Order(`"createdAt" DESC`).
 		Find(&keys).Error
@@ -901,9 +1597,16 @@ func (db *DB) GetApiKeysByPrefix(ctx context.Context, keyPrefix string) ([]ApiKe
 	return keys, nil
 }
 
-// UpdateApiKeyLastUsed updates the lastUsed timestamp for an API key
-func (db *DB) UpdateApiKeyLastUsed(ctx context.Context, keyID string) error {
-	return db.conn.WithContext(ctx).Model(&ApiKey{}).Where("id = ?", keyID).Update("lastUsed", time.Now()).Error
+// RehashApiKey replaces an API key's stored hash, but only if it is still
+// oldHash, so concurrent rehashes of the same key don't conflict
+func (db *DB) RehashApiKey(ctx context.Context, keyID, oldHash, newHash string) error {
+	err := db.conn.WithContext(ctx).Model(&ApiKey{}).
+		Where(`id = ? AND "keyHash" = ?`, keyID, oldHash).
+		Update("keyHash", newHash).Error
+	if err != nil {
+		return fmt.Errorf("failed to rehash API key: %w", err)
+	}
+	return nil
 }
 
 // GetNotesWithFewTags retrieves notes for a user that have fewer than maxTags tags
@@ -926,7 +1629,7 @@ func (db *DB) GetNotesWithFewTags(ctx context.Context, userID string, maxTags in
 
 	// Fetch tags for each note
 	for i := range notes {
-		tags, err := db.getNoteTags(ctx, notes[i].ID)
+		tags, err := getNoteTags(db.conn.WithContext(ctx), notes[i].ID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get tags for note %s: %w", notes[i].ID, err)
 		}
@@ -936,6 +1639,96 @@ func (db *DB) GetNotesWithFewTags(ctx context.Context, userID string, maxTags in
 	return notes, nil
 }
 
+// GetNotesWithoutSummary returns notes of at least minWords words that have
+// no summary yet, newest first.
+func (db *DB) GetNotesWithoutSummary(ctx context.Context, minWords int) ([]Note, error) {
+	var notes []Note
+	err := db.conn.WithContext(ctx).
+		Where(`summary = ? AND "wordCount" >= ?`, "", minWords).
+		Order(`"createdAt" DESC`).
+		Find(&notes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes without summary: %w", err)
+	}
+	return notes, nil
+}
+
+// UpdateNoteSummary stores a summary for a note, but only if its content still
+// hashes to contentHash, so an edit made while the summary was generated isn't
+// given a stale one. Like pinning it doesn't touch updatedAt. Returns false if
+// the note is gone or its content changed.
+func (db *DB) UpdateNoteSummary(ctx context.Context, noteID, contentHash, summary string) (bool, error) {
+	result := db.conn.WithContext(ctx).Model(&Note{}).
+		Where(`id = ? AND "contentHash" = ?`, noteID, contentHash).
+		UpdateColumn("summary", summary)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update note summary: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetNotesWithoutMood returns notes with content whose mood hasn't been
+// analyzed yet, newest first.
+func (db *DB) GetNotesWithoutMood(ctx context.Context) ([]Note, error) {
+	var notes []Note
+	err := db.conn.WithContext(ctx).
+		Where(`mood = ? AND content <> ?`, "", "").
+		Order(`"createdAt" DESC`).
+		Find(&notes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notes without mood: %w", err)
+	}
+	return notes, nil
+}
+
+// UpdateNoteMood stores a mood for a note if its content still hashes to
+// contentHash, like UpdateNoteSummary. Returns false if the note is gone or
+// its content changed.
+func (db *DB) UpdateNoteMood(ctx context.Context, noteID, contentHash, mood string) (bool, error) {
+	result := db.conn.WithContext(ctx).Model(&Note{}).
+		Where(`id = ? AND "contentHash" = ?`, noteID, contentHash).
+		UpdateColumn("mood", mood)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update note mood: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// findOrCreateTag returns the user's tag with the given normalized name,
+// creating it if needed. If another transaction creates the same tag first, the
+// unique index on ("userId", lower(name)) turns the insert into a no-op and the
+// other transaction's row is returned instead.
+func findOrCreateTag(tx *gorm.DB, userID, name string, now time.Time) (models.Tag, error) {
+	var tag models.Tag
+	result := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, name).First(&tag)
+	if result.Error == nil {
+		return tag, nil
+	}
+	if result.Error != gorm.ErrRecordNotFound {
+		return tag, fmt.Errorf("failed to find tag: %w", result.Error)
+	}
+
+	tag = models.Tag{
+		ID:        models.GenerateCUID(),
+		Name:      name,
+		CreatedAt: now,
+		UserID:    userID,
+	}
+	result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tag)
+	if result.Error != nil {
+		return tag, fmt.Errorf("failed to create tag: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return tag, nil
+	}
+
+	tag = models.Tag{}
+	if err := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, name).First(&tag).Error; err != nil {
+		return tag, fmt.Errorf("failed to find existing tag: %w", err)
+	}
+	return tag, nil
+}
+
 // AddTagsToNote adds tags to a note without removing existing tags
 func (db *DB) AddTagsToNote(ctx context.Context, userID, noteID string, tagNames []string) error {
 	return db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -953,32 +1746,15 @@ func (db *DB) AddTagsToNote(ctx context.Context, userID, noteID string, tagNames
 		tagsAdded := false
 
 		// Add new tags
-		for _, tagName := range tagNames {
-			tagName = strings.ToLower(strings.TrimSpace(tagName))
-			if tagName == "" {
-				continue
-			}
-
-			// Find or create the tag
-			var tag models.Tag
-			result := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, tagName).First(&tag)
-			if result.Error == gorm.ErrRecordNotFound {
-				tag = models.Tag{
-					ID:        models.GenerateCUID(),
-					Name:      tagName,
-					CreatedAt: now,
-					UserID:    userID,
-				}
-				if err := tx.Create(&tag).Error; err != nil {
-					return fmt.Errorf("failed to create tag: %w", err)
-				}
-			} else if result.Error != nil {
-				return result.Error
+		for _, tagName := range models.NormalizeTags(tagNames) {
+			tag, err := findOrCreateTag(tx, userID, tagName, now)
+			if err != nil {
+				return err
 			}
 
 			// Check if the tag is already linked to the note
 			var noteTag models.NoteTag
-			result = tx.Where(`"noteId" = ? AND "tagId" = ?`, noteID, tag.ID).First(&noteTag)
+			result := tx.Where(`"noteId" = ? AND "tagId" = ?`, noteID, tag.ID).First(&noteTag)
 			if result.Error == gorm.ErrRecordNotFound {
 				// Link note to tag if not already linked
 				noteTag = models.NoteTag{NoteID: noteID, TagID: tag.ID}
@@ -1023,7 +1799,7 @@ func (db *DB) GetUserSettings(ctx context.Context, userID string) (*User, error)
 }
 
 // UpdateUserSettings updates or creates user settings
-func (db *DB) UpdateUserSettings(ctx context.Context, userID string, notionKey, name, image, password, notionDatabaseName, profileImageGCSObject *string) (*User, error) {
+func (db *DB) UpdateUserSettings(ctx context.Context, userID string, notionKey, name, image, password, notionDatabaseName, profileImageGCSObject *string, syncIntervalMinutes *int) (*User, error) {
 	now := time.Now()
 
 	var user User
@@ -1051,7 +1827,7 @@ func (db *DB) UpdateUserSettings(ctx context.Context, userID string, notionKey,
 		updates["image"] = *image
 	}
 	if password != nil && *password != "" {
-		hash, err := bcrypt.GenerateFromPassword([]byte(*password), bcrypt.DefaultCost)
+		hash, err := bcrypt.GenerateFromPassword([]byte(*password), crypto.BcryptCost())
 		if err != nil {
 			return nil, fmt.Errorf("failed to hash password: %w", err)
 		}
@@ -1063,9 +1839,27 @@ func (db *DB) UpdateUserSettings(ctx context.Context, userID string, notionKey,
 	if profileImageGCSObject != nil {
 		updates["profileImageGCSObject"] = *profileImageGCSObject
 	}
+	if syncIntervalMinutes != nil {
+		if *syncIntervalMinutes == 0 {
+			updates["syncIntervalMinutes"] = nil
+		} else {
+			updates["syncIntervalMinutes"] = *syncIntervalMinutes
+		}
+	}
 
-	if err := db.conn.WithContext(ctx).Model(&user).Updates(updates).Error; err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(updates).Error; err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+		// Without a key the sync watermark is stale; dropping it means a
+		// re-added key starts with a full sync, as for a new user
+		if notionKey != nil && *notionKey == "" {
+			return deleteSyncState(tx, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Reload to get updated values
@@ -1113,6 +1907,28 @@ func (db *DB) ListAllUsers(ctx context.Context) ([]User, error) {
 	return users, nil
 }
 
+// ListUsers returns a page of users, newest first, along with the number of
+// users matching email. email matches case-insensitively anywhere in the
+// address; empty matches every user.
+func (db *DB) ListUsers(ctx context.Context, email string, limit, offset int) ([]User, int, error) {
+	query := db.readConn(ctx).Model(&User{})
+	if email != "" {
+		query = query.Where("email ILIKE ?", "%"+email+"%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	var users []User
+	err := query.Order(`"createdAt" DESC, id`).Limit(limit).Offset(offset).Find(&users).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, int(total), nil
+}
+
 // GetRandomNotes retrieves a random set of notes for a user
 func (db *DB) GetRandomNotes(ctx context.Context, userID string, count int) ([]Note, error) {
 	if count <= 0 {
@@ -1133,47 +1949,53 @@ func (db *DB) GetRandomNotes(ctx context.Context, userID string, count int) ([]N
 		return notes, nil
 	}
 
-	// Collect note IDs for batch fetching
-	noteIDs := make([]string, len(notes))
-	for i, n := range notes {
-		noteIDs[i] = n.ID
+	if err := loadNoteAssociations(db.conn.WithContext(ctx), notes, DefaultNoteInclude); err != nil {
+		return nil, err
 	}
 
-	// Batch fetch tags for all notes
-	tagsByNoteID, err := db.getTagsForNotes(ctx, noteIDs)
-	if err != nil {
-		return nil, fmt.Errorf("failed to batch fetch tags: %w", err)
-	}
+	return notes, nil
+}
 
-	// Batch fetch images for all notes
-	imagesByNoteID, err := db.getImagesForNotes(ctx, noteIDs)
+// ListNotesModifiedSince retrieves up to limit of a user's notes updated at or
+// after since, oldest update first, with their associations. Notes are ordered
+// by ("updatedAt", id) and only those after (since, afterID) are returned, so
+// passing the last note's updatedAt and ID pages through notes that share an
+// updatedAt without repeats. An empty afterID includes every note updated
+// exactly at since.
+func (db *DB) ListNotesModifiedSince(ctx context.Context, userID string, since time.Time, afterID string, limit int) ([]Note, error) {
+	var notes []Note
+	conn := db.readConn(ctx)
+	err := conn.
+		Where(`"userId" = ? AND ("updatedAt", id) > (?, ?)`, userID, since, afterID).
+		Order(`"updatedAt" ASC, id ASC`).
+		Limit(limit).
+		Find(&notes).Error
 	if err != nil {
-		return nil, fmt.Errorf("failed to batch fetch images: %w", err)
+		return nil, fmt.Errorf("failed to query modified notes: %w", err)
 	}
 
-	// Assign tags and images to notes
-	for i := range notes {
-		notes[i].Tags = tagsByNoteID[notes[i].ID]
-		notes[i].Images = imagesByNoteID[notes[i].ID]
+	if err := loadNoteAssociations(conn, notes, DefaultNoteInclude); err != nil {
+		return nil, err
 	}
 
 	return notes, nil
 }
 
 // parseTagSearch extracts tag:tagname patterns from a search string.
-// Returns the extracted tag names and the remaining search text.
-var tagSearchRegex = regexp.MustCompile(`\btag:([a-z0-9]+)\b`)
+// Returns the extracted tag names, normalized like stored tags, and the
+// remaining search text.
+var tagSearchRegex = regexp.MustCompile(`(^|[^\p{L}\p{N}_])tag:([\p{L}\p{M}\p{N}]+)`)
 
 func parseTagSearch(search string) (tags []string, remaining string) {
 	matches := tagSearchRegex.FindAllStringSubmatch(search, -1)
 	for _, match := range matches {
-		if len(match) > 1 {
-			tags = append(tags, match[1])
+		if tag, ok := models.NormalizeTag(match[2]); ok {
+			tags = append(tags, tag)
 		}
 	}
 
 	// Remove the tag: patterns from the search string
-	remaining = tagSearchRegex.ReplaceAllString(search, "")
+	remaining = tagSearchRegex.ReplaceAllString(search, "$1")
 	remaining = strings.TrimSpace(remaining)
 	// Clean up multiple spaces
 	remaining = regexp.MustCompile(`\s+`).ReplaceAllString(remaining, " ")
@@ -1181,27 +2003,12 @@ func parseTagSearch(search string) (tags []string, remaining string) {
 	return tags, remaining
 }
 
-func normalizeTagNames(tagNames []string) []string {
-	if len(tagNames) == 0 {
-		return nil
-	}
-
-	normalized := make([]string, 0, len(tagNames))
-	for _, tagName := range tagNames {
-		tagName = strings.ToLower(strings.TrimSpace(tagName))
-		if tagName == "" {
-			continue
-		}
-		normalized = append(normalized, tagName)
-	}
-	return normalized
-}
-
 // GetStats retrieves statistics for a user or all users
 // If userID is empty, returns stats for all users
+// The word total sums every note's stored wordCount; it is not capped.
 func (db *DB) GetStats(ctx context.Context, userID string) (totalBlips, uniqueTags, wordsWritten int64, err error) {
 	// Count total blips (notes)
-	blipsQuery := db.conn.WithContext(ctx).Model(&Note{})
+	blipsQuery := db.readConn(ctx).Model(&Note{})
 	if userID != "" {
 		blipsQuery = blipsQuery.Where(`"userId" = ?`, userID)
 	}
@@ -1210,7 +2017,7 @@ func (db *DB) GetStats(ctx context.Context, userID string) (totalBlips, uniqueTa
 	}
 
 	// Count unique tags
-	tagsQuery := db.conn.WithContext(ctx).Model(&Tag{})
+	tagsQuery := db.readConn(ctx).Model(&Tag{})
 	if userID != "" {
 		tagsQuery = tagsQuery.Where(`"userId" = ?`, userID)
 	}
@@ -1218,44 +2025,49 @@ func (db *DB) GetStats(ctx context.Context, userID string) (totalBlips, uniqueTa
 		return 0, 0, 0, fmt.Errorf("failed to count tags: %w", err)
 	}
 
-	// Calculate total words written using batch processing to avoid memory issues
-	const batchSize = 1000
-	var offset int
-
-	for {
-		var notes []Note
-		notesQuery := db.conn.WithContext(ctx).Model(&Note{}).Select("content").Limit(batchSize).Offset(offset)
-		if userID != "" {
-			notesQuery = notesQuery.Where(`"userId" = ?`, userID)
-		}
-		if err = notesQuery.Find(&notes).Error; err != nil {
-			return 0, 0, 0, fmt.Errorf("failed to fetch notes for word count: %w", err)
-		}
-
-		// If no more notes, we're done
-		if len(notes) == 0 {
-			break
-		}
+	// Sum the word counts stored on each note
+	wordsQuery := db.readConn(ctx).Model(&Note{}).Select(`COALESCE(SUM("wordCount"), 0)`)
+	if userID != "" {
+		wordsQuery = wordsQuery.Where(`"userId" = ?`, userID)
+	}
+	if err = wordsQuery.Scan(&wordsWritten).Error; err != nil {
+		return 0, 0, 0, fmt.Errorf("failed to sum word counts: %w", err)
+	}
 
-		// Count words in this batch
-		for _, note := range notes {
-			wordsWritten += countWords(note.Content)
-		}
+	return totalBlips, uniqueTags, wordsWritten, nil
+}
 
-		// If we got fewer notes than the batch size, we're done
-		if len(notes) < batchSize {
-			break
-		}
+// MoodCount is the number of notes with one mood
+type MoodCount struct {
+	Mood  string
+	Count int64
+}
 
-		offset += batchSize
+// GetMoodBreakdown counts a user's notes by mood, counting only notes created
+// within the optional startDate and endDate bounds. Notes whose mood hasn't
+// been analyzed are left out. Results are ordered by count, highest first.
+func (db *DB) GetMoodBreakdown(ctx context.Context, userID, startDate, endDate string) ([]MoodCount, error) {
+	query := db.readConn(ctx).Model(&Note{}).
+		Select(`mood, COUNT(*) as count`).
+		Where(`"userId" = ? AND mood <> ?`, userID, "")
+	if startDate != "" {
+		query = query.Where(`"createdAt" >= ?`, startDate)
+	}
+	if endDate != "" {
+		query = query.Where(`"createdAt" <= ?`, endDate)
 	}
 
-	return totalBlips, uniqueTags, wordsWritten, nil
+	var moods []MoodCount
+	err := query.Group("mood").Order("count DESC, mood").Scan(&moods).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to count moods: %w", err)
+	}
+	return moods, nil
 }
 
-// countWords counts the number of words in a string
+// CountWords counts the number of words in a string
 // Words are defined as sequences of non-whitespace characters
-func countWords(text string) int64 {
+func CountWords(text string) int64 {
 	text = strings.TrimSpace(text)
 	if text == "" {
 		return 0
@@ -1,7 +1,6 @@
 package db
 
 import (
-	"reflect"
 	"testing"
 )
 
@@ -55,10 +54,22 @@ func TestParseTagSearch(t *testing.T) {
 			wantRemaining: "",
 		},
 		{
-			name:          "invalid tag format ignored",
+			name:          "uppercase tag normalized",
 			search:        "tag:Work tag:valid",
-			wantTags:      []string{"valid"},
-			wantRemaining: "tag:Work",
+			wantTags:      []string{"work", "valid"},
+			wantRemaining: "",
+		},
+		{
+			name:          "non-latin tag",
+			search:        "встреча tag:работа",
+			wantTags:      []string{"работа"},
+			wantRemaining: "встреча",
+		},
+		{
+			name:          "tag inside a word ignored",
+			search:        "mytag:work (tag:home)",
+			wantTags:      []string{"home"},
+			wantRemaining: "mytag:work ()",
 		},
 		{
 			name:          "tag at end of string",
@@ -139,42 +150,36 @@ func TestCountWords(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := countWords(tt.input)
+			got := CountWords(tt.input)
 			if got != tt.want {
-				t.Errorf("countWords(%q) = %d, want %d", tt.input, got, tt.want)
+				t.Errorf("CountWords(%q) = %d, want %d", tt.input, got, tt.want)
 			}
 		})
 	}
 }
 
-func TestNormalizeTagNames(t *testing.T) {
+func TestNoteFilterIsEmpty(t *testing.T) {
 	tests := []struct {
-		name string
-		in   []string
-		want []string
+		name   string
+		filter NoteFilter
+		want   bool
 	}{
-		{
-			name: "lowercases and trims values",
-			in:   []string{" Work ", "PERSONAL"},
-			want: []string{"work", "personal"},
-		},
-		{
-			name: "drops empty tags after normalization",
-			in:   []string{"", "   ", "\n", "valid"},
-			want: []string{"valid"},
-		},
-		{
-			name: "returns nil for empty input",
-			in:   []string{},
-			want: nil,
-		},
+		{name: "zero", filter: NoteFilter{}, want: true},
+		{name: "search options only", filter: NoteFilter{SearchCaptions: true, SearchMedia: true}, want: true},
+		{name: "blank search", filter: NoteFilter{Search: "   "}, want: true},
+		{name: "search text", filter: NoteFilter{Search: "hello"}, want: false},
+		{name: "tag search", filter: NoteFilter{Search: "tag:work"}, want: false},
+		{name: "tags", filter: NoteFilter{Tags: []string{"work"}}, want: false},
+		{name: "blank tag", filter: NoteFilter{Tags: []string{" "}}, want: true},
+		{name: "start date", filter: NoteFilter{StartDate: "2024-01-01"}, want: false},
+		{name: "end date", filter: NoteFilter{EndDate: "2024-12-31"}, want: false},
+		{name: "color", filter: NoteFilter{Color: "green"}, want: false},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := normalizeTagNames(tt.in)
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Fatalf("normalizeTagNames() = %v, want %v", got, tt.want)
+			if got := tt.filter.IsEmpty(); got != tt.want {
+				t.Errorf("IsEmpty() = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DuplicateNote identifies one note in a DuplicateGroup
+type DuplicateNote struct {
+	ID        string
+	CreatedAt time.Time
+}
+
+// DuplicateGroup is a set of notes whose content is identical once leading,
+// trailing, and repeated whitespace is ignored. Notes are oldest first.
+type DuplicateGroup struct {
+	ContentHash string
+	Notes       []DuplicateNote
+}
+
+// FindDuplicateNotes returns groups of a user's notes that share the same
+// normalized content. Notes without text content, such as image-only notes,
+// are never reported as duplicates.
+func (db *DB) FindDuplicateNotes(ctx context.Context, userID string) ([]DuplicateGroup, error) {
+	var rows []struct {
+		ID          string    `gorm:"column:id"`
+		CreatedAt   time.Time `gorm:"column:createdAt"`
+		ContentHash string    `gorm:"column:contentHash"`
+	}
+
+	duplicated := db.conn.Model(&Note{}).
+		Select(`"contentHash"`).
+		Where(`"userId" = ? AND btrim(content) <> ''`, userID).
+		Group(`"contentHash"`).
+		Having("COUNT(*) > 1")
+
+	err := db.conn.WithContext(ctx).Model(&Note{}).
+		Select(`id, "createdAt", "contentHash"`).
+		Where(`"userId" = ? AND "contentHash" IN (?)`, userID, duplicated).
+		Order(`"contentHash" ASC, "createdAt" ASC`).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate notes: %w", err)
+	}
+
+	var groups []DuplicateGroup
+	for _, row := range rows {
+		if len(groups) == 0 || groups[len(groups)-1].ContentHash != row.ContentHash {
+			groups = append(groups, DuplicateGroup{ContentHash: row.ContentHash})
+		}
+		group := &groups[len(groups)-1]
+		group.Notes = append(group.Notes, DuplicateNote{ID: row.ID, CreatedAt: row.CreatedAt})
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,82 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindDuplicateNotes(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	day1 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	day3 := day1.AddDate(0, 0, 2)
+
+	mock.ExpectQuery(`SELECT id, "createdAt", "contentHash" FROM "Note" WHERE "userId" = \$1 AND "contentHash" IN \(SELECT "contentHash" FROM "Note" WHERE "userId" = \$2 AND btrim\(content\) <> '' GROUP BY "contentHash" HAVING COUNT\(\*\) > 1\) ORDER BY "contentHash" ASC, "createdAt" ASC`).
+		WithArgs("user-1", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "createdAt", "contentHash"}).
+			AddRow("note-a1", day1, "aaa").
+			AddRow("note-a2", day3, "aaa").
+			AddRow("note-b1", day1, "bbb").
+			AddRow("note-b2", day2, "bbb").
+			AddRow("note-b3", day3, "bbb"))
+
+	groups, err := db.FindDuplicateNotes(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("FindDuplicateNotes: %v", err)
+	}
+
+	want := []DuplicateGroup{
+		{ContentHash: "aaa", Notes: []DuplicateNote{{ID: "note-a1", CreatedAt: day1}, {ID: "note-a2", CreatedAt: day3}}},
+		{ContentHash: "bbb", Notes: []DuplicateNote{{ID: "note-b1", CreatedAt: day1}, {ID: "note-b2", CreatedAt: day2}, {ID: "note-b3", CreatedAt: day3}}},
+	}
+	if diff := cmp.Diff(want, groups); diff != "" {
+		t.Errorf("FindDuplicateNotes mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestFindDuplicateNotes_None(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT id, "createdAt", "contentHash" FROM "Note"`).
+		WithArgs("user-1", "user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "createdAt", "contentHash"}))
+
+	groups, err := db.FindDuplicateNotes(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("FindDuplicateNotes: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("expected no groups, got %+v", groups)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
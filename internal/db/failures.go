@@ -0,0 +1,80 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RecordProcessingFailure records a failed processing attempt for a resource,
+// incrementing its attempt count if it has failed before
+func (db *DB) RecordProcessingFailure(ctx context.Context, resourceType, resourceID, errorMessage string) error {
+	now := time.Now()
+	failure := ProcessingFailure{
+		ResourceType:  resourceType,
+		ResourceID:    resourceID,
+		ErrorMessage:  errorMessage,
+		Attempts:      1,
+		LastAttemptAt: now,
+		CreatedAt:     now,
+	}
+
+	err := db.conn.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "resourceType"}, {Name: "resourceId"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"attempts":      gorm.Expr(`"ProcessingFailure".attempts + 1`),
+			"errorMessage":  errorMessage,
+			"lastAttemptAt": now,
+		}),
+	}).Create(&failure).Error
+	if err != nil {
+		return fmt.Errorf("failed to record processing failure: %w", err)
+	}
+	return nil
+}
+
+// ListProcessingFailures returns recorded failures, most recent first.
+// An empty resourceType returns failures of every type.
+func (db *DB) ListProcessingFailures(ctx context.Context, resourceType string) ([]ProcessingFailure, error) {
+	var failures []ProcessingFailure
+	query := db.conn.WithContext(ctx)
+	if resourceType != "" {
+		query = query.Where(`"resourceType" = ?`, resourceType)
+	}
+	if err := query.Order(`"lastAttemptAt" DESC`).Find(&failures).Error; err != nil {
+		return nil, fmt.Errorf("failed to list processing failures: %w", err)
+	}
+	return failures, nil
+}
+
+// ResetProcessingFailure clears the failure record for a resource so it will be retried
+func (db *DB) ResetProcessingFailure(ctx context.Context, resourceType, resourceID string) (bool, error) {
+	result := db.conn.WithContext(ctx).
+		Where(`"resourceType" = ? AND "resourceId" = ?`, resourceType, resourceID).
+		Delete(&ProcessingFailure{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reset processing failure: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetExhaustedResourceIDs returns the IDs of resources that have failed at
+// least maxAttempts times and should no longer be retried
+func (db *DB) GetExhaustedResourceIDs(ctx context.Context, resourceType string, maxAttempts int) (map[string]bool, error) {
+	var ids []string
+	err := db.conn.WithContext(ctx).Model(&ProcessingFailure{}).
+		Where(`"resourceType" = ? AND attempts >= ?`, resourceType, maxAttempts).
+		Pluck(`"resourceId"`, &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exhausted resources: %w", err)
+	}
+
+	exhausted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		exhausted[id] = true
+	}
+	return exhausted, nil
+}
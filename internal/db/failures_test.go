@@ -0,0 +1,137 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecordProcessingFailure_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// Repeated failures increment the existing row instead of inserting a new one
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "ProcessingFailure" (.+) ON CONFLICT \("resourceType","resourceId"\) DO UPDATE SET (.+)"ProcessingFailure".attempts \+ 1`).
+		WithArgs(
+			sqlmock.AnyArg(), "image", "img-1", "bad image", 1, sqlmock.AnyArg(), sqlmock.AnyArg(),
+			"bad image", sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := db.RecordProcessingFailure(context.Background(), "image", "img-1", "bad image"); err != nil {
+		t.Fatalf("RecordProcessingFailure: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestListProcessingFailures_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now().UTC()
+	mock.ExpectQuery(`SELECT (.+) FROM "ProcessingFailure" WHERE "resourceType" = (.+) ORDER BY "lastAttemptAt" DESC`).
+		WithArgs("audio").
+		WillReturnRows(sqlmock.NewRows([]string{
+			"id", "resourceType", "resourceId", "errorMessage", "attempts", "lastAttemptAt", "createdAt",
+		}).AddRow("pf-1", "audio", "audio-1", "transcription failed", 3, now, now))
+
+	failures, err := db.ListProcessingFailures(context.Background(), "audio")
+	if err != nil {
+		t.Fatalf("ListProcessingFailures: %v", err)
+	}
+	want := []ProcessingFailure{{
+		ID: "pf-1", ResourceType: "audio", ResourceID: "audio-1", ErrorMessage: "transcription failed",
+		Attempts: 3, LastAttemptAt: now, CreatedAt: now,
+	}}
+	if diff := cmp.Diff(want, failures); diff != "" {
+		t.Errorf("ListProcessingFailures mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestResetProcessingFailure_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "ProcessingFailure"`).
+		WithArgs("image", "img-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	reset, err := db.ResetProcessingFailure(context.Background(), "image", "img-1")
+	if err != nil {
+		t.Fatalf("ResetProcessingFailure: %v", err)
+	}
+	if !reset {
+		t.Error("ResetProcessingFailure: want true")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetExhaustedResourceIDs_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT "resourceId" FROM "ProcessingFailure" WHERE "resourceType" = (.+) AND attempts >= (.+)`).
+		WithArgs("image", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"resourceId"}).AddRow("img-1").AddRow("img-2"))
+
+	exhausted, err := db.GetExhaustedResourceIDs(context.Background(), "image", 5)
+	if err != nil {
+		t.Fatalf("GetExhaustedResourceIDs: %v", err)
+	}
+	if diff := cmp.Diff(map[string]bool{"img-1": true, "img-2": true}, exhausted); diff != "" {
+		t.Errorf("GetExhaustedResourceIDs mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
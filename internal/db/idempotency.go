@@ -0,0 +1,75 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKeyTTL is how long a CreateNote idempotency key keeps returning
+// the note it created. After that the key can be reused for a new note.
+const IdempotencyKeyTTL = 24 * time.Hour
+
+// ErrIdempotencyKeyConflict is returned by CreateNote when a concurrent
+// request created a note with the same idempotency key first
+var ErrIdempotencyKeyConflict = errors.New("idempotency key already used")
+
+// idempotencyKeyStoredAt is when a note's idempotency key was stored, falling
+// back to createdAt for notes keyed before "idempotencyKeyAt" existed
+const idempotencyKeyStoredAt = `COALESCE("idempotencyKeyAt", "createdAt")`
+
+// FindNoteByIdempotencyKey returns the user's note whose key was stored within
+// IdempotencyKeyTTL, or nil if there is none
+func (db *DB) FindNoteByIdempotencyKey(ctx context.Context, userID, key string) (*Note, error) {
+	var note Note
+	result := db.conn.WithContext(ctx).
+		Where(`"userId" = ? AND "idempotencyKey" = ? AND `+idempotencyKeyStoredAt+` >= ?`, userID, key, time.Now().Add(-IdempotencyKeyTTL)).
+		First(&note)
+	if result.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find note by idempotency key: %w", result.Error)
+	}
+
+	return db.GetNoteFromPrimary(ctx, userID, note.ID)
+}
+
+// IdempotencyKeyInUse reports whether any of the user's notes holds key,
+// however long ago it was stored. Imports use it instead of
+// FindNoteByIdempotencyKey so a row is still skipped by a run more than
+// IdempotencyKeyTTL after the one that imported it.
+func (db *DB) IdempotencyKeyInUse(ctx context.Context, userID, key string) (bool, error) {
+	var count int64
+	err := db.conn.WithContext(ctx).Model(&Note{}).
+		Where(`"userId" = ? AND "idempotencyKey" = ?`, userID, key).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to check idempotency key: %w", err)
+	}
+	return count > 0, nil
+}
+
+// clearExpiredIdempotencyKey releases key from a note that stored it more than
+// IdempotencyKeyTTL before now, so the unique index lets it be reused. Expiry
+// is measured from when the key was stored, not from the note's createdAt, so
+// a backdated import keeps its key for the full TTL.
+func clearExpiredIdempotencyKey(tx *gorm.DB, userID, key string, now time.Time) error {
+	err := tx.Model(&Note{}).
+		Where(`"userId" = ? AND "idempotencyKey" = ? AND `+idempotencyKeyStoredAt+` < ?`, userID, key, now.Add(-IdempotencyKeyTTL)).
+		UpdateColumn("idempotencyKey", nil).Error
+	if err != nil {
+		return fmt.Errorf("failed to clear expired idempotency key: %w", err)
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err is a Postgres unique constraint violation
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
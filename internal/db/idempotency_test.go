@@ -0,0 +1,267 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestCreateNote_IdempotencyKey(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "Note" SET "idempotencyKey"=\$1 WHERE "userId" = \$2 AND "idempotencyKey" = \$3 AND COALESCE\("idempotencyKeyAt", "createdAt"\) < \$4`).
+		WithArgs(nil, "user-1", "retry-1", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "Note" (.+)"idempotencyKey"`).
+		WithArgs(
+			sqlmock.AnyArg(), "hello", 1, sqlmock.AnyArg(), sqlmock.AnyArg(), "user-1",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), false, "", "", "", "retry-1", sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	note, err := db.CreateNote(context.Background(), "user-1", "hello", "", nil, "retry-1", time.Time{})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	if note.IdempotencyKey == nil || *note.IdempotencyKey != "retry-1" {
+		t.Errorf("IdempotencyKey = %v, want retry-1", note.IdempotencyKey)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestCreateNote_IdempotencyKeyConflict(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "Note" SET "idempotencyKey"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "Note"`).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_note_user_idempotency_key"})
+	mock.ExpectRollback()
+
+	_, err = db.CreateNote(context.Background(), "user-1", "hello", "", nil, "retry-1", time.Time{})
+	if !errors.Is(err, ErrIdempotencyKeyConflict) {
+		t.Fatalf("expected ErrIdempotencyKeyConflict, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+// recentTimeArg matches a time.Time within a minute of now
+type recentTimeArg struct{}
+
+func (recentTimeArg) Match(v driver.Value) bool {
+	t, ok := v.(time.Time)
+	return ok && time.Since(t).Abs() < time.Minute
+}
+
+func TestCreateNote_BackdatedImportRace(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// Two import runs both found the key free and now create the same
+	// backdated row. The first stores the key with the current time, so the
+	// second's expiry check, measured from when the key was stored rather
+	// than from the backdated createdAt, leaves it in place and the insert
+	// conflicts instead of creating a copy.
+	createdAt := time.Date(2019, 5, 4, 0, 0, 0, 0, time.UTC)
+	clearExpired := `UPDATE "Note" SET "idempotencyKey"=\$1 WHERE "userId" = \$2 AND "idempotencyKey" = \$3 AND COALESCE\("idempotencyKeyAt", "createdAt"\) < \$4`
+
+	mock.ExpectBegin()
+	mock.ExpectExec(clearExpired).
+		WithArgs(nil, "user-1", "import-abc", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "Note"`).
+		WithArgs(
+			sqlmock.AnyArg(), "old entry", 2, createdAt, sqlmock.AnyArg(), "user-1",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), false, "", "", "", "import-abc", recentTimeArg{},
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(clearExpired).
+		WithArgs(nil, "user-1", "import-abc", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO "Note"`).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_note_user_idempotency_key"})
+	mock.ExpectRollback()
+
+	if _, err := db.CreateNote(context.Background(), "user-1", "old entry", "", nil, "import-abc", createdAt); err != nil {
+		t.Fatalf("first CreateNote: %v", err)
+	}
+	_, err = db.CreateNote(context.Background(), "user-1", "old entry", "", nil, "import-abc", createdAt)
+	if !errors.Is(err, ErrIdempotencyKeyConflict) {
+		t.Fatalf("second CreateNote = %v, want ErrIdempotencyKeyConflict", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestFindNoteByIdempotencyKey_Expired(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// Only keys stored within the TTL match
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE "userId" = \$1 AND "idempotencyKey" = \$2 AND COALESCE\("idempotencyKeyAt", "createdAt"\) >= \$3`).
+		WithArgs("user-1", "retry-1", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	note, err := db.FindNoteByIdempotencyKey(context.Background(), "user-1", "retry-1")
+	if err != nil {
+		t.Fatalf("FindNoteByIdempotencyKey: %v", err)
+	}
+	if note != nil {
+		t.Errorf("FindNoteByIdempotencyKey = %+v, want nil", note)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestFindNoteByIdempotencyKey(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId", "idempotencyKey"}
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE "userId" = \$1 AND "idempotencyKey" = \$2`).
+		WithArgs("user-1", "retry-1", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("note-1", "hello", now, now, "user-1", "retry-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id = \$1`).
+		WithArgs("note-1", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("note-1", "hello", now, now, "user-1", "retry-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	note, err := db.FindNoteByIdempotencyKey(context.Background(), "user-1", "retry-1")
+	if err != nil {
+		t.Fatalf("FindNoteByIdempotencyKey: %v", err)
+	}
+	if note == nil || note.ID != "note-1" {
+		t.Errorf("FindNoteByIdempotencyKey = %+v, want note-1", note)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestIdempotencyKeyInUse(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "Note" WHERE "userId" = \$1 AND "idempotencyKey" = \$2`).
+		WithArgs("user-1", "import-abc").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "Note" WHERE "userId" = \$1 AND "idempotencyKey" = \$2`).
+		WithArgs("user-1", "import-def").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	inUse, err := db.IdempotencyKeyInUse(context.Background(), "user-1", "import-abc")
+	if err != nil {
+		t.Fatalf("IdempotencyKeyInUse: %v", err)
+	}
+	if !inUse {
+		t.Error("import-abc: got not in use, want in use")
+	}
+
+	inUse, err = db.IdempotencyKeyInUse(context.Background(), "user-1", "import-def")
+	if err != nil {
+		t.Fatalf("IdempotencyKeyInUse: %v", err)
+	}
+	if inUse {
+		t.Error("import-def: got in use, want not in use")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/icco/etu-backend/internal/models"
+)
+
+// MaxLoginEventLength caps the stored user agent and IP address so a client
+// cannot bloat the audit table with oversized headers
+const MaxLoginEventLength = 512
+
+// RecordLoginEvent stores an authentication attempt for a user
+func (db *DB) RecordLoginEvent(ctx context.Context, userID string, success bool, authType, ipAddress, userAgent string) error {
+	event := LoginEvent{
+		ID:        models.GenerateCUID(),
+		UserID:    userID,
+		Success:   success,
+		AuthType:  authType,
+		IPAddress: truncate(ipAddress, MaxLoginEventLength),
+		UserAgent: truncate(userAgent, MaxLoginEventLength),
+		CreatedAt: time.Now(),
+	}
+
+	if err := db.conn.WithContext(ctx).Create(&event).Error; err != nil {
+		return fmt.Errorf("failed to record login event: %w", err)
+	}
+	return nil
+}
+
+// ListLoginEvents returns a user's most recent authentication attempts,
+// newest first
+func (db *DB) ListLoginEvents(ctx context.Context, userID string, limit int) ([]LoginEvent, error) {
+	var events []LoginEvent
+	err := db.readConn(ctx).
+		Where(`"userId" = ?`, userID).
+		Order(`"createdAt" DESC`).
+		Limit(limit).
+		Find(&events).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list login events: %w", err)
+	}
+	return events, nil
+}
+
+// truncate cuts s to at most n bytes without splitting a UTF-8 sequence
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
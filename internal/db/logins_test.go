@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestRecordLoginEvent_TruncatesClientInfo(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	longAgent := strings.Repeat("a", MaxLoginEventLength+100)
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "LoginEvent"`).
+		WithArgs(sqlmock.AnyArg(), "user1", false, "apikey", "203.0.113.7", longAgent[:MaxLoginEventLength], sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := db.RecordLoginEvent(context.Background(), "user1", false, "apikey", "203.0.113.7", longAgent); err != nil {
+		t.Fatalf("RecordLoginEvent: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		n    int
+		want string
+	}{
+		{in: "short", n: 10, want: "short"},
+		{in: "abcdef", n: 3, want: "abc"},
+		// "é" is two bytes; cutting inside it drops the whole rune
+		{in: "aé", n: 2, want: "a"},
+	}
+
+	for _, tt := range tests {
+		if got := truncate(tt.in, tt.n); got != tt.want {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.in, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// MergeSeparator is placed between the contents of merged notes
+const MergeSeparator = "\n\n---\n\n"
+
+// ErrMergedContentTooLong is returned by MergeNotes when the merged content
+// would be longer than the caller's limit. Nothing is changed.
+var ErrMergedContentTooLong = errors.New("merged content is too long")
+
+// ErrMergeSourceInNotion is returned by MergeNotes when a source note is
+// linked to a Notion page. Deleting it would leave the page in Notion, and
+// the next full sync would bring it back as a new note. Nothing is changed.
+var ErrMergeSourceInNotion = errors.New("source note is linked to Notion")
+
+// MergeNotes folds the source notes into the target note in one transaction.
+// Source content is appended to the target oldest first, images and audio
+// files are moved onto the target after its own, and tags are unioned. The
+// sources are then deleted. Media rows are re-pointed rather than copied, so
+// no storage objects change hands.
+//
+// If maxContent is positive and the merged content would have more characters
+// than that, ErrMergedContentTooLong is returned and nothing is merged. A
+// source linked to a Notion page fails with ErrMergeSourceInNotion; the target
+// may be linked, since it is kept.
+//
+// Returns a nil note if the target or any source does not exist or belongs to
+// another user. orphaned lists object names still attached to a source at
+// deletion time; it should always be empty, but callers can delete these
+// objects from storage if not.
+func (db *DB) MergeNotes(ctx context.Context, userID, targetID string, sourceIDs []string, maxContent int) (note *Note, orphaned []string, err error) {
+	found := false
+
+	err = db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var target Note
+		result := tx.Where(`id = ? AND "userId" = ?`, targetID, userID).First(&target)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to get target note: %w", result.Error)
+		}
+
+		var sources []Note
+		if err := tx.Where(`id IN ? AND "userId" = ?`, sourceIDs, userID).Order(`"createdAt" ASC`).Find(&sources).Error; err != nil {
+			return fmt.Errorf("failed to get source notes: %w", err)
+		}
+		if len(sources) != len(sourceIDs) {
+			return nil
+		}
+		found = true
+
+		for _, src := range sources {
+			if (src.NotionUUID != nil && *src.NotionUUID != "") || (src.ExternalID != nil && *src.ExternalID != "") {
+				return fmt.Errorf("%w: %s", ErrMergeSourceInNotion, src.ID)
+			}
+		}
+
+		contents := []string{}
+		if strings.TrimSpace(target.Content) != "" {
+			contents = append(contents, target.Content)
+		}
+		for _, src := range sources {
+			if strings.TrimSpace(src.Content) != "" {
+				contents = append(contents, src.Content)
+			}
+		}
+		merged := strings.Join(contents, MergeSeparator)
+		if n := utf8.RuneCountInString(merged); maxContent > 0 && n > maxContent {
+			return fmt.Errorf("%w: %d characters, limit %d", ErrMergedContentTooLong, n, maxContent)
+		}
+
+		for _, src := range sources {
+			for _, model := range []interface{}{&NoteImage{}, &NoteAudio{}} {
+				offset, err := nextPosition(tx, model, targetID)
+				if err != nil {
+					return err
+				}
+				err = tx.Model(model).
+					Where(`"noteId" = ?`, src.ID).
+					Updates(map[string]interface{}{
+						"noteId":   targetID,
+						"position": gorm.Expr("position + ?", offset),
+					}).Error
+				if err != nil {
+					return fmt.Errorf("failed to move attachments from note %s: %w", src.ID, err)
+				}
+			}
+		}
+
+		err := tx.Exec(`INSERT INTO "NoteTag" ("noteId", "tagId") SELECT ?, "tagId" FROM "NoteTag" WHERE "noteId" IN ? ON CONFLICT DO NOTHING`, targetID, sourceIDs).Error
+		if err != nil {
+			return fmt.Errorf("failed to merge tags: %w", err)
+		}
+
+		err = tx.Model(&Note{}).Where("id = ?", targetID).Updates(map[string]interface{}{
+			"content":   merged,
+			"updatedAt": time.Now(),
+			"wordCount": CountWords(merged),
+		}).Error
+		if err != nil {
+			return fmt.Errorf("failed to update target note: %w", err)
+		}
+
+		for _, model := range []interface{}{&NoteImage{}, &NoteAudio{}} {
+			var names []string
+			if err := tx.Model(model).Where(`"noteId" IN ?`, sourceIDs).Pluck(`"gcsObjectName"`, &names).Error; err != nil {
+				return fmt.Errorf("failed to check for leftover attachments: %w", err)
+			}
+			orphaned = append(orphaned, names...)
+		}
+
+		if err := tx.Where(`id IN ? AND "userId" = ?`, sourceIDs, userID).Delete(&Note{}).Error; err != nil {
+			return fmt.Errorf("failed to delete source notes: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if !found {
+		return nil, nil, nil
+	}
+
+	note, err = db.GetNoteFromPrimary(ctx, userID, targetID)
+	if err != nil {
+		return nil, orphaned, fmt.Errorf("failed to reload merged note: %w", err)
+	}
+	return note, orphaned, nil
+}
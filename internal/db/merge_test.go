@@ -0,0 +1,135 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMergeNotes_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("target", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("target", "first", now.Add(-2*time.Hour), now, "user-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id IN \(\$1\) AND "userId" = \$2 ORDER BY "createdAt" ASC`).
+		WithArgs("source", "user-1").
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("source", "second", now.Add(-time.Hour), now, "user-1"))
+
+	// Attachments land after the target's own
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM "NoteImage"`).
+		WithArgs("target").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(2))
+	mock.ExpectExec(`UPDATE "NoteImage" SET "noteId"=\$1,"position"=position \+ \$2 WHERE "noteId" = \$3`).
+		WithArgs("target", 2, "source").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM "NoteAudio"`).
+		WithArgs("target").
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(0))
+	mock.ExpectExec(`UPDATE "NoteAudio" SET "noteId"=\$1,"position"=position \+ \$2 WHERE "noteId" = \$3`).
+		WithArgs("target", 0, "source").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	mock.ExpectExec(`INSERT INTO "NoteTag" \("noteId", "tagId"\) SELECT \$1, "tagId" FROM "NoteTag" WHERE "noteId" IN \(\$2\) ON CONFLICT DO NOTHING`).
+		WithArgs("target", "source").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "Note" SET "content"=\$1,"updatedAt"=\$2,"wordCount"=\$3 WHERE id = \$4`).
+		WithArgs("first"+MergeSeparator+"second", sqlmock.AnyArg(), int64(3), "target").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT "gcsObjectName" FROM "NoteImage" WHERE "noteId" IN \(\$1\)`).
+		WithArgs("source").
+		WillReturnRows(sqlmock.NewRows([]string{"gcsObjectName"}))
+	mock.ExpectQuery(`SELECT "gcsObjectName" FROM "NoteAudio" WHERE "noteId" IN \(\$1\)`).
+		WithArgs("source").
+		WillReturnRows(sqlmock.NewRows([]string{"gcsObjectName"}))
+	mock.ExpectExec(`DELETE FROM "Note" WHERE id IN \(\$1\) AND "userId" = \$2`).
+		WithArgs("source", "user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Reload
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("target", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("target", "first"+MergeSeparator+"second", now, now, "user-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("target").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}).AddRow("tag-1", "work", now, "user-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("target").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "gcsObjectName", "position"}).
+			AddRow("img-1", "target", "notes/source/img-1", 2))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("target").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	note, orphaned, err := db.MergeNotes(context.Background(), "user-1", "target", []string{"source"}, 0)
+	if err != nil {
+		t.Fatalf("MergeNotes: %v", err)
+	}
+	if note == nil || note.Content != "first"+MergeSeparator+"second" {
+		t.Fatalf("MergeNotes note = %+v", note)
+	}
+	if len(note.Images) != 1 || note.Images[0].GCSObjectName != "notes/source/img-1" {
+		t.Errorf("image should move without its object being renamed, got %+v", note.Images)
+	}
+	if len(orphaned) != 0 {
+		t.Errorf("orphaned = %v, want none", orphaned)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestMergeNotes_SourceNotFound(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId"}
+
+	// One of the sources belongs to another user, so nothing is changed
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id = \$1`).
+		WithArgs("target", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("target", "first", now, now, "user-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id IN`).
+		WithArgs("mine", "theirs", "user-1").
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("mine", "second", now, now, "user-1"))
+	mock.ExpectCommit()
+
+	note, _, err := db.MergeNotes(context.Background(), "user-1", "target", []string{"mine", "theirs"}, 0)
+	if err != nil {
+		t.Fatalf("MergeNotes: %v", err)
+	}
+	if note != nil {
+		t.Errorf("MergeNotes = %+v, want nil", note)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
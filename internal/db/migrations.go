@@ -0,0 +1,278 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/icco/etu-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// migration is a numbered schema or data change that AutoMigrate cannot
+// express, such as a backfill, a rename, or a dropped column. Each one runs
+// once, in its own transaction, and is recorded in SchemaMigration.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *gorm.DB) error
+}
+
+// errSkipMigration is returned by a migration that can't be applied on this
+// database yet, such as one needing an extension the role may not create. The
+// migration is rolled back and left unrecorded so it is retried on the next
+// start. Only optional migrations that nothing later depends on may skip.
+var errSkipMigration = errors.New("migration skipped")
+
+// migrations lists every numbered migration in the order they run. Append new
+// migrations with the next version; never renumber or edit one that has
+// shipped.
+var migrations = []migration{
+	{
+		version: 1,
+		name:    "backfill_attachment_positions",
+		up: func(tx *gorm.DB) error {
+			// Attachments uploaded before positions existed all sit at 0.
+			// Number them in upload order so reordering starts from a
+			// sensible baseline.
+			for _, table := range []string{"NoteImage", "NoteAudio"} {
+				err := tx.Exec(fmt.Sprintf(`UPDATE "%[1]s" SET position = ordered.rn
+FROM (SELECT id, ROW_NUMBER() OVER (PARTITION BY "noteId" ORDER BY "createdAt", id) - 1 AS rn FROM "%[1]s") AS ordered
+WHERE "%[1]s".id = ordered.id`, table)).Error
+				if err != nil {
+					return fmt.Errorf("failed to backfill %s positions: %w", table, err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		version: 2,
+		name:    "add_note_list_indexes",
+		up: func(tx *gorm.DB) error {
+			// ListNotes filters by user and sorts by creation
+			// time; tag lookups go from tag to notes, which the NoteTag
+			// primary key ("noteId", "tagId") can't serve.
+			for _, stmt := range noteListIndexes {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return fmt.Errorf("failed to create index: %w", err)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		version: 3,
+		name:    "backfill_note_word_counts",
+		up: func(tx *gorm.DB) error {
+			// Counted in Go rather than SQL so backfilled rows agree with
+			// what CountWords stores on write.
+			var notes []Note
+			return tx.Model(&Note{}).Select("id", "content").FindInBatches(&notes, 500, func(_ *gorm.DB, _ int) error {
+				for _, n := range notes {
+					err := tx.Model(&Note{}).Where("id = ?", n.ID).UpdateColumn("wordCount", CountWords(n.Content)).Error
+					if err != nil {
+						return fmt.Errorf("failed to backfill word count for note %s: %w", n.ID, err)
+					}
+				}
+				return nil
+			}).Error
+		},
+	},
+	{
+		version: 4,
+		name:    "add_note_pinned_index",
+		up: func(tx *gorm.DB) error {
+			// ListNotes now sorts pinned notes first, which the
+			// ("userId", "createdAt") index alone can't serve.
+			err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_note_user_pinned_created_at ON "Note" ("userId", pinned DESC, "createdAt" DESC)`).Error
+			if err != nil {
+				return fmt.Errorf("failed to create index: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		version: 5,
+		name:    "add_note_color_index",
+		up: func(tx *gorm.DB) error {
+			// ListNotes can filter by color. Most notes have none, so only
+			// colored ones are indexed.
+			err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_note_user_color ON "Note" ("userId", color) WHERE color <> ''`).Error
+			if err != nil {
+				return fmt.Errorf("failed to create index: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		version: 6,
+		name:    "merge_duplicate_tags",
+		up: func(tx *gorm.DB) error {
+			// Tags used to be matched by exact name, so a user could end up
+			// with "Work" and "work" as separate tags. Normalized in Go so
+			// merged names agree with models.NormalizeTag.
+			return mergeDuplicateTags(tx)
+		},
+	},
+	{
+		version: 7,
+		name:    "add_tag_user_name_unique_index",
+		up: func(tx *gorm.DB) error {
+			// Relies on migration 6 having merged existing duplicates.
+			err := tx.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tag_user_lower_name ON "Tag" ("userId", lower(name))`).Error
+			if err != nil {
+				return fmt.Errorf("failed to create index: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		version: 8,
+		name:    "add_api_key_prefix_index",
+		up: func(tx *gorm.DB) error {
+			// Every API key verification looks keys up by prefix. Prefixes
+			// are only 32 random bits, so they can collide and the index
+			// is not unique.
+			err := tx.Exec(`CREATE INDEX IF NOT EXISTS idx_api_key_prefix ON "ApiKey" ("keyPrefix")`).Error
+			if err != nil {
+				return fmt.Errorf("failed to create index: %w", err)
+			}
+			return nil
+		},
+	},
+	{
+		version: 9,
+		name:    "add_media_text_trigram_indexes",
+		up: func(tx *gorm.DB) error {
+			// search_media matches OCR text and transcripts with ILIKE,
+			// which only a trigram index can serve. Managed Postgres may
+			// not let the app role create extensions, in which case search
+			// keeps working unindexed until pg_trgm is enabled.
+			if err := tx.Exec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).Error; err != nil {
+				return fmt.Errorf("%w: pg_trgm extension unavailable: %v", errSkipMigration, err)
+			}
+			for _, stmt := range mediaTextIndexes {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return fmt.Errorf("failed to create index: %w", err)
+				}
+			}
+			return nil
+		},
+	},
+}
+
+// noteListIndexes are created by migration 2. NoteImage and NoteAudio already
+// get a "noteId" index from their model tags, so they aren't repeated here.
+var noteListIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_note_user_created_at ON "Note" ("userId", "createdAt" DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_note_tag_tag_id ON "NoteTag" ("tagId")`,
+}
+
+// mediaTextIndexes are created by migration 9 once pg_trgm is available.
+var mediaTextIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_note_image_extracted_text_trgm ON "NoteImage" USING gin ("extractedText" gin_trgm_ops)`,
+	`CREATE INDEX IF NOT EXISTS idx_note_audio_transcribed_text_trgm ON "NoteAudio" USING gin ("transcribedText" gin_trgm_ops)`,
+}
+
+// mergeDuplicateTags collapses each user's tags that normalize to the same name
+// into the oldest one. The survivor is renamed to the normalized name and
+// picks up the others' notes before they are deleted.
+func mergeDuplicateTags(tx *gorm.DB) error {
+	var tags []Tag
+	if err := tx.Select("id", "name", "userId").Order(`"createdAt", id`).Find(&tags).Error; err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	type key struct{ userID, name string }
+	survivors := make(map[key]string, len(tags))
+	for _, tag := range tags {
+		name, ok := models.NormalizeTag(tag.Name)
+		if !ok {
+			// Blank names can't be normalized, but exact repeats still
+			// have to go for the unique index to build
+			name = strings.ToLower(tag.Name)
+		}
+
+		k := key{tag.UserID, name}
+		survivorID, seen := survivors[k]
+		if !seen {
+			survivors[k] = tag.ID
+			if ok && tag.Name != name {
+				if err := tx.Model(&Tag{}).Where("id = ?", tag.ID).UpdateColumn("name", name).Error; err != nil {
+					return fmt.Errorf("failed to rename tag %s: %w", tag.ID, err)
+				}
+			}
+			continue
+		}
+
+		err := tx.Exec(`INSERT INTO "NoteTag" ("noteId", "tagId") SELECT "noteId", ? FROM "NoteTag" WHERE "tagId" = ? ON CONFLICT DO NOTHING`, survivorID, tag.ID).Error
+		if err != nil {
+			return fmt.Errorf("failed to move notes from tag %s: %w", tag.ID, err)
+		}
+		if err := tx.Exec(`DELETE FROM "NoteTag" WHERE "tagId" = ?`, tag.ID).Error; err != nil {
+			return fmt.Errorf("failed to unlink tag %s: %w", tag.ID, err)
+		}
+		if err := tx.Exec(`DELETE FROM "Tag" WHERE id = ?`, tag.ID).Error; err != nil {
+			return fmt.Errorf("failed to delete tag %s: %w", tag.ID, err)
+		}
+	}
+	return nil
+}
+
+// Migrate brings the schema up to date. It runs AutoMigrate to create tables
+// and add columns, then applies any numbered migrations not yet recorded.
+func (db *DB) Migrate(ctx context.Context) error {
+	if err := db.AutoMigrate(); err != nil {
+		return fmt.Errorf("failed to auto-migrate: %w", err)
+	}
+	return runMigrations(db.conn.WithContext(ctx), db.log.Info, migrations)
+}
+
+// runMigrations applies each migration not yet recorded in SchemaMigration.
+// Every migration takes the same advisory lock inside its transaction and
+// re-checks whether it was applied, so concurrently starting servers apply
+// each migration exactly once. A migration that returns errSkipMigration is
+// logged and left for the next run.
+func runMigrations(conn *gorm.DB, logf func(msg string, args ...any), migrations []migration) error {
+	for _, m := range migrations {
+		applied := false
+		err := conn.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext(?))`, "SchemaMigration").Error; err != nil {
+				return fmt.Errorf("failed to lock migrations: %w", err)
+			}
+
+			var count int64
+			if err := tx.Model(&SchemaMigration{}).Where("version = ?", m.version).Count(&count).Error; err != nil {
+				return fmt.Errorf("failed to check migration status: %w", err)
+			}
+			if count > 0 {
+				return nil
+			}
+
+			if err := m.up(tx); err != nil {
+				return err
+			}
+			applied = true
+
+			return tx.Create(&SchemaMigration{
+				Version:   m.version,
+				Name:      m.name,
+				AppliedAt: time.Now(),
+			}).Error
+		})
+		if errors.Is(err, errSkipMigration) {
+			logf("skipped migration, will retry on next start", "version", m.version, "name", m.name, "reason", err)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.version, m.name, err)
+		}
+		if applied {
+			logf("applied migration", "version", m.version, "name", m.name)
+		}
+	}
+	return nil
+}
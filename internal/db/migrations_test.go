@@ -0,0 +1,460 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/gorm"
+)
+
+func TestMigrations_Ordered(t *testing.T) {
+	names := map[string]bool{}
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migrations[%d].version = %d, want %d", i, m.version, i+1)
+		}
+		if m.name == "" || names[m.name] {
+			t.Errorf("migrations[%d] has empty or repeated name %q", i, m.name)
+		}
+		names[m.name] = true
+	}
+}
+
+func TestRunMigrations(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	var ran []int
+	testMigrations := []migration{
+		{version: 1, name: "already_applied", up: func(tx *gorm.DB) error { ran = append(ran, 1); return nil }},
+		{version: 2, name: "pending", up: func(tx *gorm.DB) error {
+			ran = append(ran, 2)
+			return tx.Exec(`UPDATE "Note" SET content = btrim(content)`).Error
+		}},
+	}
+
+	// Version 1 is recorded, so only its status check runs
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("SchemaMigration").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "SchemaMigration" WHERE version = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectCommit()
+
+	// Version 2 runs and is recorded in the same transaction
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("SchemaMigration").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "SchemaMigration" WHERE version = \$1`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`UPDATE "Note" SET content = btrim\(content\)`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(`INSERT INTO "SchemaMigration" \("version","name","appliedAt"\)`).
+		WithArgs(2, "pending", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	var logged []any
+	logf := func(msg string, args ...any) { logged = append(logged, args...) }
+	if err := runMigrations(db.conn, logf, testMigrations); err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+	if len(ran) != 1 || ran[0] != 2 {
+		t.Errorf("ran migrations %v, want [2]", ran)
+	}
+	if len(logged) == 0 {
+		t.Error("expected the applied migration to be logged")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestRunMigrations_StopsOnFailure(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	errBoom := errors.New("boom")
+	laterRan := false
+	testMigrations := []migration{
+		{version: 1, name: "fails", up: func(tx *gorm.DB) error { return errBoom }},
+		{version: 2, name: "later", up: func(tx *gorm.DB) error { laterRan = true; return nil }},
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "SchemaMigration"`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	err = runMigrations(db.conn, func(string, ...any) {}, testMigrations)
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected migration error, got %v", err)
+	}
+	if laterRan {
+		t.Error("migrations after a failure must not run")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestBackfillAttachmentPositions(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`UPDATE "NoteImage" SET position = ordered.rn\s+FROM \(SELECT id, ROW_NUMBER\(\) OVER \(PARTITION BY "noteId" ORDER BY "createdAt", id\) - 1 AS rn FROM "NoteImage"\)`).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectExec(`UPDATE "NoteAudio" SET position = ordered.rn`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	if err := migrations[0].up(db.conn); err != nil {
+		t.Fatalf("backfill_attachment_positions: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAddNoteListIndexes(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_note_user_created_at ON "Note" \("userId", "createdAt" DESC\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_note_tag_tag_id ON "NoteTag" \("tagId"\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := migrations[1].up(db.conn); err != nil {
+		t.Fatalf("add_note_list_indexes: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestBackfillNoteWordCounts(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT "id","content" FROM "Note" ORDER BY "Note"."id" LIMIT \$1`).
+		WithArgs(500).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content"}).
+			AddRow("note-1", "one two  three").
+			AddRow("note-2", "   "))
+	for _, want := range []struct {
+		id    string
+		count int64
+	}{{"note-1", 3}, {"note-2", 0}} {
+		mock.ExpectBegin()
+		mock.ExpectExec(`UPDATE "Note" SET "wordCount"=\$1 WHERE id = \$2`).
+			WithArgs(want.count, want.id).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	if err := migrations[2].up(db.conn); err != nil {
+		t.Fatalf("backfill_note_word_counts: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAddNotePinnedIndex(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_note_user_pinned_created_at ON "Note" \("userId", pinned DESC, "createdAt" DESC\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := migrations[3].up(db.conn); err != nil {
+		t.Fatalf("add_note_pinned_index: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAddNoteColorIndex(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_note_user_color ON "Note" \("userId", color\) WHERE color <> ''`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := migrations[4].up(db.conn); err != nil {
+		t.Fatalf("add_note_color_index: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestMergeDuplicateTags(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// Oldest first: "Work" survives as "work" and absorbs "work " and
+	// "#work"; user-2's "work" is a different user's tag and is left alone
+	mock.ExpectQuery(`SELECT "id","name","userId" FROM "Tag" ORDER BY "createdAt", id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "userId"}).
+			AddRow("tag-1", "Work", "user-1").
+			AddRow("tag-2", "home", "user-1").
+			AddRow("tag-3", "work ", "user-1").
+			AddRow("tag-4", "work", "user-2").
+			AddRow("tag-5", "#work", "user-1"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "Tag" SET "name"=\$1 WHERE id = \$2`).
+		WithArgs("work", "tag-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	for _, dup := range []string{"tag-3", "tag-5"} {
+		mock.ExpectExec(`INSERT INTO "NoteTag" \("noteId", "tagId"\) SELECT "noteId", \$1 FROM "NoteTag" WHERE "tagId" = \$2 ON CONFLICT DO NOTHING`).
+			WithArgs("tag-1", dup).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM "NoteTag" WHERE "tagId" = \$1`).
+			WithArgs(dup).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec(`DELETE FROM "Tag" WHERE id = \$1`).
+			WithArgs(dup).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+
+	if err := migrations[5].up(db.conn); err != nil {
+		t.Fatalf("merge_duplicate_tags: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAddTagUserNameUniqueIndex(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_tag_user_lower_name ON "Tag" \("userId", lower\(name\)\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := migrations[6].up(db.conn); err != nil {
+		t.Fatalf("add_tag_user_name_unique_index: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAddApiKeyPrefixIndex(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_api_key_prefix ON "ApiKey" \("keyPrefix"\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := migrations[7].up(db.conn); err != nil {
+		t.Fatalf("add_api_key_prefix_index: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAddMediaTextTrigramIndexes(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_note_image_extracted_text_trgm ON "NoteImage" USING gin \("extractedText" gin_trgm_ops\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`CREATE INDEX IF NOT EXISTS idx_note_audio_transcribed_text_trgm ON "NoteAudio" USING gin \("transcribedText" gin_trgm_ops\)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	if err := migrations[8].up(db.conn); err != nil {
+		t.Fatalf("add_media_text_trigram_indexes: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAddMediaTextTrigramIndexes_NoExtension(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectExec(`CREATE EXTENSION IF NOT EXISTS pg_trgm`).
+		WillReturnError(errors.New("permission denied to create extension"))
+
+	err = migrations[8].up(db.conn)
+	if !errors.Is(err, errSkipMigration) {
+		t.Fatalf("expected errSkipMigration, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestRunMigrations_SkipIsRetried(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	laterRan := false
+	testMigrations := []migration{
+		{version: 1, name: "optional", up: func(tx *gorm.DB) error { return errSkipMigration }},
+		{version: 2, name: "later", up: func(tx *gorm.DB) error { laterRan = true; return nil }},
+	}
+
+	// The skipped migration is rolled back without being recorded
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "SchemaMigration"`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectRollback()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "SchemaMigration"`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(`INSERT INTO "SchemaMigration"`).
+		WithArgs(2, "later", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := runMigrations(db.conn, func(string, ...any) {}, testMigrations); err != nil {
+		t.Fatalf("runMigrations: %v", err)
+	}
+	if !laterRan {
+		t.Error("migrations after a skipped one should still run")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default connection pool settings, overridable with DB_MAX_OPEN_CONNS,
+// DB_MAX_IDLE_CONNS, and DB_CONN_MAX_LIFETIME
+const (
+	DefaultMaxOpenConns    = 25
+	DefaultMaxIdleConns    = 5
+	DefaultConnMaxLifetime = 5 * time.Minute
+)
+
+// PoolConfig holds database/sql connection pool limits. Zero means no limit,
+// matching database/sql.
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+}
+
+// PoolConfigFromEnv reads pool settings from the environment, using the
+// defaults for unset variables. Negative or unparseable values are an error
+// rather than silently ignored, since a bad pool size is easy to miss until
+// Postgres runs out of connections.
+func PoolConfigFromEnv() (PoolConfig, error) {
+	cfg := PoolConfig{
+		MaxOpenConns:    DefaultMaxOpenConns,
+		MaxIdleConns:    DefaultMaxIdleConns,
+		ConnMaxLifetime: DefaultConnMaxLifetime,
+	}
+
+	var err error
+	if cfg.MaxOpenConns, err = intFromEnv("DB_MAX_OPEN_CONNS", cfg.MaxOpenConns); err != nil {
+		return PoolConfig{}, err
+	}
+	if cfg.MaxIdleConns, err = intFromEnv("DB_MAX_IDLE_CONNS", cfg.MaxIdleConns); err != nil {
+		return PoolConfig{}, err
+	}
+
+	if value := os.Getenv("DB_CONN_MAX_LIFETIME"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return PoolConfig{}, fmt.Errorf("DB_CONN_MAX_LIFETIME must be a non-negative duration, got %q", value)
+		}
+		cfg.ConnMaxLifetime = d
+	}
+
+	return cfg, nil
+}
+
+// Apply sets the pool limits on sqlDB
+func (cfg PoolConfig) Apply(sqlDB *sql.DB) {
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+}
+
+// intFromEnv reads a non-negative integer from envVar, returning defaultValue
+// when it is unset
+func intFromEnv(envVar string, defaultValue int) (int, error) {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer, got %q", envVar, value)
+	}
+	return n, nil
+}
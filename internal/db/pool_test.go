@@ -0,0 +1,62 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPoolConfigFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		open     string
+		idle     string
+		lifetime string
+		want     PoolConfig
+		wantErr  bool
+	}{
+		{
+			name: "defaults",
+			want: PoolConfig{MaxOpenConns: 25, MaxIdleConns: 5, ConnMaxLifetime: 5 * time.Minute},
+		},
+		{
+			name:     "overrides",
+			open:     "8",
+			idle:     "2",
+			lifetime: "30m",
+			want:     PoolConfig{MaxOpenConns: 8, MaxIdleConns: 2, ConnMaxLifetime: 30 * time.Minute},
+		},
+		{
+			name: "zero means unlimited",
+			open: "0",
+			want: PoolConfig{MaxOpenConns: 0, MaxIdleConns: 5, ConnMaxLifetime: 5 * time.Minute},
+		},
+		{name: "negative open conns", open: "-1", wantErr: true},
+		{name: "non-numeric idle conns", idle: "lots", wantErr: true},
+		{name: "negative lifetime", lifetime: "-5m", wantErr: true},
+		{name: "lifetime without unit", lifetime: "300", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_MAX_OPEN_CONNS", tt.open)
+			t.Setenv("DB_MAX_IDLE_CONNS", tt.idle)
+			t.Setenv("DB_CONN_MAX_LIFETIME", tt.lifetime)
+
+			got, err := PoolConfigFromEnv()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("PoolConfigFromEnv: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("PoolConfigFromEnv mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrStorageQuotaExceeded is returned by ReserveStorage when the reservation
+// would take a user over their storage quota
+var ErrStorageQuotaExceeded = errors.New("storage quota exceeded")
+
+// StorageReservationTTL is how long a reservation holds quota if it is never
+// released, e.g. because the server crashed mid-upload
+const StorageReservationTTL = 15 * time.Minute
+
+// GetStorageUsage returns the total stored bytes and number of image and
+// audio objects attached to a user's notes
+func (db *DB) GetStorageUsage(ctx context.Context, userID string) (totalBytes, objectCount int64, err error) {
+	return storageUsage(db.conn.WithContext(ctx), userID)
+}
+
+// storageUsage sums media sizes for a user using conn, which may be a transaction
+func storageUsage(conn *gorm.DB, userID string) (totalBytes, objectCount int64, err error) {
+	for _, table := range []string{"NoteImage", "NoteAudio"} {
+		var usage struct {
+			TotalBytes  int64
+			ObjectCount int64
+		}
+		err = conn.
+			Table(fmt.Sprintf(`"%s"`, table)).
+			Select(fmt.Sprintf(`COALESCE(SUM("%s"."sizeBytes"), 0) AS total_bytes, COUNT(*) AS object_count`, table)).
+			Joins(fmt.Sprintf(`JOIN "Note" ON "Note".id = "%s"."noteId"`, table)).
+			Where(`"Note"."userId" = ?`, userID).
+			Scan(&usage).Error
+		if err != nil {
+			return 0, 0, fmt.Errorf("failed to sum %s storage usage: %w", table, err)
+		}
+		totalBytes += usage.TotalBytes
+		objectCount += usage.ObjectCount
+	}
+
+	return totalBytes, objectCount, nil
+}
+
+// ReserveStorage reserves sizeBytes of a user's quotaBytes before an upload
+// and returns the reservation ID to release once the upload is saved.
+// Reservations for the same user are serialized with an advisory lock, so
+// concurrent uploads cannot both fit under the quota. Returns an error
+// wrapping ErrStorageQuotaExceeded when the upload would not fit.
+func (db *DB) ReserveStorage(ctx context.Context, userID string, sizeBytes, quotaBytes int64) (string, error) {
+	var reservationID string
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`SELECT pg_advisory_xact_lock(hashtext(?))`, userID).Error; err != nil {
+			return fmt.Errorf("failed to lock storage quota: %w", err)
+		}
+
+		now := time.Now()
+		if err := tx.Where(`"userId" = ? AND "expiresAt" <= ?`, userID, now).Delete(&StorageReservation{}).Error; err != nil {
+			return fmt.Errorf("failed to remove expired reservations: %w", err)
+		}
+
+		used, _, err := storageUsage(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		var reserved int64
+		err = tx.Model(&StorageReservation{}).
+			Select(`COALESCE(SUM("sizeBytes"), 0)`).
+			Where(`"userId" = ?`, userID).
+			Scan(&reserved).Error
+		if err != nil {
+			return fmt.Errorf("failed to sum storage reservations: %w", err)
+		}
+
+		if used+reserved+sizeBytes > quotaBytes {
+			return fmt.Errorf("%w: %d bytes used, %d reserved, %d requested, quota %d", ErrStorageQuotaExceeded, used, reserved, sizeBytes, quotaBytes)
+		}
+
+		reservation := StorageReservation{
+			UserID:    userID,
+			SizeBytes: sizeBytes,
+			ExpiresAt: now.Add(StorageReservationTTL),
+			CreatedAt: now,
+		}
+		if err := tx.Create(&reservation).Error; err != nil {
+			return fmt.Errorf("failed to create storage reservation: %w", err)
+		}
+		reservationID = reservation.ID
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return reservationID, nil
+}
+
+// ReleaseStorage deletes a reservation once its uploads are saved or abandoned
+func (db *DB) ReleaseStorage(ctx context.Context, reservationID string) error {
+	if err := db.conn.WithContext(ctx).Where("id = ?", reservationID).Delete(&StorageReservation{}).Error; err != nil {
+		return fmt.Errorf("failed to release storage reservation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectReservationChecks sets up the queries ReserveStorage runs before
+// deciding whether the reservation fits.
+func expectReservationChecks(mock sqlmock.Sqlmock, userID string, imageBytes, audioBytes, reservedBytes int64) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\(\$1\)\)`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "StorageReservation" WHERE "userId" = \$1 AND "expiresAt" <= \$2`).
+		WithArgs(userID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "NoteImage"`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(imageBytes, 1))
+	mock.ExpectQuery(`FROM "NoteAudio"`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(audioBytes, 1))
+	mock.ExpectQuery(`SELECT COALESCE\(SUM\("sizeBytes"\), 0\) FROM "StorageReservation" WHERE "userId" = \$1`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(reservedBytes))
+}
+
+func TestReserveStorage_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// 400 used + 300 reserved + 300 requested fits exactly in a 1000 byte quota
+	expectReservationChecks(mock, "user-1", 300, 100, 300)
+	mock.ExpectExec(`INSERT INTO "StorageReservation"`).
+		WithArgs(sqlmock.AnyArg(), "user-1", int64(300), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	id, err := db.ReserveStorage(context.Background(), "user-1", 300, 1000)
+	if err != nil {
+		t.Fatalf("ReserveStorage: %v", err)
+	}
+	if id == "" {
+		t.Error("ReserveStorage returned empty reservation ID")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestReserveStorage_QuotaExceeded(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// An in-flight reservation from a concurrent upload counts against the quota
+	expectReservationChecks(mock, "user-1", 300, 100, 500)
+	mock.ExpectRollback()
+
+	_, err = db.ReserveStorage(context.Background(), "user-1", 101, 1000)
+	if !errors.Is(err, ErrStorageQuotaExceeded) {
+		t.Fatalf("ReserveStorage error = %v, want ErrStorageQuotaExceeded", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestReleaseStorage_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "StorageReservation" WHERE id = \$1`).
+		WithArgs("res-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := db.ReleaseStorage(context.Background(), "res-1"); err != nil {
+		t.Fatalf("ReleaseStorage: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
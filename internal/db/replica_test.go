@@ -0,0 +1,119 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// newTestDBWithReplica returns a DB whose primary and replica are separate
+// sqlmocks, so tests can assert which one a query went to
+func newTestDBWithReplica(t *testing.T) (*DB, sqlmock.Sqlmock, sqlmock.Sqlmock) {
+	t.Helper()
+
+	primaryDB, primary, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = primaryDB.Close() })
+
+	replicaDB, replica, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = replicaDB.Close() })
+
+	db, err := NewFromConn(primaryDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+	db.replica, err = gorm.Open(postgres.New(postgres.Config{Conn: replicaDB}), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open replica: %v", err)
+	}
+
+	return db, primary, replica
+}
+
+func TestGetNote_UsesReplica(t *testing.T) {
+	db, primary, replica := newTestDBWithReplica(t)
+
+	now := time.Now()
+	replica.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note-1", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note-1", "hello", now, now, "user-1"))
+	replica.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	replica.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	replica.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	note, err := db.GetNote(context.Background(), "user-1", "note-1", DefaultNoteInclude)
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if note == nil || note.ID != "note-1" {
+		t.Errorf("GetNote = %+v", note)
+	}
+
+	if err := replica.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled replica expectations: %v", err)
+	}
+	if err := primary.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected primary queries: %v", err)
+	}
+}
+
+func TestGetNoteFromPrimary_SkipsReplica(t *testing.T) {
+	db, primary, replica := newTestDBWithReplica(t)
+
+	primary.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note-1", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := db.GetNoteFromPrimary(context.Background(), "user-1", "note-1"); err != nil {
+		t.Fatalf("GetNoteFromPrimary: %v", err)
+	}
+
+	if err := primary.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled primary expectations: %v", err)
+	}
+	if err := replica.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected replica queries: %v", err)
+	}
+}
+
+func TestListTags_UsesReplica(t *testing.T) {
+	db, primary, replica := newTestDBWithReplica(t)
+
+	replica.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "count"}).AddRow("tag-1", "work", 2))
+
+	tags, err := db.ListTags(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("ListTags: %v", err)
+	}
+	if len(tags) != 1 {
+		t.Errorf("ListTags returned %d tags, want 1", len(tags))
+	}
+
+	if err := replica.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled replica expectations: %v", err)
+	}
+	if err := primary.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected primary queries: %v", err)
+	}
+}
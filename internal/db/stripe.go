@@ -0,0 +1,45 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ApplyStripeSubscriptionEvent updates a user's subscription status and end
+// from Stripe event eventID, unless that event was already processed.
+// Recording the event and updating the user happen in one transaction, so a
+// redelivered event is applied exactly once even when deliveries overlap.
+// Returns false, without changing the user, for an already processed event.
+func (db *DB) ApplyStripeSubscriptionEvent(ctx context.Context, eventID, eventType, userID, subscriptionStatus string, subscriptionEnd *time.Time) (bool, error) {
+	applied := false
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A concurrent delivery of the same event blocks here until the
+		// other transaction finishes, then inserts nothing if it committed
+		event := ProcessedStripeEvent{ID: eventID, Type: eventType, ProcessedAt: time.Now()}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&event)
+		if result.Error != nil {
+			return fmt.Errorf("failed to record stripe event: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		// A user deleted since the lookup has nothing to update; the event
+		// is still recorded so redeliveries are skipped
+		if _, err := updateUserSubscription(tx, userID, subscriptionStatus, nil, subscriptionEnd); err != nil {
+			return err
+		}
+		applied = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return applied, nil
+}
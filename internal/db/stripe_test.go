@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func newStripeTestDB(t *testing.T) (*DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+	return db, mock
+}
+
+func TestApplyStripeSubscriptionEvent_SQL(t *testing.T) {
+	db, mock := newStripeTestDB(t)
+	end := time.Date(2026, 11, 16, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "ProcessedStripeEvent" \("id","type","processedAt"\) VALUES \(\$1,\$2,\$3\) ON CONFLICT DO NOTHING`).
+		WithArgs("evt_1", "customer.subscription.updated", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "User" SET "subscriptionEnd"=\$1,"subscriptionStatus"=\$2,"updatedAt"=\$3 WHERE id = \$4`).
+		WithArgs(end, "active", sqlmock.AnyArg(), "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	applied, err := db.ApplyStripeSubscriptionEvent(context.Background(), "evt_1", "customer.subscription.updated", "user1", "active", &end)
+	if err != nil {
+		t.Fatalf("ApplyStripeSubscriptionEvent: %v", err)
+	}
+	if !applied {
+		t.Error("applied = false, want true for a new event")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestApplyStripeSubscriptionEvent_AlreadyProcessed(t *testing.T) {
+	db, mock := newStripeTestDB(t)
+
+	// The event row already exists, so the user is not updated
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "ProcessedStripeEvent" (.+) ON CONFLICT DO NOTHING`).
+		WithArgs("evt_1", "customer.subscription.updated", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	applied, err := db.ApplyStripeSubscriptionEvent(context.Background(), "evt_1", "customer.subscription.updated", "user1", "active", nil)
+	if err != nil {
+		t.Fatalf("ApplyStripeSubscriptionEvent: %v", err)
+	}
+	if applied {
+		t.Error("applied = true, want false for an already processed event")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestApplyStripeSubscriptionEvent_UpdateFailsRollsBack(t *testing.T) {
+	db, mock := newStripeTestDB(t)
+
+	// Rolling back removes the event row, so Stripe's retry is applied
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "ProcessedStripeEvent"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "User"`).
+		WillReturnError(errors.New("connection reset"))
+	mock.ExpectRollback()
+
+	applied, err := db.ApplyStripeSubscriptionEvent(context.Background(), "evt_1", "customer.subscription.updated", "user1", "active", nil)
+	if err == nil {
+		t.Fatal("expected error when the update fails")
+	}
+	if applied {
+		t.Error("applied = true, want false on error")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
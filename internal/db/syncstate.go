@@ -0,0 +1,21 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// DeleteSyncStateForUser removes a user's Notion sync watermark and full-sync
+// cursor, so their next sync is a full one
+func (db *DB) DeleteSyncStateForUser(ctx context.Context, userID string) error {
+	return deleteSyncState(db.conn.WithContext(ctx), userID)
+}
+
+func deleteSyncState(tx *gorm.DB, userID string) error {
+	if err := tx.Where(`"userId" = ?`, userID).Delete(&SyncState{}).Error; err != nil {
+		return fmt.Errorf("failed to delete sync state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDeleteSyncStateForUser_SQL(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "SyncState" WHERE "userId" = \$1`).
+		WithArgs("user-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := db.DeleteSyncStateForUser(context.Background(), "user-1"); err != nil {
+		t.Fatalf("DeleteSyncStateForUser: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestUpdateUserSettings_ClearNotionKeyDeletesSyncState(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	userID := "user-up"
+	now := time.Now().UTC()
+	cleared := ""
+
+	mock.ExpectQuery(`SELECT (.+) FROM "User"`).
+		WithArgs(userID, 1).
+		WillReturnRows(sqlmock.NewRows(userRowColumns).
+			AddRow(userID, "u@ex.com", nil, nil, "hash", "free", nil, now, nil, "secret_abc", nil, now))
+	// The key and the sync state are cleared in one transaction
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "User" SET "notionKey"=\$1,"updatedAt"=\$2 WHERE "id" = \$3`).
+		WithArgs("", sqlmock.AnyArg(), userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM "SyncState" WHERE "userId" = \$1`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT (.+) FROM "User"`).
+		WithArgs(userID, userID, 1).
+		WillReturnRows(sqlmock.NewRows(userRowColumns).
+			AddRow(userID, "u@ex.com", nil, nil, "hash", "free", nil, now, nil, "", nil, now))
+
+	if _, err := db.UpdateUserSettings(context.Background(), userID, &cleared, nil, nil, nil, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateUserSettings: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/icco/etu-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// normalizeTemplateTags normalizes tag names like CreateNote does, storing an
+// empty list rather than null
+func normalizeTemplateTags(tags []string) []string {
+	normalized := models.NormalizeTags(tags)
+	if normalized == nil {
+		return []string{}
+	}
+	return normalized
+}
+
+// CreateTemplate saves a new note template for the user
+func (db *DB) CreateTemplate(ctx context.Context, userID, name, content string, tags []string) (*NoteTemplate, error) {
+	now := time.Now()
+	template := NoteTemplate{
+		ID:        models.GenerateCUID(),
+		UserID:    userID,
+		Name:      name,
+		Content:   content,
+		Tags:      normalizeTemplateTags(tags),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := db.conn.WithContext(ctx).Create(&template).Error; err != nil {
+		return nil, fmt.Errorf("failed to create template: %w", err)
+	}
+	return &template, nil
+}
+
+// ListTemplates returns the user's templates ordered by name
+func (db *DB) ListTemplates(ctx context.Context, userID string) ([]NoteTemplate, error) {
+	var templates []NoteTemplate
+	err := db.readConn(ctx).
+		Where(`"userId" = ?`, userID).
+		Order("name, id").
+		Find(&templates).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list templates: %w", err)
+	}
+	return templates, nil
+}
+
+// GetTemplate returns one of the user's templates, or nil if it does not
+// exist or belongs to another user
+func (db *DB) GetTemplate(ctx context.Context, userID, templateID string) (*NoteTemplate, error) {
+	var template NoteTemplate
+	result := db.readConn(ctx).Where(`id = ? AND "userId" = ?`, templateID, userID).First(&template)
+	if result.Error == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get template: %w", result.Error)
+	}
+	return &template, nil
+}
+
+// UpdateTemplate updates one of the user's templates. Name and content are
+// left alone when nil, and tags are only replaced when updateTags is set.
+// Returns nil if the template does not exist or belongs to another user.
+func (db *DB) UpdateTemplate(ctx context.Context, userID, templateID string, name, content *string, tags []string, updateTags bool) (*NoteTemplate, error) {
+	var template NoteTemplate
+	found := false
+
+	err := db.conn.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where(`id = ? AND "userId" = ?`, templateID, userID).First(&template)
+		if result.Error == gorm.ErrRecordNotFound {
+			return nil
+		}
+		if result.Error != nil {
+			return fmt.Errorf("failed to get template: %w", result.Error)
+		}
+		found = true
+
+		if name != nil {
+			template.Name = *name
+		}
+		if content != nil {
+			template.Content = *content
+		}
+		if updateTags {
+			template.Tags = normalizeTemplateTags(tags)
+		}
+		template.UpdatedAt = time.Now()
+
+		if err := tx.Save(&template).Error; err != nil {
+			return fmt.Errorf("failed to update template: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, nil
+	}
+	return &template, nil
+}
+
+// DeleteTemplate deletes one of the user's templates. Returns false if it
+// does not exist or belongs to another user.
+func (db *DB) DeleteTemplate(ctx context.Context, userID, templateID string) (bool, error) {
+	result := db.conn.WithContext(ctx).Where(`id = ? AND "userId" = ?`, templateID, userID).Delete(&NoteTemplate{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete template: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
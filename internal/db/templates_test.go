@@ -0,0 +1,223 @@
+package db
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var templateColumns = []string{"id", "userId", "name", "content", "tags", "createdAt", "updatedAt"}
+
+func TestCreateTemplate(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// Tags are normalized and stored as a JSON list
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "NoteTemplate" \("id","userId","name","content","tags","createdAt","updatedAt"\)`).
+		WithArgs(sqlmock.AnyArg(), "user-1", "Standup", "- yesterday\n- today", `["work","meetings"]`, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	template, err := db.CreateTemplate(context.Background(), "user-1", "Standup", "- yesterday\n- today", []string{"Work", "#meetings", "work"})
+	if err != nil {
+		t.Fatalf("CreateTemplate: %v", err)
+	}
+	if template.ID == "" || !slices.Equal(template.Tags, []string{"work", "meetings"}) {
+		t.Errorf("got %+v, want an ID and normalized tags", template)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestListTemplates(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT \* FROM "NoteTemplate" WHERE "userId" = \$1 ORDER BY name, id`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows(templateColumns).
+			AddRow("tpl-1", "user-1", "Groceries", "- [ ] milk", `["shopping"]`, now, now).
+			AddRow("tpl-2", "user-1", "Standup", "- today", `[]`, now, now))
+
+	templates, err := db.ListTemplates(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("ListTemplates: %v", err)
+	}
+	if len(templates) != 2 || !slices.Equal(templates[0].Tags, []string{"shopping"}) || len(templates[1].Tags) != 0 {
+		t.Errorf("got %+v, want two templates with decoded tags", templates)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetTemplate(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT \* FROM "NoteTemplate" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("tpl-1", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows(templateColumns).
+			AddRow("tpl-1", "user-1", "Groceries", "- [ ] milk", `["shopping"]`, now, now))
+	// Another user's template is not found
+	mock.ExpectQuery(`SELECT \* FROM "NoteTemplate" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("tpl-1", "user-2", 1).
+		WillReturnRows(sqlmock.NewRows(templateColumns))
+
+	template, err := db.GetTemplate(context.Background(), "user-1", "tpl-1")
+	if err != nil {
+		t.Fatalf("GetTemplate: %v", err)
+	}
+	if template == nil || template.Name != "Groceries" {
+		t.Errorf("got %+v, want the Groceries template", template)
+	}
+
+	template, err = db.GetTemplate(context.Background(), "user-2", "tpl-1")
+	if err != nil {
+		t.Fatalf("GetTemplate: %v", err)
+	}
+	if template != nil {
+		t.Errorf("got %+v for another user, want nil", template)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestUpdateTemplate(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	created := time.Now().Add(-time.Hour)
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "NoteTemplate" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("tpl-1", "user-1", 1).
+		WillReturnRows(sqlmock.NewRows(templateColumns).
+			AddRow("tpl-1", "user-1", "Groceries", "- [ ] milk", `["shopping"]`, created, created))
+	// Content is left alone; name and tags change
+	mock.ExpectExec(`UPDATE "NoteTemplate" SET "userId"=\$1,"name"=\$2,"content"=\$3,"tags"=\$4,"createdAt"=\$5,"updatedAt"=\$6 WHERE "id" = \$7`).
+		WithArgs("user-1", "Weekly shop", "- [ ] milk", `["shopping","home"]`, created, sqlmock.AnyArg(), "tpl-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	name := "Weekly shop"
+	template, err := db.UpdateTemplate(context.Background(), "user-1", "tpl-1", &name, nil, []string{"shopping", "Home"}, true)
+	if err != nil {
+		t.Fatalf("UpdateTemplate: %v", err)
+	}
+	if template == nil || template.Name != name || !template.UpdatedAt.After(created) {
+		t.Errorf("got %+v, want the renamed template with a new updatedAt", template)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestUpdateTemplate_NotFound(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "NoteTemplate" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("tpl-1", "user-2", 1).
+		WillReturnRows(sqlmock.NewRows(templateColumns))
+	mock.ExpectCommit()
+
+	content := "stolen"
+	template, err := db.UpdateTemplate(context.Background(), "user-2", "tpl-1", nil, &content, nil, false)
+	if err != nil {
+		t.Fatalf("UpdateTemplate: %v", err)
+	}
+	if template != nil {
+		t.Errorf("got %+v for another user's template, want nil", template)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestDeleteTemplate(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	for _, rows := range []int64{1, 0} {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM "NoteTemplate" WHERE id = \$1 AND "userId" = \$2`).
+			WithArgs("tpl-1", "user-1").
+			WillReturnResult(sqlmock.NewResult(0, rows))
+		mock.ExpectCommit()
+	}
+
+	deleted, err := db.DeleteTemplate(context.Background(), "user-1", "tpl-1")
+	if err != nil || !deleted {
+		t.Errorf("first delete = %v, %v; want true", deleted, err)
+	}
+	deleted, err = db.DeleteTemplate(context.Background(), "user-1", "tpl-1")
+	if err != nil || deleted {
+		t.Errorf("second delete = %v, %v; want false", deleted, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	_ "image/gif"  // register GIF for image.DecodeConfig
+	_ "image/jpeg" // register JPEG for image.DecodeConfig
+	_ "image/png"  // register PNG for image.DecodeConfig
+)
+
+// ImageDimensions returns the width and height of an image, reading only its
+// header. JPEG, PNG, GIF and WebP are supported; any other format, or a
+// header that can't be parsed, returns zeros.
+func ImageDimensions(data []byte) (width, height int) {
+	if w, h, ok := webpDimensions(data); ok {
+		return w, h
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0
+	}
+	return cfg.Width, cfg.Height
+}
+
+// webpDimensions reads the canvas size from a WebP header. The standard
+// library has no WebP decoder, but the size sits at a fixed offset in each of
+// the three chunk layouts.
+func webpDimensions(data []byte) (width, height int, ok bool) {
+	if len(data) < 30 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return 0, 0, false
+	}
+	switch string(data[12:16]) {
+	case "VP8 ":
+		// Lossy: 3-byte frame tag and start code, then 14-bit sizes
+		if !bytes.Equal(data[23:26], []byte{0x9d, 0x01, 0x2a}) {
+			return 0, 0, false
+		}
+		width = int(binary.LittleEndian.Uint16(data[26:28]) & 0x3fff)
+		height = int(binary.LittleEndian.Uint16(data[28:30]) & 0x3fff)
+	case "VP8L":
+		// Lossless: signature byte, then 14-bit width-1 and height-1
+		if data[20] != 0x2f {
+			return 0, 0, false
+		}
+		bits := binary.LittleEndian.Uint32(data[21:25])
+		width = int(bits&0x3fff) + 1
+		height = int((bits>>14)&0x3fff) + 1
+	case "VP8X":
+		// Extended: 24-bit canvas width-1 and height-1 after the flags
+		width = int(uint32(data[24])|uint32(data[25])<<8|uint32(data[26])<<16) + 1
+		height = int(uint32(data[27])|uint32(data[28])<<8|uint32(data[29])<<16) + 1
+	default:
+		return 0, 0, false
+	}
+	return width, height, true
+}
@@ -0,0 +1,87 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// encode renders a blank width x height image with enc
+func encode(t *testing.T, width, height int, enc func(*bytes.Buffer, image.Image) error) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := enc(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatalf("failed to encode fixture: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// webpHeader builds a WebP file header for chunk followed by payload
+func webpHeader(chunk string, payload []byte) []byte {
+	data := []byte("RIFF\x00\x00\x00\x00WEBP" + chunk + "\x00\x00\x00\x00")
+	return append(data, payload...)
+}
+
+func TestImageDimensions(t *testing.T) {
+	vp8 := []byte{0x30, 0x01, 0x00, 0x9d, 0x01, 0x2a, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint16(vp8[6:], 640)
+	binary.LittleEndian.PutUint16(vp8[8:], 480)
+
+	vp8l := []byte{0x2f, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.LittleEndian.PutUint32(vp8l[1:], (100-1)|(50-1)<<14)
+
+	vp8x := []byte{0x10, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	vp8x[4], vp8x[5], vp8x[6] = 0x1f, 0x4e, 0x00 // 19999 + 1
+	vp8x[7], vp8x[8], vp8x[9] = 0x63, 0x00, 0x00 // 99 + 1
+
+	tests := []struct {
+		name       string
+		data       []byte
+		wantWidth  int
+		wantHeight int
+	}{
+		{
+			name: "jpeg",
+			data: encode(t, 64, 48, func(b *bytes.Buffer, img image.Image) error {
+				return jpeg.Encode(b, img, nil)
+			}),
+			wantWidth:  64,
+			wantHeight: 48,
+		},
+		{
+			name: "png",
+			data: encode(t, 3, 7, func(b *bytes.Buffer, img image.Image) error {
+				return png.Encode(b, img)
+			}),
+			wantWidth:  3,
+			wantHeight: 7,
+		},
+		{
+			name: "gif",
+			data: encode(t, 10, 20, func(b *bytes.Buffer, img image.Image) error {
+				return gif.Encode(b, img, nil)
+			}),
+			wantWidth:  10,
+			wantHeight: 20,
+		},
+		{name: "webp lossy", data: webpHeader("VP8 ", vp8), wantWidth: 640, wantHeight: 480},
+		{name: "webp lossless", data: webpHeader("VP8L", vp8l), wantWidth: 100, wantHeight: 50},
+		{name: "webp extended", data: webpHeader("VP8X", vp8x), wantWidth: 20000, wantHeight: 100},
+		{name: "heic", data: []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic"), wantWidth: 0, wantHeight: 0},
+		{name: "truncated png", data: []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A}, wantWidth: 0, wantHeight: 0},
+		{name: "empty", data: nil, wantWidth: 0, wantHeight: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			width, height := ImageDimensions(tt.data)
+			if width != tt.wantWidth || height != tt.wantHeight {
+				t.Errorf("ImageDimensions() = %dx%d, want %dx%d", width, height, tt.wantWidth, tt.wantHeight)
+			}
+		})
+	}
+}
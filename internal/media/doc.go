@@ -0,0 +1,2 @@
+// Package media inspects uploaded media files without fully decoding them.
+package media
@@ -2,6 +2,8 @@ package models
 
 import (
 	"crypto/rand"
+	"slices"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
@@ -11,15 +13,33 @@ import (
 type Note struct {
 	ID                 string      `gorm:"column:id;primaryKey"`
 	Content            string      `gorm:"column:content;type:text"`
+	WordCount          int         `gorm:"column:wordCount;not null;default:0"` // Maintained on write, summed by GetStats
 	CreatedAt          time.Time   `gorm:"column:createdAt"`
 	UpdatedAt          time.Time   `gorm:"column:updatedAt"`
-	UserID             string      `gorm:"column:userId;index"`
-	ExternalID         *string     `gorm:"column:externalId;index"`   // Notion page ID
-	NotionUUID         *string     `gorm:"column:notionUuid;index"`   // Notion post UUID (stored in ID property)
-	LastSyncedToNotion *time.Time  `gorm:"column:lastSyncedToNotion"` // When this note was last pushed to Notion
+	UserID             string      `gorm:"column:userId;index;uniqueIndex:idx_note_user_idempotency_key,priority:1"`
+	ExternalID         *string     `gorm:"column:externalId;index"`                      // Notion page ID
+	NotionUUID         *string     `gorm:"column:notionUuid;index"`                      // Notion post UUID (stored in ID property)
+	LastSyncedToNotion *time.Time  `gorm:"column:lastSyncedToNotion"`                    // When this note was last pushed to Notion
+	Pinned             bool        `gorm:"column:pinned;not null;default:false"`         // Listed before unpinned notes
+	Color              string      `gorm:"column:color;not null;default:''"`             // Label color from NoteColors, empty for none
+	Summary            string      `gorm:"column:summary;type:text;not null;default:''"` // One-line AI summary, empty until taggen writes one
+	Mood               string      `gorm:"column:mood;not null;default:''"`              // AI-detected mood from NoteMoods, empty until taggen writes one
 	Tags               []Tag       `gorm:"many2many:NoteTag;foreignKey:ID;joinForeignKey:noteId;References:ID;joinReferences:tagId"`
 	Images             []NoteImage `gorm:"foreignKey:NoteID"`
 	Audios             []NoteAudio `gorm:"foreignKey:NoteID"`
+
+	// ContentHash is an md5 of the whitespace-normalized content. Postgres
+	// maintains it as a generated column, so it is read-only here.
+	ContentHash string `gorm:"column:contentHash;->;type:text GENERATED ALWAYS AS (md5(regexp_replace(btrim(content), '\\s+', ' ', 'g'))) STORED;index"`
+
+	// IdempotencyKey is the client-supplied key the note was created with, if
+	// any. It is unique per user while set and is cleared once it expires.
+	IdempotencyKey *string `gorm:"column:idempotencyKey;uniqueIndex:idx_note_user_idempotency_key,priority:2"`
+
+	// IdempotencyKeyAt is when IdempotencyKey was stored. The key expires
+	// relative to it rather than CreatedAt, which imports backdate. Nil for
+	// notes keyed before the column existed, whose keys expire from CreatedAt.
+	IdempotencyKeyAt *time.Time `gorm:"column:idempotencyKeyAt"`
 }
 
 // TableName specifies the table name for Note
@@ -34,7 +54,12 @@ type NoteImage struct {
 	URL           string    `gorm:"column:url;not null"`
 	GCSObjectName string    `gorm:"column:gcsObjectName;not null"` // Object name in GCS for deletion
 	ExtractedText string    `gorm:"column:extractedText;type:text"`
+	Caption       string    `gorm:"column:caption;type:text"` // Human-written alt text, separate from OCR output
 	MimeType      string    `gorm:"column:mimeType"`
+	SizeBytes     int64     `gorm:"column:sizeBytes;not null;default:0"` // Stored object size, 0 if not yet backfilled
+	Width         int       `gorm:"column:width;not null;default:0"`     // Pixel width, 0 if unknown or not yet backfilled
+	Height        int       `gorm:"column:height;not null;default:0"`    // Pixel height, 0 if unknown or not yet backfilled
+	Position      int       `gorm:"column:position;not null;default:0"`  // Display order within the note
 	CreatedAt     time.Time `gorm:"column:createdAt"`
 }
 
@@ -51,6 +76,8 @@ type NoteAudio struct {
 	GCSObjectName   string    `gorm:"column:gcsObjectName;not null"` // Object name in GCS for deletion
 	TranscribedText string    `gorm:"column:transcribedText;type:text"`
 	MimeType        string    `gorm:"column:mimeType"`
+	SizeBytes       int64     `gorm:"column:sizeBytes;not null;default:0"` // Stored object size, 0 if not yet backfilled
+	Position        int       `gorm:"column:position;not null;default:0"`  // Display order within the note
 	CreatedAt       time.Time `gorm:"column:createdAt"`
 }
 
@@ -105,6 +132,10 @@ type User struct {
 	DisabledReason      *string    `gorm:"column:disabledReason"`
 	FailedLoginAttempts int        `gorm:"column:failedLoginAttempts;default:0"`
 	LastFailedLogin     *time.Time `gorm:"column:lastFailedLogin"`
+
+	// SyncIntervalMinutes is the minimum time between Notion syncs for this
+	// user. Nil means every run of the sync job.
+	SyncIntervalMinutes *int `gorm:"column:syncIntervalMinutes"`
 }
 
 // TableName specifies the table name for User
@@ -121,6 +152,8 @@ type ApiKey struct {
 	UserID    string     `gorm:"column:userId;index"`
 	CreatedAt time.Time  `gorm:"column:createdAt"`
 	LastUsed  *time.Time `gorm:"column:lastUsed"`
+	// UsageCount is how many requests the key has authenticated
+	UsageCount int64 `gorm:"column:usageCount;not null;default:0"`
 }
 
 // TableName specifies the table name for ApiKey
@@ -130,8 +163,9 @@ func (ApiKey) TableName() string {
 
 // SyncState tracks the last sync time per user
 type SyncState struct {
-	UserID       string    `gorm:"column:userId;primaryKey"`
-	LastSyncedAt time.Time `gorm:"column:lastSyncedAt"`
+	UserID         string    `gorm:"column:userId;primaryKey"`
+	LastSyncedAt   time.Time `gorm:"column:lastSyncedAt"`
+	FullSyncCursor *string   `gorm:"column:fullSyncCursor"` // Notion cursor of an interrupted full sync
 }
 
 // TableName specifies the table name for SyncState
@@ -139,6 +173,185 @@ func (SyncState) TableName() string {
 	return "SyncState"
 }
 
+// DefaultMaxContentLength is the default limit, in characters, on a note's
+// content. It keeps notes small enough to store and push to Notion.
+const DefaultMaxContentLength = 100000
+
+// NoteColors lists the label colors a note can be assigned.
+var NoteColors = []string{"red", "orange", "yellow", "green", "teal", "blue", "purple", "pink", "gray"}
+
+// IsValidNoteColor reports whether color is empty or one of NoteColors.
+func IsValidNoteColor(color string) bool {
+	return color == "" || slices.Contains(NoteColors, color)
+}
+
+// NormalizeTag returns the canonical form of a tag name so that "Work",
+// "work ", and "#work" are stored as one tag: surrounding whitespace and a
+// leading '#' are removed, inner whitespace collapses to a single space, and
+// letters are lowercased. ok is false if nothing is left.
+func NormalizeTag(name string) (string, bool) {
+	name = strings.TrimPrefix(strings.TrimSpace(name), "#")
+	name = strings.ToLower(strings.Join(strings.Fields(name), " "))
+	return name, name != ""
+}
+
+// NormalizeTags normalizes each name with NormalizeTag and drops empty names
+// and duplicates, keeping the first occurrence's position.
+func NormalizeTags(names []string) []string {
+	if len(names) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name, ok := NormalizeTag(name)
+		if !ok || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+	return normalized
+}
+
+// MoodNeutral is the mood recorded when no clearer mood is detected.
+const MoodNeutral = "neutral"
+
+// NoteMoods lists the moods AI analysis can assign to a note.
+var NoteMoods = []string{"happy", "excited", "grateful", "calm", MoodNeutral, "tired", "sad", "anxious", "angry"}
+
+// IsValidNoteMood reports whether mood is one of NoteMoods.
+func IsValidNoteMood(mood string) bool {
+	return slices.Contains(NoteMoods, mood)
+}
+
+// PremiumSubscriptionStatuses lists the subscription statuses that grant
+// premium features.
+var PremiumSubscriptionStatuses = []string{"active", "trialing"}
+
+// IsPremium reports whether the user's subscription status is a premium
+// tier. It does not look at SubscriptionEnd; use IsActive for that.
+func (u *User) IsPremium() bool {
+	return slices.Contains(PremiumSubscriptionStatuses, u.SubscriptionStatus)
+}
+
+// IsActive reports whether the user has a premium subscription that has not
+// expired at now. A subscription with no end date never expires, and one is
+// expired from the instant SubscriptionEnd is reached.
+func (u *User) IsActive(now time.Time) bool {
+	if !u.IsPremium() {
+		return false
+	}
+	return u.SubscriptionEnd == nil || now.Before(*u.SubscriptionEnd)
+}
+
+// Resource types recorded in ProcessingFailure
+const (
+	ResourceTypeImage = "image"
+	ResourceTypeAudio = "audio"
+)
+
+// ProcessingFailure records repeated AI processing failures for a resource so
+// background jobs can stop retrying it after too many attempts
+type ProcessingFailure struct {
+	ID            string    `gorm:"column:id;primaryKey"`
+	ResourceType  string    `gorm:"column:resourceType;not null;uniqueIndex:idx_processing_failure_resource"`
+	ResourceID    string    `gorm:"column:resourceId;not null;uniqueIndex:idx_processing_failure_resource"`
+	ErrorMessage  string    `gorm:"column:errorMessage;type:text"`
+	Attempts      int       `gorm:"column:attempts;not null;default:0"`
+	LastAttemptAt time.Time `gorm:"column:lastAttemptAt"`
+	CreatedAt     time.Time `gorm:"column:createdAt"`
+}
+
+// TableName specifies the table name for ProcessingFailure
+func (ProcessingFailure) TableName() string {
+	return "ProcessingFailure"
+}
+
+// Auth types recorded on a LoginEvent
+const (
+	LoginAuthPassword = "password"
+	LoginAuthAPIKey   = "apikey"
+)
+
+// LoginEvent is an audit record of one authentication attempt by a known
+// user. Failed attempts for unknown emails or keys are not recorded since
+// they cannot be tied to a user.
+type LoginEvent struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	UserID    string    `gorm:"column:userId;not null;index:idx_login_event_user_created,priority:1"`
+	Success   bool      `gorm:"column:success;not null"`
+	AuthType  string    `gorm:"column:authType;not null"`
+	IPAddress string    `gorm:"column:ipAddress;not null;default:''"`
+	UserAgent string    `gorm:"column:userAgent;type:text;not null;default:''"`
+	CreatedAt time.Time `gorm:"column:createdAt;index:idx_login_event_user_created,priority:2,sort:desc"`
+}
+
+// TableName specifies the table name for LoginEvent
+func (LoginEvent) TableName() string {
+	return "LoginEvent"
+}
+
+// StorageReservation holds upload quota for a user while files are being
+// uploaded, so concurrent uploads cannot together exceed the quota. Rows are
+// deleted once the upload is saved; ExpiresAt bounds abandoned reservations.
+type StorageReservation struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	UserID    string    `gorm:"column:userId;index;not null"`
+	SizeBytes int64     `gorm:"column:sizeBytes;not null"`
+	ExpiresAt time.Time `gorm:"column:expiresAt;not null"`
+	CreatedAt time.Time `gorm:"column:createdAt"`
+}
+
+// TableName specifies the table name for StorageReservation
+func (StorageReservation) TableName() string {
+	return "StorageReservation"
+}
+
+// ProcessedStripeEvent records a Stripe webhook event that has been applied.
+// Stripe redelivers events until it gets a 2xx, so the webhook skips events
+// already recorded here.
+type ProcessedStripeEvent struct {
+	ID          string    `gorm:"column:id;primaryKey"` // Stripe event ID, e.g. evt_...
+	Type        string    `gorm:"column:type;not null"`
+	ProcessedAt time.Time `gorm:"column:processedAt;not null"`
+}
+
+// TableName specifies the table name for ProcessedStripeEvent
+func (ProcessedStripeEvent) TableName() string {
+	return "ProcessedStripeEvent"
+}
+
+// NoteTemplate is a user's reusable starting point for new notes, such as a
+// checklist or a meeting outline
+type NoteTemplate struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	UserID    string    `gorm:"column:userId;index;not null"`
+	Name      string    `gorm:"column:name;not null"`
+	Content   string    `gorm:"column:content;type:text;not null;default:''"`
+	Tags      []string  `gorm:"column:tags;type:text;serializer:json"` // Normalized names applied to notes created from the template
+	CreatedAt time.Time `gorm:"column:createdAt"`
+	UpdatedAt time.Time `gorm:"column:updatedAt"`
+}
+
+// TableName specifies the table name for NoteTemplate
+func (NoteTemplate) TableName() string {
+	return "NoteTemplate"
+}
+
+// SchemaMigration records a numbered migration that has been applied
+type SchemaMigration struct {
+	Version   int       `gorm:"column:version;primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"column:name;not null"`
+	AppliedAt time.Time `gorm:"column:appliedAt;not null"`
+}
+
+// TableName specifies the table name for SchemaMigration
+func (SchemaMigration) TableName() string {
+	return "SchemaMigration"
+}
+
 // BeforeCreate hook to generate CUID-like ID for notes
 func (n *Note) BeforeCreate(tx *gorm.DB) error {
 	if n.ID == "" {
@@ -187,6 +400,14 @@ func (na *NoteAudio) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// BeforeCreate hook to generate CUID-like ID for processing failures
+func (pf *ProcessingFailure) BeforeCreate(tx *gorm.DB) error {
+	if pf.ID == "" {
+		pf.ID = GenerateCUID()
+	}
+	return nil
+}
+
 // GenerateCUID generates a CUID-like identifier
 func GenerateCUID() string {
 	const chars = "0123456789abcdefghijklmnopqrstuvwxyz"
@@ -210,3 +431,11 @@ func GenerateCUID() string {
 
 	return string(result)
 }
+
+// BeforeCreate hook to generate CUID-like ID for storage reservations
+func (sr *StorageReservation) BeforeCreate(tx *gorm.DB) error {
+	if sr.ID == "" {
+		sr.ID = GenerateCUID()
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOK bool
+	}{
+		{name: "work", want: "work", wantOK: true},
+		{name: "Work", want: "work", wantOK: true},
+		{name: "work ", want: "work", wantOK: true},
+		{name: "  WORK\t", want: "work", wantOK: true},
+		{name: "#work", want: "work", wantOK: true},
+		{name: " #Work ", want: "work", wantOK: true},
+		{name: "Daily  Log", want: "daily log", wantOK: true},
+		{name: "Работа", want: "работа", wantOK: true},
+		{name: "家族", want: "家族", wantOK: true},
+		{name: "", want: "", wantOK: false},
+		{name: "   ", want: "", wantOK: false},
+		{name: "#", want: "", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NormalizeTag(tt.name)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("NormalizeTag(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	got := NormalizeTags([]string{"Work", "work ", "#work", "", "\n", "Home", " home", "travel"})
+	want := []string{"work", "home", "travel"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("NormalizeTags mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := NormalizeTags(nil); got != nil {
+		t.Errorf("NormalizeTags(nil) = %v, want nil", got)
+	}
+}
+
+func TestUserSubscriptionFlags(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) *time.Time {
+		end := now.Add(d)
+		return &end
+	}
+
+	tests := []struct {
+		name        string
+		status      string
+		end         *time.Time
+		wantPremium bool
+		wantActive  bool
+	}{
+		{name: "active, no end", status: "active", wantPremium: true, wantActive: true},
+		{name: "trialing, no end", status: "trialing", wantPremium: true, wantActive: true},
+		{name: "active, ends later", status: "active", end: at(time.Hour), wantPremium: true, wantActive: true},
+		{name: "active, ends in 1ns", status: "active", end: at(time.Nanosecond), wantPremium: true, wantActive: true},
+		{name: "active, ends now", status: "active", end: at(0), wantPremium: true, wantActive: false},
+		{name: "active, ended 1ns ago", status: "active", end: at(-time.Nanosecond), wantPremium: true, wantActive: false},
+		{name: "trialing, ended", status: "trialing", end: at(-time.Hour), wantPremium: true, wantActive: false},
+		{name: "free", status: "free", wantPremium: false, wantActive: false},
+		{name: "free, ends later", status: "free", end: at(time.Hour), wantPremium: false, wantActive: false},
+		{name: "canceled, ends later", status: "canceled", end: at(time.Hour), wantPremium: false, wantActive: false},
+		{name: "past_due", status: "past_due", wantPremium: false, wantActive: false},
+		{name: "empty", status: "", wantPremium: false, wantActive: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &User{SubscriptionStatus: tt.status, SubscriptionEnd: tt.end}
+			if got := u.IsPremium(); got != tt.wantPremium {
+				t.Errorf("IsPremium() = %v, want %v", got, tt.wantPremium)
+			}
+			if got := u.IsActive(now); got != tt.wantActive {
+				t.Errorf("IsActive() = %v, want %v", got, tt.wantActive)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package notion
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -14,8 +16,21 @@ import (
 const (
 	// DefaultDatabaseName is the default Notion database name to sync with
 	DefaultDatabaseName = "Journal"
+
+	// maxRichTextLength is the most characters Notion accepts in one rich
+	// text object
+	maxRichTextLength = 2000
+
+	// maxBlocksPerRequest is the most child blocks Notion accepts when
+	// creating a page or appending to one
+	maxBlocksPerRequest = 100
 )
 
+// ErrInvalidCursor is returned by ListPostsPage when Notion rejects the start
+// cursor, for example because it expired. Callers should restart from the
+// first page.
+var ErrInvalidCursor = errors.New("invalid Notion pagination cursor")
+
 // Post represents a journal entry from Notion.
 type Post struct {
 	ID         string    // Unique identifier (UUID stored in Notion)
@@ -33,6 +48,8 @@ type Client struct {
 	cachedDbID notionapi.DatabaseID
 	client     *notionapi.Client
 	clientOnce sync.Once
+	retry      RetryConfig
+	transport  http.RoundTripper // Overrides the HTTP transport under retries, for tests
 }
 
 // NewClient creates a new Notion client from environment variables.
@@ -47,22 +64,34 @@ func NewClient() (*Client, error) {
 
 // NewClientWithKey creates a new Notion client with a specific API key and database name.
 func NewClientWithKey(notionKey string, databaseName string) *Client {
+	return NewClientWithConfig(notionKey, databaseName, DefaultRetryConfig())
+}
+
+// NewClientWithConfig creates a new Notion client like NewClientWithKey, with
+// custom retry settings.
+func NewClientWithConfig(notionKey string, databaseName string, retry RetryConfig) *Client {
 	if databaseName == "" {
 		databaseName = DefaultDatabaseName
 	}
 	return &Client{
 		notionKey: notionKey,
 		rootPage:  databaseName,
+		retry:     retry,
 	}
 }
 
-// getClient returns a cached Notion client.
+// getClient returns a cached Notion client. Retries are handled by
+// retryTransport; notionapi's own 429 retry is disabled because it resends an
+// already-consumed request body.
 func (c *Client) getClient() *notionapi.Client {
 	c.clientOnce.Do(func() {
 		c.client = notionapi.NewClient(
 			notionapi.Token(c.notionKey),
 			notionapi.WithVersion("2022-06-28"),
-			notionapi.WithRetry(2),
+			notionapi.WithRetry(1),
+			notionapi.WithHTTPClient(&http.Client{
+				Transport: newRetryTransport(c.transport, c.retry),
+			}),
 		)
 	})
 	return c.client
@@ -70,47 +99,66 @@ func (c *Client) getClient() *notionapi.Client {
 
 // ListAllPosts retrieves all journal entries from Notion using pagination.
 func (c *Client) ListAllPosts(ctx context.Context) ([]*Post, error) {
-	dbID, err := c.getDatabaseID(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get database ID: %w", err)
-	}
-
-	client := c.getClient()
 	var allPosts []*Post
-	var cursor notionapi.Cursor
+	var cursor string
 
 	for {
-		req := &notionapi.DatabaseQueryRequest{
-			Sorts: []notionapi.SortObject{
-				{Property: "Created At", Direction: notionapi.SortOrderDESC},
-			},
-			PageSize: 100,
-		}
-		if cursor != "" {
-			req.StartCursor = cursor
-		}
-
-		resp, err := client.Database.Query(ctx, dbID, req)
+		posts, next, err := c.ListPostsPage(ctx, cursor)
 		if err != nil {
-			return nil, fmt.Errorf("failed to query database: %w", err)
+			return nil, err
 		}
-
-		posts, err := c.processPages(ctx, client, resp.Results)
-		if err != nil {
-			return nil, fmt.Errorf("failed to process pages: %w", err)
-		}
-
 		allPosts = append(allPosts, posts...)
 
-		if !resp.HasMore {
+		if next == "" {
 			break
 		}
-		cursor = resp.NextCursor
+		cursor = next
 	}
 
 	return allPosts, nil
 }
 
+// ListPostsPage retrieves one page of journal entries, newest first, starting
+// at cursor (empty for the first page). It returns the cursor for the next
+// page, which is empty after the last one, so a caller can persist it and
+// resume a full listing later.
+func (c *Client) ListPostsPage(ctx context.Context, cursor string) (posts []*Post, next string, err error) {
+	dbID, err := c.getDatabaseID(ctx)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get database ID: %w", err)
+	}
+
+	client := c.getClient()
+	req := &notionapi.DatabaseQueryRequest{
+		Sorts: []notionapi.SortObject{
+			{Property: "Created At", Direction: notionapi.SortOrderDESC},
+		},
+		PageSize: 100,
+	}
+	if cursor != "" {
+		req.StartCursor = notionapi.Cursor(cursor)
+	}
+
+	resp, err := client.Database.Query(ctx, dbID, req)
+	if err != nil {
+		var apiErr *notionapi.Error
+		if cursor != "" && errors.As(err, &apiErr) && apiErr.Status == http.StatusBadRequest {
+			return nil, "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+		}
+		return nil, "", fmt.Errorf("failed to query database: %w", err)
+	}
+
+	posts, err = c.processPages(ctx, client, resp.Results)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to process pages: %w", err)
+	}
+
+	if resp.HasMore {
+		next = string(resp.NextCursor)
+	}
+	return posts, next, nil
+}
+
 // ListPostsSince retrieves journal entries modified since the given time.
 func (c *Client) ListPostsSince(ctx context.Context, since time.Time) ([]*Post, error) {
 	dbID, err := c.getDatabaseID(ctx)
@@ -312,9 +360,10 @@ func (c *Client) getDatabaseID(ctx context.Context) (notionapi.DatabaseID, error
 	return c.cachedDbID, nil
 }
 
-// CreatePost creates a new page in the Notion database.
+// CreatePost creates a new page in the Notion database. imageURLs are added
+// after the content as external image blocks.
 // Returns the Notion page ID and UUID on success.
-func (c *Client) CreatePost(ctx context.Context, id, content string, tags []string) (pageID string, err error) {
+func (c *Client) CreatePost(ctx context.Context, id, content string, tags []string, imageURLs []string) (pageID string, err error) {
 	dbID, err := c.getDatabaseID(ctx)
 	if err != nil {
 		return "", fmt.Errorf("failed to get database ID: %w", err)
@@ -349,44 +398,56 @@ func (c *Client) CreatePost(ctx context.Context, id, content string, tags []stri
 				MultiSelect: multiSelectTags,
 			},
 		},
-		Children: c.contentToBlocks(content),
 	}
 
+	// Notion caps the children of a create request, so anything past the
+	// first batch is appended afterwards
+	blocks := c.pageBlocks(content, imageURLs)
+	first := blocks[:min(len(blocks), maxBlocksPerRequest)]
+	createReq.Children = first
+
 	page, err := client.Page.Create(ctx, createReq)
 	if err != nil {
 		return "", fmt.Errorf("failed to create page: %w", err)
 	}
 
+	if err := appendBlocks(ctx, client, page.ID.String(), blocks[len(first):]); err != nil {
+		return "", fmt.Errorf("failed to add page content: %w", err)
+	}
+
 	return page.ID.String(), nil
 }
 
-// UpdatePost updates an existing Notion page's content and tags.
-func (c *Client) UpdatePost(ctx context.Context, pageID, content string, tags []string) error {
+// UpdatePost updates an existing Notion page's content, images, and, if
+// updateTags is set, tags. Otherwise the page's tags are left as they are.
+func (c *Client) UpdatePost(ctx context.Context, pageID, content string, tags []string, updateTags bool, imageURLs []string) error {
 	client := c.getClient()
 
-	// Build multi-select options for tags
-	multiSelectTags := make([]notionapi.Option, len(tags))
-	for i, tag := range tags {
-		multiSelectTags[i] = notionapi.Option{Name: tag}
-	}
+	if updateTags {
+		// Build multi-select options for tags
+		multiSelectTags := make([]notionapi.Option, len(tags))
+		for i, tag := range tags {
+			multiSelectTags[i] = notionapi.Option{Name: tag}
+		}
 
-	// Update page properties (tags)
-	updateReq := &notionapi.PageUpdateRequest{
-		Properties: notionapi.Properties{
-			"Tags": notionapi.MultiSelectProperty{
-				Type:        notionapi.PropertyTypeMultiSelect,
-				MultiSelect: multiSelectTags,
+		// Update page properties (tags)
+		updateReq := &notionapi.PageUpdateRequest{
+			Properties: notionapi.Properties{
+				"Tags": notionapi.MultiSelectProperty{
+					Type:        notionapi.PropertyTypeMultiSelect,
+					MultiSelect: multiSelectTags,
+				},
 			},
-		},
-	}
+		}
 
-	_, err := client.Page.Update(ctx, notionapi.PageID(pageID), updateReq)
-	if err != nil {
-		return fmt.Errorf("failed to update page properties: %w", err)
+		_, err := client.Page.Update(ctx, notionapi.PageID(pageID), updateReq)
+		if err != nil {
+			return fmt.Errorf("failed to update page properties: %w", err)
+		}
 	}
 
 	// Update content: first delete existing blocks, then add new ones
-	if err := c.replacePageContent(ctx, client, pageID, content); err != nil {
+	if err := c.replacePageContent(ctx, client, pageID, content, imageURLs); err != nil {
 		return fmt.Errorf("failed to update page content: %w", err)
 	}
 
@@ -410,7 +471,7 @@ func (c *Client) ArchivePost(ctx context.Context, pageID string) error {
 }
 
 // replacePageContent deletes all existing blocks and adds new content.
-func (c *Client) replacePageContent(ctx context.Context, client *notionapi.Client, pageID, content string) error {
+func (c *Client) replacePageContent(ctx context.Context, client *notionapi.Client, pageID, content string, imageURLs []string) error {
 	// First, get all existing blocks
 	var cursor string
 	var blockIDs []notionapi.BlockID
@@ -445,16 +506,26 @@ func (c *Client) replacePageContent(ctx context.Context, client *notionapi.Clien
 	}
 
 	// Add new content blocks
-	newBlocks := c.contentToBlocks(content)
-	if len(newBlocks) > 0 {
+	if err := appendBlocks(ctx, client, pageID, c.pageBlocks(content, imageURLs)); err != nil {
+		return fmt.Errorf("failed to append new blocks: %w", err)
+	}
+
+	return nil
+}
+
+// appendBlocks adds blocks to the end of a page, in batches no larger than
+// Notion allows per request.
+func appendBlocks(ctx context.Context, client *notionapi.Client, pageID string, blocks []notionapi.Block) error {
+	for len(blocks) > 0 {
+		batch := blocks[:min(len(blocks), maxBlocksPerRequest)]
 		_, err := client.Block.AppendChildren(ctx, notionapi.BlockID(pageID), &notionapi.AppendBlockChildrenRequest{
-			Children: newBlocks,
+			Children: batch,
 		})
 		if err != nil {
-			return fmt.Errorf("failed to append new blocks: %w", err)
+			return err
 		}
+		blocks = blocks[len(batch):]
 	}
-
 	return nil
 }
 
@@ -475,15 +546,55 @@ func (c *Client) contentToBlocks(content string) []notionapi.Block {
 				Object: notionapi.ObjectTypeBlock,
 			},
 			Paragraph: notionapi.Paragraph{
-				RichText: []notionapi.RichText{
-					{
-						Type: notionapi.ObjectTypeText,
-						Text: &notionapi.Text{Content: line},
-					},
-				},
+				RichText: richText(line),
 			},
 		})
 	}
 
 	return blocks
 }
+
+// richText splits a line into rich text objects no longer than Notion allows.
+func richText(line string) []notionapi.RichText {
+	runes := []rune(line)
+	texts := make([]notionapi.RichText, 0, len(runes)/maxRichTextLength+1)
+	for {
+		chunk := runes[:min(len(runes), maxRichTextLength)]
+		texts = append(texts, notionapi.RichText{
+			Type: notionapi.ObjectTypeText,
+			Text: &notionapi.Text{Content: string(chunk)},
+		})
+		runes = runes[len(chunk):]
+		if len(runes) == 0 {
+			return texts
+		}
+	}
+}
+
+// pageBlocks builds a page body: the content's paragraphs followed by one
+// image block per URL.
+func (c *Client) pageBlocks(content string, imageURLs []string) []notionapi.Block {
+	return append(c.contentToBlocks(content), imageBlocks(imageURLs)...)
+}
+
+// imageBlocks converts image URLs to external Notion image blocks. Notion
+// fetches the image when the page is viewed, so the URLs must stay reachable.
+func imageBlocks(urls []string) []notionapi.Block {
+	blocks := make([]notionapi.Block, 0, len(urls))
+	for _, url := range urls {
+		if url == "" {
+			continue
+		}
+		blocks = append(blocks, &notionapi.ImageBlock{
+			BasicBlock: notionapi.BasicBlock{
+				Type:   notionapi.BlockTypeImage,
+				Object: notionapi.ObjectTypeBlock,
+			},
+			Image: notionapi.Image{
+				Type:     notionapi.FileTypeExternal,
+				External: &notionapi.FileObject{URL: url},
+			},
+		})
+	}
+	return blocks
+}
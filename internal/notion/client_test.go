@@ -0,0 +1,112 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/jomei/notionapi"
+)
+
+func TestPageBlocks(t *testing.T) {
+	c := NewClientWithKey("key", DefaultDatabaseName)
+
+	blocks := c.pageBlocks("first line\nsecond line", []string{
+		"https://img.example/notes/n1/a.png",
+		"",
+		"https://img.example/notes/n1/b.png",
+	})
+	if len(blocks) != 4 {
+		t.Fatalf("got %d blocks, want 2 paragraphs and 2 images", len(blocks))
+	}
+
+	for i, want := range []string{"first line", "second line"} {
+		p, ok := blocks[i].(*notionapi.ParagraphBlock)
+		if !ok {
+			t.Fatalf("block %d is %T, want paragraph", i, blocks[i])
+		}
+		if got := p.Paragraph.RichText[0].Text.Content; got != want {
+			t.Errorf("block %d text = %q, want %q", i, got, want)
+		}
+	}
+
+	for i, want := range []string{"https://img.example/notes/n1/a.png", "https://img.example/notes/n1/b.png"} {
+		img, ok := blocks[2+i].(*notionapi.ImageBlock)
+		if !ok {
+			t.Fatalf("block %d is %T, want image", 2+i, blocks[2+i])
+		}
+		if img.Type != notionapi.BlockTypeImage || img.Image.Type != notionapi.FileTypeExternal {
+			t.Errorf("block %d has type %q/%q, want an external image", 2+i, img.Type, img.Image.Type)
+		}
+		if got := img.Image.GetURL(); got != want {
+			t.Errorf("block %d URL = %q, want %q", 2+i, got, want)
+		}
+	}
+}
+
+func TestPageBlocks_NoContentOrImages(t *testing.T) {
+	c := NewClientWithKey("key", DefaultDatabaseName)
+	if blocks := c.pageBlocks("", nil); len(blocks) != 0 {
+		t.Errorf("got %d blocks for an empty note, want 0", len(blocks))
+	}
+}
+
+func TestPageBlocks_SplitsLongLines(t *testing.T) {
+	c := NewClientWithKey("key", DefaultDatabaseName)
+	line := strings.Repeat("é", 2*maxRichTextLength+5)
+
+	blocks := c.pageBlocks(line, nil)
+	if len(blocks) != 1 {
+		t.Fatalf("got %d blocks, want 1 paragraph", len(blocks))
+	}
+	p := blocks[0].(*notionapi.ParagraphBlock)
+
+	var lengths []int
+	var joined strings.Builder
+	for _, rt := range p.Paragraph.RichText {
+		lengths = append(lengths, utf8.RuneCountInString(rt.Text.Content))
+		joined.WriteString(rt.Text.Content)
+	}
+	if diff := cmp.Diff([]int{maxRichTextLength, maxRichTextLength, 5}, lengths); diff != "" {
+		t.Errorf("rich text lengths mismatch (-want +got):\n%s", diff)
+	}
+	if joined.String() != line {
+		t.Error("split rich text does not rejoin to the original line")
+	}
+}
+
+func TestCreatePost_BatchesBlocks(t *testing.T) {
+	transport := &fakeTransport{responses: []*http.Response{
+		response(http.StatusOK, `{"object":"page","id":"page-1"}`, nil),
+		response(http.StatusOK, `{"object":"list","results":[]}`, nil),
+		response(http.StatusOK, `{"object":"list","results":[]}`, nil),
+	}}
+	c := newFakeClient(transport, 0)
+
+	content := strings.TrimSuffix(strings.Repeat("line\n", 2*maxBlocksPerRequest+50), "\n")
+	pageID, err := c.CreatePost(context.Background(), "note-1", content, nil, nil)
+	if err != nil {
+		t.Fatalf("CreatePost: %v", err)
+	}
+	if pageID != "page-1" {
+		t.Errorf("pageID = %q, want page-1", pageID)
+	}
+
+	var counts []int
+	for _, body := range transport.bodies {
+		var req struct {
+			Children []json.RawMessage `json:"children"`
+		}
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("decoding request body: %v", err)
+		}
+		counts = append(counts, len(req.Children))
+	}
+	if diff := cmp.Diff([]int{maxBlocksPerRequest, maxBlocksPerRequest, 50}, counts); diff != "" {
+		t.Errorf("blocks per request mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,158 @@
+package notion
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Default retry settings, overridable with NOTION_MAX_RETRIES,
+// NOTION_RETRY_MAX_DELAY, and NOTION_REQUEST_TIMEOUT
+const (
+	DefaultMaxRetries     = 4
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	DefaultRetryMaxDelay  = 30 * time.Second
+	DefaultRequestTimeout = 30 * time.Second
+)
+
+// RetryConfig controls how Notion API calls are retried. Rate-limited (429)
+// responses wait for Notion's Retry-After; 502, 503, 504, and network errors
+// back off exponentially from BaseDelay. No wait exceeds MaxDelay.
+type RetryConfig struct {
+	MaxRetries     int           // Retries after the first attempt
+	BaseDelay      time.Duration // First backoff, doubled on each retry
+	MaxDelay       time.Duration // Longest single wait, including Retry-After
+	RequestTimeout time.Duration // How long each attempt waits for response headers, 0 for no limit
+}
+
+// DefaultRetryConfig returns the retry settings used when none are given.
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxRetries:     DefaultMaxRetries,
+		BaseDelay:      DefaultRetryBaseDelay,
+		MaxDelay:       DefaultRetryMaxDelay,
+		RequestTimeout: DefaultRequestTimeout,
+	}
+}
+
+// RetryConfigFromEnv reads retry settings from the environment, using the
+// defaults for unset variables.
+func RetryConfigFromEnv() (RetryConfig, error) {
+	cfg := DefaultRetryConfig()
+
+	if value := os.Getenv("NOTION_MAX_RETRIES"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return RetryConfig{}, fmt.Errorf("NOTION_MAX_RETRIES must be a non-negative integer, got %q", value)
+		}
+		cfg.MaxRetries = n
+	}
+
+	var err error
+	if cfg.MaxDelay, err = durationFromEnv("NOTION_RETRY_MAX_DELAY", cfg.MaxDelay); err != nil {
+		return RetryConfig{}, err
+	}
+	if cfg.RequestTimeout, err = durationFromEnv("NOTION_REQUEST_TIMEOUT", cfg.RequestTimeout); err != nil {
+		return RetryConfig{}, err
+	}
+
+	return cfg, nil
+}
+
+// durationFromEnv reads a non-negative duration from envVar, returning
+// defaultValue when it is unset
+func durationFromEnv(envVar string, defaultValue time.Duration) (time.Duration, error) {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative duration, got %q", envVar, value)
+	}
+	return d, nil
+}
+
+// retryTransport retries Notion requests that failed transiently. It sits
+// below notionapi, so every API call goes through it.
+type retryTransport struct {
+	base http.RoundTripper
+	cfg  RetryConfig
+}
+
+// newRetryTransport wraps base, or a copy of http.DefaultTransport with the
+// configured header timeout when base is nil.
+func newRetryTransport(base http.RoundTripper, cfg RetryConfig) *retryTransport {
+	if base == nil {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.ResponseHeaderTimeout = cfg.RequestTimeout
+		base = t
+	}
+	return &retryTransport{base: base, cfg: cfg}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, errors.New("notion: cannot retry request without GetBody")
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		wait, retry := t.retryAfter(resp, err, attempt)
+		if !retry || attempt >= t.cfg.MaxRetries || ctx.Err() != nil {
+			return resp, err
+		}
+		if resp != nil {
+			// Drain so the connection can be reused
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// retryAfter reports whether a response or error is worth retrying and how
+// long to wait first.
+func (t *retryTransport) retryAfter(resp *http.Response, err error, attempt int) (time.Duration, bool) {
+	backoff := min(t.cfg.BaseDelay<<attempt, t.cfg.MaxDelay)
+
+	if err != nil {
+		return backoff, true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// https://developers.notion.com/reference/request-limits#rate-limits
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, t.cfg.MaxDelay), true
+		}
+		return backoff, true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return backoff, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,157 @@
+package notion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jomei/notionapi"
+)
+
+const emptyQueryResponse = `{"object":"list","results":[],"has_more":false}`
+
+// fakeTransport replays canned responses in order and records request bodies.
+type fakeTransport struct {
+	responses []*http.Response
+	bodies    []string
+}
+
+func (f *fakeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		body, _ := io.ReadAll(req.Body)
+		f.bodies = append(f.bodies, string(body))
+	} else {
+		f.bodies = append(f.bodies, "")
+	}
+	if len(f.bodies) > len(f.responses) {
+		return nil, errors.New("unexpected request")
+	}
+	return f.responses[len(f.bodies)-1], nil
+}
+
+func response(status int, body string, header http.Header) *http.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func newFakeClient(transport http.RoundTripper, maxRetries int) *Client {
+	c := NewClientWithConfig("key", DefaultDatabaseName, RetryConfig{
+		MaxRetries: maxRetries,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   10 * time.Millisecond,
+	})
+	c.transport = transport
+	c.cachedDbID = notionapi.DatabaseID("db1")
+	return c
+}
+
+func TestRetryRateLimited(t *testing.T) {
+	transport := &fakeTransport{responses: []*http.Response{
+		response(http.StatusTooManyRequests, `{"object":"error","status":429,"code":"rate_limited"}`, http.Header{"Retry-After": {"0"}}),
+		response(http.StatusOK, emptyQueryResponse, nil),
+	}}
+	c := newFakeClient(transport, 3)
+
+	posts, next, err := c.ListPostsPage(context.Background(), "")
+	if err != nil {
+		t.Fatalf("ListPostsPage failed: %v", err)
+	}
+	if len(posts) != 0 || next != "" {
+		t.Errorf("got %d posts and cursor %q, want none", len(posts), next)
+	}
+	if len(transport.bodies) != 2 {
+		t.Fatalf("got %d requests, want 2", len(transport.bodies))
+	}
+	if transport.bodies[1] == "" || transport.bodies[1] != transport.bodies[0] {
+		t.Errorf("retried body = %q, want the original %q", transport.bodies[1], transport.bodies[0])
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	transport := &fakeTransport{responses: []*http.Response{
+		response(http.StatusBadGateway, "bad gateway", nil),
+		response(http.StatusServiceUnavailable, "unavailable", nil),
+		response(http.StatusOK, emptyQueryResponse, nil),
+	}}
+	c := newFakeClient(transport, 3)
+
+	if _, _, err := c.ListPostsPage(context.Background(), ""); err != nil {
+		t.Fatalf("ListPostsPage failed: %v", err)
+	}
+	if len(transport.bodies) != 3 {
+		t.Errorf("got %d requests, want 3", len(transport.bodies))
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	transport := &fakeTransport{responses: []*http.Response{
+		response(http.StatusServiceUnavailable, "unavailable", nil),
+		response(http.StatusServiceUnavailable, "unavailable", nil),
+		response(http.StatusServiceUnavailable, "unavailable", nil),
+	}}
+	c := newFakeClient(transport, 2)
+
+	if _, _, err := c.ListPostsPage(context.Background(), ""); err == nil {
+		t.Fatal("expected an error after retries were exhausted")
+	}
+	if len(transport.bodies) != 3 {
+		t.Errorf("got %d requests, want 1 attempt and 2 retries", len(transport.bodies))
+	}
+}
+
+func TestRetryNotOnClientErrors(t *testing.T) {
+	transport := &fakeTransport{responses: []*http.Response{
+		response(http.StatusBadRequest, `{"object":"error","status":400,"code":"validation_error"}`, nil),
+	}}
+	c := newFakeClient(transport, 3)
+
+	if _, _, err := c.ListPostsPage(context.Background(), ""); err == nil {
+		t.Fatal("expected the 400 to be returned")
+	}
+	if len(transport.bodies) != 1 {
+		t.Errorf("got %d requests, want 1", len(transport.bodies))
+	}
+}
+
+func TestRetryAfterCappedAtMaxDelay(t *testing.T) {
+	rt := &retryTransport{cfg: RetryConfig{BaseDelay: time.Second, MaxDelay: 5 * time.Second}}
+
+	wait, retry := rt.retryAfter(response(http.StatusTooManyRequests, "", http.Header{"Retry-After": {"60"}}), nil, 0)
+	if !retry || wait != 5*time.Second {
+		t.Errorf("Retry-After 60: got (%v, %v), want (5s, true)", wait, retry)
+	}
+
+	wait, retry = rt.retryAfter(response(http.StatusBadGateway, "", nil), nil, 2)
+	if !retry || wait != 4*time.Second {
+		t.Errorf("502 on attempt 2: got (%v, %v), want (4s, true)", wait, retry)
+	}
+}
+
+func TestRetryConfigFromEnv(t *testing.T) {
+	t.Setenv("NOTION_MAX_RETRIES", "7")
+	t.Setenv("NOTION_RETRY_MAX_DELAY", "1m")
+	t.Setenv("NOTION_REQUEST_TIMEOUT", "")
+
+	cfg, err := RetryConfigFromEnv()
+	if err != nil {
+		t.Fatalf("RetryConfigFromEnv failed: %v", err)
+	}
+	if cfg.MaxRetries != 7 || cfg.MaxDelay != time.Minute || cfg.RequestTimeout != DefaultRequestTimeout {
+		t.Errorf("got %+v", cfg)
+	}
+
+	t.Setenv("NOTION_MAX_RETRIES", "-1")
+	if _, err := RetryConfigFromEnv(); err == nil {
+		t.Error("expected an error for a negative NOTION_MAX_RETRIES")
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	MaxUsersLimit     = 100
+	DefaultUsersLimit = 50
+)
+
+// AdminListUsers pages through all users for operators
+func (s *AuthService) AdminListUsers(ctx context.Context, req *pb.AdminListUsersRequest) (*pb.AdminListUsersResponse, error) {
+	if err := requireM2M(ctx); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = DefaultUsersLimit
+	}
+	if limit > MaxUsersLimit {
+		limit = MaxUsersLimit
+	}
+
+	offset := int(req.Offset)
+	if offset < 0 {
+		offset = 0
+	}
+
+	users, total, err := s.db.ListUsers(ctx, req.Email, limit, offset)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list users: %v", err)
+	}
+
+	pbUsers := make([]*pb.User, len(users))
+	for i := range users {
+		pbUsers[i] = adminUserToProto(&users[i])
+	}
+
+	return &pb.AdminListUsersResponse{
+		Users:  pbUsers,
+		Total:  int32(total),
+		Limit:  int32(limit),
+		Offset: int32(offset),
+	}, nil
+}
+
+// AdminDisableUser disables a user's account so they can no longer log in
+func (s *AuthService) AdminDisableUser(ctx context.Context, req *pb.AdminDisableUserRequest) (*pb.AdminDisableUserResponse, error) {
+	if err := requireM2M(ctx); err != nil {
+		return nil, err
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	reason := disabledReasonToString(req.Reason)
+	if reason == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	if _, err := s.getUserForAdmin(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	if err := s.db.DisableUser(ctx, req.UserId, reason); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to disable user: %v", err)
+	}
+
+	user, err := s.getUserForAdmin(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AdminDisableUserResponse{
+		User: adminUserToProto(user),
+	}, nil
+}
+
+// AdminEnableUser re-enables a disabled account
+func (s *AuthService) AdminEnableUser(ctx context.Context, req *pb.AdminEnableUserRequest) (*pb.AdminEnableUserResponse, error) {
+	if err := requireM2M(ctx); err != nil {
+		return nil, err
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if _, err := s.getUserForAdmin(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	if err := s.db.EnableUser(ctx, req.UserId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to enable user: %v", err)
+	}
+
+	user, err := s.getUserForAdmin(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AdminEnableUserResponse{
+		User: adminUserToProto(user),
+	}, nil
+}
+
+// AdminUnlockAccount clears a lockout caused by too many failed logins
+func (s *AuthService) AdminUnlockAccount(ctx context.Context, req *pb.AdminUnlockAccountRequest) (*pb.AdminUnlockAccountResponse, error) {
+	if err := requireM2M(ctx); err != nil {
+		return nil, err
+	}
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if _, err := s.getUserForAdmin(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+	if err := s.db.UnlockAccount(ctx, req.UserId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to unlock account: %v", err)
+	}
+
+	user, err := s.getUserForAdmin(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AdminUnlockAccountResponse{
+		User: adminUserToProto(user),
+	}, nil
+}
+
+// getUserForAdmin loads a user, mapping a missing row to NotFound
+func (s *AuthService) getUserForAdmin(ctx context.Context, userID string) (*db.User, error) {
+	user, err := s.db.GetUser(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+	return user, nil
+}
+
+// adminUserToProto converts a user for admin responses, leaving out the
+// Notion key so operators never see users' credentials
+func adminUserToProto(u *db.User) *pb.User {
+	pbUser := userToProto(u)
+	pbUser.NotionKey = nil
+	return pbUser
+}
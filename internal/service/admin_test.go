@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAuthService(t *testing.T) (*AuthService, sqlmock.Sqlmock, func()) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	database, err := db.NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	cleanup := func() { _ = sqlDB.Close() }
+	return NewAuthService(database), mock, cleanup
+}
+
+func TestAdminListUsers_RequiresM2M(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	for name, ctx := range map[string]context.Context{
+		"api key":         auth.SetAuthContext(context.Background(), "user1", "apikey"),
+		"unauthenticated": context.Background(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.AdminListUsers(ctx, &pb.AdminListUsersRequest{})
+			if status.Code(err) != codes.PermissionDenied {
+				t.Errorf("expected PermissionDenied, got %v", err)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestAdminListUsers(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "User" WHERE email ILIKE \$1`).
+		WithArgs("%example.com%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE email ILIKE \$1 ORDER BY "createdAt" DESC, id LIMIT \$2 OFFSET \$3`).
+		WithArgs("%example.com%", MaxUsersLimit, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "passwordHash", "notionKey", "createdAt", "updatedAt"}).
+			AddRow("user3", "c@example.com", "hash", "secret_notion_key", now, now))
+
+	ctx := auth.SetAuthContext(context.Background(), "m2m", "m2m")
+	resp, err := svc.AdminListUsers(ctx, &pb.AdminListUsersRequest{Email: "example.com", Limit: 500, Offset: 2})
+	if err != nil {
+		t.Fatalf("AdminListUsers: %v", err)
+	}
+	if resp.Total != 3 || resp.Limit != MaxUsersLimit || resp.Offset != 2 {
+		t.Errorf("AdminListUsers total/limit/offset = %d/%d/%d, want 3/%d/2", resp.Total, resp.Limit, resp.Offset, MaxUsersLimit)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].Id != "user3" {
+		t.Fatalf("AdminListUsers users = %v, want user3", resp.Users)
+	}
+	if resp.Users[0].NotionKey != nil {
+		t.Errorf("AdminListUsers returned a Notion key: %q", *resp.Users[0].NotionKey)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAdminDisableEnableUser_RequiresM2M(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	if _, err := svc.AdminDisableUser(ctx, &pb.AdminDisableUserRequest{UserId: "user1", Reason: pb.DisabledReason_OTHER}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("AdminDisableUser: expected PermissionDenied, got %v", err)
+	}
+	if _, err := svc.AdminEnableUser(ctx, &pb.AdminEnableUserRequest{UserId: "user1"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("AdminEnableUser: expected PermissionDenied, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestAdminDisableUser_RequiresReason(t *testing.T) {
+	svc, _, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "m2m", "m2m")
+	_, err := svc.AdminDisableUser(ctx, &pb.AdminDisableUserRequest{UserId: "user1"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestAdminDisableUser_NotFound(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("missing", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "m2m", "m2m")
+	_, err := svc.AdminDisableUser(ctx, &pb.AdminDisableUserRequest{UserId: "missing", Reason: pb.DisabledReason_OTHER})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+// TestAdminDisableUser_BlocksAuthenticate disables a user through the RPC and
+// checks that their next login is refused.
+func TestAdminDisableUser_BlocksAuthenticate(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	now := time.Now()
+	userCols := []string{"id", "email", "passwordHash", "disabled", "disabledReason", "createdAt", "updatedAt"}
+
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow("user1", "a@example.com", string(hash), false, nil, now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "User" SET "disabled"=\$1,"disabledReason"=\$2,"updatedAt"=\$3 WHERE id = \$4`).
+		WithArgs(true, "security_concern", sqlmock.AnyArg(), "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow("user1", "a@example.com", string(hash), true, "security_concern", now, now))
+
+	m2mCtx := auth.SetAuthContext(context.Background(), "m2m", "m2m")
+	resp, err := svc.AdminDisableUser(m2mCtx, &pb.AdminDisableUserRequest{UserId: "user1", Reason: pb.DisabledReason_SECURITY_CONCERN})
+	if err != nil {
+		t.Fatalf("AdminDisableUser: %v", err)
+	}
+	if !resp.User.Disabled || resp.User.GetDisabledReason() != pb.DisabledReason_SECURITY_CONCERN {
+		t.Errorf("AdminDisableUser user disabled=%v reason=%v, want true/SECURITY_CONCERN", resp.User.Disabled, resp.User.GetDisabledReason())
+	}
+
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE email = \$1`).
+		WithArgs("a@example.com", 1).
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow("user1", "a@example.com", string(hash), true, "security_concern", now, now))
+	expectLoginEvent(mock, "user1", false)
+
+	_, err = svc.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: "a@example.com", Password: "password"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Authenticate after disable: expected PermissionDenied, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAdminEnableUser(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	now := time.Now()
+	userCols := []string{"id", "email", "disabled", "disabledReason", "createdAt", "updatedAt"}
+
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow("user1", "a@example.com", true, "other", now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "User" SET "disabled"=\$1,"disabledReason"=\$2,"updatedAt"=\$3 WHERE id = \$4`).
+		WithArgs(false, nil, sqlmock.AnyArg(), "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow("user1", "a@example.com", false, nil, now, now))
+
+	ctx := auth.SetAuthContext(context.Background(), "m2m", "m2m")
+	resp, err := svc.AdminEnableUser(ctx, &pb.AdminEnableUserRequest{UserId: "user1"})
+	if err != nil {
+		t.Fatalf("AdminEnableUser: %v", err)
+	}
+	if resp.User.Disabled || resp.User.DisabledReason != nil {
+		t.Errorf("AdminEnableUser user disabled=%v reason=%v, want enabled", resp.User.Disabled, resp.User.DisabledReason)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestAdminUnlockAccount_RequiresM2M(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	for name, ctx := range map[string]context.Context{
+		"api key":         auth.SetAuthContext(context.Background(), "user1", "apikey"),
+		"unauthenticated": context.Background(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.AdminUnlockAccount(ctx, &pb.AdminUnlockAccountRequest{UserId: "user1"})
+			if status.Code(err) != codes.PermissionDenied {
+				t.Errorf("expected PermissionDenied, got %v", err)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestAdminUnlockAccount(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	now := time.Now()
+	userCols := []string{"id", "email", "failedLoginAttempts", "lastFailedLogin", "createdAt", "updatedAt"}
+
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow("user1", "a@example.com", 10, now, now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "User" SET "failedLoginAttempts"=\$1,"lastFailedLogin"=\$2,"updatedAt"=\$3 WHERE id = \$4`).
+		WithArgs(0, nil, sqlmock.AnyArg(), "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE id = \$1`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userCols).AddRow("user1", "a@example.com", 0, nil, now, now))
+
+	ctx := auth.SetAuthContext(context.Background(), "m2m", "m2m")
+	resp, err := svc.AdminUnlockAccount(ctx, &pb.AdminUnlockAccountRequest{UserId: "user1"})
+	if err != nil {
+		t.Fatalf("AdminUnlockAccount: %v", err)
+	}
+	if resp.User.Id != "user1" {
+		t.Errorf("AdminUnlockAccount user = %q, want user1", resp.User.Id)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -4,10 +4,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"log/slog"
 
+	"github.com/icco/etu-backend/internal/crypto"
 	"github.com/icco/etu-backend/internal/db"
 	pb "github.com/icco/etu-backend/proto"
-	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -16,12 +17,28 @@ import (
 // ApiKeysService implements the ApiKeysService gRPC service
 type ApiKeysService struct {
 	pb.UnimplementedApiKeysServiceServer
-	db *db.DB
+	db       *db.DB
+	keyCache keyInvalidator
+	usage    keyUsageRecorder
+	hasher   *crypto.APIKeyHasher
 }
 
-// NewApiKeysService creates a new ApiKeysService
-func NewApiKeysService(database *db.DB) *ApiKeysService {
-	return &ApiKeysService{db: database}
+// keyInvalidator forgets cached verifications of a deleted API key
+type keyInvalidator interface {
+	InvalidateAPIKey(keyID string)
+}
+
+// keyUsageRecorder counts API key uses and writes them out in batches
+type keyUsageRecorder interface {
+	RecordAPIKeyUse(keyID string)
+}
+
+// NewApiKeysService creates a new ApiKeysService. keyCache, if non-nil, is
+// told about deleted keys so they stop authenticating immediately. usage, if
+// non-nil, counts keys checked by VerifyApiKey. hasher hashes new keys and
+// checks keys in VerifyApiKey.
+func NewApiKeysService(database *db.DB, keyCache keyInvalidator, usage keyUsageRecorder, hasher *crypto.APIKeyHasher) *ApiKeysService {
+	return &ApiKeysService{db: database, keyCache: keyCache, usage: usage, hasher: hasher}
 }
 
 // CreateApiKey creates a new API key for a user
@@ -49,13 +66,13 @@ func (s *ApiKeysService) CreateApiKey(ctx context.Context, req *pb.CreateApiKeyR
 	keyPrefix := rawKey[:12]
 
 	// Hash the full key for storage
-	keyHash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.DefaultCost)
+	keyHash, err := s.hasher.Hash(rawKey)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to hash API key: %v", err)
 	}
 
 	// Create the API key in database
-	apiKey, err := s.db.CreateApiKey(ctx, req.UserId, req.Name, keyPrefix, string(keyHash))
+	apiKey, err := s.db.CreateApiKey(ctx, req.UserId, req.Name, keyPrefix, keyHash)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create API key: %v", err)
 	}
@@ -110,6 +127,9 @@ func (s *ApiKeysService) DeleteApiKey(ctx context.Context, req *pb.DeleteApiKeyR
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to delete API key: %v", err)
 	}
+	if deleted && s.keyCache != nil {
+		s.keyCache.InvalidateAPIKey(req.KeyId)
+	}
 
 	return &pb.DeleteApiKeyResponse{
 		Success: deleted,
@@ -138,11 +158,15 @@ func (s *ApiKeysService) VerifyApiKey(ctx context.Context, req *pb.VerifyApiKeyR
 
 	// Check each potential match
 	for _, k := range keys {
-		if err := bcrypt.CompareHashAndPassword([]byte(k.KeyHash), []byte(req.RawKey)); err == nil {
-			// Update last used timestamp asynchronously
-			go func(keyID string) {
-				_ = s.db.UpdateApiKeyLastUsed(context.Background(), keyID)
-			}(k.ID)
+		if match, rehash := s.hasher.Verify(k.KeyHash, req.RawKey); match {
+			if rehash {
+				s.rehash(ctx, k, req.RawKey)
+			}
+
+			// Usage is batched rather than written on every check
+			if s.usage != nil {
+				s.usage.RecordAPIKeyUse(k.ID)
+			}
 
 			return &pb.VerifyApiKeyResponse{
 				Valid:  true,
@@ -154,13 +178,26 @@ func (s *ApiKeysService) VerifyApiKey(ctx context.Context, req *pb.VerifyApiKeyR
 	return &pb.VerifyApiKeyResponse{Valid: false}, nil
 }
 
+// rehash replaces a key's bcrypt hash with an HMAC. It is best effort: the
+// key has already been verified, and a failed rehash is retried next use.
+func (s *ApiKeysService) rehash(ctx context.Context, k db.ApiKey, rawKey string) {
+	newHash, err := s.hasher.Hash(rawKey)
+	if err == nil {
+		err = s.db.RehashApiKey(ctx, k.ID, k.KeyHash, newHash)
+	}
+	if err != nil {
+		slog.Warn("failed to rehash API key", "key_id", k.ID, "error", err)
+	}
+}
+
 // apiKeyToProto converts a db.ApiKey to a protobuf ApiKey
 func apiKeyToProto(k *db.ApiKey) *pb.ApiKey {
 	pbKey := &pb.ApiKey{
-		Id:        k.ID,
-		Name:      k.Name,
-		KeyPrefix: k.KeyPrefix,
-		CreatedAt: timestamppb.New(k.CreatedAt),
+		Id:         k.ID,
+		Name:       k.Name,
+		KeyPrefix:  k.KeyPrefix,
+		CreatedAt:  timestamppb.New(k.CreatedAt),
+		UsageCount: k.UsageCount,
 	}
 
 	if k.LastUsed != nil {
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/crypto"
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeKeyCache records invalidated key IDs
+type fakeKeyCache struct {
+	invalidated []string
+}
+
+func (f *fakeKeyCache) InvalidateAPIKey(keyID string) {
+	f.invalidated = append(f.invalidated, keyID)
+}
+
+// fakeKeyUsage records API key uses by key ID
+type fakeKeyUsage struct {
+	used []string
+}
+
+func (f *fakeKeyUsage) RecordAPIKeyUse(keyID string) {
+	f.used = append(f.used, keyID)
+}
+
+func TestDeleteApiKey_InvalidatesCache(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	database, err := db.NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	cache := &fakeKeyCache{}
+	svc := NewApiKeysService(database, cache, nil, nil)
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "ApiKey" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("missing", "user1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "ApiKey" WHERE id = \$1 AND "userId" = \$2`).
+		WithArgs("key1", "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if _, err := svc.DeleteApiKey(ctx, &pb.DeleteApiKeyRequest{UserId: "user1", KeyId: "missing"}); err != nil {
+		t.Fatalf("DeleteApiKey(missing): %v", err)
+	}
+	resp, err := svc.DeleteApiKey(ctx, &pb.DeleteApiKeyRequest{UserId: "user1", KeyId: "key1"})
+	if err != nil {
+		t.Fatalf("DeleteApiKey(key1): %v", err)
+	}
+	if !resp.Success {
+		t.Error("DeleteApiKey(key1) reported no deletion")
+	}
+
+	if len(cache.invalidated) != 1 || cache.invalidated[0] != "key1" {
+		t.Errorf("invalidated = %v, want [key1]", cache.invalidated)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestVerifyApiKey_PrefixMatches(t *testing.T) {
+	const rawKey = "etu_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	const otherKey = "etu_01234567ffffffffffffffffffffffffffffffffffffffffffffffffffffffff"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	otherHash, err := bcrypt.GenerateFromPassword([]byte(otherKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		rows       [][]string // id, keyHash, userId
+		wantValid  bool
+		wantUserID string
+	}{
+		{name: "single match", rows: [][]string{{"key1", string(hash), "user1"}}, wantValid: true, wantUserID: "user1"},
+		{name: "single row wrong key", rows: [][]string{{"key2", string(otherHash), "user2"}}, wantValid: false},
+		{
+			name:       "colliding prefixes",
+			rows:       [][]string{{"key2", string(otherHash), "user2"}, {"key1", string(hash), "user1"}},
+			wantValid:  true,
+			wantUserID: "user1",
+		},
+		{name: "no rows", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sqlDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("sqlmock.New: %v", err)
+			}
+			defer func() { _ = sqlDB.Close() }()
+
+			database, err := db.NewFromConn(sqlDB)
+			if err != nil {
+				t.Fatalf("NewFromConn: %v", err)
+			}
+			usage := &fakeKeyUsage{}
+			svc := NewApiKeysService(database, nil, usage, nil)
+
+			rows := sqlmock.NewRows([]string{"id", "keyHash", "userId"})
+			for _, r := range tt.rows {
+				rows.AddRow(r[0], r[1], r[2])
+			}
+			mock.ExpectQuery(`SELECT \* FROM "ApiKey" WHERE "keyPrefix" = \$1`).
+				WithArgs(rawKey[:12]).
+				WillReturnRows(rows)
+
+			resp, err := svc.VerifyApiKey(context.Background(), &pb.VerifyApiKeyRequest{RawKey: rawKey})
+			if err != nil {
+				t.Fatalf("VerifyApiKey: %v", err)
+			}
+			if resp.Valid != tt.wantValid || resp.GetUserId() != tt.wantUserID {
+				t.Errorf("VerifyApiKey = valid %v user %q, want valid %v user %q", resp.Valid, resp.GetUserId(), tt.wantValid, tt.wantUserID)
+			}
+
+			// Valid keys are counted through the batched recorder, never
+			// written directly
+			var wantUsed []string
+			if tt.wantValid {
+				wantUsed = []string{"key1"}
+			}
+			if !slices.Equal(usage.used, wantUsed) {
+				t.Errorf("recorded uses = %v, want %v", usage.used, wantUsed)
+			}
+		})
+	}
+}
+
+func TestVerifyApiKey_RehashesBcrypt(t *testing.T) {
+	const rawKey = "etu_0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(rawKey), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	hasher, err := crypto.NewAPIKeyHasher([]byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("NewAPIKeyHasher: %v", err)
+	}
+	wantHash, err := hasher.Hash(rawKey)
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+	database, err := db.NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+	svc := NewApiKeysService(database, nil, nil, hasher)
+
+	mock.ExpectQuery(`SELECT \* FROM "ApiKey" WHERE "keyPrefix" = \$1`).
+		WithArgs(rawKey[:12]).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "keyHash", "userId"}).AddRow("key1", string(hash), "user1"))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "ApiKey" SET "keyHash"=\$1 WHERE id = \$2 AND "keyHash" = \$3`).
+		WithArgs(wantHash, "key1", string(hash)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	resp, err := svc.VerifyApiKey(context.Background(), &pb.VerifyApiKeyRequest{RawKey: rawKey})
+	if err != nil {
+		t.Fatalf("VerifyApiKey: %v", err)
+	}
+	if !resp.Valid || resp.GetUserId() != "user1" {
+		t.Errorf("VerifyApiKey = valid %v user %q, want valid user1", resp.Valid, resp.GetUserId())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -2,9 +2,13 @@ package service
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/crypto"
 	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/models"
 	pb "github.com/icco/etu-backend/proto"
 	"golang.org/x/crypto/bcrypt"
 	"google.golang.org/grpc/codes"
@@ -15,12 +19,24 @@ import (
 // AuthService implements the AuthService gRPC service
 type AuthService struct {
 	pb.UnimplementedAuthServiceServer
-	db *db.DB
+	db       *db.DB
+	log      *slog.Logger
+	password passwordPolicy
 }
 
+const (
+	MaxLoginHistoryLimit     = 100
+	DefaultLoginHistoryLimit = 20
+)
+
 // NewAuthService creates a new AuthService
 func NewAuthService(database *db.DB) *AuthService {
-	return &AuthService{db: database}
+	log := slog.Default()
+	return &AuthService{
+		db:       database,
+		log:      log,
+		password: passwordPolicyFromEnv(log),
+	}
 }
 
 // Register creates a new user account
@@ -31,6 +47,9 @@ func (s *AuthService) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 	if req.Password == "" {
 		return nil, status.Error(codes.InvalidArgument, "password is required")
 	}
+	if err := s.password.validate(req.Password); err != nil {
+		return nil, err
+	}
 
 	// Check if user already exists
 	existingUser, err := s.db.GetUserByEmail(ctx, req.Email)
@@ -42,7 +61,7 @@ func (s *AuthService) Register(ctx context.Context, req *pb.RegisterRequest) (*p
 	}
 
 	// Hash the password
-	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(req.Password), crypto.BcryptCost())
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to hash password: %v", err)
 	}
@@ -78,11 +97,13 @@ func (s *AuthService) Authenticate(ctx context.Context, req *pb.AuthenticateRequ
 
 	// Check if account is disabled
 	if user.Disabled {
+		s.recordLogin(ctx, user.ID, false)
 		return nil, status.Error(codes.PermissionDenied, "account is disabled")
 	}
 
 	// Check if account is locked due to too many failed attempts
 	if user.FailedLoginAttempts >= 10 {
+		s.recordLogin(ctx, user.ID, false)
 		return nil, status.Error(codes.PermissionDenied, "account is locked")
 	}
 
@@ -92,6 +113,7 @@ func (s *AuthService) Authenticate(ctx context.Context, req *pb.AuthenticateRequ
 		if recordErr := s.db.RecordFailedLogin(ctx, user.ID); recordErr != nil {
 			return nil, status.Errorf(codes.Internal, "failed to record login attempt: %v", recordErr)
 		}
+		s.recordLogin(ctx, user.ID, false)
 		return &pb.AuthenticateResponse{Success: false}, nil
 	}
 
@@ -99,6 +121,7 @@ func (s *AuthService) Authenticate(ctx context.Context, req *pb.AuthenticateRequ
 	if clearErr := s.db.RecordSuccessfulLogin(ctx, user.ID); clearErr != nil {
 		return nil, status.Errorf(codes.Internal, "failed to clear login attempts: %v", clearErr)
 	}
+	s.recordLogin(ctx, user.ID, true)
 
 	return &pb.AuthenticateResponse{
 		Success: true,
@@ -106,6 +129,16 @@ func (s *AuthService) Authenticate(ctx context.Context, req *pb.AuthenticateRequ
 	}, nil
 }
 
+// recordLogin writes a password login to the audit log. Logging is best
+// effort and never changes the outcome of the login.
+func (s *AuthService) recordLogin(ctx context.Context, userID string, success bool) {
+	// x-forwarded-for can be set by any caller, so only trust the connection
+	_, userAgent := auth.ClientInfo(ctx)
+	if err := s.db.RecordLoginEvent(ctx, userID, success, models.LoginAuthPassword, auth.RemoteIP(ctx), userAgent); err != nil {
+		s.log.Error("failed to record login event", "user_id", userID, "error", err)
+	}
+}
+
 // GetUser retrieves a user by ID
 func (s *AuthService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
 	if req.UserId == "" {
@@ -130,6 +163,37 @@ func (s *AuthService) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.
 	}, nil
 }
 
+// GetSubscriptionStatus returns a user's subscription status, period end, and
+// derived premium and active flags
+func (s *AuthService) GetSubscriptionStatus(ctx context.Context, req *pb.GetSubscriptionStatusRequest) (*pb.GetSubscriptionStatusResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	user, err := s.db.GetUser(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	resp := &pb.GetSubscriptionStatusResponse{
+		SubscriptionStatus: user.SubscriptionStatus,
+		IsPremium:          user.IsPremium(),
+		IsActive:           user.IsActive(time.Now()),
+	}
+	if user.SubscriptionEnd != nil {
+		resp.SubscriptionEnd = timestamppb.New(*user.SubscriptionEnd)
+	}
+	return resp, nil
+}
+
 // GetUserByStripeCustomerId retrieves a user by Stripe customer ID
 func (s *AuthService) GetUserByStripeCustomerId(ctx context.Context, req *pb.GetUserByStripeCustomerIdRequest) (*pb.GetUserByStripeCustomerIdResponse, error) {
 	if req.StripeCustomerId == "" {
@@ -184,6 +248,47 @@ func (s *AuthService) UpdateUserSubscription(ctx context.Context, req *pb.Update
 	}, nil
 }
 
+// GetLoginHistory lists a user's recent authentication attempts
+func (s *AuthService) GetLoginHistory(ctx context.Context, req *pb.GetLoginHistoryRequest) (*pb.GetLoginHistoryResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = DefaultLoginHistoryLimit
+	}
+	if limit > MaxLoginHistoryLimit {
+		limit = MaxLoginHistoryLimit
+	}
+
+	events, err := s.db.ListLoginEvents(ctx, req.UserId, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get login history: %v", err)
+	}
+
+	pbEvents := make([]*pb.LoginEvent, len(events))
+	for i, e := range events {
+		pbEvents[i] = &pb.LoginEvent{
+			Id:        e.ID,
+			Success:   e.Success,
+			AuthType:  e.AuthType,
+			IpAddress: e.IPAddress,
+			UserAgent: e.UserAgent,
+			CreatedAt: timestamppb.New(e.CreatedAt),
+		}
+	}
+
+	return &pb.GetLoginHistoryResponse{
+		Events: pbEvents,
+	}, nil
+}
+
 // userToProto converts a db.User to a protobuf User
 func userToProto(u *db.User) *pb.User {
 	pbUser := &pb.User{
@@ -193,6 +298,8 @@ func userToProto(u *db.User) *pb.User {
 		CreatedAt:          timestamppb.New(u.CreatedAt),
 		UpdatedAt:          timestamppb.New(u.UpdatedAt),
 		Disabled:           u.Disabled,
+		IsPremium:          u.IsPremium(),
+		IsActive:           u.IsActive(time.Now()),
 	}
 
 	if u.Name != nil {
@@ -213,6 +320,10 @@ func userToProto(u *db.User) *pb.User {
 	if u.NotionDatabaseName != nil {
 		pbUser.NotionDatabaseName = u.NotionDatabaseName
 	}
+	if u.SyncIntervalMinutes != nil {
+		minutes := int32(*u.SyncIntervalMinutes)
+		pbUser.SyncIntervalMinutes = &minutes
+	}
 	if u.DisabledReason != nil && *u.DisabledReason != "" {
 		// Convert string to enum
 		reason := stringToDisabledReason(*u.DisabledReason)
@@ -239,3 +350,22 @@ func stringToDisabledReason(reason string) pb.DisabledReason {
 		return pb.DisabledReason_UNSPECIFIED
 	}
 }
+
+// disabledReasonToString converts the protobuf enum to the string stored on
+// the user, the inverse of stringToDisabledReason
+func disabledReasonToString(reason pb.DisabledReason) string {
+	switch reason {
+	case pb.DisabledReason_TERMS_VIOLATION:
+		return "terms_violation"
+	case pb.DisabledReason_SECURITY_CONCERN:
+		return "security_concern"
+	case pb.DisabledReason_USER_REQUEST:
+		return "user_request"
+	case pb.DisabledReason_PAYMENT_ISSUE:
+		return "payment_issue"
+	case pb.DisabledReason_OTHER:
+		return "other"
+	default:
+		return ""
+	}
+}
@@ -27,3 +27,12 @@ func verifyUserAuthorization(ctx context.Context, requestedUserID string) error
 
 	return nil
 }
+
+// requireM2M rejects callers that did not authenticate with an M2M token, for
+// admin methods that act across users
+func requireM2M(ctx context.Context) error {
+	if !auth.IsM2MAuth(ctx) {
+		return status.Error(codes.PermissionDenied, "admin access requires M2M authentication")
+	}
+	return nil
+}
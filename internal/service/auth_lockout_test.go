@@ -51,6 +51,7 @@ func TestAuthenticate_AccountLockout(t *testing.T) {
 			WithArgs(0, nil, sqlmock.AnyArg(), "user123").
 			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
+		expectLoginEvent(mock, "user123", true)
 
 		resp, err := service.Authenticate(ctx, &pb.AuthenticateRequest{
 			Email:    "test@example.com",
@@ -97,9 +98,10 @@ func TestAuthenticate_AccountLockout(t *testing.T) {
 			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "user123").
+				sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "user123").
 			WillReturnResult(sqlmock.NewResult(0, 1))
 		mock.ExpectCommit()
+		expectLoginEvent(mock, "user123", false)
 
 		resp, err := service.Authenticate(ctx, &pb.AuthenticateRequest{
 			Email:    "test@example.com",
@@ -127,6 +129,7 @@ func TestAuthenticate_AccountLockout(t *testing.T) {
 				"user123", "test@example.com", string(passwordHash), "free", time.Now(), time.Now(),
 				true, "Terms violation", 0,
 			))
+		expectLoginEvent(mock, "user123", false)
 
 		_, err := service.Authenticate(ctx, &pb.AuthenticateRequest{
 			Email:    "test@example.com",
@@ -159,6 +162,7 @@ func TestAuthenticate_AccountLockout(t *testing.T) {
 				"user123", "test@example.com", string(passwordHash), "free", time.Now(), time.Now(),
 				false, 10,
 			))
+		expectLoginEvent(mock, "user123", false)
 
 		_, err := service.Authenticate(ctx, &pb.AuthenticateRequest{
 			Email:    "test@example.com",
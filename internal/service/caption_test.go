@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUpdateImageCaption_InvalidArguments(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name string
+		req  *pb.UpdateImageCaptionRequest
+	}{
+		{name: "missing user_id", req: &pb.UpdateImageCaptionRequest{ImageId: "img1", Caption: "hi"}},
+		{name: "missing image_id", req: &pb.UpdateImageCaptionRequest{UserId: "user1", Caption: "hi"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.UpdateImageCaption(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateImageCaption_NotFound(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT "NoteImage".(.+) FROM "NoteImage"`).
+		WithArgs("img1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.UpdateImageCaption(ctx, &pb.UpdateImageCaptionRequest{UserId: "user1", ImageId: "img1", Caption: "hi"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestUpdateImageCaption(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT "NoteImage".(.+) FROM "NoteImage"`).
+		WithArgs("img1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "extractedText", "mimeType", "width", "height", "createdAt"}).
+			AddRow("img1", "note1", "https://example.com/img1", "notes/note1/img1", "EXIT", "image/png", 800, 600, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "NoteImage" SET "caption"`).
+		WithArgs("exit sign above a door", "img1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.UpdateImageCaption(ctx, &pb.UpdateImageCaptionRequest{UserId: "user1", ImageId: "img1", Caption: "exit sign above a door"})
+	if err != nil {
+		t.Fatalf("UpdateImageCaption: %v", err)
+	}
+	if resp.Image.Caption != "exit sign above a door" {
+		t.Errorf("Caption = %q", resp.Image.Caption)
+	}
+	if resp.Image.ExtractedText != "EXIT" {
+		t.Errorf("ExtractedText = %q, want OCR text untouched", resp.Image.ExtractedText)
+	}
+	if resp.Image.Width != 800 || resp.Image.Height != 600 {
+		t.Errorf("dimensions = %dx%d, want 800x600", resp.Image.Width, resp.Image.Height)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestNoteColor_RejectsUnknownColors(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	magenta := "magenta"
+
+	if _, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{UserId: "user1", Content: "hi", Color: "magenta"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("CreateNote: expected InvalidArgument, got %v", err)
+	}
+	if _, err := svc.UpdateNote(ctx, &pb.UpdateNoteRequest{UserId: "user1", Id: "note1", Color: &magenta}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("UpdateNote: expected InvalidArgument, got %v", err)
+	}
+	if _, err := svc.ListNotes(ctx, &pb.ListNotesRequest{UserId: "user1", Color: "Blue"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("ListNotes: expected InvalidArgument, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestListNotes_FiltersByColor(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT count\(.+\) FROM "Note" WHERE "userId" = \$1 AND color = \$2`).
+		WithArgs("user1", "green").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE "userId" = \$1 AND color = \$2`).
+		WithArgs("user1", "green", DefaultNotesLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId", "color"}).
+			AddRow("note1", "garden", now, now, "user1", "green"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"note_id", "id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "Note" WHERE "userId" = \$1$`).
+		WithArgs("user1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ListNotes(ctx, &pb.ListNotesRequest{UserId: "user1", Color: "green"})
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	if len(resp.Notes) != 1 || resp.Notes[0].Color != "green" {
+		t.Errorf("notes = %v, want one green note", resp.Notes)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
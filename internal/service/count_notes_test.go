@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCountNotes_InvalidArguments(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name string
+		req  *pb.CountNotesRequest
+	}{
+		{name: "missing user_id", req: &pb.CountNotesRequest{}},
+		{name: "unknown color", req: &pb.CountNotesRequest{UserId: "user1", Color: "plaid"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CountNotes(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCountNotes(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "Note" WHERE "userId" = \$1 AND "createdAt" >= \$2`).
+		WithArgs("user1", "2024-01-01").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1234))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.CountNotes(ctx, &pb.CountNotesRequest{UserId: "user1", StartDate: "2024-01-01"})
+	if err != nil {
+		t.Fatalf("CountNotes: %v", err)
+	}
+	if resp.Total != 1234 {
+		t.Errorf("Total = %d, want 1234", resp.Total)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+)
+
+func TestGetNote_Counts(t *testing.T) {
+	tests := []struct {
+		name           string
+		countImageText bool
+		wantWords      int64
+		wantChars      int64
+	}{
+		{name: "content only", wantWords: 3, wantChars: 14},
+		{name: "with image text", countImageText: true, wantWords: 5, wantChars: 22},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestNotesService(t)
+			defer cleanup()
+
+			now := time.Now()
+			mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+				WithArgs("note1", "user1", 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+					AddRow("note1", "café au  lait\n", now, now, "user1"))
+			mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+				WithArgs("note1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+			mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+				WithArgs("note1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "extractedText", "mimeType", "createdAt"}).
+					AddRow("img1", "note1", "https://example.com/img1", "notes/note1/img1", "open 9-5", "image/png", now).
+					AddRow("img2", "note1", "https://example.com/img2", "notes/note1/img2", "", "image/png", now))
+			mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+				WithArgs("note1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+			resp, err := svc.GetNote(ctx, &pb.GetNoteRequest{UserId: "user1", Id: "note1", CountImageText: tt.countImageText})
+			if err != nil {
+				t.Fatalf("GetNote: %v", err)
+			}
+			if resp.Note.WordCount != tt.wantWords || resp.Note.CharCount != tt.wantChars {
+				t.Errorf("got word_count=%d char_count=%d, want %d and %d",
+					resp.Note.WordCount, resp.Note.CharCount, tt.wantWords, tt.wantChars)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled mock expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/storage"
+	pb "github.com/icco/etu-backend/proto"
+)
+
+// expectReserveQuota expects CreateNote to reserve upload quota for a free
+// user1 with nothing stored yet
+func expectReserveQuota(mock sqlmock.Sqlmock, now time.Time) {
+	mock.ExpectQuery(`SELECT \* FROM "User"`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(
+			"user1", "a@b.com", nil, nil, "hash",
+			"free", nil, now, nil, nil, nil, nil, now,
+			false, nil, 0, nil,
+		))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("user1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "StorageReservation"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(0, 0))
+	mock.ExpectQuery(`FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(0, 0))
+	mock.ExpectQuery(`FROM "StorageReservation"`).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+	mock.ExpectExec(`INSERT INTO "StorageReservation"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+// expectReleaseQuota expects CreateNote to release its upload reservation
+func expectReleaseQuota(mock sqlmock.Sqlmock) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "StorageReservation"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func TestCreateNote_ReturnsStoredImages(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	store := storage.NewMemory()
+	svc.storage = store
+
+	now := time.Now()
+	images := []*pb.ImageUpload{
+		{Data: pngHeader, MimeType: "image/png", Caption: "first"},
+		{Data: jpegHeader, MimeType: "image/jpeg", Caption: "second"},
+	}
+
+	expectReserveQuota(mock, now)
+
+	// Create the note
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "Note"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	// Save each image
+	for i := range images {
+		mock.ExpectQuery(`SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM "NoteImage"`).
+			WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(i))
+		mock.ExpectBegin()
+		mock.ExpectExec(`INSERT INTO "NoteImage"`).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+	}
+
+	// Reload from the primary; the stored rows come back in position order
+	mock.ExpectQuery(`SELECT \* FROM "Note"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "userId", "createdAt", "updatedAt"}).
+			AddRow("note1", "", "user1", now, now))
+	mock.ExpectQuery(`FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteImage" WHERE "noteId" = \$1 ORDER BY position ASC`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "caption", "mimeType", "width", "height", "position", "createdAt"}).
+			AddRow("img-a", "note1", "https://storage.example.com/a", "notes/note1/img-a", "first", "image/png", 0, 0, 0, now).
+			AddRow("img-b", "note1", "https://storage.example.com/b", "notes/note1/img-b", "second", "image/jpeg", 0, 0, 1, now))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	expectReleaseQuota(mock)
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{UserId: "user1", Images: images})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	var mimeTypes []string
+	for _, name := range store.Objects() {
+		mimeTypes = append(mimeTypes, store.MimeType(name))
+	}
+	slices.Sort(mimeTypes)
+	if want := []string{"image/jpeg", "image/png"}; !slices.Equal(mimeTypes, want) {
+		t.Errorf("stored MIME types = %v, want %v", mimeTypes, want)
+	}
+	var got []string
+	for _, img := range resp.Note.Images {
+		if img.Id == "" {
+			t.Errorf("image %+v has no ID", img)
+		}
+		got = append(got, img.Id+":"+img.Caption)
+	}
+	if want := "[img-a:first img-b:second]"; fmt.Sprint(got) != want {
+		t.Errorf("images = %v, want %s", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestCreateNote_ExtractTextSync(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	svc.storage = storage.NewMemory()
+	gen := &fakeGenerator{ocrText: "OPEN 9-5"}
+	svc.aiClient = gen
+
+	now := time.Now()
+	expectReserveQuota(mock, now)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "Note"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	// The OCR text is stored with the image row rather than left for taggen
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "NoteImage"`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), "OPEN 9-5",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectQuery(`SELECT \* FROM "Note"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "userId", "createdAt", "updatedAt"}).
+			AddRow("note1", "", "user1", now, now))
+	mock.ExpectQuery(`FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "extractedText", "mimeType", "position", "createdAt"}).
+			AddRow("img-a", "note1", "https://storage.example.com/a", "notes/note1/img-a", "OPEN 9-5", "image/png", 0, now))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	expectReleaseQuota(mock)
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{
+		UserId:          "user1",
+		Images:          []*pb.ImageUpload{{Data: pngHeader, MimeType: "image/png"}},
+		ExtractTextSync: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+
+	if gen.ocrCalls != 1 {
+		t.Errorf("ExtractTextFromImage called %d times, want 1", gen.ocrCalls)
+	}
+	if len(resp.Note.Images) != 1 || resp.Note.Images[0].ExtractedText != "OPEN 9-5" {
+		t.Errorf("images = %+v, want one with extracted text", resp.Note.Images)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestUpdateNote_ReturnsAddedAudio(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	store := storage.NewMemory()
+	svc.storage = store
+
+	now := time.Now()
+	expectReserveQuota(mock, now)
+
+	// Update the note
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "userId", "createdAt", "updatedAt"}).
+			AddRow("note1", "memo", "user1", now, now))
+	mock.ExpectExec(`UPDATE "Note"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	// Save the audio
+	mock.ExpectQuery(`SELECT COALESCE\(MAX\(position\) \+ 1, 0\) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"position"}).AddRow(0))
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "NoteAudio"`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Reload from the primary, which now includes the stored audio
+	mock.ExpectQuery(`SELECT \* FROM "Note"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "userId", "createdAt", "updatedAt"}).
+			AddRow("note1", "memo", "user1", now, now))
+	mock.ExpectQuery(`FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT \* FROM "NoteAudio" WHERE "noteId" = \$1 ORDER BY position ASC`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "mimeType", "position", "createdAt"}).
+			AddRow("aud-a", "note1", "https://storage.example.com/a", "notes/note1/aud-a", "audio/mpeg", 0, now))
+
+	expectReleaseQuota(mock)
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.UpdateNote(ctx, &pb.UpdateNoteRequest{
+		UserId:    "user1",
+		Id:        "note1",
+		AddAudios: []*pb.AudioUpload{{Data: mp3ID3, MimeType: "audio/mpeg"}},
+	})
+	if err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+
+	objects := store.Objects()
+	if len(objects) != 1 || store.MimeType(objects[0]) != "audio/mpeg" {
+		t.Errorf("stored objects = %v, want one audio/mpeg upload", objects)
+	}
+	if len(resp.Note.Audios) != 1 {
+		t.Fatalf("got %d audios, want 1", len(resp.Note.Audios))
+	}
+	if aud := resp.Note.Audios[0]; aud.Id != "aud-a" || aud.MimeType != "audio/mpeg" || aud.Url == "" {
+		t.Errorf("audio = %+v, want aud-a with a URL", aud)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
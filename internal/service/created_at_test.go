@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCreateNote_CreatedAt(t *testing.T) {
+	imported := time.Date(2019, 6, 1, 8, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		createdAt *timestamppb.Timestamp
+		key       string
+		wantCode  codes.Code
+	}{
+		{name: "backdated", createdAt: timestamppb.New(imported), wantCode: codes.OK},
+		{name: "within skew", createdAt: timestamppb.New(time.Now().Add(time.Minute)), wantCode: codes.OK},
+		{name: "future", createdAt: timestamppb.New(time.Now().Add(time.Hour)), wantCode: codes.InvalidArgument},
+		{name: "invalid timestamp", createdAt: &timestamppb.Timestamp{Nanos: -1}, wantCode: codes.InvalidArgument},
+		{name: "with idempotency key", createdAt: timestamppb.New(imported), key: "import-1", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newFakeNotesStore()
+			svc := NewNotesService(store, nil, nil, "")
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "m2m")
+			resp, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{
+				UserId:         "user1",
+				Content:        "old entry",
+				CreatedAt:      tt.createdAt,
+				IdempotencyKey: tt.key,
+			})
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("got %v, want %v", err, tt.wantCode)
+			}
+			if tt.wantCode != codes.OK {
+				if len(store.notes) != 0 {
+					t.Errorf("stored %d notes, want none", len(store.notes))
+				}
+				return
+			}
+
+			stored := store.notes[resp.Note.Id]
+			if want := tt.createdAt.AsTime(); !stored.CreatedAt.Equal(want) || !resp.Note.CreatedAt.AsTime().Equal(want) {
+				t.Errorf("stored CreatedAt = %v, response %v, want %v", stored.CreatedAt, resp.Note.CreatedAt.AsTime(), want)
+			}
+		})
+	}
+}
+
+func TestCreateNote_DefaultsCreatedAtToNow(t *testing.T) {
+	store := newFakeNotesStore()
+	svc := NewNotesService(store, nil, nil, "")
+
+	before := time.Now()
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{UserId: "user1", Content: "today"})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	if got := store.notes[resp.Note.Id].CreatedAt; got.Before(before) {
+		t.Errorf("CreatedAt = %v, want now", got)
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/storage"
+	pb "github.com/icco/etu-backend/proto"
+)
+
+func TestDeleteNote_RemovesStoredMedia(t *testing.T) {
+	tests := []struct {
+		name        string
+		rowsDeleted int64
+		wantObjects []string
+	}{
+		{name: "deleted", rowsDeleted: 1, wantObjects: []string{"notes/note2/img-c"}},
+		// Another user's note, or one already gone: its media must be kept
+		{name: "not deleted", rowsDeleted: 0, wantObjects: []string{"audio/note1/aud-a", "notes/note1/img-a", "notes/note1/img-b", "notes/note2/img-c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestNotesService(t)
+			defer cleanup()
+			store := storage.NewMemory()
+			svc.storage = store
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+			for _, name := range []string{"notes/note1/img-a", "notes/note1/img-b", "audio/note1/aud-a", "notes/note2/img-c"} {
+				if _, err := store.UploadImage(ctx, name, []byte("data"), "application/octet-stream"); err != nil {
+					t.Fatalf("UploadImage: %v", err)
+				}
+			}
+
+			mock.ExpectQuery(`SELECT \* FROM "NoteImage" WHERE "noteId" = \$1`).
+				WithArgs("note1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "gcsObjectName"}).
+					AddRow("img-a", "note1", "notes/note1/img-a").
+					AddRow("img-b", "note1", "notes/note1/img-b"))
+			mock.ExpectQuery(`SELECT \* FROM "NoteAudio" WHERE "noteId" = \$1`).
+				WithArgs("note1").
+				WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "gcsObjectName"}).
+					AddRow("aud-a", "note1", "audio/note1/aud-a"))
+			mock.ExpectBegin()
+			mock.ExpectExec(`DELETE FROM "Note"`).
+				WithArgs("note1", "user1").
+				WillReturnResult(sqlmock.NewResult(0, tt.rowsDeleted))
+			mock.ExpectCommit()
+
+			resp, err := svc.DeleteNote(ctx, &pb.DeleteNoteRequest{UserId: "user1", Id: "note1"})
+			if err != nil {
+				t.Fatalf("DeleteNote: %v", err)
+			}
+			if resp.Success != (tt.rowsDeleted > 0) {
+				t.Errorf("Success = %v, want %v", resp.Success, tt.rowsDeleted > 0)
+			}
+			if got := store.Objects(); !slices.Equal(got, tt.wantObjects) {
+				t.Errorf("stored objects = %v, want %v", got, tt.wantObjects)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled mock expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/models"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// storedMedia is an attachment's stored object, read so it can be copied
+type storedMedia struct {
+	id       string
+	mimeType string
+	data     []byte
+}
+
+// DuplicateNote copies a note with its tags and attachments. Attachments are
+// uploaded again under the new note rather than shared, so deleting either
+// note never removes media the other still shows.
+func (s *NotesService) DuplicateNote(ctx context.Context, req *pb.DuplicateNoteRequest) (*pb.DuplicateNoteResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	source, err := s.db.GetNote(ctx, req.UserId, req.Id, db.DefaultNoteInclude)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get note: %v", err)
+	}
+	if source == nil {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+	audios, err := s.db.GetAudiosByNoteID(ctx, source.ID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get audio files: %v", err)
+	}
+
+	var sources []storedMedia
+	if len(source.Images) > 0 || len(audios) > 0 {
+		if s.storage == nil {
+			return nil, status.Error(codes.FailedPrecondition, "storage is not configured")
+		}
+		for _, img := range source.Images {
+			data, err := s.storage.GetImage(ctx, img.GCSObjectName)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to read image %s: %v", img.ID, err)
+			}
+			sources = append(sources, storedMedia{id: img.ID, mimeType: img.MimeType, data: data})
+		}
+		for _, aud := range audios {
+			data, err := s.storage.GetImage(ctx, aud.GCSObjectName)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to read audio %s: %v", aud.ID, err)
+			}
+			sources = append(sources, storedMedia{id: aud.ID, mimeType: aud.MimeType, data: data})
+		}
+	}
+
+	// Copies count against quota like new uploads, so reserve before writing
+	var size int64
+	for _, m := range sources {
+		size += int64(len(m.data))
+	}
+	release, err := s.reserveUploadQuota(ctx, req.UserId, size)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	newNoteID := models.GenerateCUID()
+	copies, err := s.uploadCopies(ctx, newNoteID, sources)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to copy attachments: %v", err)
+	}
+
+	note, err := s.db.DuplicateNote(ctx, req.UserId, req.Id, newNoteID, copies)
+	if err != nil || note == nil {
+		s.deleteCopies(ctx, copies)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to duplicate note: %v", err)
+	}
+	if note == nil {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	pbNote := s.noteToProto(ctx, note, s.urlExpiry)
+	s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_CREATED, pbNote)
+	if len(pbNote.Tags) > 0 {
+		s.publishTags(req.UserId, pbNote)
+	}
+
+	return &pb.DuplicateNoteResponse{
+		Note: pbNote,
+	}, nil
+}
+
+// uploadCopies stores each attachment again under newNoteID. If any upload
+// fails, the copies already written are deleted.
+func (s *NotesService) uploadCopies(ctx context.Context, newNoteID string, sources []storedMedia) ([]db.MediaCopy, error) {
+	copies := make([]db.MediaCopy, 0, len(sources))
+	for _, m := range sources {
+		id := models.GenerateCUID()
+		objectName := fmt.Sprintf("notes/%s/%s", newNoteID, id)
+		url, err := s.storage.UploadImage(ctx, objectName, m.data, m.mimeType)
+		if err != nil {
+			s.deleteCopies(ctx, copies)
+			return nil, fmt.Errorf("failed to upload copy of %s: %w", m.id, err)
+		}
+		copies = append(copies, db.MediaCopy{
+			SourceID:      m.id,
+			ID:            id,
+			URL:           url,
+			GCSObjectName: objectName,
+			SizeBytes:     int64(len(m.data)),
+		})
+	}
+	return copies, nil
+}
+
+// deleteCopies removes copied objects that won't be saved to the database
+func (s *NotesService) deleteCopies(ctx context.Context, copies []db.MediaCopy) {
+	for _, c := range copies {
+		if err := s.storage.DeleteImage(context.WithoutCancel(ctx), c.GCSObjectName); err != nil {
+			s.log.Error("failed to clean up copied media from GCS", "object_name", c.GCSObjectName, "error", err)
+		}
+	}
+}
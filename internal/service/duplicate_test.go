@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/storage"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newFakeDuplicateService returns a service over a note with one image and
+// one audio file, both present in an in-memory store
+func newFakeDuplicateService(t *testing.T) (*NotesService, *fakeNotesStore, *storage.Memory) {
+	t.Helper()
+	now := time.Now()
+	store := newFakeNotesStore(db.Note{
+		ID:        "note1",
+		Content:   "- [ ] milk",
+		Color:     "blue",
+		Pinned:    true,
+		UserID:    "user1",
+		CreatedAt: now.Add(-time.Hour),
+		UpdatedAt: now.Add(-time.Hour),
+		Tags:      []db.Tag{{ID: "tag1", Name: "shopping", UserID: "user1", CreatedAt: now}},
+		Images: []db.NoteImage{{
+			ID: "img1", NoteID: "note1", GCSObjectName: "notes/note1/img1", MimeType: "image/png",
+			ExtractedText: "MILK", Caption: "list", SizeBytes: 3, CreatedAt: now,
+		}},
+		Audios: []db.NoteAudio{{
+			ID: "aud1", NoteID: "note1", GCSObjectName: "notes/note1/aud1", MimeType: "audio/mpeg",
+			TranscribedText: "buy milk", SizeBytes: 5, CreatedAt: now,
+		}},
+	})
+
+	blobs := storage.NewMemory()
+	ctx := context.Background()
+	if _, err := blobs.UploadImage(ctx, "notes/note1/img1", []byte("png"), "image/png"); err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+	if _, err := blobs.UploadImage(ctx, "notes/note1/aud1", []byte("audio"), "audio/mpeg"); err != nil {
+		t.Fatalf("UploadImage: %v", err)
+	}
+
+	svc := NewNotesService(store, nil, nil, "")
+	svc.storage = blobs
+	return svc, store, blobs
+}
+
+func TestDuplicateNote(t *testing.T) {
+	svc, store, blobs := newFakeDuplicateService(t)
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	resp, err := svc.DuplicateNote(ctx, &pb.DuplicateNoteRequest{UserId: "user1", Id: "note1"})
+	if err != nil {
+		t.Fatalf("DuplicateNote: %v", err)
+	}
+
+	copied := resp.Note
+	if copied.Id == "" || copied.Id == "note1" {
+		t.Fatalf("copy has ID %q, want a new note", copied.Id)
+	}
+	if copied.Content != "- [ ] milk" || copied.Color != "blue" || copied.Pinned {
+		t.Errorf("copy = %+v, want same content and color, unpinned", copied)
+	}
+	if !slices.Equal(copied.Tags, []string{"shopping"}) {
+		t.Errorf("copy tags = %v, want [shopping]", copied.Tags)
+	}
+	if len(copied.Images) != 1 || copied.Images[0].ExtractedText != "MILK" || copied.Images[0].Caption != "list" {
+		t.Errorf("copy images = %+v, want the copied image with its text and caption", copied.Images)
+	}
+
+	// Each attachment is stored again under the copy
+	n := store.notes[copied.Id]
+	if len(n.Images) != 1 || len(n.Audios) != 1 {
+		t.Fatalf("stored copy has %d images and %d audio files, want 1 and 1", len(n.Images), len(n.Audios))
+	}
+	for _, name := range []string{n.Images[0].GCSObjectName, n.Audios[0].GCSObjectName} {
+		if !strings.HasPrefix(name, "notes/"+copied.Id+"/") {
+			t.Errorf("copied object %q is not under the new note", name)
+		}
+	}
+	if got, err := blobs.GetImage(ctx, n.Audios[0].GCSObjectName); err != nil || string(got) != "audio" {
+		t.Errorf("copied audio = %q, %v; want the original bytes", got, err)
+	}
+	if got := len(blobs.Objects()); got != 4 {
+		t.Errorf("store holds %d objects, want 4", got)
+	}
+
+	if !slices.Equal(store.reserved, []int64{8}) || store.released != 1 {
+		t.Errorf("reserved %v and released %d times, want one 8 byte reservation released once", store.reserved, store.released)
+	}
+}
+
+func TestDuplicateNote_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      *pb.DuplicateNoteRequest
+		setup    func(svc *NotesService, blobs *storage.Memory)
+		wantCode codes.Code
+	}{
+		{name: "missing id", req: &pb.DuplicateNoteRequest{UserId: "user1"}, wantCode: codes.InvalidArgument},
+		{name: "not found", req: &pb.DuplicateNoteRequest{UserId: "user1", Id: "nope"}, wantCode: codes.NotFound},
+		{
+			name:     "storage not configured",
+			req:      &pb.DuplicateNoteRequest{UserId: "user1", Id: "note1"},
+			setup:    func(svc *NotesService, _ *storage.Memory) { svc.storage = nil },
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name: "stored object missing",
+			req:  &pb.DuplicateNoteRequest{UserId: "user1", Id: "note1"},
+			setup: func(_ *NotesService, blobs *storage.Memory) {
+				_ = blobs.DeleteImage(context.Background(), "notes/note1/aud1")
+			},
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, store, blobs := newFakeDuplicateService(t)
+			if tt.setup != nil {
+				tt.setup(svc, blobs)
+			}
+			before := blobs.Objects()
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+			_, err := svc.DuplicateNote(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Fatalf("got %v, want %v", err, tt.wantCode)
+			}
+
+			if len(store.notes) != 1 {
+				t.Errorf("store holds %d notes, want only the original", len(store.notes))
+			}
+			if got := blobs.Objects(); !slices.Equal(got, before) {
+				t.Errorf("stored objects = %v, want unchanged %v", got, before)
+			}
+		})
+	}
+}
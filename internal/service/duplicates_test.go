@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFindDuplicates_RequiresUserID(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.FindDuplicates(ctx, &pb.FindDuplicatesRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestFindDuplicates_OtherUser(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.FindDuplicates(ctx, &pb.FindDuplicatesRequest{UserId: "user2"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	older := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	mock.ExpectQuery(`SELECT id, "createdAt", "contentHash" FROM "Note"`).
+		WithArgs("user1", "user1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "createdAt", "contentHash"}).
+			AddRow("note1", older, "abc").
+			AddRow("note2", newer, "abc"))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.FindDuplicates(ctx, &pb.FindDuplicatesRequest{UserId: "user1"})
+	if err != nil {
+		t.Fatalf("FindDuplicates: %v", err)
+	}
+	if len(resp.Groups) != 1 || len(resp.Groups[0].Notes) != 2 {
+		t.Fatalf("unexpected groups: %+v", resp.Groups)
+	}
+	first := resp.Groups[0].Notes[0]
+	if first.Id != "note1" || !first.CreatedAt.AsTime().Equal(older) {
+		t.Errorf("first note = %+v, want note1 created %v", first, older)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
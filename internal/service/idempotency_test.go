@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateNote_IdempotencyKeyTooLong(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{
+		UserId:         "user1",
+		Content:        "hello",
+		IdempotencyKey: strings.Repeat("k", MaxIdempotencyKeyLength+1),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestCreateNote_IdempotencyKeyReplay(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId", "idempotencyKey"}
+
+	// The key already created a note, so no INSERT is expected
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE "userId" = \$1 AND "idempotencyKey" = \$2`).
+		WithArgs("user1", "retry-1", sqlmock.AnyArg(), 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("note1", "hello", now, now, "user1", "retry-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE id = \$1`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("note1", "hello", now, now, "user1", "retry-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{UserId: "user1", Content: "hello", IdempotencyKey: "retry-1"})
+	if err != nil {
+		t.Fatalf("CreateNote: %v", err)
+	}
+	if resp.Note.Id != "note1" {
+		t.Errorf("Note.Id = %q, want the existing note1", resp.Note.Id)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
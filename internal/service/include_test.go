@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNoteInclude(t *testing.T) {
+	tests := []struct {
+		name   string
+		tags   *bool
+		images *bool
+		audios *bool
+		want   db.NoteInclude
+	}{
+		{name: "defaults", want: db.NoteInclude{Tags: true, Images: true, Audios: true}},
+		{name: "skip images", images: proto.Bool(false), want: db.NoteInclude{Tags: true, Audios: true}},
+		{name: "skip tags", tags: proto.Bool(false), want: db.NoteInclude{Images: true, Audios: true}},
+		{name: "skip media", images: proto.Bool(false), audios: proto.Bool(false), want: db.NoteInclude{Tags: true}},
+		{name: "explicit", tags: proto.Bool(true), images: proto.Bool(true), audios: proto.Bool(true), want: db.NoteInclude{Tags: true, Images: true, Audios: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := noteInclude(tt.tags, tt.images, tt.audios); got != tt.want {
+				t.Errorf("noteInclude() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetNote_WithoutImages(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note1", "hello", now, now, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}).
+			AddRow("tag1", "work", now, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetNote(ctx, &pb.GetNoteRequest{UserId: "user1", Id: "note1", IncludeImages: proto.Bool(false)})
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if len(resp.Note.Tags) != 1 || len(resp.Note.Images) != 0 {
+		t.Errorf("got tags %v and %d images, want one tag and no images", resp.Note.Tags, len(resp.Note.Images))
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetNote_CountImageTextNeedsImages(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.GetNote(ctx, &pb.GetNoteRequest{UserId: "user1", Id: "note1", CountImageText: true, IncludeImages: proto.Bool(false)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("code = %v, want InvalidArgument", status.Code(err))
+	}
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RequestSizeOverhead is the room left in a gRPC request beyond the largest
+// single upload, for the rest of the message and smaller attachments
+const RequestSizeOverhead = 8 * 1024 * 1024
+
+// MaxRequestSize returns the largest gRPC request the server should accept,
+// read from GRPC_MAX_RECV_SIZE. It defaults to the larger of the configured
+// image and audio upload limits plus RequestSizeOverhead, so a maximum-size
+// upload of either kind always fits.
+func MaxRequestSize(log *slog.Logger) int {
+	maxImage := sizeLimitFromEnv(log, "MAX_IMAGE_SIZE", MaxImageSize)
+	maxAudio := sizeLimitFromEnv(log, "MAX_AUDIO_SIZE", MaxAudioSize)
+	return sizeLimitFromEnv(log, "GRPC_MAX_RECV_SIZE", max(maxImage, maxAudio)+RequestSizeOverhead)
+}
+
+// byteUnits maps size suffixes to multipliers. Decimal-looking suffixes use
+// binary multiples to match the existing "10MB" = 10 * 1024 * 1024 defaults.
+var byteUnits = []struct {
+	suffix     string
+	multiplier int64
+}{
+	{"KIB", 1 << 10},
+	{"MIB", 1 << 20},
+	{"GIB", 1 << 30},
+	{"KB", 1 << 10},
+	{"MB", 1 << 20},
+	{"GB", 1 << 30},
+	{"K", 1 << 10},
+	{"M", 1 << 20},
+	{"G", 1 << 30},
+	{"B", 1},
+}
+
+// parseByteSize parses a size given in bytes ("1048576") or with a unit
+// suffix ("15MB", "512 KB", "1GiB").
+func parseByteSize(value string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+
+	multiplier := int64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			multiplier = unit.multiplier
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", value)
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("size must be positive: %q", value)
+	}
+	if n > (1<<62)/multiplier {
+		return 0, fmt.Errorf("size too large: %q", value)
+	}
+
+	return n * multiplier, nil
+}
+
+// sizeLimitFromEnv reads a byte size limit from an environment variable,
+// falling back to the default when unset or invalid.
+func sizeLimitFromEnv(log *slog.Logger, envVar string, defaultSize int) int {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultSize
+	}
+
+	size, err := parseByteSize(value)
+	if err != nil {
+		log.Error("invalid size limit, using default", "env", envVar, "value", value, "default", defaultSize, "error", err)
+		return defaultSize
+	}
+	return int(size)
+}
+
+// intFromEnv reads a positive integer from an environment variable, falling
+// back to the default when unset, invalid, or not positive.
+func intFromEnv(log *slog.Logger, envVar string, defaultValue int) int {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || n <= 0 {
+		log.Error("invalid limit, using default", "env", envVar, "value", value, "default", defaultValue, "error", err)
+		return defaultValue
+	}
+	return n
+}
+
+// durationFromEnv reads a duration ("24h", "90m") from an environment
+// variable, falling back to the default when unset, invalid, or not positive.
+func durationFromEnv(log *slog.Logger, envVar string, defaultDuration time.Duration) time.Duration {
+	value := os.Getenv(envVar)
+	if value == "" {
+		return defaultDuration
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		log.Error("invalid duration, using default", "env", envVar, "value", value, "default", defaultDuration, "error", err)
+		return defaultDuration
+	}
+	return d
+}
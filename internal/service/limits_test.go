@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/models"
+	"github.com/icco/etu-backend/internal/storage"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{value: "1048576", want: 1048576},
+		{value: "15MB", want: 15 * 1024 * 1024},
+		{value: "15mb", want: 15 * 1024 * 1024},
+		{value: "512 KB", want: 512 * 1024},
+		{value: "1GiB", want: 1024 * 1024 * 1024},
+		{value: "2M", want: 2 * 1024 * 1024},
+		{value: "100B", want: 100},
+		{value: "", wantErr: true},
+		{value: "MB", wantErr: true},
+		{value: "1.5MB", wantErr: true},
+		{value: "-1", wantErr: true},
+		{value: "0", wantErr: true},
+		{value: "ten", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseByteSize(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("parseByteSize(%q) = %d, want error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseByteSize(%q): %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Errorf("parseByteSize(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNotesService_SizeLimitsFromEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		imageEnv  string
+		audioEnv  string
+		wantImage int
+		wantAudio int
+	}{
+		{name: "defaults", wantImage: MaxImageSize, wantAudio: MaxAudioSize},
+		{name: "overrides", imageEnv: "15MB", audioEnv: "50000000", wantImage: 15 * 1024 * 1024, wantAudio: 50000000},
+		{name: "invalid falls back", imageEnv: "lots", audioEnv: "-5", wantImage: MaxImageSize, wantAudio: MaxAudioSize},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAX_IMAGE_SIZE", tt.imageEnv)
+			t.Setenv("MAX_AUDIO_SIZE", tt.audioEnv)
+
+			svc := NewNotesService(nil, nil, nil, "")
+			if svc.maxImageSize != tt.wantImage {
+				t.Errorf("maxImageSize = %d, want %d", svc.maxImageSize, tt.wantImage)
+			}
+			if svc.maxAudioSize != tt.wantAudio {
+				t.Errorf("maxAudioSize = %d, want %d", svc.maxAudioSize, tt.wantAudio)
+			}
+		})
+	}
+}
+
+func TestMaxRequestSize(t *testing.T) {
+	tests := []struct {
+		name     string
+		imageEnv string
+		audioEnv string
+		recvEnv  string
+		want     int
+	}{
+		{name: "default fits max upload", want: max(MaxImageSize, MaxAudioSize) + RequestSizeOverhead},
+		{name: "follows audio limit", audioEnv: "50MB", want: 50*1024*1024 + RequestSizeOverhead},
+		{name: "follows larger image limit", imageEnv: "80MB", audioEnv: "50MB", want: 80*1024*1024 + RequestSizeOverhead},
+		{name: "override", audioEnv: "50MB", recvEnv: "100MB", want: 100 * 1024 * 1024},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAX_IMAGE_SIZE", tt.imageEnv)
+			t.Setenv("MAX_AUDIO_SIZE", tt.audioEnv)
+			t.Setenv("GRPC_MAX_RECV_SIZE", tt.recvEnv)
+
+			if got := MaxRequestSize(slog.New(slog.DiscardHandler)); got != tt.want {
+				t.Errorf("MaxRequestSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNotesService_URLExpiryFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want time.Duration
+	}{
+		{name: "default", want: storage.SignedURLDuration},
+		{name: "override", env: "1h", want: time.Hour},
+		{name: "invalid falls back", env: "soon", want: storage.SignedURLDuration},
+		{name: "negative falls back", env: "-1h", want: storage.SignedURLDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SIGNED_URL_EXPIRY", tt.env)
+
+			svc := NewNotesService(nil, nil, nil, "")
+			if svc.urlExpiry != tt.want {
+				t.Errorf("urlExpiry = %v, want %v", svc.urlExpiry, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewNotesService_MaxContentFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		want int
+	}{
+		{name: "default", want: models.DefaultMaxContentLength},
+		{name: "override", env: "5000", want: 5000},
+		{name: "invalid falls back", env: "lots", want: models.DefaultMaxContentLength},
+		{name: "zero falls back", env: "0", want: models.DefaultMaxContentLength},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("MAX_CONTENT_LENGTH", tt.env)
+
+			svc := NewNotesService(nil, nil, nil, "")
+			if svc.maxContent != tt.want {
+				t.Errorf("maxContent = %d, want %d", svc.maxContent, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeContent(t *testing.T) {
+	t.Setenv("MAX_CONTENT_LENGTH", "5")
+	svc := NewNotesService(nil, nil, nil, "")
+
+	tests := []struct {
+		content string
+		want    string
+		wantErr bool
+	}{
+		{content: "héllo", want: "héllo"},
+		{content: "héllo \n\t ", want: "héllo"},
+		{content: "  hi", want: "  hi"},
+		{content: "hello!", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := svc.normalizeContent(tt.content)
+		if tt.wantErr {
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("normalizeContent(%q) error = %v, want InvalidArgument", tt.content, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("normalizeContent(%q): %v", tt.content, err)
+		}
+		if got != tt.want {
+			t.Errorf("normalizeContent(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestCreateNote_ContentTooLong(t *testing.T) {
+	t.Setenv("MAX_CONTENT_LENGTH", "10")
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{
+		UserId:  "user1",
+		Content: strings.Repeat("a", 11),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+func TestValidateImage_SizeLimit(t *testing.T) {
+	data := append(append([]byte{}, pngHeader...), make([]byte, 100)...)
+	if err := validateImage(data, "image/png", len(data)); err != nil {
+		t.Errorf("validateImage at limit: %v", err)
+	}
+	if err := validateImage(data, "image/png", len(data)-1); err == nil {
+		t.Error("validateImage over limit: expected error")
+	}
+}
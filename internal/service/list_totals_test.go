@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// expectNotePage expects ListNotes to fetch one page holding a single note
+func expectNotePage(mock sqlmock.Sqlmock, countQuery string, total int) {
+	now := time.Now()
+	mock.ExpectQuery(countQuery).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(total))
+	mock.ExpectQuery(`SELECT \* FROM "Note"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note1", "hello", now, now, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"note_id", "id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+}
+
+func TestListNotes_TotalUnfiltered_NoFilters(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	// Only the one count query; the filtered total is already the user's total
+	expectNotePage(mock, `SELECT count\(\*\) FROM "Note" WHERE "userId" = \$1$`, 340)
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ListNotes(ctx, &pb.ListNotesRequest{UserId: "user1", SearchMedia: true})
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	if resp.Total != 340 || resp.TotalUnfiltered != 340 {
+		t.Errorf("Total = %d, TotalUnfiltered = %d, want 340 and 340", resp.Total, resp.TotalUnfiltered)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestListNotes_TotalUnfiltered_WithFilters(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	expectNotePage(mock, `SELECT count\(\*\) FROM "Note" WHERE "userId" = \$1 AND content ILIKE`, 12)
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "Note" WHERE "userId" = \$1$`).
+		WithArgs("user1").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(340))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ListNotes(ctx, &pb.ListNotesRequest{UserId: "user1", Search: "hello"})
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	if resp.Total != 12 || resp.TotalUnfiltered != 340 {
+		t.Errorf("Total = %d, TotalUnfiltered = %d, want 12 and 340", resp.Total, resp.TotalUnfiltered)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestListNotes_InvalidSort(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	for _, req := range []*pb.ListNotesRequest{
+		{UserId: "user1", SortBy: "title"},
+		{UserId: "user1", SortBy: "updated_at", SortDir: "up"},
+	} {
+		_, err := svc.ListNotes(ctx, req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ListNotes(sort_by=%q, sort_dir=%q) = %v, want InvalidArgument", req.SortBy, req.SortDir, err)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
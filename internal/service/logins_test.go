@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// expectLoginEvent expects Authenticate to audit a password login
+func expectLoginEvent(mock sqlmock.Sqlmock, userID string, success bool) {
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "LoginEvent"`).
+		WithArgs(sqlmock.AnyArg(), userID, success, "password", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+}
+
+func TestAuthenticate_RecordsClientInfo(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		peerIP string
+		wantIP string
+	}{
+		// The HTTP gateway relays over loopback and appends the client address
+		{name: "gateway", peerIP: "127.0.0.1", wantIP: "203.0.113.7"},
+		// A direct caller can't pick the audited address with the header
+		{name: "forged header", peerIP: "198.51.100.9", wantIP: "198.51.100.9"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestAuthService(t)
+			defer cleanup()
+
+			now := time.Now()
+			mock.ExpectQuery(`SELECT \* FROM "User" WHERE email = \$1`).
+				WithArgs("a@example.com", 1).
+				WillReturnRows(sqlmock.NewRows([]string{"id", "email", "passwordHash", "createdAt", "updatedAt"}).
+					AddRow("user1", "a@example.com", string(hash), now, now))
+			mock.ExpectBegin()
+			mock.ExpectExec(`UPDATE "User"`).
+				WithArgs(0, nil, sqlmock.AnyArg(), "user1").
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+			mock.ExpectBegin()
+			mock.ExpectExec(`INSERT INTO "LoginEvent" \("id","userId","success","authType","ipAddress","userAgent","createdAt"\)`).
+				WithArgs(sqlmock.AnyArg(), "user1", true, "password", tt.wantIP, "Mozilla/5.0", sqlmock.AnyArg()).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+			mock.ExpectCommit()
+
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				Addr: &net.TCPAddr{IP: net.ParseIP(tt.peerIP), Port: 40000},
+			})
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+				"x-forwarded-for", "203.0.113.7",
+				"grpcgateway-user-agent", "Mozilla/5.0",
+			))
+			resp, err := svc.Authenticate(ctx, &pb.AuthenticateRequest{Email: "a@example.com", Password: "password"})
+			if err != nil {
+				t.Fatalf("Authenticate: %v", err)
+			}
+			if !resp.Success {
+				t.Error("expected successful authentication")
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled mock expectations: %v", err)
+			}
+		})
+	}
+}
+
+// TestAuthenticate_LoginEventFailureIgnored checks that an audit log outage
+// does not lock users out.
+func TestAuthenticate_LoginEventFailureIgnored(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE email = \$1`).
+		WithArgs("a@example.com", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "passwordHash", "createdAt", "updatedAt"}).
+			AddRow("user1", "a@example.com", string(hash), now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "User"`).
+		WithArgs(0, nil, sqlmock.AnyArg(), "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "LoginEvent"`).
+		WillReturnError(errors.New("relation does not exist"))
+	mock.ExpectRollback()
+
+	resp, err := svc.Authenticate(context.Background(), &pb.AuthenticateRequest{Email: "a@example.com", Password: "password"})
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if !resp.Success {
+		t.Error("expected successful authentication")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetLoginHistory(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT \* FROM "LoginEvent" WHERE "userId" = \$1 ORDER BY "createdAt" DESC LIMIT \$2`).
+		WithArgs("user1", MaxLoginHistoryLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "userId", "success", "authType", "ipAddress", "userAgent", "createdAt"}).
+			AddRow("ev2", "user1", true, "apikey", "203.0.113.7", "etu-cli", now).
+			AddRow("ev1", "user1", false, "password", "", "", now.Add(-time.Hour)))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetLoginHistory(ctx, &pb.GetLoginHistoryRequest{UserId: "user1", Limit: 1000})
+	if err != nil {
+		t.Fatalf("GetLoginHistory: %v", err)
+	}
+	if len(resp.Events) != 2 {
+		t.Fatalf("GetLoginHistory returned %d events, want 2", len(resp.Events))
+	}
+	if got := resp.Events[0]; got.Id != "ev2" || !got.Success || got.AuthType != "apikey" || got.IpAddress != "203.0.113.7" {
+		t.Errorf("GetLoginHistory first event = %v", got)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetLoginHistory_OtherUser(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.GetLoginHistory(ctx, &pb.GetLoginHistoryRequest{UserId: "user2"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
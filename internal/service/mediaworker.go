@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/icco/etu-backend/internal/models"
+)
+
+// mediaQueueSize is how many uploads may wait for the media worker. Uploads
+// beyond it are left for the taggen job.
+const mediaQueueSize = 256
+
+// mediaJob is an uploaded attachment waiting for OCR or transcription.
+type mediaJob struct {
+	resourceType string // models.ResourceTypeImage or models.ResourceTypeAudio
+	id           string
+	objectName   string
+	mimeType     string
+}
+
+// mediaFetcher downloads stored attachments.
+type mediaFetcher interface {
+	GetImage(ctx context.Context, objectName string) ([]byte, error)
+}
+
+// mediaAI extracts text from attachments.
+type mediaAI interface {
+	ExtractTextFromImage(ctx context.Context, imageData []byte, mimeType string) (string, error)
+	TranscribeAudio(ctx context.Context, audioData []byte, mimeType string) (string, error)
+}
+
+// mediaTextStore saves extracted text.
+type mediaTextStore interface {
+	UpdateImageExtractedText(ctx context.Context, imageID string, extractedText string) error
+	UpdateAudioTranscribedText(ctx context.Context, audioID string, transcribedText string) error
+}
+
+// mediaWorker runs OCR and transcription on fresh uploads in the background,
+// so their text is available within seconds instead of at the next taggen
+// run. It is best effort: anything it drops or fails on keeps empty text and
+// is picked up by taggen as before.
+type mediaWorker struct {
+	log    *slog.Logger
+	fetch  mediaFetcher
+	ai     mediaAI
+	store  mediaTextStore
+	jobs   chan mediaJob
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newMediaWorker starts concurrency goroutines processing queued uploads.
+func newMediaWorker(log *slog.Logger, fetch mediaFetcher, aiClient mediaAI, store mediaTextStore, concurrency int) *mediaWorker {
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &mediaWorker{
+		log:    log,
+		fetch:  fetch,
+		ai:     aiClient,
+		store:  store,
+		jobs:   make(chan mediaJob, mediaQueueSize),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	for range concurrency {
+		w.wg.Add(1)
+		go w.run()
+	}
+	return w
+}
+
+// mediaWorkersFromEnv reads MEDIA_WORKERS, the number of background OCR and
+// transcription goroutines. Unset or 0 disables the worker.
+func mediaWorkersFromEnv(log *slog.Logger) int {
+	value := strings.TrimSpace(os.Getenv("MEDIA_WORKERS"))
+	if value == "" {
+		return 0
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		log.Error("invalid MEDIA_WORKERS, media worker disabled", "value", value, "error", err)
+		return 0
+	}
+	return n
+}
+
+// enqueue queues an upload without blocking. It reports false if the queue is
+// full or the worker has stopped.
+func (w *mediaWorker) enqueue(job mediaJob) bool {
+	if w.ctx.Err() != nil {
+		return false
+	}
+	select {
+	case w.jobs <- job:
+		return true
+	default:
+		w.log.Warn("media worker queue full, leaving upload for taggen", "resource_type", job.resourceType, "id", job.id)
+		return false
+	}
+}
+
+// stop cancels in-flight work and waits for the goroutines to exit. Queued
+// uploads are left for taggen.
+func (w *mediaWorker) stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+func (w *mediaWorker) run() {
+	defer w.wg.Done()
+	for {
+		select {
+		case <-w.ctx.Done():
+			return
+		case job := <-w.jobs:
+			w.process(job)
+		}
+	}
+}
+
+// process downloads one attachment, extracts its text and saves it.
+func (w *mediaWorker) process(job mediaJob) {
+	ctx := w.ctx
+	log := w.log.With("resource_type", job.resourceType, "id", job.id)
+
+	data, err := w.fetch.GetImage(ctx, job.objectName)
+	if err != nil {
+		log.Warn("media worker failed to download upload", "error", err)
+		return
+	}
+
+	switch job.resourceType {
+	case models.ResourceTypeImage:
+		text, err := w.ai.ExtractTextFromImage(ctx, data, job.mimeType)
+		if err != nil {
+			log.Warn("media worker failed to extract text", "error", err)
+			return
+		}
+		err = w.store.UpdateImageExtractedText(ctx, job.id, text)
+	case models.ResourceTypeAudio:
+		text, err := w.ai.TranscribeAudio(ctx, data, job.mimeType)
+		if err != nil {
+			log.Warn("media worker failed to transcribe audio", "error", err)
+			return
+		}
+		err = w.store.UpdateAudioTranscribedText(ctx, job.id, text)
+	}
+	if err != nil {
+		log.Warn("media worker failed to save text", "error", err)
+		return
+	}
+	log.Info("media worker processed upload")
+}
+
+// enqueueImage hands a saved image without text to the media worker, if any.
+func (s *NotesService) enqueueImage(img *models.NoteImage) {
+	if s.media == nil || img.ExtractedText != "" {
+		return
+	}
+	s.media.enqueue(mediaJob{resourceType: models.ResourceTypeImage, id: img.ID, objectName: img.GCSObjectName, mimeType: img.MimeType})
+}
+
+// enqueueAudio hands a saved audio file without text to the media worker, if any.
+func (s *NotesService) enqueueAudio(aud *models.NoteAudio) {
+	if s.media == nil || aud.TranscribedText != "" {
+		return
+	}
+	s.media.enqueue(mediaJob{resourceType: models.ResourceTypeAudio, id: aud.ID, objectName: aud.GCSObjectName, mimeType: aud.MimeType})
+}
+
+// StopMediaWorker stops background OCR and transcription. Call it after the
+// gRPC server has stopped so no new uploads are queued.
+func (s *NotesService) StopMediaWorker() {
+	if s.media != nil {
+		s.media.stop()
+	}
+}
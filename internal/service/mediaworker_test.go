@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/icco/etu-backend/internal/models"
+)
+
+// fakeMediaFetcher serves object contents from memory
+type fakeMediaFetcher map[string][]byte
+
+func (f fakeMediaFetcher) GetImage(ctx context.Context, objectName string) ([]byte, error) {
+	data, ok := f[objectName]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return data, nil
+}
+
+// fakeMediaAI returns the input bytes as text, or blocks until cancelled
+// when block is set
+type fakeMediaAI struct {
+	block bool
+}
+
+func (f *fakeMediaAI) answer(ctx context.Context, data []byte) (string, error) {
+	if f.block {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	return string(data), nil
+}
+
+func (f *fakeMediaAI) ExtractTextFromImage(ctx context.Context, imageData []byte, mimeType string) (string, error) {
+	return f.answer(ctx, imageData)
+}
+
+func (f *fakeMediaAI) TranscribeAudio(ctx context.Context, audioData []byte, mimeType string) (string, error) {
+	return f.answer(ctx, audioData)
+}
+
+// fakeMediaTextStore records saved text and signals each save on saved
+type fakeMediaTextStore struct {
+	mu    sync.Mutex
+	text  map[string]string
+	saved chan string
+}
+
+func newFakeMediaTextStore() *fakeMediaTextStore {
+	return &fakeMediaTextStore{text: map[string]string{}, saved: make(chan string, 10)}
+}
+
+func (f *fakeMediaTextStore) save(id, text string) error {
+	f.mu.Lock()
+	f.text[id] = text
+	f.mu.Unlock()
+	f.saved <- id
+	return nil
+}
+
+func (f *fakeMediaTextStore) UpdateImageExtractedText(ctx context.Context, imageID string, extractedText string) error {
+	return f.save(imageID, extractedText)
+}
+
+func (f *fakeMediaTextStore) UpdateAudioTranscribedText(ctx context.Context, audioID string, transcribedText string) error {
+	return f.save(audioID, transcribedText)
+}
+
+func waitSaved(t *testing.T, store *fakeMediaTextStore, n int) {
+	t.Helper()
+	for range n {
+		select {
+		case <-store.saved:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for the media worker")
+		}
+	}
+}
+
+func TestMediaWorker_ProcessesUploads(t *testing.T) {
+	fetch := fakeMediaFetcher{"notes/n1/img1": []byte("EXIT"), "notes/n1/aud1": []byte("hello there")}
+	store := newFakeMediaTextStore()
+	w := newMediaWorker(slog.New(slog.DiscardHandler), fetch, &fakeMediaAI{}, store, 2)
+	defer w.stop()
+
+	svc := &NotesService{media: w}
+	svc.enqueueImage(&models.NoteImage{ID: "img1", GCSObjectName: "notes/n1/img1", MimeType: "image/png"})
+	svc.enqueueAudio(&models.NoteAudio{ID: "aud1", GCSObjectName: "notes/n1/aud1", MimeType: "audio/mpeg"})
+	// Already has text from extract_text_sync, so it is not queued
+	svc.enqueueImage(&models.NoteImage{ID: "img2", GCSObjectName: "notes/n1/img2", ExtractedText: "done"})
+
+	waitSaved(t, store, 2)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	want := map[string]string{"img1": "EXIT", "aud1": "hello there"}
+	if len(store.text) != len(want) {
+		t.Errorf("saved %v, want %v", store.text, want)
+	}
+	for id, text := range want {
+		if store.text[id] != text {
+			t.Errorf("text for %s = %q, want %q", id, store.text[id], text)
+		}
+	}
+}
+
+func TestMediaWorker_StopCancelsInFlight(t *testing.T) {
+	fetch := fakeMediaFetcher{"notes/n1/img1": []byte("EXIT")}
+	store := newFakeMediaTextStore()
+	w := newMediaWorker(slog.New(slog.DiscardHandler), fetch, &fakeMediaAI{block: true}, store, 1)
+
+	if !w.enqueue(mediaJob{resourceType: models.ResourceTypeImage, id: "img1", objectName: "notes/n1/img1"}) {
+		t.Fatal("enqueue rejected a job on an idle worker")
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		w.stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stop did not cancel the in-flight job")
+	}
+
+	if len(store.text) != 0 {
+		t.Errorf("saved %v after a cancelled job", store.text)
+	}
+	if w.enqueue(mediaJob{resourceType: models.ResourceTypeImage, id: "img2"}) {
+		t.Error("enqueue accepted a job after stop")
+	}
+}
+
+func TestMediaWorker_QueueFull(t *testing.T) {
+	// No goroutines, so nothing drains the queue
+	w := newMediaWorker(slog.New(slog.DiscardHandler), fakeMediaFetcher{}, &fakeMediaAI{}, newFakeMediaTextStore(), 0)
+	defer w.stop()
+
+	for i := range mediaQueueSize {
+		if !w.enqueue(mediaJob{id: string(rune('a' + i%26))}) {
+			t.Fatalf("enqueue %d rejected before the queue was full", i)
+		}
+	}
+	if w.enqueue(mediaJob{id: "overflow"}) {
+		t.Error("enqueue accepted a job on a full queue")
+	}
+}
+
+func TestMediaWorkersFromEnv(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+	tests := map[string]int{"": 0, "0": 0, "3": 3, " 2 ": 2, "-1": 0, "many": 0}
+	for value, want := range tests {
+		t.Setenv("MEDIA_WORKERS", value)
+		if got := mediaWorkersFromEnv(log); got != want {
+			t.Errorf("mediaWorkersFromEnv(%q) = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestNewNotesService_MediaWorkerNeedsClients(t *testing.T) {
+	t.Setenv("MEDIA_WORKERS", "2")
+	svc := NewNotesService(nil, nil, nil, "")
+	defer svc.StopMediaWorker()
+	if svc.media != nil {
+		t.Error("media worker started without storage or AI clients")
+	}
+}
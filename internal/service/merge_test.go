@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMergeNotes_InvalidArguments(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name string
+		req  *pb.MergeNotesRequest
+	}{
+		{name: "missing user_id", req: &pb.MergeNotesRequest{TargetId: "a", SourceIds: []string{"b"}}},
+		{name: "missing target_id", req: &pb.MergeNotesRequest{UserId: "user1", SourceIds: []string{"b"}}},
+		{name: "missing source_ids", req: &pb.MergeNotesRequest{UserId: "user1", TargetId: "a"}},
+		{name: "target in sources", req: &pb.MergeNotesRequest{UserId: "user1", TargetId: "a", SourceIds: []string{"b", "a"}}},
+		{name: "repeated source", req: &pb.MergeNotesRequest{UserId: "user1", TargetId: "a", SourceIds: []string{"b", "b"}}},
+		{name: "empty source", req: &pb.MergeNotesRequest{UserId: "user1", TargetId: "a", SourceIds: []string{""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.MergeNotes(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMergeNotes_NotFound(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("a", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectCommit()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.MergeNotes(ctx, &pb.MergeNotesRequest{UserId: "user1", TargetId: "a", SourceIds: []string{"b"}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestMergeNotes_SourceLinkedToNotion(t *testing.T) {
+	tests := []struct {
+		name       string
+		notionUUID interface{}
+		externalID interface{}
+	}{
+		{name: "notion uuid", notionUUID: "uuid-b"},
+		{name: "page id", externalID: "page-b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestNotesService(t)
+			defer cleanup()
+
+			now := time.Now()
+			noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId", "notionUuid", "externalId"}
+			mock.ExpectBegin()
+			// The target may be linked; only sources are deleted
+			mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+				WithArgs("a", "user1", 1).
+				WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("a", "first", now, now, "user1", "uuid-a", "page-a"))
+			mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+				WithArgs("b", "user1").
+				WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("b", "second", now, now, "user1", tt.notionUUID, tt.externalID))
+			// Nothing is moved or deleted
+			mock.ExpectRollback()
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+			_, err := svc.MergeNotes(ctx, &pb.MergeNotesRequest{UserId: "user1", TargetId: "a", SourceIds: []string{"b"}})
+			if status.Code(err) != codes.FailedPrecondition {
+				t.Errorf("expected FailedPrecondition, got %v", err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled mock expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestMergeNotes_ContentTooLong(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	svc.maxContent = 10
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId"}
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("a", "user1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("a", "first", now, now, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("b", "user1").
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("b", "second", now, now, "user1"))
+	// Nothing is moved or rewritten
+	mock.ExpectRollback()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.MergeNotes(ctx, &pb.MergeNotesRequest{UserId: "user1", TargetId: "a", SourceIds: []string{"b"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
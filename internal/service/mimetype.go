@@ -0,0 +1,69 @@
+package service
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// heicBrands are the ISO BMFF major brands used by HEIC/HEIF images.
+var heicBrands = map[string]bool{
+	"heic": true, "heix": true, "hevc": true, "hevx": true,
+	"heim": true, "heis": true, "mif1": true, "msf1": true,
+}
+
+// mimeAliases maps alternative names for the same format to one canonical type
+// so declared and sniffed types can be compared.
+var mimeAliases = map[string]string{
+	"image/jpg":       "image/jpeg",
+	"image/heif":      "image/heic",
+	"audio/mp3":       "audio/mpeg",
+	"audio/wave":      "audio/wav",
+	"audio/x-wav":     "audio/wav",
+	"audio/m4a":       "audio/mp4",
+	"audio/x-m4a":     "audio/mp4",
+	"video/mp4":       "audio/mp4",
+	"application/ogg": "audio/ogg",
+	"video/webm":      "audio/webm",
+}
+
+// canonicalMimeType lowercases a MIME type, strips parameters and resolves aliases.
+func canonicalMimeType(mimeType string) string {
+	mimeType = strings.ToLower(strings.TrimSpace(mimeType))
+	if i := strings.IndexByte(mimeType, ';'); i >= 0 {
+		mimeType = strings.TrimSpace(mimeType[:i])
+	}
+	if canonical, ok := mimeAliases[mimeType]; ok {
+		return canonical
+	}
+	return mimeType
+}
+
+// sniffMimeType detects the content type of an upload from its leading bytes.
+// Formats that http.DetectContentType doesn't recognize (HEIC, FLAC, AAC, M4A
+// and MP3 without an ID3 tag) are checked first by magic number.
+func sniffMimeType(data []byte) string {
+	switch {
+	case len(data) >= 12 && bytes.Equal(data[4:8], []byte("ftyp")):
+		if heicBrands[string(data[8:12])] {
+			return "image/heic"
+		}
+		return "audio/mp4"
+	case bytes.HasPrefix(data, []byte("fLaC")):
+		return "audio/flac"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xF6 == 0xF0:
+		// ADTS frame sync with layer bits 00
+		return "audio/aac"
+	case len(data) >= 2 && data[0] == 0xFF && data[1]&0xE0 == 0xE0 && data[1]&0x06 != 0:
+		// MPEG audio frame sync without an ID3 header
+		return "audio/mpeg"
+	}
+
+	return canonicalMimeType(http.DetectContentType(data))
+}
+
+// mimeTypeMatches reports whether the sniffed content type agrees with the declared one.
+func mimeTypeMatches(declared string, data []byte) (sniffed string, ok bool) {
+	sniffed = sniffMimeType(data)
+	return sniffed, canonicalMimeType(declared) == sniffed
+}
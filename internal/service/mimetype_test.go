@@ -0,0 +1,125 @@
+package service
+
+import (
+	"strings"
+	"testing"
+)
+
+// Crafted file headers for each supported format.
+var (
+	jpegHeader = []byte{0xFF, 0xD8, 0xFF, 0xE0, 0x00, 0x10, 'J', 'F', 'I', 'F', 0x00}
+	pngHeader  = []byte{0x89, 'P', 'N', 'G', 0x0D, 0x0A, 0x1A, 0x0A, 0x00, 0x00, 0x00, 0x0D}
+	gifHeader  = []byte("GIF89a\x01\x00\x01\x00")
+	webpHeader = []byte("RIFF\x24\x00\x00\x00WEBPVP8 ")
+	heicHeader = []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00mif1heic")
+	heifHeader = []byte("\x00\x00\x00\x18ftypmif1\x00\x00\x00\x00mif1heic")
+	wavHeader  = []byte("RIFF\x24\x00\x00\x00WAVEfmt ")
+	oggHeader  = []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00")
+	webmHeader = []byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F, 0x42, 0x86, 0x81, 0x01, 0x42, 0xF7, 0x81, 0x01, 0x42, 0x82, 0x84, 'w', 'e', 'b', 'm'}
+	m4aHeader  = []byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00M4A mp42isom")
+	flacHeader = []byte("fLaC\x00\x00\x00\x22")
+	aacHeader  = []byte{0xFF, 0xF1, 0x50, 0x80, 0x02, 0x1F, 0xFC}
+	mp3ID3     = []byte("ID3\x03\x00\x00\x00\x00\x00\x00")
+	mp3Frame   = []byte{0xFF, 0xFB, 0x90, 0x64, 0x00}
+)
+
+func TestSniffMimeType(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "jpeg", data: jpegHeader, want: "image/jpeg"},
+		{name: "png", data: pngHeader, want: "image/png"},
+		{name: "gif", data: gifHeader, want: "image/gif"},
+		{name: "webp", data: webpHeader, want: "image/webp"},
+		{name: "heic", data: heicHeader, want: "image/heic"},
+		{name: "heif", data: heifHeader, want: "image/heic"},
+		{name: "wav", data: wavHeader, want: "audio/wav"},
+		{name: "ogg", data: oggHeader, want: "audio/ogg"},
+		{name: "webm", data: webmHeader, want: "audio/webm"},
+		{name: "m4a", data: m4aHeader, want: "audio/mp4"},
+		{name: "flac", data: flacHeader, want: "audio/flac"},
+		{name: "aac", data: aacHeader, want: "audio/aac"},
+		{name: "mp3 with id3", data: mp3ID3, want: "audio/mpeg"},
+		{name: "mp3 frame", data: mp3Frame, want: "audio/mpeg"},
+		{name: "text", data: []byte("just some text"), want: "text/plain"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sniffMimeType(tt.data); got != tt.want {
+				t.Errorf("sniffMimeType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateImage_ContentSniffing(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		mimeType string
+		wantErr  string
+	}{
+		{name: "png declared png", data: pngHeader, mimeType: "image/png"},
+		{name: "jpeg declared jpg alias", data: jpegHeader, mimeType: "image/jpg"},
+		{name: "webp", data: webpHeader, mimeType: "image/webp"},
+		{name: "heic declared heif alias", data: heicHeader, mimeType: "image/heif"},
+		{name: "png declared jpeg", data: pngHeader, mimeType: "image/jpeg", wantErr: "does not match"},
+		{name: "text declared png", data: []byte("<html>not an image</html>"), mimeType: "image/png", wantErr: "does not match"},
+		{name: "audio declared png", data: mp3ID3, mimeType: "image/png", wantErr: "does not match"},
+		{name: "unsupported declared type", data: pngHeader, mimeType: "image/bmp", wantErr: "unsupported image type"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateImage(tt.data, tt.mimeType, MaxImageSize)
+			checkValidationError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestValidateAudio_ContentSniffing(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		mimeType string
+		wantErr  string
+	}{
+		{name: "mp3 declared mpeg", data: mp3ID3, mimeType: "audio/mpeg"},
+		{name: "mp3 declared mp3 alias", data: mp3Frame, mimeType: "audio/mp3"},
+		{name: "wav declared wave alias", data: wavHeader, mimeType: "audio/wave"},
+		{name: "ogg", data: oggHeader, mimeType: "audio/ogg"},
+		{name: "webm", data: webmHeader, mimeType: "audio/webm"},
+		{name: "m4a", data: m4aHeader, mimeType: "audio/m4a"},
+		{name: "flac", data: flacHeader, mimeType: "audio/flac"},
+		{name: "aac", data: aacHeader, mimeType: "audio/aac"},
+		{name: "image declared mpeg", data: jpegHeader, mimeType: "audio/mpeg", wantErr: "does not match"},
+		{name: "flac declared wav", data: flacHeader, mimeType: "audio/wav", wantErr: "does not match"},
+		{name: "heic declared mp4", data: heicHeader, mimeType: "audio/mp4", wantErr: "does not match"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAudio(tt.data, tt.mimeType, MaxAudioSize)
+			checkValidationError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func checkValidationError(t *testing.T, err error, wantErr string) {
+	t.Helper()
+	if wantErr == "" {
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		return
+	}
+	if err == nil {
+		t.Fatalf("expected error containing %q, got nil", wantErr)
+	}
+	if !strings.Contains(err.Error(), wantErr) {
+		t.Errorf("error = %q, want it to contain %q", err.Error(), wantErr)
+	}
+}
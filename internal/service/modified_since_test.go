@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestListModifiedSince_ReturnsWatermark(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	last := since.Add(2 * time.Hour)
+	mock.ExpectQuery(`SELECT \* FROM "Note" WHERE "userId" = \$1 AND \("updatedAt", id\) > \(\$2, \$3\) ORDER BY "updatedAt" ASC, id ASC LIMIT \$4`).
+		WithArgs("user1", since, "", MaxNotesLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note1", "first", since, since.Add(time.Hour), "user1").
+			AddRow("note2", "second", since, last, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"note_id", "id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ListModifiedSince(ctx, &pb.ListModifiedSinceRequest{
+		UserId: "user1",
+		Since:  timestamppb.New(since),
+		Limit:  1000,
+	})
+	if err != nil {
+		t.Fatalf("ListModifiedSince: %v", err)
+	}
+	if len(resp.Notes) != 2 {
+		t.Fatalf("len(Notes) = %d, want 2", len(resp.Notes))
+	}
+	if !resp.NextSince.AsTime().Equal(last) {
+		t.Errorf("NextSince = %v, want %v", resp.NextSince.AsTime(), last)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestListModifiedSince_NothingChangedKeepsWatermark(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	since := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(`SELECT \* FROM "Note" WHERE "userId" = \$1 AND \("updatedAt", id\) > \(\$2, \$3\)`).
+		WithArgs("user1", since, "", DefaultNotesLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ListModifiedSince(ctx, &pb.ListModifiedSinceRequest{UserId: "user1", Since: timestamppb.New(since)})
+	if err != nil {
+		t.Fatalf("ListModifiedSince: %v", err)
+	}
+	if len(resp.Notes) != 0 || !resp.NextSince.AsTime().Equal(since) {
+		t.Errorf("got %d notes and NextSince %v, want none and %v", len(resp.Notes), resp.NextSince.AsTime(), since)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestListModifiedSince_CursorPagesThroughTies(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	// Three notes share one minute-precision updatedAt, more than the limit
+	tied := time.Date(2026, 3, 1, 12, 30, 0, 0, time.UTC)
+	noteRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"})
+	}
+	expectAssociations := func() {
+		mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+			WillReturnRows(sqlmock.NewRows([]string{"note_id", "id", "name", "createdAt", "userId"}))
+		mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+		mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+	}
+	query := `SELECT \* FROM "Note" WHERE "userId" = \$1 AND \("updatedAt", id\) > \(\$2, \$3\) ORDER BY "updatedAt" ASC, id ASC LIMIT \$4`
+
+	mock.ExpectQuery(query).
+		WithArgs("user1", tied, "", 2).
+		WillReturnRows(noteRows().
+			AddRow("note1", "one", tied, tied, "user1").
+			AddRow("note2", "two", tied, tied, "user1"))
+	expectAssociations()
+	mock.ExpectQuery(query).
+		WithArgs("user1", tied, "note2", 2).
+		WillReturnRows(noteRows().
+			AddRow("note3", "three", tied, tied, "user1"))
+	expectAssociations()
+	mock.ExpectQuery(query).
+		WithArgs("user1", tied, "note3", 2).
+		WillReturnRows(noteRows())
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	req := &pb.ListModifiedSinceRequest{UserId: "user1", Since: timestamppb.New(tied), Limit: 2}
+	var got []string
+	for page := 0; page < 3; page++ {
+		resp, err := svc.ListModifiedSince(ctx, req)
+		if err != nil {
+			t.Fatalf("ListModifiedSince page %d: %v", page+1, err)
+		}
+		for _, n := range resp.Notes {
+			got = append(got, n.Id)
+		}
+		if resp.NextCursor == "" {
+			t.Fatalf("page %d has no next_cursor", page+1)
+		}
+		req = &pb.ListModifiedSinceRequest{UserId: "user1", Cursor: resp.NextCursor, Limit: 2}
+	}
+	if want := []string{"note1", "note2", "note3"}; !slices.Equal(got, want) {
+		t.Errorf("notes = %v, want %v", got, want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestListModifiedSince_InvalidCursor(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.ListModifiedSince(ctx, &pb.ListModifiedSinceRequest{UserId: "user1", Cursor: "not a cursor"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("got %v, want InvalidArgument", err)
+	}
+}
@@ -2,11 +2,18 @@ package service
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/icco/etu-backend/internal/ai"
 	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/media"
 	"github.com/icco/etu-backend/internal/models"
 	"github.com/icco/etu-backend/internal/storage"
 	pb "github.com/icco/etu-backend/proto"
@@ -18,33 +25,94 @@ import (
 const (
 	MaxNotesLimit     = 100
 	DefaultNotesLimit = 50
-	MaxImageSize      = 10 * 1024 * 1024 // 10MB max image size
-	MaxAudioSize      = 25 * 1024 * 1024 // 25MB max audio size
+	MaxImageSize      = 10 * 1024 * 1024 // 10MB default max image size, override with MAX_IMAGE_SIZE
+	MaxAudioSize      = 25 * 1024 * 1024 // 25MB default max audio size, override with MAX_AUDIO_SIZE
+
+	// SyncOCRTimeout bounds inline text extraction when extract_text_sync is set
+	SyncOCRTimeout = 20 * time.Second
+
+	// MaxIdempotencyKeyLength bounds CreateNote idempotency keys
+	MaxIdempotencyKeyLength = 255
+
+	// MaxCreatedAtSkew is how far in the future a CreateNote created_at may be
+	MaxCreatedAtSkew = 5 * time.Minute
 )
 
 // NotesService implements the NotesService gRPC service
 type NotesService struct {
 	pb.UnimplementedNotesServiceServer
-	db          *db.DB
-	storage     *storage.Client
-	aiClient    *ai.Client
-	imgixDomain string
-	log         *slog.Logger
+	db           NotesStore
+	storage      storage.Blobstore
+	aiClient     ai.Generator
+	imgixDomain  string
+	signer       urlSigner
+	urlExpiry    time.Duration
+	maxImageSize int
+	maxAudioSize int
+	maxContent   int
+	log          *slog.Logger
+	changes      *changeBroker
+	// converter transcodes HEIC uploads to JPEG; nil stores them as is
+	converter imageConverter
+	// media extracts text from fresh uploads; nil leaves them for taggen
+	media *mediaWorker
+
+	freeStorageQuota    int64
+	premiumStorageQuota int64
 }
 
-// NewNotesService creates a new NotesService
-func NewNotesService(database *db.DB, storageClient *storage.Client, aiClient *ai.Client, imgixDomain string) *NotesService {
-	return &NotesService{
-		db:          database,
-		storage:     storageClient,
-		aiClient:    aiClient,
-		imgixDomain: imgixDomain,
-		log:         slog.Default(),
+// urlSigner re-signs media URLs on read so clients never receive the
+// possibly-expired URL stored at upload time.
+type urlSigner interface {
+	GetSignedURLsWithExpiry(ctx context.Context, objectNames []string, expiry time.Duration) (map[string]string, error)
+}
+
+// NewNotesService creates a new NotesService. storageClient and aiClient may
+// be nil to disable uploads and AI features.
+func NewNotesService(database NotesStore, storageClient *storage.Client, aiClient ai.Generator, imgixDomain string) *NotesService {
+	log := slog.Default()
+	s := &NotesService{
+		db:           database,
+		aiClient:     aiClient,
+		imgixDomain:  imgixDomain,
+		urlExpiry:    durationFromEnv(log, "SIGNED_URL_EXPIRY", storage.SignedURLDuration),
+		maxImageSize: sizeLimitFromEnv(log, "MAX_IMAGE_SIZE", MaxImageSize),
+		maxAudioSize: sizeLimitFromEnv(log, "MAX_AUDIO_SIZE", MaxAudioSize),
+		maxContent:   intFromEnv(log, "MAX_CONTENT_LENGTH", models.DefaultMaxContentLength),
+		log:          log,
+		changes:      newChangeBroker(),
+		converter:    newHEIFConverterFromEnv(log),
+
+		freeStorageQuota:    int64(sizeLimitFromEnv(log, "STORAGE_QUOTA_FREE", DefaultFreeStorageQuota)),
+		premiumStorageQuota: int64(sizeLimitFromEnv(log, "STORAGE_QUOTA_PREMIUM", DefaultPremiumStorageQuota)),
+	}
+	// Only assign a non-nil client so a missing bucket leaves storage and
+	// signer nil rather than typed-nil interfaces.
+	if storageClient != nil {
+		s.storage = storageClient
+		s.signer = storageClient
 	}
+	if workers := mediaWorkersFromEnv(log); workers > 0 && storageClient != nil && aiClient != nil {
+		s.media = newMediaWorker(log, storageClient, aiClient, database, workers)
+		log.Info("media worker started", "workers", workers)
+	}
+	log.Info("upload size limits configured",
+		"max_image_size", s.maxImageSize,
+		"max_audio_size", s.maxAudioSize,
+		"max_content_length", s.maxContent,
+		"free_storage_quota", s.freeStorageQuota,
+		"premium_storage_quota", s.premiumStorageQuota)
+	return s
 }
 
 // ListNotes retrieves notes for a user with optional filtering
 func (s *NotesService) ListNotes(ctx context.Context, req *pb.ListNotesRequest) (*pb.ListNotesResponse, error) {
+	return s.listNotes(ctx, req, true)
+}
+
+// listNotes implements ListNotes. countAll also sets TotalUnfiltered, which
+// costs a second count query when filters are set.
+func (s *NotesService) listNotes(ctx context.Context, req *pb.ListNotesRequest, countAll bool) (*pb.ListNotesResponse, error) {
 	if req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
@@ -67,21 +135,81 @@ func (s *NotesService) ListNotes(ctx context.Context, req *pb.ListNotesRequest)
 		offset = 0
 	}
 
-	notes, total, err := s.db.ListNotes(ctx, req.UserId, req.Search, req.Tags, req.StartDate, req.EndDate, limit, offset)
+	if err := validateColor(req.Color); err != nil {
+		return nil, err
+	}
+
+	sort := db.NoteSort{By: req.SortBy, Dir: req.SortDir}
+	if err := sort.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	filter := db.NoteFilter{
+		Search:         req.Search,
+		Tags:           req.Tags,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		SearchCaptions: req.SearchCaptions,
+		SearchMedia:    req.SearchMedia,
+		Color:          req.Color,
+	}
+	include := noteInclude(req.IncludeTags, req.IncludeImages, req.IncludeAudios)
+	notes, total, err := s.db.ListNotes(ctx, req.UserId, filter, sort, include, limit, offset)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to list notes: %v", err)
 	}
 
-	pbNotes := make([]*pb.Note, len(notes))
-	for i, n := range notes {
-		pbNotes[i] = s.noteToProto(&n)
+	// Without filters the count above is already the user's total
+	totalUnfiltered := total
+	if countAll && !filter.IsEmpty() {
+		totalUnfiltered, err = s.db.CountNotes(ctx, req.UserId, db.NoteFilter{})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to count notes: %v", err)
+		}
 	}
 
+	pbNotes := s.notesToProto(ctx, notes, s.urlExpiry)
+
 	return &pb.ListNotesResponse{
-		Notes:  pbNotes,
-		Total:  int32(total),
-		Limit:  int32(limit),
-		Offset: int32(offset),
+		Notes:           pbNotes,
+		Total:           int32(total),
+		Limit:           int32(limit),
+		Offset:          int32(offset),
+		TotalUnfiltered: int32(totalUnfiltered),
+	}, nil
+}
+
+// CountNotes counts a user's notes matching the same filters as ListNotes
+func (s *NotesService) CountNotes(ctx context.Context, req *pb.CountNotesRequest) (*pb.CountNotesResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := validateColor(req.Color); err != nil {
+		return nil, err
+	}
+
+	filter := db.NoteFilter{
+		Search:         req.Search,
+		Tags:           req.Tags,
+		StartDate:      req.StartDate,
+		EndDate:        req.EndDate,
+		SearchCaptions: req.SearchCaptions,
+		SearchMedia:    req.SearchMedia,
+		Color:          req.Color,
+	}
+	total, err := s.db.CountNotes(ctx, req.UserId, filter)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count notes: %v", err)
+	}
+
+	return &pb.CountNotesResponse{
+		Total: int32(total),
 	}, nil
 }
 
@@ -90,19 +218,65 @@ func (s *NotesService) CreateNote(ctx context.Context, req *pb.CreateNoteRequest
 	if req.UserId == "" {
 		return nil, status.Error(codes.InvalidArgument, "user_id is required")
 	}
-	if req.Content == "" && len(req.Images) == 0 && len(req.Audios) == 0 {
+	content, err := s.normalizeContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	if content == "" && len(req.Images) == 0 && len(req.Audios) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "at least one of content, images, or audio files is required")
 	}
-	if req.Content == "" && (len(req.Images) > 0 || len(req.Audios) > 0) && s.storage == nil {
+	if content == "" && (len(req.Images) > 0 || len(req.Audios) > 0) && s.storage == nil {
 		return nil, status.Error(codes.FailedPrecondition, "storage is not configured")
 	}
+	if len(req.IdempotencyKey) > MaxIdempotencyKeyLength {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency_key must be at most %d characters", MaxIdempotencyKeyLength)
+	}
+	if err := validateColor(req.Color); err != nil {
+		return nil, err
+	}
+	createdAt, err := validateCreatedAt(req)
+	if err != nil {
+		return nil, err
+	}
 
 	// Verify authorization
 	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
 		return nil, err
 	}
 
-	note, err := s.db.CreateNote(ctx, req.UserId, req.Content, req.Tags)
+	// A retry of a request that already succeeded gets the original note back
+	if req.IdempotencyKey != "" {
+		existing, err := s.db.FindNoteByIdempotencyKey(ctx, req.UserId, req.IdempotencyKey)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to check idempotency key: %v", err)
+		}
+		if existing != nil {
+			return &pb.CreateNoteResponse{
+				Note: s.noteToProto(ctx, existing, s.urlExpiry),
+			}, nil
+		}
+	}
+
+	// Reserve quota for all attachments up front so nothing is created if they won't fit
+	if s.storage != nil {
+		release, err := s.reserveUploadQuota(ctx, req.UserId, uploadSize(req.Images, req.Audios))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	note, err := s.db.CreateNote(ctx, req.UserId, content, req.Color, req.Tags, req.IdempotencyKey, createdAt)
+	if errors.Is(err, db.ErrIdempotencyKeyConflict) {
+		// A concurrent retry won the race, so return its note instead
+		existing, findErr := s.db.FindNoteByIdempotencyKey(ctx, req.UserId, req.IdempotencyKey)
+		if findErr != nil || existing == nil {
+			return nil, status.Errorf(codes.Aborted, "note with this idempotency key is being created: %v", err)
+		}
+		return &pb.CreateNoteResponse{
+			Note: s.noteToProto(ctx, existing, s.urlExpiry),
+		}, nil
+	}
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create note: %v", err)
 	}
@@ -111,11 +285,16 @@ func (s *NotesService) CreateNote(ctx context.Context, req *pb.CreateNoteRequest
 	if len(req.Images) > 0 && s.storage != nil {
 		for i, img := range req.Images {
 			// Upload image to GCS
-			noteImage, err := s.processAndUploadImage(ctx, note.ID, img.Data, img.MimeType)
+			noteImage, imageData, err := s.processAndUploadImage(ctx, note.ID, img.Data, img.MimeType)
 			if err != nil {
 				s.log.Error("failed to process image", "note_id", note.ID, "image_index", i, "error", err)
 				continue // Continue with other images even if one fails
 			}
+			noteImage.Caption = img.Caption
+
+			if req.ExtractTextSync {
+				s.extractTextInline(ctx, noteImage, imageData)
+			}
 
 			// Add image to database
 			if err := s.db.AddImageToNote(ctx, note.ID, noteImage); err != nil {
@@ -129,6 +308,7 @@ func (s *NotesService) CreateNote(ctx context.Context, req *pb.CreateNoteRequest
 				continue
 			}
 
+			s.enqueueImage(noteImage)
 			note.Images = append(note.Images, *noteImage)
 		}
 	}
@@ -155,41 +335,87 @@ func (s *NotesService) CreateNote(ctx context.Context, req *pb.CreateNoteRequest
 				continue
 			}
 
+			s.enqueueAudio(noteAudio)
 			note.Audios = append(note.Audios, *noteAudio)
 		}
 	}
 
+	// Reload so attachments come back in stored order with every column set.
+	// The note is already saved, so a failed reload falls back to the copy in
+	// hand rather than failing a request the client might retry.
+	if len(note.Images) > 0 || len(note.Audios) > 0 {
+		reloaded, err := s.db.GetNoteFromPrimary(ctx, req.UserId, note.ID)
+		if err != nil {
+			s.log.Warn("failed to reload created note", "note_id", note.ID, "error", err)
+		} else if reloaded != nil {
+			note = reloaded
+		}
+	}
+
+	pbNote := s.noteToProto(ctx, note, s.urlExpiry)
+	s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_CREATED, pbNote)
+	if len(pbNote.Tags) > 0 {
+		s.publishTags(req.UserId, pbNote)
+	}
+
 	return &pb.CreateNoteResponse{
-		Note: s.noteToProto(note),
+		Note: pbNote,
 	}, nil
 }
 
+// normalizeContent trims trailing whitespace from note content and rejects
+// content longer than the configured limit.
+func (s *NotesService) normalizeContent(content string) (string, error) {
+	content = strings.TrimRightFunc(content, unicode.IsSpace)
+	if utf8.RuneCountInString(content) > s.maxContent {
+		return "", status.Errorf(codes.InvalidArgument, "content must be at most %d characters", s.maxContent)
+	}
+	return content, nil
+}
+
+// validateColor rejects label colors outside models.NoteColors
+func validateColor(color string) error {
+	if !models.IsValidNoteColor(color) {
+		return status.Errorf(codes.InvalidArgument, "color must be empty or one of: %s", strings.Join(models.NoteColors, ", "))
+	}
+	return nil
+}
+
 // validateImage validates the image MIME type and size
-func validateImage(imageData []byte, mimeType string) error {
+func validateImage(imageData []byte, mimeType string, maxSize int) error {
 	// Validate MIME type against allow-list
 	if !ai.IsValidImageMimeType(mimeType) {
 		return fmt.Errorf("unsupported image type: %s. Allowed types: image/jpeg, image/png, image/gif, image/webp, image/heic, image/heif", mimeType)
 	}
 
 	// Validate image size
-	if len(imageData) > MaxImageSize {
-		return fmt.Errorf("image size %d bytes exceeds maximum allowed size of %d bytes", len(imageData), MaxImageSize)
+	if len(imageData) > maxSize {
+		return fmt.Errorf("image size %d bytes exceeds maximum allowed size of %d bytes", len(imageData), maxSize)
+	}
+
+	// Validate the content actually matches the declared type
+	if sniffed, ok := mimeTypeMatches(mimeType, imageData); !ok {
+		return fmt.Errorf("image content type %s does not match declared type %s", sniffed, mimeType)
 	}
 
 	return nil
 }
 
-// processAndUploadImage uploads an image to GCS and extracts text using Gemini OCR
-func (s *NotesService) processAndUploadImage(ctx context.Context, noteID string, imageData []byte, mimeType string) (*models.NoteImage, error) {
+// processAndUploadImage uploads an image to GCS, converting HEIC/HEIF to JPEG
+// first. It returns the stored bytes so inline OCR sees what was uploaded.
+func (s *NotesService) processAndUploadImage(ctx context.Context, noteID string, imageData []byte, mimeType string) (*models.NoteImage, []byte, error) {
 	if s.storage == nil {
-		return nil, fmt.Errorf("storage client not configured")
+		return nil, nil, fmt.Errorf("storage client not configured")
 	}
 
 	// Validate image before uploading
-	if err := validateImage(imageData, mimeType); err != nil {
-		return nil, err
+	if err := validateImage(imageData, mimeType, s.maxImageSize); err != nil {
+		return nil, nil, err
 	}
 
+	imageData, mimeType = s.transcodeImage(ctx, imageData, mimeType)
+	width, height := media.ImageDimensions(imageData)
+
 	// Generate a unique object name
 	imageID := models.GenerateCUID()
 	objectName := fmt.Sprintf("notes/%s/%s", noteID, imageID)
@@ -197,30 +423,77 @@ func (s *NotesService) processAndUploadImage(ctx context.Context, noteID string,
 	// Upload to GCS
 	url, err := s.storage.UploadImage(ctx, objectName, imageData, mimeType)
 	if err != nil {
-		return nil, fmt.Errorf("failed to upload image: %w", err)
+		return nil, nil, fmt.Errorf("failed to upload image: %w", err)
 	}
 
-	// Note: Text extraction is handled asynchronously by a background job for performance
+	// Note: Text extraction is handled asynchronously by a background job unless
+	// the caller asks for it inline via extract_text_sync
 	return &models.NoteImage{
 		ID:            imageID,
 		NoteID:        noteID,
 		URL:           url,
 		GCSObjectName: objectName,
-		ExtractedText: "", // Will be filled by background job
+		ExtractedText: "", // Will be filled inline or by background job
 		MimeType:      mimeType,
-	}, nil
+		SizeBytes:     int64(len(imageData)),
+		Width:         width,
+		Height:        height,
+	}, imageData, nil
+}
+
+// extractTextInline runs OCR on a freshly uploaded image so the text is
+// returned with the note. On failure or timeout the text is left empty and the
+// background job fills it in later.
+func (s *NotesService) extractTextInline(ctx context.Context, img *models.NoteImage, imageData []byte) {
+	if s.aiClient == nil {
+		return
+	}
+
+	ocrCtx, cancel := context.WithTimeout(ctx, SyncOCRTimeout)
+	defer cancel()
+
+	text, err := s.aiClient.ExtractTextFromImage(ocrCtx, imageData, img.MimeType)
+	if err != nil {
+		s.log.Warn("inline text extraction failed, deferring to background job", "image_id", img.ID, "error", err)
+		return
+	}
+	img.ExtractedText = text
+}
+
+// validateCreatedAt returns the creation time requested for a new note, or
+// the zero time to use now
+func validateCreatedAt(req *pb.CreateNoteRequest) (time.Time, error) {
+	if req.CreatedAt == nil {
+		return time.Time{}, nil
+	}
+	if err := req.CreatedAt.CheckValid(); err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "invalid created_at: %v", err)
+	}
+	if req.IdempotencyKey != "" {
+		return time.Time{}, status.Error(codes.InvalidArgument, "created_at cannot be combined with idempotency_key")
+	}
+	createdAt := req.CreatedAt.AsTime()
+	if createdAt.After(time.Now().Add(MaxCreatedAtSkew)) {
+		return time.Time{}, status.Error(codes.InvalidArgument, "created_at must not be in the future")
+	}
+	return createdAt, nil
 }
 
 // validateAudio validates the audio MIME type and size
-func validateAudio(audioData []byte, mimeType string) error {
+func validateAudio(audioData []byte, mimeType string, maxSize int) error {
 	// Validate MIME type against allow-list
 	if !ai.IsValidAudioMimeType(mimeType) {
 		return fmt.Errorf("unsupported audio type: %s. Allowed types: audio/mpeg, audio/mp3, audio/wav, audio/wave, audio/ogg, audio/webm, audio/mp4, audio/m4a, audio/flac, audio/aac", mimeType)
 	}
 
 	// Validate audio size
-	if len(audioData) > MaxAudioSize {
-		return fmt.Errorf("audio size %d bytes exceeds maximum allowed size of %d bytes", len(audioData), MaxAudioSize)
+	if len(audioData) > maxSize {
+		return fmt.Errorf("audio size %d bytes exceeds maximum allowed size of %d bytes", len(audioData), maxSize)
+	}
+
+	// Validate the content actually matches the declared type
+	if sniffed, ok := mimeTypeMatches(mimeType, audioData); !ok {
+		return fmt.Errorf("audio content type %s does not match declared type %s", sniffed, mimeType)
 	}
 
 	return nil
@@ -233,7 +506,7 @@ func (s *NotesService) processAndUploadAudio(ctx context.Context, noteID string,
 	}
 
 	// Validate audio before uploading
-	if err := validateAudio(audioData, mimeType); err != nil {
+	if err := validateAudio(audioData, mimeType, s.maxAudioSize); err != nil {
 		return nil, err
 	}
 
@@ -255,6 +528,7 @@ func (s *NotesService) processAndUploadAudio(ctx context.Context, noteID string,
 		GCSObjectName:   objectName,
 		TranscribedText: "", // Will be filled by background job
 		MimeType:        mimeType,
+		SizeBytes:       int64(len(audioData)),
 	}, nil
 }
 
@@ -266,13 +540,20 @@ func (s *NotesService) GetNote(ctx context.Context, req *pb.GetNoteRequest) (*pb
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
+	if req.UrlExpirySeconds < 0 {
+		return nil, status.Error(codes.InvalidArgument, "url_expiry_seconds must not be negative")
+	}
+	include := noteInclude(req.IncludeTags, req.IncludeImages, req.IncludeAudios)
+	if req.CountImageText && !include.Images {
+		return nil, status.Error(codes.InvalidArgument, "count_image_text requires include_images")
+	}
 
 	// Verify authorization
 	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
 		return nil, err
 	}
 
-	note, err := s.db.GetNote(ctx, req.UserId, req.Id)
+	note, err := s.db.GetNote(ctx, req.UserId, req.Id, include)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get note: %v", err)
 	}
@@ -280,8 +561,21 @@ func (s *NotesService) GetNote(ctx context.Context, req *pb.GetNoteRequest) (*pb
 		return nil, status.Error(codes.NotFound, "note not found")
 	}
 
+	urlExpiry := s.urlExpiry
+	if req.UrlExpirySeconds > 0 {
+		urlExpiry = min(time.Duration(req.UrlExpirySeconds)*time.Second, storage.SignedURLDuration)
+	}
+
+	pbNote := s.noteToProto(ctx, note, urlExpiry)
+	if req.CountImageText {
+		for _, img := range note.Images {
+			pbNote.WordCount += db.CountWords(img.ExtractedText)
+			pbNote.CharCount += int64(utf8.RuneCountInString(img.ExtractedText))
+		}
+	}
+
 	return &pb.GetNoteResponse{
-		Note: s.noteToProto(note),
+		Note: pbNote,
 	}, nil
 }
 
@@ -293,18 +587,35 @@ func (s *NotesService) UpdateNote(ctx context.Context, req *pb.UpdateNoteRequest
 	if req.Id == "" {
 		return nil, status.Error(codes.InvalidArgument, "id is required")
 	}
+	var content *string
+	if req.Content != nil {
+		normalized, err := s.normalizeContent(*req.Content)
+		if err != nil {
+			return nil, err
+		}
+		content = &normalized
+	}
+	if req.Color != nil {
+		if err := validateColor(*req.Color); err != nil {
+			return nil, err
+		}
+	}
 
 	// Verify authorization
 	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
 		return nil, err
 	}
 
-	var content *string
-	if req.Content != nil {
-		content = req.Content
+	// Reserve quota for new attachments before changing anything
+	if s.storage != nil {
+		release, err := s.reserveUploadQuota(ctx, req.UserId, uploadSize(req.AddImages, req.AddAudios))
+		if err != nil {
+			return nil, err
+		}
+		defer release()
 	}
 
-	note, err := s.db.UpdateNote(ctx, req.UserId, req.Id, content, req.Tags, req.UpdateTags)
+	note, err := s.db.UpdateNote(ctx, req.UserId, req.Id, content, req.Color, req.Tags, req.UpdateTags)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update note: %v", err)
 	}
@@ -315,11 +626,16 @@ func (s *NotesService) UpdateNote(ctx context.Context, req *pb.UpdateNoteRequest
 	// Add new images if any
 	if len(req.AddImages) > 0 && s.storage != nil {
 		for i, img := range req.AddImages {
-			noteImage, err := s.processAndUploadImage(ctx, note.ID, img.Data, img.MimeType)
+			noteImage, imageData, err := s.processAndUploadImage(ctx, note.ID, img.Data, img.MimeType)
 			if err != nil {
 				s.log.Error("failed to process image", "note_id", note.ID, "image_index", i, "error", err)
 				continue
 			}
+			noteImage.Caption = img.Caption
+
+			if req.ExtractTextSync {
+				s.extractTextInline(ctx, noteImage, imageData)
+			}
 
 			if err := s.db.AddImageToNote(ctx, note.ID, noteImage); err != nil {
 				s.log.Error("failed to save image to database", "note_id", note.ID, "image_id", noteImage.ID, "error", err)
@@ -330,6 +646,7 @@ func (s *NotesService) UpdateNote(ctx context.Context, req *pb.UpdateNoteRequest
 				}
 				continue
 			}
+			s.enqueueImage(noteImage)
 		}
 	}
 
@@ -351,17 +668,24 @@ func (s *NotesService) UpdateNote(ctx context.Context, req *pb.UpdateNoteRequest
 				}
 				continue
 			}
+			s.enqueueAudio(noteAudio)
 		}
 	}
 
 	// Reload note to get updated images and audios
-	note, err = s.db.GetNote(ctx, req.UserId, req.Id)
+	note, err = s.db.GetNoteFromPrimary(ctx, req.UserId, req.Id)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to reload note: %v", err)
 	}
 
+	pbNote := s.noteToProto(ctx, note, s.urlExpiry)
+	s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_UPDATED, pbNote)
+	if req.UpdateTags {
+		s.publishTags(req.UserId, pbNote)
+	}
+
 	return &pb.UpdateNoteResponse{
-		Note: s.noteToProto(note),
+		Note: pbNote,
 	}, nil
 }
 
@@ -396,6 +720,10 @@ func (s *NotesService) DeleteNote(ctx context.Context, req *pb.DeleteNoteRequest
 		return nil, status.Errorf(codes.Internal, "failed to delete note: %v", err)
 	}
 
+	if deleted {
+		s.publishDeleted(req.UserId, req.Id)
+	}
+
 	// Clean up images from GCS if the note was deleted
 	if deleted && s.storage != nil {
 		for _, img := range images {
@@ -419,28 +747,101 @@ func (s *NotesService) DeleteNote(ctx context.Context, req *pb.DeleteNoteRequest
 	}, nil
 }
 
-// getImageURL returns the appropriate URL for an image.
-// If imgix is configured, it returns an imgix URL using the GCS object name.
-// Otherwise, it returns the original GCS signed URL.
-func (s *NotesService) getImageURL(img *models.NoteImage) string {
-	if s.imgixDomain != "" && img.GCSObjectName != "" {
-		return fmt.Sprintf("https://%s/%s", s.imgixDomain, img.GCSObjectName)
+// signMediaURLs signs fresh URLs for every image and audio object in notes
+// in a single batch, keyed by object name. It returns nil when imgix serves
+// the media or storage is not configured. Objects that fail to sign are
+// missing from the map so callers fall back to the stored URL.
+func (s *NotesService) signMediaURLs(ctx context.Context, notes []db.Note, expiry time.Duration) map[string]string {
+	if s.imgixDomain != "" || s.signer == nil {
+		return nil
+	}
+
+	var objectNames []string
+	for _, n := range notes {
+		for _, img := range n.Images {
+			objectNames = append(objectNames, img.GCSObjectName)
+		}
+		for _, aud := range n.Audios {
+			objectNames = append(objectNames, aud.GCSObjectName)
+		}
+	}
+	if len(objectNames) == 0 {
+		return nil
 	}
-	return img.URL
+
+	urls, err := s.signer.GetSignedURLsWithExpiry(ctx, objectNames, expiry)
+	if err != nil {
+		s.log.Warn("failed to sign some media URLs, using stored URLs", "error", err)
+	}
+	return urls
 }
 
-// getAudioURL returns the appropriate URL for an audio file.
+// mediaURL returns the URL clients should use for a stored media object.
 // If imgix is configured, it returns an imgix URL using the GCS object name.
-// Otherwise, it returns the original GCS signed URL.
-func (s *NotesService) getAudioURL(aud *models.NoteAudio) string {
-	if s.imgixDomain != "" && aud.GCSObjectName != "" {
-		return fmt.Sprintf("https://%s/%s", s.imgixDomain, aud.GCSObjectName)
+// Otherwise it uses the freshly signed URL, falling back to the URL stored at
+// upload time when signing is unavailable or failed.
+func (s *NotesService) mediaURL(objectName, storedURL string, signedURLs map[string]string) string {
+	if objectName == "" {
+		return storedURL
+	}
+	if s.imgixDomain != "" {
+		return fmt.Sprintf("https://%s/%s", s.imgixDomain, objectName)
+	}
+	if url, ok := signedURLs[objectName]; ok {
+		return url
+	}
+	return storedURL
+}
+
+// noteInclude returns the associations to load for a request's include_*
+// fields, each of which defaults to included when unset
+func noteInclude(tags, images, audios *bool) db.NoteInclude {
+	include := db.DefaultNoteInclude
+	if tags != nil {
+		include.Tags = *tags
+	}
+	if images != nil {
+		include.Images = *images
+	}
+	if audios != nil {
+		include.Audios = *audios
 	}
-	return aud.URL
+	return include
 }
 
-// noteToProto converts a db.Note to a protobuf Note
-func (s *NotesService) noteToProto(n *db.Note) *pb.Note {
+// notesToProto converts notes to protobuf Notes, signing all media URLs for
+// urlExpiry in one batch
+func (s *NotesService) notesToProto(ctx context.Context, notes []db.Note, urlExpiry time.Duration) []*pb.Note {
+	signedURLs := s.signMediaURLs(ctx, notes, urlExpiry)
+
+	pbNotes := make([]*pb.Note, len(notes))
+	for i := range notes {
+		pbNotes[i] = s.convertNote(&notes[i], signedURLs)
+	}
+	return pbNotes
+}
+
+// noteToProto converts a db.Note to a protobuf Note, signing media URLs for urlExpiry
+func (s *NotesService) noteToProto(ctx context.Context, n *db.Note, urlExpiry time.Duration) *pb.Note {
+	return s.convertNote(n, s.signMediaURLs(ctx, []db.Note{*n}, urlExpiry))
+}
+
+// imageToProto converts a db.NoteImage to a protobuf NoteImage using pre-signed media URLs
+func (s *NotesService) imageToProto(img *db.NoteImage, signedURLs map[string]string) *pb.NoteImage {
+	return &pb.NoteImage{
+		Id:            img.ID,
+		Url:           s.mediaURL(img.GCSObjectName, img.URL, signedURLs),
+		ExtractedText: img.ExtractedText,
+		MimeType:      img.MimeType,
+		CreatedAt:     timestamppb.New(img.CreatedAt),
+		Caption:       img.Caption,
+		Width:         int32(img.Width),
+		Height:        int32(img.Height),
+	}
+}
+
+// convertNote converts a db.Note to a protobuf Note using pre-signed media URLs
+func (s *NotesService) convertNote(n *db.Note, signedURLs map[string]string) *pb.Note {
 	// Convert []Tag to []string
 	tagNames := make([]string, len(n.Tags))
 	for i, t := range n.Tags {
@@ -449,14 +850,8 @@ func (s *NotesService) noteToProto(n *db.Note) *pb.Note {
 
 	// Convert []NoteImage to []*pb.NoteImage
 	pbImages := make([]*pb.NoteImage, len(n.Images))
-	for i, img := range n.Images {
-		pbImages[i] = &pb.NoteImage{
-			Id:            img.ID,
-			Url:           s.getImageURL(&img),
-			ExtractedText: img.ExtractedText,
-			MimeType:      img.MimeType,
-			CreatedAt:     timestamppb.New(img.CreatedAt),
-		}
+	for i := range n.Images {
+		pbImages[i] = s.imageToProto(&n.Images[i], signedURLs)
 	}
 
 	// Convert []NoteAudio to []*pb.NoteAudio
@@ -464,7 +859,7 @@ func (s *NotesService) noteToProto(n *db.Note) *pb.Note {
 	for i, aud := range n.Audios {
 		pbAudios[i] = &pb.NoteAudio{
 			Id:              aud.ID,
-			Url:             s.getAudioURL(&aud),
+			Url:             s.mediaURL(aud.GCSObjectName, aud.URL, signedURLs),
 			TranscribedText: aud.TranscribedText,
 			MimeType:        aud.MimeType,
 			CreatedAt:       timestamppb.New(aud.CreatedAt),
@@ -479,6 +874,12 @@ func (s *NotesService) noteToProto(n *db.Note) *pb.Note {
 		UpdatedAt: timestamppb.New(n.UpdatedAt),
 		Images:    pbImages,
 		Audios:    pbAudios,
+		WordCount: db.CountWords(n.Content),
+		CharCount: int64(utf8.RuneCountInString(n.Content)),
+		Pinned:    n.Pinned,
+		Color:     n.Color,
+		Summary:   n.Summary,
+		Mood:      n.Mood,
 	}
 }
 
@@ -503,12 +904,364 @@ func (s *NotesService) GetRandomNotes(ctx context.Context, req *pb.GetRandomNote
 		return nil, status.Errorf(codes.Internal, "failed to get random notes: %v", err)
 	}
 
-	pbNotes := make([]*pb.Note, len(notes))
-	for i, n := range notes {
-		pbNotes[i] = s.noteToProto(&n)
-	}
+	pbNotes := s.notesToProto(ctx, notes, s.urlExpiry)
 
 	return &pb.GetRandomNotesResponse{
 		Notes: pbNotes,
 	}, nil
 }
+
+// ListModifiedSince returns the user's notes updated at or after req.Since, or
+// after req.Cursor, oldest update first, with a cursor for the next call
+func (s *NotesService) ListModifiedSince(ctx context.Context, req *pb.ListModifiedSinceRequest) (*pb.ListModifiedSinceResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Since != nil {
+		if err := req.Since.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid since: %v", err)
+		}
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = DefaultNotesLimit
+	}
+	if limit > MaxNotesLimit {
+		limit = MaxNotesLimit
+	}
+
+	var since time.Time
+	var afterID string
+	if req.Cursor != "" {
+		var err error
+		since, afterID, err = decodeModifiedCursor(req.Cursor)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid cursor: %v", err)
+		}
+	} else if req.Since != nil {
+		since = req.Since.AsTime()
+	}
+
+	notes, err := s.db.ListNotesModifiedSince(ctx, req.UserId, since, afterID, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list modified notes: %v", err)
+	}
+
+	if len(notes) > 0 {
+		last := notes[len(notes)-1]
+		since, afterID = last.UpdatedAt, last.ID
+	}
+
+	return &pb.ListModifiedSinceResponse{
+		Notes:      s.notesToProto(ctx, notes, s.urlExpiry),
+		NextSince:  timestamppb.New(since),
+		NextCursor: encodeModifiedCursor(since, afterID),
+	}, nil
+}
+
+// encodeModifiedCursor encodes a ListModifiedSince position: the updatedAt and
+// ID of the last note returned
+func encodeModifiedCursor(updatedAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(updatedAt.UTC().Format(time.RFC3339Nano) + " " + id))
+}
+
+// decodeModifiedCursor reverses encodeModifiedCursor
+func decodeModifiedCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	ts, id, ok := strings.Cut(string(raw), " ")
+	if !ok {
+		return time.Time{}, "", fmt.Errorf("malformed cursor")
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return updatedAt, id, nil
+}
+
+// parseReprocessTasks converts ReprocessNote task names into the data to clear.
+// The names match the taggen -tasks flag.
+func parseReprocessTasks(tasks []string) (db.ReprocessOptions, error) {
+	var opts db.ReprocessOptions
+	for _, task := range tasks {
+		switch strings.ToLower(strings.TrimSpace(task)) {
+		case "ocr":
+			opts.ExtractedText = true
+		case "transcribe":
+			opts.TranscribedText = true
+		case "tags":
+			opts.Tags = true
+		default:
+			return opts, fmt.Errorf("unknown task %q (valid: ocr, transcribe, tags)", task)
+		}
+	}
+	return opts, nil
+}
+
+// ReprocessNote clears AI-derived data for a note so the taggen job regenerates it
+func (s *NotesService) ReprocessNote(ctx context.Context, req *pb.ReprocessNoteRequest) (*pb.ReprocessNoteResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if len(req.Tasks) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "tasks is required")
+	}
+
+	opts, err := parseReprocessTasks(req.Tasks)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	found, err := s.db.ReprocessNote(ctx, req.UserId, req.Id, opts)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reprocess note: %v", err)
+	}
+	if !found {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	note, err := s.db.GetNoteFromPrimary(ctx, req.UserId, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reload note: %v", err)
+	}
+	if note == nil {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	pbNote := s.noteToProto(ctx, note, s.urlExpiry)
+	s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_UPDATED, pbNote)
+
+	return &pb.ReprocessNoteResponse{
+		Note: pbNote,
+	}, nil
+}
+
+// ReorderImages sets the display order of a note's images
+func (s *NotesService) ReorderImages(ctx context.Context, req *pb.ReorderImagesRequest) (*pb.ReorderImagesResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.NoteId == "" {
+		return nil, status.Error(codes.InvalidArgument, "note_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	found, err := s.db.ReorderNoteImages(ctx, req.UserId, req.NoteId, req.ImageIds)
+	if errors.Is(err, db.ErrInvalidImageOrder) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reorder images: %v", err)
+	}
+	if !found {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	note, err := s.db.GetNoteFromPrimary(ctx, req.UserId, req.NoteId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reload note: %v", err)
+	}
+	if note == nil {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	pbNote := s.noteToProto(ctx, note, s.urlExpiry)
+	s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_UPDATED, pbNote)
+
+	return &pb.ReorderImagesResponse{
+		Note: pbNote,
+	}, nil
+}
+
+// SetNotePinned pins or unpins a note
+func (s *NotesService) SetNotePinned(ctx context.Context, req *pb.SetNotePinnedRequest) (*pb.SetNotePinnedResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	found, err := s.db.SetNotePinned(ctx, req.UserId, req.Id, req.Pinned)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to set note pinned: %v", err)
+	}
+	if !found {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	note, err := s.db.GetNoteFromPrimary(ctx, req.UserId, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reload note: %v", err)
+	}
+	if note == nil {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	pbNote := s.noteToProto(ctx, note, s.urlExpiry)
+	s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_UPDATED, pbNote)
+
+	return &pb.SetNotePinnedResponse{
+		Note: pbNote,
+	}, nil
+}
+
+// UpdateImageCaption sets the caption of a note image
+func (s *NotesService) UpdateImageCaption(ctx context.Context, req *pb.UpdateImageCaptionRequest) (*pb.UpdateImageCaptionResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.ImageId == "" {
+		return nil, status.Error(codes.InvalidArgument, "image_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	img, err := s.db.UpdateImageCaption(ctx, req.UserId, req.ImageId, req.Caption)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update image caption: %v", err)
+	}
+	if img == nil {
+		return nil, status.Error(codes.NotFound, "image not found")
+	}
+
+	// The response only carries the image, so watchers need the note reloaded
+	if s.changes.watching(req.UserId) {
+		note, err := s.db.GetNoteFromPrimary(ctx, req.UserId, img.NoteID)
+		if err != nil || note == nil {
+			s.log.Warn("failed to reload note for watchers", "note_id", img.NoteID, "error", err)
+		} else {
+			s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_UPDATED, s.noteToProto(ctx, note, s.urlExpiry))
+		}
+	}
+
+	signedURLs := s.signMediaURLs(ctx, []db.Note{{Images: []db.NoteImage{*img}}}, s.urlExpiry)
+	return &pb.UpdateImageCaptionResponse{
+		Image: s.imageToProto(img, signedURLs),
+	}, nil
+}
+
+// FindDuplicates returns groups of notes with the same normalized content
+func (s *NotesService) FindDuplicates(ctx context.Context, req *pb.FindDuplicatesRequest) (*pb.FindDuplicatesResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	groups, err := s.db.FindDuplicateNotes(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to find duplicate notes: %v", err)
+	}
+
+	pbGroups := make([]*pb.DuplicateGroup, len(groups))
+	for i, g := range groups {
+		notes := make([]*pb.DuplicateNote, len(g.Notes))
+		for j, n := range g.Notes {
+			notes[j] = &pb.DuplicateNote{
+				Id:        n.ID,
+				CreatedAt: timestamppb.New(n.CreatedAt),
+			}
+		}
+		pbGroups[i] = &pb.DuplicateGroup{Notes: notes}
+	}
+
+	return &pb.FindDuplicatesResponse{Groups: pbGroups}, nil
+}
+
+// MergeNotes merges the source notes into the target note
+func (s *NotesService) MergeNotes(ctx context.Context, req *pb.MergeNotesRequest) (*pb.MergeNotesResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.TargetId == "" {
+		return nil, status.Error(codes.InvalidArgument, "target_id is required")
+	}
+	if len(req.SourceIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "source_ids is required")
+	}
+	seen := map[string]bool{req.TargetId: true}
+	for _, id := range req.SourceIds {
+		if id == "" || seen[id] {
+			return nil, status.Errorf(codes.InvalidArgument, "source_ids must be distinct note ids other than target_id, got %q", id)
+		}
+		seen[id] = true
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	note, orphaned, err := s.db.MergeNotes(ctx, req.UserId, req.TargetId, req.SourceIds, s.maxContent)
+
+	// Media rows are moved onto the target, so this only cleans up after an
+	// unexpected leftover. It runs before the error check because the merge
+	// has already committed if only the reload failed.
+	if len(orphaned) > 0 {
+		s.log.Warn("merged notes left media behind", "target_id", req.TargetId, "objects", orphaned)
+		if s.storage != nil {
+			for _, name := range orphaned {
+				if err := s.storage.DeleteImage(ctx, name); err != nil {
+					s.log.Error("failed to delete orphaned media from GCS", "object_name", name, "error", err)
+				}
+			}
+		}
+	}
+
+	if errors.Is(err, db.ErrMergedContentTooLong) {
+		return nil, status.Errorf(codes.InvalidArgument, "merged content must be at most %d characters", s.maxContent)
+	}
+	if errors.Is(err, db.ErrMergeSourceInNotion) {
+		return nil, status.Errorf(codes.FailedPrecondition, "source notes synced to Notion cannot be merged away: %v", err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to merge notes: %v", err)
+	}
+	if note == nil {
+		return nil, status.Error(codes.NotFound, "note not found")
+	}
+
+	pbNote := s.noteToProto(ctx, note, s.urlExpiry)
+	for _, id := range req.SourceIds {
+		s.publishDeleted(req.UserId, id)
+	}
+	s.publishNote(req.UserId, pb.NoteEventType_NOTE_EVENT_TYPE_UPDATED, pbNote)
+	s.publishTags(req.UserId, pbNote)
+
+	return &pb.MergeNotesResponse{
+		Note: pbNote,
+	}, nil
+}
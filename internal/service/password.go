@@ -0,0 +1,108 @@
+package service
+
+import (
+	_ "embed"
+	"log/slog"
+	"strings"
+	"unicode"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultMinPasswordLength is the shortest password accepted
+	DefaultMinPasswordLength = 8
+	// DefaultMinPasswordClasses is how many of lowercase, uppercase, digits,
+	// and symbols a password must mix
+	DefaultMinPasswordClasses = 2
+	// MaxPasswordLength is bcrypt's input limit in bytes; longer passwords
+	// are rejected rather than silently truncated
+	MaxPasswordLength = 72
+)
+
+//go:embed common_passwords.txt
+var commonPasswordList string
+
+// commonPasswords holds the lowercased entries of common_passwords.txt
+var commonPasswords = parseCommonPasswords(commonPasswordList)
+
+func parseCommonPasswords(list string) map[string]bool {
+	passwords := make(map[string]bool)
+	for _, line := range strings.Split(list, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		passwords[strings.ToLower(line)] = true
+	}
+	return passwords
+}
+
+// passwordPolicy is the strength check applied to new passwords
+type passwordPolicy struct {
+	minLength  int
+	minClasses int
+}
+
+// passwordPolicyFromEnv reads PASSWORD_MIN_LENGTH and PASSWORD_MIN_CLASSES,
+// falling back to the defaults when unset or invalid
+func passwordPolicyFromEnv(log *slog.Logger) passwordPolicy {
+	policy := passwordPolicy{
+		minLength:  intFromEnv(log, "PASSWORD_MIN_LENGTH", DefaultMinPasswordLength),
+		minClasses: intFromEnv(log, "PASSWORD_MIN_CLASSES", DefaultMinPasswordClasses),
+	}
+	if policy.minLength > MaxPasswordLength {
+		log.Error("password minimum length exceeds bcrypt limit, using default", "env", "PASSWORD_MIN_LENGTH", "value", policy.minLength, "default", DefaultMinPasswordLength)
+		policy.minLength = DefaultMinPasswordLength
+	}
+	if policy.minClasses > 4 {
+		log.Error("password minimum classes exceeds 4, using 4", "env", "PASSWORD_MIN_CLASSES", "value", policy.minClasses)
+		policy.minClasses = 4
+	}
+	return policy
+}
+
+// validate returns an InvalidArgument error describing why password fails
+// the policy, or nil if it passes
+func (p passwordPolicy) validate(password string) error {
+	if len([]rune(password)) < p.minLength {
+		return status.Errorf(codes.InvalidArgument, "password must be at least %d characters", p.minLength)
+	}
+	if len(password) > MaxPasswordLength {
+		return status.Errorf(codes.InvalidArgument, "password must be at most %d bytes", MaxPasswordLength)
+	}
+	if countCharacterClasses(password) < p.minClasses {
+		return status.Errorf(codes.InvalidArgument, "password must mix at least %d of lowercase letters, uppercase letters, digits, and symbols", p.minClasses)
+	}
+	if commonPasswords[strings.ToLower(password)] {
+		return status.Error(codes.InvalidArgument, "password is too common")
+	}
+	return nil
+}
+
+// countCharacterClasses counts which of lowercase, uppercase, digits, and
+// everything else appear in s
+func countCharacterClasses(s string) int {
+	var lower, upper, digit, other bool
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+	}
+
+	count := 0
+	for _, present := range []bool{lower, upper, digit, other} {
+		if present {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"database/sql/driver"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	pb "github.com/icco/etu-backend/proto"
+	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPasswordPolicyValidate(t *testing.T) {
+	policy := passwordPolicy{minLength: DefaultMinPasswordLength, minClasses: DefaultMinPasswordClasses}
+
+	tests := []struct {
+		name     string
+		password string
+		wantErr  string
+	}{
+		{name: "letters and digits", password: "journal2024"},
+		{name: "mixed case", password: "BlueHorizon"},
+		{name: "passphrase with spaces", password: "correct horse battery"},
+		{name: "unicode letters", password: "Žluťoučký kůň"},
+		{name: "too short", password: "Ab1!", wantErr: "at least 8 characters"},
+		{name: "multibyte counted as characters", password: "ñññññññ", wantErr: "at least 8 characters"},
+		{name: "one class", password: "abcdefghij", wantErr: "mix at least 2"},
+		{name: "digits only", password: "9876543210", wantErr: "mix at least 2"},
+		{name: "common", password: "password1", wantErr: "too common"},
+		{name: "common ignores case", password: "PassWord123", wantErr: "too common"},
+		{name: "too long for bcrypt", password: strings.Repeat("aB1", 25), wantErr: "at most 72 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.validate(tt.password)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("validate(%q) = %v, want nil", tt.password, err)
+				}
+				return
+			}
+			if status.Code(err) != codes.InvalidArgument {
+				t.Fatalf("validate(%q) = %v, want InvalidArgument", tt.password, err)
+			}
+			if !strings.Contains(status.Convert(err).Message(), tt.wantErr) {
+				t.Errorf("validate(%q) = %q, want message containing %q", tt.password, status.Convert(err).Message(), tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyFromEnv(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	t.Setenv("PASSWORD_MIN_LENGTH", "12")
+	t.Setenv("PASSWORD_MIN_CLASSES", "3")
+	policy := passwordPolicyFromEnv(log)
+	if policy.minLength != 12 || policy.minClasses != 3 {
+		t.Errorf("passwordPolicyFromEnv() = %+v, want minLength 12, minClasses 3", policy)
+	}
+	if err := policy.validate("journal2024"); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("validate with stricter policy = %v, want InvalidArgument", err)
+	}
+
+	t.Setenv("PASSWORD_MIN_LENGTH", "500")
+	t.Setenv("PASSWORD_MIN_CLASSES", "9")
+	policy = passwordPolicyFromEnv(log)
+	if policy.minLength != DefaultMinPasswordLength || policy.minClasses != 4 {
+		t.Errorf("passwordPolicyFromEnv() = %+v, want defaults clamped", policy)
+	}
+}
+
+func TestRegister_RejectsWeakPassword(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	_, err := svc.Register(context.Background(), &pb.RegisterRequest{Email: "a@example.com", Password: "password"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Register with weak password = %v, want InvalidArgument", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
+
+// bcryptCostArg matches a bcrypt hash argument made with cost
+type bcryptCostArg int
+
+func (c bcryptCostArg) Match(v driver.Value) bool {
+	hash, ok := v.(string)
+	if !ok {
+		return false
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost == int(c)
+}
+
+func TestRegister_UsesBcryptCost(t *testing.T) {
+	t.Setenv("BCRYPT_COST", "5")
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	mock.ExpectQuery(`SELECT \* FROM "User" WHERE email = \$1`).
+		WithArgs("new@example.com", 1).
+		WillReturnRows(sqlmock.NewRows(userColumns))
+	mock.ExpectBegin()
+	mock.ExpectExec(`INSERT INTO "User"`).
+		WithArgs(
+			sqlmock.AnyArg(), "new@example.com", sqlmock.AnyArg(), sqlmock.AnyArg(), bcryptCostArg(5), "free",
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(),
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	_, err := svc.Register(context.Background(), &pb.RegisterRequest{Email: "new@example.com", Password: "Correct-Horse-7"})
+	if err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("password hash not made with BCRYPT_COST: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSetNotePinned_InvalidArguments(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name string
+		req  *pb.SetNotePinnedRequest
+	}{
+		{name: "missing user_id", req: &pb.SetNotePinnedRequest{Id: "note1", Pinned: true}},
+		{name: "missing id", req: &pb.SetNotePinnedRequest{UserId: "user1", Pinned: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.SetNotePinned(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSetNotePinned_NotFound(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "Note" SET "pinned"`).
+		WithArgs(true, "note1", "user1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.SetNotePinned(ctx, &pb.SetNotePinnedRequest{UserId: "user1", Id: "note1", Pinned: true})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestSetNotePinned(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "Note" SET "pinned"`).
+		WithArgs(true, "note1", "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId", "pinned"}).
+			AddRow("note1", "hello", now, now, "user1", true))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.SetNotePinned(ctx, &pb.SetNotePinnedRequest{UserId: "user1", Id: "note1", Pinned: true})
+	if err != nil {
+		t.Fatalf("SetNotePinned: %v", err)
+	}
+	if !resp.Note.Pinned {
+		t.Error("returned note is not pinned")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultFreeStorageQuota    = 1 << 30   // 1GB, override with STORAGE_QUOTA_FREE
+	DefaultPremiumStorageQuota = 100 << 30 // 100GB, override with STORAGE_QUOTA_PREMIUM
+)
+
+// uploadSize returns the combined size of the files in an upload request
+func uploadSize(images []*pb.ImageUpload, audios []*pb.AudioUpload) int64 {
+	var total int64
+	for _, img := range images {
+		total += int64(len(img.Data))
+	}
+	for _, aud := range audios {
+		total += int64(len(aud.Data))
+	}
+	return total
+}
+
+// reserveUploadQuota reserves sizeBytes of the user's storage quota before
+// anything is uploaded. The returned release func must be called once the
+// uploads have been saved (or abandoned); saved files then count through
+// their sizeBytes column instead of the reservation.
+func (s *NotesService) reserveUploadQuota(ctx context.Context, userID string, sizeBytes int64) (func(), error) {
+	if sizeBytes == 0 {
+		return func() {}, nil
+	}
+
+	user, err := s.db.GetUser(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get user: %v", err)
+	}
+	if user == nil {
+		return nil, status.Error(codes.NotFound, "user not found")
+	}
+
+	quota := s.freeStorageQuota
+	if user.IsPremium() {
+		quota = s.premiumStorageQuota
+	}
+
+	reservationID, err := s.db.ReserveStorage(ctx, userID, sizeBytes, quota)
+	if errors.Is(err, db.ErrStorageQuotaExceeded) {
+		return nil, status.Errorf(codes.ResourceExhausted, "upload of %d bytes would exceed storage quota of %d bytes", sizeBytes, quota)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reserve storage: %v", err)
+	}
+
+	return func() {
+		// Release even if the request was cancelled so quota isn't held until expiry
+		if err := s.db.ReleaseStorage(context.WithoutCancel(ctx), reservationID); err != nil {
+			s.log.Warn("failed to release storage reservation", "reservation_id", reservationID, "error", err)
+		}
+	}, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/storage"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateNote_StorageQuotaExceeded(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	// The quota check runs before any upload, so the client is never called
+	svc.storage = &storage.Client{}
+	svc.freeStorageQuota = 1024
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT \* FROM "User"`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(
+			"user1", "a@b.com", nil, nil, "hash",
+			"free", nil, now, nil, nil, nil, nil, now,
+			false, nil, 0, nil,
+		))
+	mock.ExpectBegin()
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock`).
+		WithArgs("user1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM "StorageReservation"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(1020, 1))
+	mock.ExpectQuery(`FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(0, 0))
+	mock.ExpectQuery(`FROM "StorageReservation"`).
+		WillReturnRows(sqlmock.NewRows([]string{"coalesce"}).AddRow(0))
+	mock.ExpectRollback()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.CreateNote(ctx, &pb.CreateNoteRequest{
+		UserId: "user1",
+		Images: []*pb.ImageUpload{{Data: pngHeader, MimeType: "image/png"}},
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("expected ResourceExhausted, got %v", err)
+	}
+
+	// No note should have been created
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
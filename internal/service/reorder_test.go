@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReorderImages_InvalidArguments(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name string
+		req  *pb.ReorderImagesRequest
+	}{
+		{name: "missing user_id", req: &pb.ReorderImagesRequest{NoteId: "note1"}},
+		{name: "missing note_id", req: &pb.ReorderImagesRequest{UserId: "user1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ReorderImages(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReorderImages_MismatchedIDs(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId", "externalId", "notionUuid", "lastSyncedToNotion"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("note1", "hello", now, now, "user1", nil, nil, nil))
+	mock.ExpectQuery(`SELECT "id" FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("img1").AddRow("img2"))
+	mock.ExpectRollback()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.ReorderImages(ctx, &pb.ReorderImagesRequest{UserId: "user1", NoteId: "note1", ImageIds: []string{"img2"}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestReorderImages(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	now := time.Now()
+	noteColumns := []string{"id", "content", "createdAt", "updatedAt", "userId", "externalId", "notionUuid", "lastSyncedToNotion"}
+	imageColumns := []string{"id", "noteId", "url", "gcsObjectName", "extractedText", "mimeType", "position", "createdAt"}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("note1", "hello", now, now, "user1", nil, nil, nil))
+	mock.ExpectQuery(`SELECT "id" FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("img1").AddRow("img2"))
+	mock.ExpectExec(`UPDATE "NoteImage" SET "position"`).
+		WithArgs(0, "img2").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE "NoteImage" SET "position"`).
+		WithArgs(1, "img1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	// Reload the note for the response
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows(noteColumns).AddRow("note1", "hello", now, now, "user1", nil, nil, nil))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage" (.+) ORDER BY position ASC, "createdAt" ASC`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows(imageColumns).
+			AddRow("img2", "note1", "https://example.com/img2", "notes/note1/img2", "", "image/png", 0, now).
+			AddRow("img1", "note1", "https://example.com/img1", "notes/note1/img1", "", "image/png", 1, now.Add(-time.Minute)))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ReorderImages(ctx, &pb.ReorderImagesRequest{UserId: "user1", NoteId: "note1", ImageIds: []string{"img2", "img1"}})
+	if err != nil {
+		t.Fatalf("ReorderImages: %v", err)
+	}
+
+	var got []string
+	for _, img := range resp.Note.Images {
+		got = append(got, img.Id)
+	}
+	if diff := cmp.Diff([]string{"img2", "img1"}, got); diff != "" {
+		t.Errorf("image order mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// newTestNotesService creates a sqlmock-backed NotesService.
+func newTestNotesService(t *testing.T) (*NotesService, sqlmock.Sqlmock, func()) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	database, err := db.NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	svc := NewNotesService(database, nil, nil, "")
+	cleanup := func() { _ = sqlDB.Close() }
+	return svc, mock, cleanup
+}
+
+func TestParseReprocessTasks(t *testing.T) {
+	tests := []struct {
+		name    string
+		tasks   []string
+		want    db.ReprocessOptions
+		wantErr bool
+	}{
+		{name: "ocr only", tasks: []string{"ocr"}, want: db.ReprocessOptions{ExtractedText: true}},
+		{name: "transcribe only", tasks: []string{"transcribe"}, want: db.ReprocessOptions{TranscribedText: true}},
+		{name: "tags only", tasks: []string{"tags"}, want: db.ReprocessOptions{Tags: true}},
+		{
+			name:  "all tasks, mixed case",
+			tasks: []string{"OCR", " transcribe ", "Tags"},
+			want:  db.ReprocessOptions{ExtractedText: true, TranscribedText: true, Tags: true},
+		},
+		{name: "unknown task", tasks: []string{"ocr", "bogus"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseReprocessTasks(tt.tasks)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseReprocessTasks: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("parseReprocessTasks mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// newFakeReprocessService returns a NotesService backed by a fakeNotesStore
+// holding user1's note1, which has one image with OCR text and one tag
+func newFakeReprocessService() (*NotesService, *fakeNotesStore) {
+	now := time.Now()
+	store := newFakeNotesStore(db.Note{
+		ID:        "note1",
+		Content:   "hello",
+		UserID:    "user1",
+		CreatedAt: now,
+		UpdatedAt: now,
+		Tags:      []db.Tag{{ID: "tag1", Name: "work", UserID: "user1", CreatedAt: now}},
+		Images: []db.NoteImage{{
+			ID: "img1", NoteID: "note1", URL: "https://example.com/img1", GCSObjectName: "notes/note1/img1",
+			ExtractedText: "OPEN 9-5", MimeType: "image/png", CreatedAt: now,
+		}},
+	})
+	return NewNotesService(store, nil, nil, ""), store
+}
+
+func TestReprocessNote_InvalidArguments(t *testing.T) {
+	svc, store := newFakeReprocessService()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name string
+		req  *pb.ReprocessNoteRequest
+	}{
+		{name: "missing user_id", req: &pb.ReprocessNoteRequest{Id: "note1", Tasks: []string{"ocr"}}},
+		{name: "missing id", req: &pb.ReprocessNoteRequest{UserId: "user1", Tasks: []string{"ocr"}}},
+		{name: "missing tasks", req: &pb.ReprocessNoteRequest{UserId: "user1", Id: "note1"}},
+		{name: "unknown task", req: &pb.ReprocessNoteRequest{UserId: "user1", Id: "note1", Tasks: []string{"summarize"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.ReprocessNote(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("expected InvalidArgument, got %v", err)
+			}
+		})
+	}
+	if len(store.reprocessed) != 0 {
+		t.Errorf("store reprocessed %v, want nothing", store.reprocessed)
+	}
+}
+
+func TestReprocessNote_OtherUser(t *testing.T) {
+	svc, store := newFakeReprocessService()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.ReprocessNote(ctx, &pb.ReprocessNoteRequest{UserId: "user2", Id: "note1", Tasks: []string{"ocr"}})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied, got %v", err)
+	}
+	if len(store.reprocessed) != 0 {
+		t.Errorf("store reprocessed %v, want nothing", store.reprocessed)
+	}
+}
+
+func TestReprocessNote_NotFound(t *testing.T) {
+	svc, _ := newFakeReprocessService()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.ReprocessNote(ctx, &pb.ReprocessNoteRequest{UserId: "user1", Id: "missing", Tasks: []string{"ocr"}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+}
+
+func TestReprocessNote_ClearsExtractedText(t *testing.T) {
+	svc, store := newFakeReprocessService()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ReprocessNote(ctx, &pb.ReprocessNoteRequest{UserId: "user1", Id: "note1", Tasks: []string{"ocr"}})
+	if err != nil {
+		t.Fatalf("ReprocessNote: %v", err)
+	}
+	if diff := cmp.Diff([]db.ReprocessOptions{{ExtractedText: true}}, store.reprocessed); diff != "" {
+		t.Errorf("reprocess options mismatch (-want +got):\n%s", diff)
+	}
+	if len(resp.Note.Images) != 1 || resp.Note.Images[0].ExtractedText != "" {
+		t.Errorf("unexpected images: %+v", resp.Note.Images)
+	}
+	if diff := cmp.Diff([]string{"work"}, resp.Note.Tags); diff != "" {
+		t.Errorf("tags should be untouched (-want +got):\n%s", diff)
+	}
+}
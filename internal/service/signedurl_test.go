@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/storage"
+	pb "github.com/icco/etu-backend/proto"
+)
+
+// fakeSigner returns predictable URLs that encode the requested expiry.
+type fakeSigner struct {
+	err   error
+	calls int
+}
+
+func (f *fakeSigner) GetSignedURLsWithExpiry(ctx context.Context, objectNames []string, expiry time.Duration) (map[string]string, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	urls := make(map[string]string, len(objectNames))
+	for _, name := range objectNames {
+		urls[name] = fmt.Sprintf("https://signed.example/%s?expires=%d", name, int(expiry.Seconds()))
+	}
+	return urls, nil
+}
+
+func expectGetNoteWithImage(mock sqlmock.Sqlmock, storedURL string) {
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId", "externalId", "notionUuid", "lastSyncedToNotion"}).
+			AddRow("note1", "hello", now, now, "user1", nil, nil, nil))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "extractedText", "mimeType", "createdAt"}).
+			AddRow("img1", "note1", storedURL, "notes/note1/img1", "", "image/png", now))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+}
+
+func TestGetNote_ResignsMediaURLs(t *testing.T) {
+	const staleURL = "https://storage.googleapis.com/bucket/notes/note1/img1?X-Goog-Expires=expired"
+
+	tests := []struct {
+		name          string
+		signer        urlSigner
+		expirySeconds int32
+		want          string
+	}{
+		{
+			name:   "default expiry",
+			signer: &fakeSigner{},
+			want:   fmt.Sprintf("https://signed.example/notes/note1/img1?expires=%d", int(storage.SignedURLDuration.Seconds())),
+		},
+		{
+			name:          "per-request expiry",
+			signer:        &fakeSigner{},
+			expirySeconds: 60,
+			want:          "https://signed.example/notes/note1/img1?expires=60",
+		},
+		{
+			name:          "per-request expiry is capped",
+			signer:        &fakeSigner{},
+			expirySeconds: 30 * 24 * 60 * 60,
+			want:          fmt.Sprintf("https://signed.example/notes/note1/img1?expires=%d", int(storage.SignedURLDuration.Seconds())),
+		},
+		{
+			name:   "signing failure falls back to stored URL",
+			signer: &fakeSigner{err: errors.New("no credentials")},
+			want:   staleURL,
+		},
+		{
+			name: "no storage configured",
+			want: staleURL,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestNotesService(t)
+			defer cleanup()
+			svc.signer = tt.signer
+
+			expectGetNoteWithImage(mock, staleURL)
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+			resp, err := svc.GetNote(ctx, &pb.GetNoteRequest{UserId: "user1", Id: "note1", UrlExpirySeconds: tt.expirySeconds})
+			if err != nil {
+				t.Fatalf("GetNote: %v", err)
+			}
+			if len(resp.Note.Images) != 1 {
+				t.Fatalf("expected 1 image, got %d", len(resp.Note.Images))
+			}
+			if got := resp.Note.Images[0].Url; got != tt.want {
+				t.Errorf("image URL = %q, want %q", got, tt.want)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled mock expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetNote_ImgixTakesPrecedence(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	svc.signer = &fakeSigner{}
+	svc.imgixDomain = "etu.imgix.net"
+
+	expectGetNoteWithImage(mock, "https://stale.example/img1")
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetNote(ctx, &pb.GetNoteRequest{UserId: "user1", Id: "note1"})
+	if err != nil {
+		t.Fatalf("GetNote: %v", err)
+	}
+	if got, want := resp.Note.Images[0].Url, "https://etu.imgix.net/notes/note1/img1"; got != want {
+		t.Errorf("image URL = %q, want %q", got, want)
+	}
+}
+
+func TestListNotes_SignsMediaURLsInOneBatch(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	signer := &fakeSigner{}
+	svc.signer = signer
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT count\(\*\) FROM "Note"`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId", "externalId", "notionUuid", "lastSyncedToNotion"}).
+			AddRow("note1", "one", now, now, "user1", nil, nil, nil).
+			AddRow("note2", "two", now, now, "user1", nil, nil, nil))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WillReturnRows(sqlmock.NewRows([]string{"noteId", "id", "name", "createdAt", "userId"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId", "url", "gcsObjectName", "extractedText", "mimeType", "createdAt"}).
+			AddRow("img1", "note1", "https://stale/1", "notes/note1/img1", "", "image/png", now).
+			AddRow("img2", "note2", "https://stale/2", "notes/note2/img2", "", "image/png", now))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.ListNotes(ctx, &pb.ListNotesRequest{UserId: "user1"})
+	if err != nil {
+		t.Fatalf("ListNotes: %v", err)
+	}
+	if signer.calls != 1 {
+		t.Errorf("signer calls = %d, want 1", signer.calls)
+	}
+	for _, n := range resp.Notes {
+		if len(n.Images) != 1 || !strings.HasPrefix(n.Images[0].Url, "https://signed.example/") {
+			t.Errorf("note %s images not re-signed: %+v", n.Id, n.Images)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
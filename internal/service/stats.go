@@ -44,3 +44,51 @@ func (s *StatsService) GetStats(ctx context.Context, req *pb.GetStatsRequest) (*
 		WordsWritten: wordsWritten,
 	}, nil
 }
+
+// GetStorageUsage retrieves media storage usage for a user
+func (s *StatsService) GetStorageUsage(ctx context.Context, req *pb.GetStorageUsageRequest) (*pb.GetStorageUsageResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	totalBytes, objectCount, err := s.db.GetStorageUsage(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get storage usage: %v", err)
+	}
+
+	return &pb.GetStorageUsageResponse{
+		TotalBytes:  totalBytes,
+		ObjectCount: objectCount,
+	}, nil
+}
+
+// GetMoodBreakdown counts a user's notes by AI-detected mood
+func (s *StatsService) GetMoodBreakdown(ctx context.Context, req *pb.GetMoodBreakdownRequest) (*pb.GetMoodBreakdownResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	moods, err := s.db.GetMoodBreakdown(ctx, req.UserId, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count moods: %v", err)
+	}
+
+	pbMoods := make([]*pb.MoodCount, len(moods))
+	for i, m := range moods {
+		pbMoods[i] = &pb.MoodCount{Mood: m.Mood, Count: m.Count}
+	}
+
+	return &pb.GetMoodBreakdownResponse{
+		Moods: pbMoods,
+	}, nil
+}
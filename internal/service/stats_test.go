@@ -4,6 +4,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/db"
 	pb "github.com/icco/etu-backend/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -89,6 +92,21 @@ func TestGetStats(t *testing.T) {
 			wantUniqueTags:   25,
 			wantWordsWritten: 50000,
 		},
+		{
+			name: "user with more than 1000 notes",
+			req: &pb.GetStatsRequest{
+				UserId: "user-456",
+			},
+			mockDB: &mockStatsDB{
+				totalBlips:   1200,
+				uniqueTags:   30,
+				wordsWritten: 144000,
+			},
+			wantErr:          codes.OK,
+			wantTotalBlips:   1200,
+			wantUniqueTags:   30,
+			wantWordsWritten: 144000,
+		},
 		{
 			name: "user with no notes",
 			req: &pb.GetStatsRequest{
@@ -155,3 +173,82 @@ func TestGetStats(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStorageUsage(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+	database, err := db.NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+	svc := NewStatsService(database)
+
+	mock.ExpectQuery(`FROM "NoteImage"`).
+		WithArgs("user1").
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(1500, 2))
+	mock.ExpectQuery(`FROM "NoteAudio"`).
+		WithArgs("user1").
+		WillReturnRows(sqlmock.NewRows([]string{"total_bytes", "object_count"}).AddRow(500, 1))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetStorageUsage(ctx, &pb.GetStorageUsageRequest{UserId: "user1"})
+	if err != nil {
+		t.Fatalf("GetStorageUsage: %v", err)
+	}
+	if resp.TotalBytes != 2000 || resp.ObjectCount != 3 {
+		t.Errorf("GetStorageUsage = (%d, %d), want (2000, 3)", resp.TotalBytes, resp.ObjectCount)
+	}
+
+	if _, err := svc.GetStorageUsage(ctx, &pb.GetStorageUsageRequest{UserId: "user2"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for another user, got %v", err)
+	}
+	if _, err := svc.GetStorageUsage(ctx, &pb.GetStorageUsageRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for missing user_id, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetMoodBreakdown(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+	database, err := db.NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+	svc := NewStatsService(database)
+
+	mock.ExpectQuery(`SELECT mood, COUNT\(\*\) as count FROM "Note"`).
+		WithArgs("user1", "").
+		WillReturnRows(sqlmock.NewRows([]string{"mood", "count"}).
+			AddRow("calm", 3).
+			AddRow("neutral", 2))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetMoodBreakdown(ctx, &pb.GetMoodBreakdownRequest{UserId: "user1"})
+	if err != nil {
+		t.Fatalf("GetMoodBreakdown: %v", err)
+	}
+	if len(resp.Moods) != 2 || resp.Moods[0].Mood != "calm" || resp.Moods[0].Count != 3 || resp.Moods[1].Mood != "neutral" {
+		t.Errorf("GetMoodBreakdown = %v, want calm:3, neutral:2", resp.Moods)
+	}
+
+	if _, err := svc.GetMoodBreakdown(ctx, &pb.GetMoodBreakdownRequest{UserId: "user2"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected PermissionDenied for another user, got %v", err)
+	}
+	if _, err := svc.GetMoodBreakdown(ctx, &pb.GetMoodBreakdownRequest{}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument for missing user_id, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/icco/etu-backend/internal/db"
+)
+
+// NotesStore is the data access NotesService needs. *db.DB implements it;
+// tests can substitute a fake to exercise handlers without a database.
+type NotesStore interface {
+	// Notes
+	ListNotes(ctx context.Context, userID string, filter db.NoteFilter, sort db.NoteSort, include db.NoteInclude, limit, offset int) ([]db.Note, int, error)
+	CountNotes(ctx context.Context, userID string, filter db.NoteFilter) (int, error)
+	ListNotesModifiedSince(ctx context.Context, userID string, since time.Time, afterID string, limit int) ([]db.Note, error)
+	GetRandomNotes(ctx context.Context, userID string, count int) ([]db.Note, error)
+	GetNote(ctx context.Context, userID, noteID string, include db.NoteInclude) (*db.Note, error)
+	GetNoteFromPrimary(ctx context.Context, userID, noteID string) (*db.Note, error)
+	FindNoteByIdempotencyKey(ctx context.Context, userID, key string) (*db.Note, error)
+	CreateNote(ctx context.Context, userID, content, color string, tagNames []string, idempotencyKey string, createdAt time.Time) (*db.Note, error)
+	UpdateNote(ctx context.Context, userID, noteID string, content, color *string, tagNames []string, updateTags bool) (*db.Note, error)
+	DeleteNote(ctx context.Context, userID, noteID string) (bool, error)
+	SetNotePinned(ctx context.Context, userID, noteID string, pinned bool) (bool, error)
+	ReprocessNote(ctx context.Context, userID, noteID string, opts db.ReprocessOptions) (bool, error)
+	FindDuplicateNotes(ctx context.Context, userID string) ([]db.DuplicateGroup, error)
+	MergeNotes(ctx context.Context, userID, targetID string, sourceIDs []string, maxContent int) (note *db.Note, orphaned []string, err error)
+	DuplicateNote(ctx context.Context, userID, noteID, newNoteID string, media []db.MediaCopy) (*db.Note, error)
+	ListTags(ctx context.Context, userID string) ([]db.Tag, error)
+
+	// Attachments
+	AddImageToNote(ctx context.Context, noteID string, image *db.NoteImage) error
+	AddAudioToNote(ctx context.Context, noteID string, audio *db.NoteAudio) error
+	GetImagesByNoteID(ctx context.Context, noteID string) ([]db.NoteImage, error)
+	GetAudiosByNoteID(ctx context.Context, noteID string) ([]db.NoteAudio, error)
+	ReorderNoteImages(ctx context.Context, userID, noteID string, orderedIDs []string) (bool, error)
+	UpdateImageCaption(ctx context.Context, userID, imageID, caption string) (*db.NoteImage, error)
+	UpdateImageExtractedText(ctx context.Context, imageID string, extractedText string) error
+	UpdateAudioTranscribedText(ctx context.Context, audioID string, transcribedText string) error
+
+	// Upload quota
+	GetUser(ctx context.Context, userID string) (*db.User, error)
+	ReserveStorage(ctx context.Context, userID string, sizeBytes, quotaBytes int64) (string, error)
+	ReleaseStorage(ctx context.Context, reservationID string) error
+}
+
+// TagsStore is the data access TagsService needs. *db.DB implements it.
+type TagsStore interface {
+	ListTags(ctx context.Context, userID string) ([]db.Tag, error)
+	GetTag(ctx context.Context, userID, tagID string) (*db.Tag, error)
+	GetTagCounts(ctx context.Context, userID, startDate, endDate string) ([]db.Tag, error)
+	GetRelatedTags(ctx context.Context, userID, tagName string, limit int) ([]db.Tag, error)
+}
+
+// TemplatesStore is the data access TemplatesService needs. *db.DB implements it.
+type TemplatesStore interface {
+	CreateTemplate(ctx context.Context, userID, name, content string, tags []string) (*db.NoteTemplate, error)
+	ListTemplates(ctx context.Context, userID string) ([]db.NoteTemplate, error)
+	GetTemplate(ctx context.Context, userID, templateID string) (*db.NoteTemplate, error)
+	UpdateTemplate(ctx context.Context, userID, templateID string, name, content *string, tags []string, updateTags bool) (*db.NoteTemplate, error)
+	DeleteTemplate(ctx context.Context, userID, templateID string) (bool, error)
+}
+
+var (
+	_ NotesStore     = (*db.DB)(nil)
+	_ TagsStore      = (*db.DB)(nil)
+	_ TemplatesStore = (*db.DB)(nil)
+	_ SyncStore      = (*db.DB)(nil)
+)
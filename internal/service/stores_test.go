@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/models"
+)
+
+// fakeNotesStore is an in-memory NotesStore for handler tests. Only the
+// methods the tests need are implemented; calling any other method panics on
+// the nil embedded interface, which flags unexpected data access.
+type fakeNotesStore struct {
+	NotesStore
+
+	notes       map[string]db.Note
+	reprocessed []db.ReprocessOptions
+	reserved    []int64 // sizes passed to ReserveStorage
+	released    int
+}
+
+func newFakeNotesStore(notes ...db.Note) *fakeNotesStore {
+	f := &fakeNotesStore{notes: make(map[string]db.Note)}
+	for _, n := range notes {
+		f.notes[n.ID] = n
+	}
+	return f
+}
+
+// note returns a copy of the user's note, so callers can't modify the store
+func (f *fakeNotesStore) note(userID, noteID string) (*db.Note, bool) {
+	n, ok := f.notes[noteID]
+	if !ok || n.UserID != userID {
+		return nil, false
+	}
+	n.Tags = slices.Clone(n.Tags)
+	n.Images = slices.Clone(n.Images)
+	n.Audios = slices.Clone(n.Audios)
+	return &n, true
+}
+
+func (f *fakeNotesStore) GetNote(ctx context.Context, userID, noteID string, include db.NoteInclude) (*db.Note, error) {
+	n, ok := f.note(userID, noteID)
+	if !ok {
+		return nil, nil
+	}
+	if !include.Tags {
+		n.Tags = nil
+	}
+	if !include.Images {
+		n.Images = nil
+	}
+	if !include.Audios {
+		n.Audios = nil
+	}
+	return n, nil
+}
+
+func (f *fakeNotesStore) GetNoteFromPrimary(ctx context.Context, userID, noteID string) (*db.Note, error) {
+	return f.GetNote(ctx, userID, noteID, db.DefaultNoteInclude)
+}
+
+func (f *fakeNotesStore) ReprocessNote(ctx context.Context, userID, noteID string, opts db.ReprocessOptions) (bool, error) {
+	n, ok := f.note(userID, noteID)
+	if !ok {
+		return false, nil
+	}
+	f.reprocessed = append(f.reprocessed, opts)
+	if opts.ExtractedText {
+		for i := range n.Images {
+			n.Images[i].ExtractedText = ""
+		}
+	}
+	if opts.TranscribedText {
+		for i := range n.Audios {
+			n.Audios[i].TranscribedText = ""
+		}
+	}
+	if opts.Tags {
+		n.Tags = nil
+	}
+	f.notes[noteID] = *n
+	return true, nil
+}
+
+func (f *fakeNotesStore) CreateNote(ctx context.Context, userID, content, color string, tagNames []string, idempotencyKey string, createdAt time.Time) (*db.Note, error) {
+	now := time.Now()
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	n := db.Note{
+		ID:        fmt.Sprintf("note%d", len(f.notes)+1),
+		Content:   content,
+		Color:     color,
+		UserID:    userID,
+		CreatedAt: createdAt,
+		UpdatedAt: now,
+	}
+	for _, name := range models.NormalizeTags(tagNames) {
+		n.Tags = append(n.Tags, db.Tag{ID: "tag-" + name, Name: name, UserID: userID, CreatedAt: now})
+	}
+	f.notes[n.ID] = n
+	return &n, nil
+}
+
+func (f *fakeNotesStore) GetAudiosByNoteID(ctx context.Context, noteID string) ([]db.NoteAudio, error) {
+	return slices.Clone(f.notes[noteID].Audios), nil
+}
+
+func (f *fakeNotesStore) DuplicateNote(ctx context.Context, userID, noteID, newNoteID string, media []db.MediaCopy) (*db.Note, error) {
+	n, ok := f.note(userID, noteID)
+	if !ok {
+		return nil, nil
+	}
+	copies := make(map[string]db.MediaCopy, len(media))
+	for _, m := range media {
+		copies[m.SourceID] = m
+	}
+
+	now := time.Now()
+	n.ID, n.Pinned, n.ExternalID, n.CreatedAt, n.UpdatedAt = newNoteID, false, nil, now, now
+	images, audios := n.Images, n.Audios
+	n.Images, n.Audios = nil, nil
+	for _, img := range images {
+		if c, ok := copies[img.ID]; ok {
+			img.ID, img.NoteID, img.URL, img.GCSObjectName, img.SizeBytes = c.ID, newNoteID, c.URL, c.GCSObjectName, c.SizeBytes
+			n.Images = append(n.Images, img)
+		}
+	}
+	for _, aud := range audios {
+		if c, ok := copies[aud.ID]; ok {
+			aud.ID, aud.NoteID, aud.URL, aud.GCSObjectName, aud.SizeBytes = c.ID, newNoteID, c.URL, c.GCSObjectName, c.SizeBytes
+			n.Audios = append(n.Audios, aud)
+		}
+	}
+	f.notes[newNoteID] = *n
+	return n, nil
+}
+
+func (f *fakeNotesStore) GetUser(ctx context.Context, userID string) (*db.User, error) {
+	return &db.User{ID: userID, SubscriptionStatus: "free"}, nil
+}
+
+func (f *fakeNotesStore) ReserveStorage(ctx context.Context, userID string, sizeBytes, quotaBytes int64) (string, error) {
+	f.reserved = append(f.reserved, sizeBytes)
+	return fmt.Sprintf("reservation%d", len(f.reserved)), nil
+}
+
+func (f *fakeNotesStore) ReleaseStorage(ctx context.Context, reservationID string) error {
+	f.released++
+	return nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGetSubscriptionStatus(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		status      string
+		end         any
+		wantPremium bool
+		wantActive  bool
+	}{
+		{name: "active", status: "active", end: now.Add(24 * time.Hour), wantPremium: true, wantActive: true},
+		{name: "expired", status: "active", end: now.Add(-time.Hour), wantPremium: true, wantActive: false},
+		{name: "free", status: "free", end: nil, wantPremium: false, wantActive: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestAuthService(t)
+			defer cleanup()
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+			mock.ExpectQuery(`SELECT \* FROM "User"`).
+				WithArgs("user1", 1).
+				WillReturnRows(sqlmock.NewRows(userColumns).AddRow(
+					"user1", "a@b.com", nil, nil, "hash",
+					tt.status, tt.end, now, nil, nil, nil, nil, now,
+					false, nil, 0, nil,
+				))
+
+			resp, err := svc.GetSubscriptionStatus(ctx, &pb.GetSubscriptionStatusRequest{UserId: "user1"})
+			if err != nil {
+				t.Fatalf("GetSubscriptionStatus: %v", err)
+			}
+			if resp.SubscriptionStatus != tt.status {
+				t.Errorf("subscription_status = %q, want %q", resp.SubscriptionStatus, tt.status)
+			}
+			if (resp.SubscriptionEnd != nil) != (tt.end != nil) {
+				t.Errorf("subscription_end = %v, want set = %v", resp.SubscriptionEnd, tt.end != nil)
+			}
+			if resp.IsPremium != tt.wantPremium || resp.IsActive != tt.wantActive {
+				t.Errorf("is_premium = %v, is_active = %v, want %v, %v", resp.IsPremium, resp.IsActive, tt.wantPremium, tt.wantActive)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestGetSubscriptionStatus_Validation(t *testing.T) {
+	svc, mock, cleanup := newTestAuthService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	_, err := svc.GetSubscriptionStatus(ctx, &pb.GetSubscriptionStatusRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("missing user_id: got %v, want InvalidArgument", err)
+	}
+
+	_, err = svc.GetSubscriptionStatus(ctx, &pb.GetSubscriptionStatusRequest{UserId: "user2"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("other user: got %v, want PermissionDenied", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unexpected queries: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/icco/etu-backend/internal/ai"
+	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/tagging"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SuggestTags asks Gemini for tags for a note or unsaved content and returns
+// them without writing anything, so the user can accept or reject each one.
+// Suggestions the user already uses elsewhere are listed first, as in the
+// taggen job.
+func (s *NotesService) SuggestTags(ctx context.Context, req *pb.SuggestTagsRequest) (*pb.SuggestTagsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if (req.Id == "") == (req.Content == "") {
+		return nil, status.Error(codes.InvalidArgument, "exactly one of id and content is required")
+	}
+	opts := ai.TagGenOptions{MaxTags: int(req.MaxTags)}
+	if err := opts.Validate(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid max_tags: %v", err)
+	}
+	if opts.MaxTags == 0 {
+		opts.MaxTags = ai.DefaultMaxTags
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if s.aiClient == nil {
+		return nil, status.Error(codes.FailedPrecondition, "tag suggestions are not configured on this server")
+	}
+
+	content := req.Content
+	var noteTagValues []string
+	if req.Id != "" {
+		note, err := s.db.GetNote(ctx, req.UserId, req.Id, db.NoteInclude{Tags: true})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to get note: %v", err)
+		}
+		if note == nil {
+			return nil, status.Error(codes.NotFound, "note not found")
+		}
+		content = note.Content
+		for _, tag := range note.Tags {
+			noteTagValues = append(noteTagValues, tag.Name)
+		}
+	}
+
+	userTags, err := s.db.ListTags(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list tags: %v", err)
+	}
+	userTagValues := make([]string, 0, len(userTags))
+	for _, tag := range userTags {
+		userTagValues = append(userTagValues, tag.Name)
+	}
+	existingTagNames, existingTagList := tagging.BuildExistingTagContext(userTagValues)
+
+	generated, err := s.aiClient.GenerateTags(ctx, content, existingTagList, opts)
+	if err != nil {
+		return nil, aiStatus(err, "failed to suggest tags")
+	}
+
+	noteTagNames := tagging.BuildExistingTagSet(noteTagValues)
+	tags := tagging.SelectGeneratedTags(generated, noteTagNames, existingTagNames, opts.MaxTags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	return &pb.SuggestTagsResponse{Tags: tags}, nil
+}
+
+// aiStatus converts an error from the ai package to a gRPC status, keeping
+// its category so clients can tell a retryable failure from a bad request
+func aiStatus(err error, msg string) error {
+	code := codes.Internal
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, ai.ErrRateLimited):
+		code = codes.ResourceExhausted
+	case errors.Is(err, ai.ErrInvalidInput):
+		code = codes.InvalidArgument
+	case errors.Is(err, ai.ErrTimeout):
+		code = codes.DeadlineExceeded
+	}
+	return status.Errorf(code, "%s: %v", msg, err)
+}
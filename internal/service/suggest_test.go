@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/ai"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeGenerator is an ai.Generator returning canned results. It records the
+// calls it receives.
+type fakeGenerator struct {
+	tags    []string
+	ocrText string
+	err     error
+
+	gotText     string
+	gotExisting []string
+	gotOpts     ai.TagGenOptions
+	ocrCalls    int
+}
+
+func (f *fakeGenerator) GenerateTags(ctx context.Context, text string, existingTags []string, opts ai.TagGenOptions) ([]string, error) {
+	f.gotText, f.gotExisting, f.gotOpts = text, existingTags, opts
+	return f.tags, f.err
+}
+
+func (f *fakeGenerator) ExtractTextFromImage(ctx context.Context, imageData []byte, mimeType string) (string, error) {
+	f.ocrCalls++
+	return f.ocrText, f.err
+}
+
+func (f *fakeGenerator) TranscribeAudio(ctx context.Context, audioData []byte, mimeType string) (string, error) {
+	return "", f.err
+}
+
+// expectUserTags expects ListTags for user1 to return names
+func expectUserTags(mock sqlmock.Sqlmock, names ...string) {
+	rows := sqlmock.NewRows([]string{"id", "name", "createdAt", "userId", "count"})
+	for _, name := range names {
+		rows.AddRow("tag-"+name, name, time.Now(), "user1", 1)
+	}
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag" LEFT JOIN "NoteTag"`).
+		WithArgs("user1").
+		WillReturnRows(rows)
+}
+
+func TestSuggestTags_ForNote(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	fake := &fakeGenerator{tags: []string{"Standup", "work", "meeting", "coffee"}}
+	svc.aiClient = fake
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM "Note"`).
+		WithArgs("note1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note1", "standup with the team", now, now, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId"}).
+			AddRow("tag-work", "work", now, "user1"))
+	// Only the note's content and tags are needed, so images aren't loaded
+	expectUserTags(mock, "meeting", "work")
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.SuggestTags(ctx, &pb.SuggestTagsRequest{UserId: "user1", Id: "note1", MaxTags: 3})
+	if err != nil {
+		t.Fatalf("SuggestTags: %v", err)
+	}
+
+	// "work" is already on the note; the user's existing "meeting" tag is
+	// preferred over new ones
+	if want := []string{"meeting", "standup", "coffee"}; !slices.Equal(resp.Tags, want) {
+		t.Errorf("Tags = %v, want %v", resp.Tags, want)
+	}
+	if fake.gotText != "standup with the team" || !slices.Equal(fake.gotExisting, []string{"meeting", "work"}) || fake.gotOpts.MaxTags != 3 {
+		t.Errorf("GenerateTags called with %q, %v, %+v", fake.gotText, fake.gotExisting, fake.gotOpts)
+	}
+
+	// Suggesting never writes: any insert would be an unexpected query
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestSuggestTags_ForContent(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+	fake := &fakeGenerator{tags: []string{"travel"}}
+	svc.aiClient = fake
+
+	expectUserTags(mock)
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.SuggestTags(ctx, &pb.SuggestTagsRequest{UserId: "user1", Content: "packing for lisbon"})
+	if err != nil {
+		t.Fatalf("SuggestTags: %v", err)
+	}
+	if !slices.Equal(resp.Tags, []string{"travel"}) {
+		t.Errorf("Tags = %v, want [travel]", resp.Tags)
+	}
+	if fake.gotText != "packing for lisbon" || fake.gotOpts.MaxTags != ai.DefaultMaxTags {
+		t.Errorf("GenerateTags called with %q, %+v", fake.gotText, fake.gotOpts)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestSuggestTags_Errors(t *testing.T) {
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name     string
+		ai       ai.Generator
+		req      *pb.SuggestTagsRequest
+		mockTags bool
+		wantCode codes.Code
+	}{
+		{name: "id and content", ai: &fakeGenerator{}, req: &pb.SuggestTagsRequest{UserId: "user1", Id: "note1", Content: "hi"}, wantCode: codes.InvalidArgument},
+		{name: "neither id nor content", ai: &fakeGenerator{}, req: &pb.SuggestTagsRequest{UserId: "user1"}, wantCode: codes.InvalidArgument},
+		{name: "max tags too high", ai: &fakeGenerator{}, req: &pb.SuggestTagsRequest{UserId: "user1", Content: "hi", MaxTags: 50}, wantCode: codes.InvalidArgument},
+		{name: "other user", ai: &fakeGenerator{}, req: &pb.SuggestTagsRequest{UserId: "user2", Content: "hi"}, wantCode: codes.PermissionDenied},
+		{name: "ai not configured", ai: nil, req: &pb.SuggestTagsRequest{UserId: "user1", Content: "hi"}, wantCode: codes.FailedPrecondition},
+		{name: "rate limited", ai: &fakeGenerator{err: fmt.Errorf("failed to generate tags: %w", ai.ErrRateLimited)}, req: &pb.SuggestTagsRequest{UserId: "user1", Content: "hi"}, mockTags: true, wantCode: codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestNotesService(t)
+			defer cleanup()
+			svc.aiClient = tt.ai
+			if tt.mockTags {
+				expectUserTags(mock)
+			}
+
+			_, err := svc.SuggestTags(ctx, tt.req)
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("got %v, want %v", err, tt.wantCode)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled mock expectations: %v", err)
+			}
+		})
+	}
+}
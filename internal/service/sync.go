@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SyncStore is the data access SyncService needs. *db.DB implements it.
+type SyncStore interface {
+	DeleteSyncStateForUser(ctx context.Context, userID string) error
+}
+
+// SyncService implements the SyncService gRPC service
+type SyncService struct {
+	pb.UnimplementedSyncServiceServer
+	db SyncStore
+}
+
+// NewSyncService creates a new SyncService
+func NewSyncService(database SyncStore) *SyncService {
+	return &SyncService{db: database}
+}
+
+// ResetSyncState deletes a user's sync state so the sync job fully resyncs
+// them on its next run, like running it with -full for just that user
+func (s *SyncService) ResetSyncState(ctx context.Context, req *pb.ResetSyncStateRequest) (*pb.ResetSyncStateResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.DeleteSyncStateForUser(ctx, req.UserId); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to reset sync state: %v", err)
+	}
+
+	return &pb.ResetSyncStateResponse{
+		Success: true,
+	}, nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeSyncStore records which users had their sync state reset
+type fakeSyncStore struct {
+	err   error
+	reset []string
+}
+
+func (f *fakeSyncStore) DeleteSyncStateForUser(ctx context.Context, userID string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.reset = append(f.reset, userID)
+	return nil
+}
+
+func TestResetSyncState(t *testing.T) {
+	tests := []struct {
+		name     string
+		authType string
+		userID   string
+		storeErr error
+		wantCode codes.Code
+	}{
+		{name: "own user", authType: "apikey", userID: "user1", wantCode: codes.OK},
+		{name: "m2m", authType: "m2m", userID: "user2", wantCode: codes.OK},
+		{name: "other user", authType: "apikey", userID: "user2", wantCode: codes.PermissionDenied},
+		{name: "missing user_id", authType: "apikey", userID: "", wantCode: codes.InvalidArgument},
+		{name: "db error", authType: "apikey", userID: "user1", storeErr: errors.New("db down"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeSyncStore{err: tt.storeErr}
+			svc := NewSyncService(store)
+			ctx := auth.SetAuthContext(context.Background(), "user1", tt.authType)
+
+			resp, err := svc.ResetSyncState(ctx, &pb.ResetSyncStateRequest{UserId: tt.userID})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("code = %v, want %v (err: %v)", got, tt.wantCode, err)
+			}
+			if tt.wantCode != codes.OK {
+				if len(store.reset) != 0 {
+					t.Errorf("reset = %v, want none", store.reset)
+				}
+				return
+			}
+			if !resp.GetSuccess() {
+				t.Error("success = false, want true")
+			}
+			if !slices.Equal(store.reset, []string{tt.userID}) {
+				t.Errorf("reset = %v, want [%s]", store.reset, tt.userID)
+			}
+		})
+	}
+}
@@ -4,21 +4,31 @@ import (
 	"context"
 
 	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/models"
 	pb "github.com/icco/etu-backend/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// GetRelatedTags limits
+const (
+	DefaultRelatedTagsLimit = 10
+	MaxRelatedTagsLimit     = 50
+)
+
 // TagsService implements the TagsService gRPC service
 type TagsService struct {
 	pb.UnimplementedTagsServiceServer
-	db *db.DB
+	db    TagsStore
+	notes *NotesService // Lists a tag's notes for GetTag; may be nil
 }
 
-// NewTagsService creates a new TagsService
-func NewTagsService(database *db.DB) *TagsService {
-	return &TagsService{db: database}
+// NewTagsService creates a new TagsService. notes is used to embed a tag's
+// notes in GetTag responses, with the same filtering and media URL handling
+// as ListNotes.
+func NewTagsService(database TagsStore, notes *NotesService) *TagsService {
+	return &TagsService{db: database, notes: notes}
 }
 
 // ListTags retrieves all tags for a user with usage counts
@@ -38,16 +48,123 @@ func (s *TagsService) ListTags(ctx context.Context, req *pb.ListTagsRequest) (*p
 	}
 
 	pbTags := make([]*pb.Tag, len(tags))
-	for i, t := range tags {
-		pbTags[i] = &pb.Tag{
-			Id:        t.ID,
-			Name:      t.Name,
-			Count:     int32(t.Count),
-			CreatedAt: timestamppb.New(t.CreatedAt),
-		}
+	for i := range tags {
+		pbTags[i] = tagToProto(&tags[i])
 	}
 
 	return &pb.ListTagsResponse{
 		Tags: pbTags,
 	}, nil
 }
+
+// GetTag retrieves a single tag with its usage count and first page of notes
+func (s *TagsService) GetTag(ctx context.Context, req *pb.GetTagRequest) (*pb.GetTagResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	tag, err := s.db.GetTag(ctx, req.UserId, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get tag: %v", err)
+	}
+	if tag == nil {
+		return nil, status.Error(codes.NotFound, "tag not found")
+	}
+
+	resp := &pb.GetTagResponse{Tag: tagToProto(tag)}
+	if s.notes != nil {
+		notes, err := s.notes.listNotes(ctx, &pb.ListNotesRequest{
+			UserId: req.UserId,
+			Tags:   []string{tag.Name},
+			Limit:  req.Limit,
+		}, false)
+		if err != nil {
+			return nil, err
+		}
+		resp.Notes = notes.Notes
+		resp.Total = notes.Total
+	}
+
+	return resp, nil
+}
+
+// GetTagCounts retrieves tag usage counts for notes created in a date range
+func (s *TagsService) GetTagCounts(ctx context.Context, req *pb.GetTagCountsRequest) (*pb.GetTagCountsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.db.GetTagCounts(ctx, req.UserId, req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to count tags: %v", err)
+	}
+
+	pbTags := make([]*pb.Tag, len(tags))
+	for i := range tags {
+		pbTags[i] = tagToProto(&tags[i])
+	}
+
+	return &pb.GetTagCountsResponse{
+		Tags: pbTags,
+	}, nil
+}
+
+// GetRelatedTags retrieves tags that co-occur with a tag, most shared first
+func (s *TagsService) GetRelatedTags(ctx context.Context, req *pb.GetRelatedTagsRequest) (*pb.GetRelatedTagsResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if _, ok := models.NormalizeTag(req.TagName); !ok {
+		return nil, status.Error(codes.InvalidArgument, "tag_name is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	limit := int(req.Limit)
+	if limit <= 0 {
+		limit = DefaultRelatedTagsLimit
+	}
+	if limit > MaxRelatedTagsLimit {
+		limit = MaxRelatedTagsLimit
+	}
+
+	tags, err := s.db.GetRelatedTags(ctx, req.UserId, req.TagName, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get related tags: %v", err)
+	}
+
+	pbTags := make([]*pb.Tag, len(tags))
+	for i := range tags {
+		pbTags[i] = tagToProto(&tags[i])
+	}
+
+	return &pb.GetRelatedTagsResponse{
+		Tags: pbTags,
+	}, nil
+}
+
+// tagToProto converts a db.Tag to a protobuf Tag
+func tagToProto(t *db.Tag) *pb.Tag {
+	return &pb.Tag{
+		Id:        t.ID,
+		Name:      t.Name,
+		Count:     int32(t.Count),
+		CreatedAt: timestamppb.New(t.CreatedAt),
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
 	"github.com/icco/etu-backend/internal/db"
 	pb "github.com/icco/etu-backend/proto"
 	"google.golang.org/grpc/codes"
@@ -125,3 +127,129 @@ func TestListTags(t *testing.T) {
 		})
 	}
 }
+
+// newTestTagsService returns a TagsService sharing a sqlmock database with
+// the NotesService it embeds notes from
+func newTestTagsService(t *testing.T) (*TagsService, sqlmock.Sqlmock, func()) {
+	t.Helper()
+	notes, mock, cleanup := newTestNotesService(t)
+	return NewTagsService(notes.db.(*db.DB), notes), mock, cleanup
+}
+
+func TestGetTag_NotFound(t *testing.T) {
+	svc, mock, cleanup := newTestTagsService(t)
+	defer cleanup()
+
+	// A tag owned by another user is indistinguishable from a missing one
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("tag1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, err := svc.GetTag(ctx, &pb.GetTagRequest{UserId: "user1", Id: "tag1"})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("expected NotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetTag_EmbedsNotes(t *testing.T) {
+	svc, mock, cleanup := newTestTagsService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("tag1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId", "count"}).
+			AddRow("tag1", "work", now, "user1", 1))
+
+	// ListNotes filtered to the tag's name
+	mock.ExpectQuery(`SELECT count\(.+\) FROM "Note" WHERE (.+)EXISTS`).
+		WithArgs("user1", "work").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE (.+)EXISTS`).
+		WithArgs("user1", "work", 5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note1", "standup notes", now, now, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"note_id", "id", "name", "createdAt", "userId"}).
+			AddRow("note1", "tag1", "work", now, "user1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).
+		WithArgs("note1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetTag(ctx, &pb.GetTagRequest{UserId: "user1", Id: "tag1", Limit: 5})
+	if err != nil {
+		t.Fatalf("GetTag: %v", err)
+	}
+	if resp.Tag.Name != "work" || resp.Tag.Count != 1 {
+		t.Errorf("Tag = %+v", resp.Tag)
+	}
+	if resp.Total != 1 || len(resp.Notes) != 1 || resp.Notes[0].Id != "note1" {
+		t.Errorf("Notes = %+v (total %d)", resp.Notes, resp.Total)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetTagCounts(t *testing.T) {
+	svc, mock, cleanup := newTestTagsService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag" (.+) "Note"."createdAt" >= \$2`).
+		WithArgs("user1", "2026-10-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId", "count"}).
+			AddRow("tag1", "work", now, "user1", 4))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetTagCounts(ctx, &pb.GetTagCountsRequest{UserId: "user1", StartDate: "2026-10-01"})
+	if err != nil {
+		t.Fatalf("GetTagCounts: %v", err)
+	}
+	if len(resp.Tags) != 1 || resp.Tags[0].Name != "work" || resp.Tags[0].Count != 4 {
+		t.Errorf("Tags = %+v", resp.Tags)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetRelatedTags(t *testing.T) {
+	svc, mock, cleanup := newTestTagsService(t)
+	defer cleanup()
+
+	now := time.Now()
+	mock.ExpectQuery(`SELECT (.+) FROM "Tag" (.+)source_tag(.+) LIMIT \$4`).
+		WithArgs("user1", "user1", "work", DefaultRelatedTagsLimit).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "createdAt", "userId", "count"}).
+			AddRow("tag2", "meeting", now, "user1", 3))
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	resp, err := svc.GetRelatedTags(ctx, &pb.GetRelatedTagsRequest{UserId: "user1", TagName: "work"})
+	if err != nil {
+		t.Fatalf("GetRelatedTags: %v", err)
+	}
+	if len(resp.Tags) != 1 || resp.Tags[0].Name != "meeting" || resp.Tags[0].Count != 3 {
+		t.Errorf("Tags = %+v", resp.Tags)
+	}
+
+	if _, err := svc.GetRelatedTags(ctx, &pb.GetRelatedTagsRequest{UserId: "user1", TagName: " # "}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("blank tag_name: expected InvalidArgument, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
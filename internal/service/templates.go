@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// MaxTemplateNameLength is the longest template name, in characters
+const MaxTemplateNameLength = 100
+
+// TemplatesService implements the TemplatesService gRPC service
+type TemplatesService struct {
+	pb.UnimplementedTemplatesServiceServer
+	db    TemplatesStore
+	notes *NotesService
+}
+
+// NewTemplatesService creates a new TemplatesService. Notes created from
+// templates go through notes, so they are validated and streamed to watchers
+// like any other new note.
+func NewTemplatesService(database TemplatesStore, notes *NotesService) *TemplatesService {
+	return &TemplatesService{
+		db:    database,
+		notes: notes,
+	}
+}
+
+// CreateTemplate saves a new note template
+func (s *TemplatesService) CreateTemplate(ctx context.Context, req *pb.CreateTemplateRequest) (*pb.CreateTemplateResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	name, err := validateTemplateName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	content, err := s.templateContent(req.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	template, err := s.db.CreateTemplate(ctx, req.UserId, name, content, req.Tags)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create template: %v", err)
+	}
+
+	return &pb.CreateTemplateResponse{
+		Template: templateToProto(template),
+	}, nil
+}
+
+// ListTemplates lists a user's templates by name
+func (s *TemplatesService) ListTemplates(ctx context.Context, req *pb.ListTemplatesRequest) (*pb.ListTemplatesResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	templates, err := s.db.ListTemplates(ctx, req.UserId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list templates: %v", err)
+	}
+
+	pbTemplates := make([]*pb.NoteTemplate, len(templates))
+	for i := range templates {
+		pbTemplates[i] = templateToProto(&templates[i])
+	}
+
+	return &pb.ListTemplatesResponse{
+		Templates: pbTemplates,
+	}, nil
+}
+
+// GetTemplate retrieves a single template
+func (s *TemplatesService) GetTemplate(ctx context.Context, req *pb.GetTemplateRequest) (*pb.GetTemplateResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	template, err := s.db.GetTemplate(ctx, req.UserId, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get template: %v", err)
+	}
+	if template == nil {
+		return nil, status.Error(codes.NotFound, "template not found")
+	}
+
+	return &pb.GetTemplateResponse{
+		Template: templateToProto(template),
+	}, nil
+}
+
+// UpdateTemplate changes a template's name, content, or tags
+func (s *TemplatesService) UpdateTemplate(ctx context.Context, req *pb.UpdateTemplateRequest) (*pb.UpdateTemplateResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	var name, content *string
+	if req.Name != nil {
+		validated, err := validateTemplateName(*req.Name)
+		if err != nil {
+			return nil, err
+		}
+		name = &validated
+	}
+	if req.Content != nil {
+		normalized, err := s.templateContent(*req.Content)
+		if err != nil {
+			return nil, err
+		}
+		content = &normalized
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	template, err := s.db.UpdateTemplate(ctx, req.UserId, req.Id, name, content, req.Tags, req.UpdateTags)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update template: %v", err)
+	}
+	if template == nil {
+		return nil, status.Error(codes.NotFound, "template not found")
+	}
+
+	return &pb.UpdateTemplateResponse{
+		Template: templateToProto(template),
+	}, nil
+}
+
+// DeleteTemplate deletes a template. Notes created from it are unaffected.
+func (s *TemplatesService) DeleteTemplate(ctx context.Context, req *pb.DeleteTemplateRequest) (*pb.DeleteTemplateResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.Id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.db.DeleteTemplate(ctx, req.UserId, req.Id)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete template: %v", err)
+	}
+
+	return &pb.DeleteTemplateResponse{
+		Success: deleted,
+	}, nil
+}
+
+// CreateNoteFromTemplate creates a note with a template's content and tags
+func (s *TemplatesService) CreateNoteFromTemplate(ctx context.Context, req *pb.CreateNoteFromTemplateRequest) (*pb.CreateNoteFromTemplateResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.TemplateId == "" {
+		return nil, status.Error(codes.InvalidArgument, "template_id is required")
+	}
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return nil, err
+	}
+
+	template, err := s.db.GetTemplate(ctx, req.UserId, req.TemplateId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get template: %v", err)
+	}
+	if template == nil {
+		return nil, status.Error(codes.NotFound, "template not found")
+	}
+
+	resp, err := s.notes.CreateNote(ctx, &pb.CreateNoteRequest{
+		UserId:  req.UserId,
+		Content: template.Content,
+		Tags:    template.Tags,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.CreateNoteFromTemplateResponse{
+		Note: resp.Note,
+	}, nil
+}
+
+// validateTemplateName trims a template name and checks it is set and not too long
+func validateTemplateName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", status.Error(codes.InvalidArgument, "name is required")
+	}
+	if utf8.RuneCountInString(name) > MaxTemplateNameLength {
+		return "", status.Errorf(codes.InvalidArgument, "name must be at most %d characters", MaxTemplateNameLength)
+	}
+	return name, nil
+}
+
+// templateContent normalizes template content like note content, since notes
+// are created from it, and requires it to be set
+func (s *TemplatesService) templateContent(content string) (string, error) {
+	content, err := s.notes.normalizeContent(content)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(content) == "" {
+		return "", status.Error(codes.InvalidArgument, "content is required")
+	}
+	return content, nil
+}
+
+// templateToProto converts a template to its protobuf message
+func templateToProto(t *db.NoteTemplate) *pb.NoteTemplate {
+	return &pb.NoteTemplate{
+		Id:        t.ID,
+		Name:      t.Name,
+		Content:   t.Content,
+		Tags:      t.Tags,
+		CreatedAt: timestamppb.New(t.CreatedAt),
+		UpdatedAt: timestamppb.New(t.UpdatedAt),
+	}
+}
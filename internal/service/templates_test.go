@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/icco/etu-backend/internal/auth"
+	"github.com/icco/etu-backend/internal/db"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTemplatesStore is an in-memory TemplatesStore for handler tests
+type fakeTemplatesStore struct {
+	templates map[string]db.NoteTemplate
+}
+
+func (f *fakeTemplatesStore) CreateTemplate(ctx context.Context, userID, name, content string, tags []string) (*db.NoteTemplate, error) {
+	t := db.NoteTemplate{ID: fmt.Sprintf("tmpl%d", len(f.templates)+1), UserID: userID, Name: name, Content: content, Tags: tags}
+	f.templates[t.ID] = t
+	return &t, nil
+}
+
+func (f *fakeTemplatesStore) ListTemplates(ctx context.Context, userID string) ([]db.NoteTemplate, error) {
+	var out []db.NoteTemplate
+	for _, t := range f.templates {
+		if t.UserID == userID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeTemplatesStore) GetTemplate(ctx context.Context, userID, templateID string) (*db.NoteTemplate, error) {
+	t, ok := f.templates[templateID]
+	if !ok || t.UserID != userID {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+func (f *fakeTemplatesStore) UpdateTemplate(ctx context.Context, userID, templateID string, name, content *string, tags []string, updateTags bool) (*db.NoteTemplate, error) {
+	t, err := f.GetTemplate(ctx, userID, templateID)
+	if t == nil || err != nil {
+		return nil, err
+	}
+	if name != nil {
+		t.Name = *name
+	}
+	if content != nil {
+		t.Content = *content
+	}
+	if updateTags {
+		t.Tags = tags
+	}
+	f.templates[t.ID] = *t
+	return t, nil
+}
+
+func (f *fakeTemplatesStore) DeleteTemplate(ctx context.Context, userID, templateID string) (bool, error) {
+	if t, _ := f.GetTemplate(ctx, userID, templateID); t == nil {
+		return false, nil
+	}
+	delete(f.templates, templateID)
+	return true, nil
+}
+
+func newFakeTemplatesService() (*TemplatesService, *fakeNotesStore) {
+	notes := newFakeNotesStore()
+	templates := &fakeTemplatesStore{templates: map[string]db.NoteTemplate{
+		"tmpl0": {ID: "tmpl0", UserID: "user1", Name: "Standup", Content: "## Yesterday\n\n## Today", Tags: []string{"work", "standup"}},
+		"other": {ID: "other", UserID: "user2", Name: "Private", Content: "secret"},
+	}}
+	return NewTemplatesService(templates, NewNotesService(notes, nil, nil, "")), notes
+}
+
+func TestCreateNoteFromTemplate(t *testing.T) {
+	svc, notes := newFakeTemplatesService()
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	resp, err := svc.CreateNoteFromTemplate(ctx, &pb.CreateNoteFromTemplateRequest{UserId: "user1", TemplateId: "tmpl0"})
+	if err != nil {
+		t.Fatalf("CreateNoteFromTemplate: %v", err)
+	}
+	if resp.Note.Content != "## Yesterday\n\n## Today" {
+		t.Errorf("content = %q, want the template content", resp.Note.Content)
+	}
+	if !slices.Equal(resp.Note.Tags, []string{"work", "standup"}) {
+		t.Errorf("tags = %v, want the template tags", resp.Note.Tags)
+	}
+	if len(notes.notes) != 1 {
+		t.Errorf("store has %d notes, want 1", len(notes.notes))
+	}
+}
+
+func TestCreateNoteFromTemplate_OtherUsersTemplate(t *testing.T) {
+	svc, notes := newFakeTemplatesService()
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	_, err := svc.CreateNoteFromTemplate(ctx, &pb.CreateNoteFromTemplateRequest{UserId: "user1", TemplateId: "other"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("got %v, want NotFound", err)
+	}
+	if len(notes.notes) != 0 {
+		t.Errorf("store has %d notes, want none", len(notes.notes))
+	}
+}
+
+func TestTemplatesService_Authorization(t *testing.T) {
+	svc, _ := newFakeTemplatesService()
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	_, err := svc.GetTemplate(ctx, &pb.GetTemplateRequest{UserId: "user2", Id: "other"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("got %v, want PermissionDenied", err)
+	}
+}
+
+func TestCreateTemplate_Validation(t *testing.T) {
+	svc, _ := newFakeTemplatesService()
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	tests := []struct {
+		name string
+		req  *pb.CreateTemplateRequest
+	}{
+		{name: "missing name", req: &pb.CreateTemplateRequest{UserId: "user1", Name: "  ", Content: "x"}},
+		{name: "long name", req: &pb.CreateTemplateRequest{UserId: "user1", Name: strings.Repeat("a", MaxTemplateNameLength+1), Content: "x"}},
+		{name: "missing content", req: &pb.CreateTemplateRequest{UserId: "user1", Name: "Empty", Content: " \n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateTemplate(ctx, tt.req)
+			if status.Code(err) != codes.InvalidArgument {
+				t.Errorf("got %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
+
+func TestUpdateTemplate(t *testing.T) {
+	svc, _ := newFakeTemplatesService()
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+
+	name := " Daily standup "
+	resp, err := svc.UpdateTemplate(ctx, &pb.UpdateTemplateRequest{UserId: "user1", Id: "tmpl0", Name: &name})
+	if err != nil {
+		t.Fatalf("UpdateTemplate: %v", err)
+	}
+	if resp.Template.Name != "Daily standup" {
+		t.Errorf("name = %q, want trimmed name", resp.Template.Name)
+	}
+	if !slices.Equal(resp.Template.Tags, []string{"work", "standup"}) {
+		t.Errorf("tags = %v, want unchanged tags", resp.Template.Tags)
+	}
+
+	_, err = svc.UpdateTemplate(ctx, &pb.UpdateTemplateRequest{UserId: "user1", Id: "other", Name: &name})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("other user's template: got %v, want NotFound", err)
+	}
+}
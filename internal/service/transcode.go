@@ -0,0 +1,97 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	// TranscodeTimeout bounds a single image conversion
+	TranscodeTimeout = 30 * time.Second
+
+	// transcodeQuality is the JPEG quality passed to the converter
+	transcodeQuality = 90
+)
+
+// imageConverter converts images that browsers can't display into JPEG.
+type imageConverter interface {
+	ToJPEG(ctx context.Context, data []byte) ([]byte, error)
+}
+
+// heifConverter converts HEIC/HEIF images with libheif's heif-convert tool.
+type heifConverter struct {
+	path string
+}
+
+// newHEIFConverterFromEnv finds heif-convert, or the binary named by
+// HEIF_CONVERT_PATH. It returns nil when the tool isn't installed, in which
+// case HEIC uploads are stored as is.
+func newHEIFConverterFromEnv(log *slog.Logger) imageConverter {
+	name := os.Getenv("HEIF_CONVERT_PATH")
+	if name == "" {
+		name = "heif-convert"
+	}
+	path, err := exec.LookPath(name)
+	if err != nil {
+		log.Warn("heif-convert not found, HEIC images will be stored without conversion", "path", name, "error", err)
+		return nil
+	}
+	return &heifConverter{path: path}
+}
+
+// ToJPEG writes data to a temporary file, runs heif-convert on it and
+// returns the resulting JPEG.
+func (h *heifConverter) ToJPEG(ctx context.Context, data []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "etu-heif-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	in := filepath.Join(dir, "in.heic")
+	out := filepath.Join(dir, "out.jpg")
+	if err := os.WriteFile(in, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write image: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, TranscodeTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, h.path, "-q", strconv.Itoa(transcodeQuality), in, out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("heif-convert failed: %w: %s", err, bytes.TrimSpace(output))
+	}
+
+	jpeg, err := os.ReadFile(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read converted image: %w", err)
+	}
+	if sniffed := http.DetectContentType(jpeg); sniffed != "image/jpeg" {
+		return nil, fmt.Errorf("converter produced %s, want image/jpeg", sniffed)
+	}
+	return jpeg, nil
+}
+
+// transcodeImage converts HEIC/HEIF images to JPEG so browsers and imgix can
+// display them, returning the bytes and MIME type to store. Any other image,
+// or a failed conversion, is returned unchanged.
+func (s *NotesService) transcodeImage(ctx context.Context, imageData []byte, mimeType string) ([]byte, string) {
+	if s.converter == nil || canonicalMimeType(mimeType) != "image/heic" {
+		return imageData, mimeType
+	}
+
+	converted, err := s.converter.ToJPEG(ctx, imageData)
+	if err != nil {
+		s.log.Warn("failed to convert image to JPEG, storing original", "mime_type", mimeType, "size", len(imageData), "error", err)
+		return imageData, mimeType
+	}
+	return converted, "image/jpeg"
+}
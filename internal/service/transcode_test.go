@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeConverter returns out or err for every conversion and counts calls
+type fakeConverter struct {
+	out   []byte
+	err   error
+	calls int
+}
+
+func (f *fakeConverter) ToJPEG(ctx context.Context, data []byte) ([]byte, error) {
+	f.calls++
+	return f.out, f.err
+}
+
+func TestTranscodeImage(t *testing.T) {
+	tests := []struct {
+		name      string
+		mimeType  string
+		converter *fakeConverter
+		wantData  []byte
+		wantMime  string
+		wantCalls int
+	}{
+		{
+			name:      "heic converted",
+			mimeType:  "image/heic",
+			converter: &fakeConverter{out: jpegHeader},
+			wantData:  jpegHeader,
+			wantMime:  "image/jpeg",
+			wantCalls: 1,
+		},
+		{
+			name:      "heif converted",
+			mimeType:  "image/heif",
+			converter: &fakeConverter{out: jpegHeader},
+			wantData:  jpegHeader,
+			wantMime:  "image/jpeg",
+			wantCalls: 1,
+		},
+		{
+			name:      "conversion fails",
+			mimeType:  "image/heic",
+			converter: &fakeConverter{err: errors.New("corrupt image")},
+			wantData:  heicHeader,
+			wantMime:  "image/heic",
+			wantCalls: 1,
+		},
+		{
+			name:      "png untouched",
+			mimeType:  "image/png",
+			converter: &fakeConverter{out: jpegHeader},
+			wantData:  heicHeader,
+			wantMime:  "image/png",
+			wantCalls: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &NotesService{log: slog.New(slog.DiscardHandler), converter: tt.converter}
+
+			data, mimeType := svc.transcodeImage(context.Background(), heicHeader, tt.mimeType)
+			if !bytes.Equal(data, tt.wantData) || mimeType != tt.wantMime {
+				t.Errorf("transcodeImage = (%q, %q), want (%q, %q)", data, mimeType, tt.wantData, tt.wantMime)
+			}
+			if tt.converter.calls != tt.wantCalls {
+				t.Errorf("converter called %d times, want %d", tt.converter.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTranscodeImage_NoConverter(t *testing.T) {
+	svc := &NotesService{log: slog.New(slog.DiscardHandler)}
+
+	data, mimeType := svc.transcodeImage(context.Background(), heicHeader, "image/heic")
+	if !bytes.Equal(data, heicHeader) || mimeType != "image/heic" {
+		t.Errorf("transcodeImage = (%q, %q), want the original HEIC", data, mimeType)
+	}
+}
+
+// writeScript writes an executable shell script standing in for heif-convert
+func writeScript(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "heif-convert")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("failed to write script: %v", err)
+	}
+	return path
+}
+
+func TestHEIFConverter(t *testing.T) {
+	ctx := context.Background()
+
+	// Arguments are: -q <quality> <input> <output>
+	copying := &heifConverter{path: writeScript(t, `cp "$3" "$4"`)}
+	out, err := copying.ToJPEG(ctx, jpegHeader)
+	if err != nil {
+		t.Fatalf("ToJPEG: %v", err)
+	}
+	if !bytes.Equal(out, jpegHeader) {
+		t.Errorf("ToJPEG = %q, want the converter's output", out)
+	}
+
+	if _, err := copying.ToJPEG(ctx, heicHeader); err == nil {
+		t.Error("ToJPEG accepted output that isn't a JPEG")
+	}
+
+	failing := &heifConverter{path: writeScript(t, `echo "unsupported file" >&2; exit 1`)}
+	if _, err := failing.ToJPEG(ctx, heicHeader); err == nil {
+		t.Error("ToJPEG succeeded although the converter failed")
+	}
+}
+
+func TestNewHEIFConverterFromEnv(t *testing.T) {
+	log := slog.New(slog.DiscardHandler)
+
+	t.Setenv("HEIF_CONVERT_PATH", filepath.Join(t.TempDir(), "missing"))
+	if c := newHEIFConverterFromEnv(log); c != nil {
+		t.Errorf("newHEIFConverterFromEnv = %v, want nil for a missing binary", c)
+	}
+
+	path := writeScript(t, "exit 0")
+	t.Setenv("HEIF_CONVERT_PATH", path)
+	c, ok := newHEIFConverterFromEnv(log).(*heifConverter)
+	if !ok || c.path != path {
+		t.Errorf("newHEIFConverterFromEnv = %v, want converter at %s", c, path)
+	}
+}
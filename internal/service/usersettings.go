@@ -16,20 +16,29 @@ import (
 // UserSettingsService implements the UserSettings gRPC service
 type UserSettingsService struct {
 	pb.UnimplementedUserSettingsServiceServer
-	db          *db.DB
-	storage     *storage.Client
-	imgixDomain string
-	log         *slog.Logger
+	db           *db.DB
+	storage      storage.Blobstore
+	imgixDomain  string
+	maxImageSize int
+	password     passwordPolicy
+	log          *slog.Logger
 }
 
 // NewUserSettingsService creates a new UserSettingsService
 func NewUserSettingsService(database *db.DB, storageClient *storage.Client, imgixDomain string) *UserSettingsService {
-	return &UserSettingsService{
-		db:          database,
-		storage:     storageClient,
-		imgixDomain: imgixDomain,
-		log:         slog.Default().With("service", "user_settings"),
+	log := slog.Default().With("service", "user_settings")
+	s := &UserSettingsService{
+		db:           database,
+		imgixDomain:  imgixDomain,
+		maxImageSize: sizeLimitFromEnv(log, "MAX_IMAGE_SIZE", MaxImageSize),
+		password:     passwordPolicyFromEnv(log),
+		log:          log,
 	}
+	// Avoid a typed-nil interface when storage is not configured
+	if storageClient != nil {
+		s.storage = storageClient
+	}
+	return s
 }
 
 // GetUserSettings retrieves user settings
@@ -71,6 +80,22 @@ func (s *UserSettingsService) UpdateUserSettings(ctx context.Context, req *pb.Up
 		return nil, err
 	}
 
+	var syncIntervalMinutes *int
+	if req.SyncIntervalMinutes != nil {
+		if *req.SyncIntervalMinutes < 0 {
+			return nil, status.Error(codes.InvalidArgument, "sync_interval_minutes must not be negative")
+		}
+		minutes := int(*req.SyncIntervalMinutes)
+		syncIntervalMinutes = &minutes
+	}
+
+	// A new password must meet the same policy as at registration
+	if req.Password != nil && *req.Password != "" {
+		if err := s.password.validate(*req.Password); err != nil {
+			return nil, err
+		}
+	}
+
 	var image *string
 	var profileImageGCSObject *string
 
@@ -80,7 +105,7 @@ func (s *UserSettingsService) UpdateUserSettings(ctx context.Context, req *pb.Up
 			return nil, status.Error(codes.FailedPrecondition, "storage client not configured")
 		}
 
-		if err := validateImage(req.ProfileImageUpload.Data, req.ProfileImageUpload.MimeType); err != nil {
+		if err := validateImage(req.ProfileImageUpload.Data, req.ProfileImageUpload.MimeType, s.maxImageSize); err != nil {
 			return nil, status.Errorf(codes.InvalidArgument, "invalid profile image: %v", err)
 		}
 
@@ -99,7 +124,7 @@ func (s *UserSettingsService) UpdateUserSettings(ctx context.Context, req *pb.Up
 		profileImageGCSObject = &empty
 	}
 
-	user, err := s.db.UpdateUserSettings(ctx, req.UserId, req.NotionKey, req.Name, image, req.Password, req.NotionDatabaseName, profileImageGCSObject)
+	user, err := s.db.UpdateUserSettings(ctx, req.UserId, req.NotionKey, req.Name, image, req.Password, req.NotionDatabaseName, profileImageGCSObject, syncIntervalMinutes)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to update user settings: %v", err)
 	}
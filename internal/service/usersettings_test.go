@@ -8,6 +8,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/icco/etu-backend/internal/auth"
 	"github.com/icco/etu-backend/internal/db"
+	"github.com/icco/etu-backend/internal/storage"
 	pb "github.com/icco/etu-backend/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -73,6 +74,34 @@ func TestGetUserSettings_Basic(t *testing.T) {
 	}
 }
 
+func TestGetUserSettings_ExpiredSubscriptionFlags(t *testing.T) {
+	svc, mock, cleanup := newTestUserSettingsService(t, "")
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "m2m")
+	now := time.Now()
+
+	mock.ExpectQuery(`SELECT \* FROM "User"`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows(userColumns).AddRow(
+			"user1", "a@b.com", "Alice", nil, "hash",
+			"active", now.Add(-time.Hour), now, nil, nil, nil, nil, now,
+			false, nil, 0, nil,
+		))
+
+	resp, err := svc.GetUserSettings(ctx, &pb.GetUserSettingsRequest{UserId: "user1"})
+	if err != nil {
+		t.Fatalf("GetUserSettings: %v", err)
+	}
+	if !resp.User.IsPremium || resp.User.IsActive {
+		t.Errorf("is_premium = %v, is_active = %v, want premium but not active", resp.User.IsPremium, resp.User.IsActive)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
 func TestGetUserSettings_MissingUserID(t *testing.T) {
 	svc, _, cleanup := newTestUserSettingsService(t, "")
 	defer cleanup()
@@ -204,6 +233,82 @@ func TestUpdateUserSettings_ProfileImageUpload_NilStorage(t *testing.T) {
 	}
 }
 
+func TestUpdateUserSettings_ProfileImageUpload_TooLarge(t *testing.T) {
+	t.Setenv("MAX_IMAGE_SIZE", "8")
+	svc, _, cleanup := newTestUserSettingsService(t, "")
+	defer cleanup()
+	store := storage.NewMemory()
+	svc.storage = store
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "m2m")
+
+	_, err := svc.UpdateUserSettings(ctx, &pb.UpdateUserSettingsRequest{
+		UserId: "user1",
+		ProfileImageUpload: &pb.ImageUpload{
+			Data:     []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR"),
+			MimeType: "image/png",
+		},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for an image over MAX_IMAGE_SIZE, got %v", err)
+	}
+	if objects := store.Objects(); len(objects) != 0 {
+		t.Errorf("stored %v, want no objects", objects)
+	}
+}
+
+func TestUpdateUserSettings_PasswordPolicy(t *testing.T) {
+	tests := []struct {
+		name     string
+		password string
+		wantCode codes.Code
+	}{
+		{name: "strong", password: "journal2024", wantCode: codes.OK},
+		{name: "empty leaves password alone", password: "", wantCode: codes.OK},
+		{name: "too short", password: "a", wantCode: codes.InvalidArgument},
+		{name: "one class", password: "abcdefghij", wantCode: codes.InvalidArgument},
+		{name: "common", password: "password", wantCode: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, mock, cleanup := newTestUserSettingsService(t, "")
+			defer cleanup()
+
+			ctx := auth.SetAuthContext(context.Background(), "user1", "m2m")
+			now := time.Now()
+
+			if tt.wantCode == codes.OK {
+				row := func() *sqlmock.Rows {
+					return sqlmock.NewRows(userColumns).AddRow(
+						"user1", "a@b.com", "Alice", nil, "hash",
+						"active", nil, now, nil, nil, nil, nil, now,
+						false, nil, 0, nil,
+					)
+				}
+				mock.ExpectQuery(`SELECT \* FROM "User"`).WillReturnRows(row())
+				mock.ExpectBegin()
+				mock.ExpectExec(`UPDATE "User"`).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				mock.ExpectQuery(`SELECT \* FROM "User"`).WillReturnRows(row())
+			}
+
+			_, err := svc.UpdateUserSettings(ctx, &pb.UpdateUserSettingsRequest{
+				UserId:   "user1",
+				Password: &tt.password,
+			})
+			if got := status.Code(err); got != tt.wantCode {
+				t.Fatalf("UpdateUserSettings(password %q) code = %v, want %v (err: %v)", tt.password, got, tt.wantCode, err)
+			}
+
+			// Rejected passwords never reach the database
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
 func TestUpdateUserSettings_NoImageFieldInProto(t *testing.T) {
 	// The `image` field (5) has been reserved in the proto. UpdateUserSettingsRequest
 	// no longer has an Image field. Without ProfileImageUpload, the image and
@@ -356,6 +461,59 @@ func TestUpdateUserSettings_ClearProfileImage(t *testing.T) {
 	}
 }
 
+func TestUpdateUserSettings_SyncInterval(t *testing.T) {
+	svc, mock, cleanup := newTestUserSettingsService(t, "")
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "m2m")
+	now := time.Now()
+	minutes := int32(15)
+
+	mock.ExpectQuery(`SELECT \* FROM "User"`).
+		WithArgs("user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "createdAt", "updatedAt"}).
+			AddRow("user1", "a@b.com", now, now))
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE "User" SET "syncIntervalMinutes"=\$1,"updatedAt"=\$2`).
+		WithArgs(15, sqlmock.AnyArg(), "user1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectQuery(`SELECT \* FROM "User"`).
+		WithArgs("user1", "user1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "email", "createdAt", "updatedAt", "syncIntervalMinutes"}).
+			AddRow("user1", "a@b.com", now, now, 15))
+
+	resp, err := svc.UpdateUserSettings(ctx, &pb.UpdateUserSettingsRequest{
+		UserId:              "user1",
+		SyncIntervalMinutes: &minutes,
+	})
+	if err != nil {
+		t.Fatalf("UpdateUserSettings: %v", err)
+	}
+	if resp.User.SyncIntervalMinutes == nil || *resp.User.SyncIntervalMinutes != 15 {
+		t.Errorf("sync_interval_minutes = %v, want 15", resp.User.SyncIntervalMinutes)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestUpdateUserSettings_NegativeSyncInterval(t *testing.T) {
+	svc, _, cleanup := newTestUserSettingsService(t, "")
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "m2m")
+	minutes := int32(-5)
+	_, err := svc.UpdateUserSettings(ctx, &pb.UpdateUserSettingsRequest{
+		UserId:              "user1",
+		SyncIntervalMinutes: &minutes,
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("expected InvalidArgument, got %v", err)
+	}
+}
+
 // ---------- refreshProfileImageURL ----------
 
 func TestRefreshProfileImageURL_NilObject(t *testing.T) {
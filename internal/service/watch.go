@@ -0,0 +1,168 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// watchBufferSize is how many events a watcher may fall behind by before it
+// is disconnected.
+const watchBufferSize = 64
+
+// changeBroker fans note change events out to WatchNotes streams on this
+// server. It is in-process only: events published by other server instances
+// or by the sync and taggen jobs never reach it.
+type changeBroker struct {
+	mu      sync.Mutex
+	subs    map[string]map[*watcher]struct{}
+	stopped bool
+}
+
+// watcher is one WatchNotes stream's subscription. events is closed when the
+// watcher falls behind or the broker stops.
+type watcher struct {
+	events chan *pb.NoteEvent
+	behind bool
+}
+
+func newChangeBroker() *changeBroker {
+	return &changeBroker{subs: make(map[string]map[*watcher]struct{})}
+}
+
+// subscribe registers a watcher for userID's events. The returned function
+// removes it and must be called when the stream ends.
+func (b *changeBroker) subscribe(userID string) (*watcher, func()) {
+	w := &watcher{events: make(chan *pb.NoteEvent, watchBufferSize)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.stopped {
+		close(w.events)
+		return w, func() {}
+	}
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*watcher]struct{})
+	}
+	b.subs[userID][w] = struct{}{}
+
+	return w, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[userID][w]; !ok {
+			return // Already dropped and closed by publish or stop
+		}
+		delete(b.subs[userID], w)
+		if len(b.subs[userID]) == 0 {
+			delete(b.subs, userID)
+		}
+		close(w.events)
+	}
+}
+
+// publish sends event to every watcher of userID without blocking. A watcher
+// whose buffer is full is dropped rather than silently missing events.
+func (b *changeBroker) publish(userID string, event *pb.NoteEvent) {
+	event.OccurredAt = timestamppb.New(time.Now())
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for w := range b.subs[userID] {
+		select {
+		case w.events <- event:
+		default:
+			w.behind = true
+			delete(b.subs[userID], w)
+			close(w.events)
+		}
+	}
+	if len(b.subs[userID]) == 0 {
+		delete(b.subs, userID)
+	}
+}
+
+// watching reports whether userID has any watchers, so callers can skip
+// building events nobody will receive.
+func (b *changeBroker) watching(userID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subs[userID]) > 0
+}
+
+// stop disconnects every watcher and rejects new ones, so a graceful server
+// shutdown isn't held open by streams that never end.
+func (b *changeBroker) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopped = true
+	for userID, watchers := range b.subs {
+		for w := range watchers {
+			close(w.events)
+		}
+		delete(b.subs, userID)
+	}
+}
+
+// publishNote publishes a created or updated event carrying note.
+func (s *NotesService) publishNote(userID string, eventType pb.NoteEventType, note *pb.Note) {
+	s.changes.publish(userID, &pb.NoteEvent{Type: eventType, NoteId: note.Id, Note: note})
+}
+
+// publishTags publishes a tags-changed event with the note's new tags.
+func (s *NotesService) publishTags(userID string, note *pb.Note) {
+	s.changes.publish(userID, &pb.NoteEvent{
+		Type:   pb.NoteEventType_NOTE_EVENT_TYPE_TAGS_CHANGED,
+		NoteId: note.Id,
+		Tags:   note.Tags,
+	})
+}
+
+// publishDeleted publishes a deleted event for noteID.
+func (s *NotesService) publishDeleted(userID, noteID string) {
+	s.changes.publish(userID, &pb.NoteEvent{Type: pb.NoteEventType_NOTE_EVENT_TYPE_DELETED, NoteId: noteID})
+}
+
+// StopWatchers ends all open WatchNotes streams. Call it before stopping the
+// gRPC server gracefully, which otherwise waits for streams to finish.
+func (s *NotesService) StopWatchers() {
+	s.changes.stop()
+}
+
+// WatchNotes streams changes to the user's notes made through this server
+// until the client disconnects.
+func (s *NotesService) WatchNotes(req *pb.WatchNotesRequest, stream pb.NotesService_WatchNotesServer) error {
+	if req.UserId == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	ctx := stream.Context()
+
+	// Verify authorization
+	if err := verifyUserAuthorization(ctx, req.UserId); err != nil {
+		return err
+	}
+
+	w, unsubscribe := s.changes.subscribe(req.UserId)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-w.events:
+			if !ok {
+				if w.behind {
+					return status.Error(codes.Aborted, "watcher fell behind; list notes again and re-watch")
+				}
+				return status.Error(codes.Unavailable, "server is shutting down")
+			}
+			if err := stream.Send(&pb.WatchNotesResponse{Event: event}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/icco/etu-backend/internal/auth"
+	pb "github.com/icco/etu-backend/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeWatchStream is a WatchNotes server stream that hands sent events to the
+// test.
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.NoteEvent
+}
+
+func (f *fakeWatchStream) Context() context.Context { return f.ctx }
+
+func (f *fakeWatchStream) Send(resp *pb.WatchNotesResponse) error {
+	f.sent <- resp.Event
+	return nil
+}
+
+// startWatch runs WatchNotes for user1 in the background and waits until it
+// is subscribed. The returned channel receives WatchNotes' result.
+func startWatch(t *testing.T, ctx context.Context, svc *NotesService) (*fakeWatchStream, <-chan error) {
+	t.Helper()
+	stream := &fakeWatchStream{ctx: ctx, sent: make(chan *pb.NoteEvent, watchBufferSize)}
+	done := make(chan error, 1)
+	go func() {
+		done <- svc.WatchNotes(&pb.WatchNotesRequest{UserId: "user1"}, stream)
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !svc.changes.watching("user1") {
+		if time.Now().After(deadline) {
+			t.Fatal("WatchNotes never subscribed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return stream, done
+}
+
+func TestWatchNotes_StreamsDeletes(t *testing.T) {
+	svc, mock, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(auth.SetAuthContext(context.Background(), "user1", "apikey"))
+	defer cancel()
+	stream, done := startWatch(t, ctx, svc)
+
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage"`).WithArgs("note1").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteAudio"`).WithArgs("note1").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "Note"`).WithArgs("note1", "user1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if _, err := svc.DeleteNote(ctx, &pb.DeleteNoteRequest{UserId: "user1", Id: "note1"}); err != nil {
+		t.Fatalf("DeleteNote: %v", err)
+	}
+	// Another user's changes must not reach this watcher
+	svc.publishDeleted("user2", "note2")
+
+	select {
+	case event := <-stream.sent:
+		if event.Type != pb.NoteEventType_NOTE_EVENT_TYPE_DELETED || event.NoteId != "note1" {
+			t.Errorf("got %v event for %q, want a delete of note1", event.Type, event.NoteId)
+		}
+		if event.OccurredAt == nil {
+			t.Error("event has no occurred_at")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event received")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("WatchNotes returned %v after the client left, want nil", err)
+	}
+	if len(stream.sent) != 0 {
+		t.Errorf("got %d unexpected events", len(stream.sent))
+	}
+	if svc.changes.watching("user1") {
+		t.Error("watcher still subscribed after the stream ended")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestWatchNotes_OtherUser(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user2", "apikey")
+	stream := &fakeWatchStream{ctx: ctx, sent: make(chan *pb.NoteEvent, 1)}
+	err := svc.WatchNotes(&pb.WatchNotesRequest{UserId: "user1"}, stream)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("got %v, want PermissionDenied", err)
+	}
+}
+
+func TestWatchNotes_DisconnectsSlowWatcher(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	w, unsubscribe := svc.changes.subscribe("user1")
+	defer unsubscribe()
+	for i := 0; i <= watchBufferSize; i++ {
+		svc.publishDeleted("user1", "note1")
+	}
+
+	for range watchBufferSize {
+		<-w.events
+	}
+	if _, ok := <-w.events; ok || !w.behind {
+		t.Fatal("watcher was not dropped after its buffer filled")
+	}
+	if svc.changes.watching("user1") {
+		t.Error("dropped watcher is still subscribed")
+	}
+}
+
+func TestWatchNotes_StopWatchers(t *testing.T) {
+	svc, _, cleanup := newTestNotesService(t)
+	defer cleanup()
+
+	ctx := auth.SetAuthContext(context.Background(), "user1", "apikey")
+	_, done := startWatch(t, ctx, svc)
+
+	svc.StopWatchers()
+	if err := <-done; status.Code(err) != codes.Unavailable {
+		t.Errorf("got %v, want Unavailable", err)
+	}
+}
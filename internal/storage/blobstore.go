@@ -0,0 +1,22 @@
+package storage
+
+import "context"
+
+// Blobstore stores note media objects by name. It is implemented by *Client
+// for GCS and by *Memory for tests.
+type Blobstore interface {
+	// UploadImage stores data under objectName and returns a signed URL for it.
+	UploadImage(ctx context.Context, objectName string, data []byte, mimeType string) (string, error)
+	// DeleteImage removes an object. Deleting a missing object is not an error.
+	DeleteImage(ctx context.Context, objectName string) error
+	// GetImage returns an object's data.
+	GetImage(ctx context.Context, objectName string) ([]byte, error)
+	// GetSignedURL returns a URL for reading an object, valid for
+	// SignedURLDuration.
+	GetSignedURL(ctx context.Context, objectName string) (string, error)
+}
+
+var (
+	_ Blobstore = (*Client)(nil)
+	_ Blobstore = (*Memory)(nil)
+)
@@ -2,11 +2,15 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"runtime"
+	"sync"
 	"time"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
 )
 
 // Client wraps the GCS client for image storage operations.
@@ -35,7 +39,8 @@ func (c *Client) Close() error {
 	return c.client.Close()
 }
 
-// SignedURLDuration is how long signed URLs remain valid
+// SignedURLDuration is how long signed URLs remain valid. It is also the
+// longest expiry GCS allows for V4 signed URLs.
 const SignedURLDuration = 7 * 24 * time.Hour // 7 days
 
 // UploadImage uploads image data to GCS and returns a signed URL for access.
@@ -71,10 +76,20 @@ func (c *Client) UploadImage(ctx context.Context, objectName string, data []byte
 // GetSignedURL generates a signed URL for accessing an object.
 // The URL is valid for SignedURLDuration.
 func (c *Client) GetSignedURL(ctx context.Context, objectName string) (string, error) {
+	return c.GetSignedURLWithExpiry(ctx, objectName, SignedURLDuration)
+}
+
+// GetSignedURLWithExpiry generates a signed URL that is valid for expiry.
+// Expiries outside (0, SignedURLDuration] are clamped to SignedURLDuration.
+func (c *Client) GetSignedURLWithExpiry(ctx context.Context, objectName string, expiry time.Duration) (string, error) {
+	if expiry <= 0 || expiry > SignedURLDuration {
+		expiry = SignedURLDuration
+	}
+
 	opts := &storage.SignedURLOptions{
 		Scheme:  storage.SigningSchemeV4,
 		Method:  "GET",
-		Expires: time.Now().Add(SignedURLDuration),
+		Expires: time.Now().Add(expiry),
 	}
 
 	url, err := c.client.Bucket(c.bucket).SignedURL(objectName, opts)
@@ -85,6 +100,78 @@ func (c *Client) GetSignedURL(ctx context.Context, objectName string) (string, e
 	return url, nil
 }
 
+// GetSignedURLs generates signed URLs for many objects at once, valid for
+// SignedURLDuration. See GetSignedURLsWithExpiry.
+func (c *Client) GetSignedURLs(ctx context.Context, objectNames []string) (map[string]string, error) {
+	return c.GetSignedURLsWithExpiry(ctx, objectNames, SignedURLDuration)
+}
+
+// GetSignedURLsWithExpiry signs URLs for objectNames in parallel and returns
+// them keyed by object name. Empty and duplicate names are skipped. If some
+// objects fail to sign, the URLs that succeeded are still returned along with
+// an error describing the failures.
+func (c *Client) GetSignedURLsWithExpiry(ctx context.Context, objectNames []string, expiry time.Duration) (map[string]string, error) {
+	return signAll(ctx, objectNames, runtime.GOMAXPROCS(0), func(objectName string) (string, error) {
+		return c.GetSignedURLWithExpiry(ctx, objectName, expiry)
+	})
+}
+
+// signAll runs sign for each unique object name using at most concurrency
+// goroutines. Signing is CPU-bound, so concurrency should track GOMAXPROCS.
+func signAll(ctx context.Context, objectNames []string, concurrency int, sign func(objectName string) (string, error)) (map[string]string, error) {
+	unique := make([]string, 0, len(objectNames))
+	seen := make(map[string]bool, len(objectNames))
+	for _, name := range objectNames {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		unique = append(unique, name)
+	}
+
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		urls = make(map[string]string, len(unique))
+		errs []error
+		sem  = make(chan struct{}, max(concurrency, 1))
+	)
+
+	for _, name := range unique {
+		if err := ctx.Err(); err != nil {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+			break
+		}
+
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(name string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			url, err := sign(name)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				return
+			}
+			urls[name] = url
+		}(name)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return urls, fmt.Errorf("failed to sign %d of %d URLs: %w", len(unique)-len(urls), len(unique), errors.Join(errs...))
+	}
+	return urls, nil
+}
+
 // DeleteImage deletes an image from GCS.
 func (c *Client) DeleteImage(ctx context.Context, objectName string) error {
 	obj := c.client.Bucket(c.bucket).Object(objectName)
@@ -128,6 +215,64 @@ func (c *Client) GetImage(ctx context.Context, objectName string) (data []byte,
 	return data, nil
 }
 
+// Exists reports whether an object exists in the bucket.
+func (c *Client) Exists(ctx context.Context, objectName string) (bool, error) {
+	_, err := c.Stat(ctx, objectName)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Stat returns information about a single object without downloading it.
+func (c *Client) Stat(ctx context.Context, objectName string) (ObjectInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	attrs, err := c.client.Bucket(c.bucket).Object(objectName).Attrs(ctx)
+	if err != nil {
+		return ObjectInfo{}, fmt.Errorf("failed to get object attributes: %w", err)
+	}
+	return ObjectInfo{
+		Name:    attrs.Name,
+		Size:    attrs.Size,
+		Created: attrs.Created,
+	}, nil
+}
+
+// ObjectInfo describes a stored object.
+type ObjectInfo struct {
+	Name    string
+	Size    int64
+	Created time.Time
+}
+
+// List returns every object whose name starts with prefix.
+func (c *Client) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	it := c.client.Bucket(c.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+
+	var objects []ObjectInfo
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list objects: %w", err)
+		}
+		objects = append(objects, ObjectInfo{
+			Name:    attrs.Name,
+			Size:    attrs.Size,
+			Created: attrs.Created,
+		})
+	}
+
+	return objects, nil
+}
+
 // Bucket returns the bucket name.
 func (c *Client) Bucket() string {
 	return c.bucket
@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSignAll(t *testing.T) {
+	names := []string{"notes/a/1", "notes/a/2", "", "notes/b/1", "notes/a/1"}
+
+	var inFlight, peak atomic.Int32
+	urls, err := signAll(context.Background(), names, 2, func(name string) (string, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		return "https://signed/" + name, nil
+	})
+	if err != nil {
+		t.Fatalf("signAll: %v", err)
+	}
+
+	want := map[string]string{
+		"notes/a/1": "https://signed/notes/a/1",
+		"notes/a/2": "https://signed/notes/a/2",
+		"notes/b/1": "https://signed/notes/b/1",
+	}
+	if diff := cmp.Diff(want, urls); diff != "" {
+		t.Errorf("signAll mismatch (-want +got):\n%s", diff)
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("peak concurrency = %d, want <= 2", p)
+	}
+}
+
+func TestSignAll_PartialFailure(t *testing.T) {
+	urls, err := signAll(context.Background(), []string{"ok", "bad"}, 4, func(name string) (string, error) {
+		if name == "bad" {
+			return "", errors.New("boom")
+		}
+		return "https://signed/" + name, nil
+	})
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	if diff := cmp.Diff(map[string]string{"ok": "https://signed/ok"}, urls); diff != "" {
+		t.Errorf("signAll mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func BenchmarkSignAll(b *testing.B) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		b.Fatalf("GenerateKey: %v", err)
+	}
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	opts := &storage.SignedURLOptions{
+		GoogleAccessID: "bench@example.iam.gserviceaccount.com",
+		PrivateKey:     pemKey,
+		Scheme:         storage.SigningSchemeV4,
+		Method:         "GET",
+		Expires:        time.Now().Add(time.Hour),
+	}
+	sign := func(name string) (string, error) {
+		return storage.SignedURL("bench-bucket", name, opts)
+	}
+
+	// A full ListNotes page with one attachment per note
+	names := make([]string, 100)
+	for i := range names {
+		names[i] = fmt.Sprintf("notes/note%d/img%d", i, i)
+	}
+
+	for _, concurrency := range []int{1, 4, 8} {
+		b.Run(fmt.Sprintf("concurrency=%d", concurrency), func(b *testing.B) {
+			for b.Loop() {
+				if _, err := signAll(context.Background(), names, concurrency, sign); err != nil {
+					b.Fatalf("signAll: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"cloud.google.com/go/storage"
+)
+
+// memoryURLPrefix prefixes the URLs Memory returns in place of signed URLs.
+const memoryURLPrefix = "https://storage.test/"
+
+// Memory is an in-memory Blobstore for tests. It is safe for concurrent use.
+type Memory struct {
+	mu      sync.Mutex
+	objects map[string]memoryObject
+}
+
+// memoryObject is an object held by Memory.
+type memoryObject struct {
+	data     []byte
+	mimeType string
+}
+
+// NewMemory creates an empty in-memory Blobstore.
+func NewMemory() *Memory {
+	return &Memory{objects: make(map[string]memoryObject)}
+}
+
+// UploadImage stores a copy of data under objectName.
+func (m *Memory) UploadImage(ctx context.Context, objectName string, data []byte, mimeType string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[objectName] = memoryObject{data: slices.Clone(data), mimeType: mimeType}
+	return memoryURLPrefix + objectName, nil
+}
+
+// DeleteImage removes objectName if present.
+func (m *Memory) DeleteImage(ctx context.Context, objectName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.objects, objectName)
+	return nil
+}
+
+// GetImage returns a copy of objectName's data. A missing object returns an
+// error wrapping storage.ErrObjectNotExist, as from GCS.
+func (m *Memory) GetImage(ctx context.Context, objectName string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[objectName]
+	if !ok {
+		return nil, fmt.Errorf("failed to create reader: %w", storage.ErrObjectNotExist)
+	}
+	return slices.Clone(obj.data), nil
+}
+
+// GetSignedURL returns a fake URL for objectName. It does not check that the
+// object exists, matching GCS signing.
+func (m *Memory) GetSignedURL(ctx context.Context, objectName string) (string, error) {
+	return memoryURLPrefix + objectName, nil
+}
+
+// Objects returns the names of the stored objects, sorted.
+func (m *Memory) Objects() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.objects))
+	for name := range m.objects {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// MimeType returns the MIME type objectName was uploaded with, or "" if it is
+// not stored.
+func (m *Memory) MimeType(objectName string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.objects[objectName].mimeType
+}
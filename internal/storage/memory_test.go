@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"cloud.google.com/go/storage"
+)
+
+func TestMemory(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemory()
+
+	data := []byte("png bytes")
+	url, err := m.UploadImage(ctx, "notes/n1/img1", data, "image/png")
+	if err != nil || url == "" {
+		t.Fatalf("UploadImage = %q, %v", url, err)
+	}
+	data[0] = 'X' // the store keeps its own copy
+
+	got, err := m.GetImage(ctx, "notes/n1/img1")
+	if err != nil || string(got) != "png bytes" {
+		t.Errorf("GetImage = %q, %v; want %q", got, err, "png bytes")
+	}
+	if m.MimeType("notes/n1/img1") != "image/png" {
+		t.Errorf("MimeType = %q, want image/png", m.MimeType("notes/n1/img1"))
+	}
+
+	if err := m.DeleteImage(ctx, "notes/n1/img1"); err != nil {
+		t.Fatalf("DeleteImage: %v", err)
+	}
+	if err := m.DeleteImage(ctx, "notes/n1/img1"); err != nil {
+		t.Errorf("DeleteImage of a missing object = %v, want nil", err)
+	}
+	if _, err := m.GetImage(ctx, "notes/n1/img1"); !errors.Is(err, storage.ErrObjectNotExist) {
+		t.Errorf("GetImage after delete = %v, want ErrObjectNotExist", err)
+	}
+	if objects := m.Objects(); !slices.Equal(objects, []string{}) {
+		t.Errorf("Objects = %v, want none", objects)
+	}
+}
@@ -2,27 +2,168 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
+	"unicode/utf8"
 
+	"github.com/icco/etu-backend/internal/models"
 	"github.com/icco/etu-backend/internal/notion"
+	"github.com/icco/etu-backend/internal/storage"
 	"github.com/icco/etu-backend/internal/syncdb"
 )
 
+// DefaultIncrementalBuffer is how far before the last sync an incremental
+// sync starts looking for edited Notion pages when Options.IncrementalBuffer
+// is unset.
+const DefaultIncrementalBuffer = 5 * time.Minute
+
+// DefaultMaxPruneFraction is the largest share of a user's Notion-linked notes
+// one prune may delete when Options.MaxPruneFraction is unset.
+const DefaultMaxPruneFraction = 0.5
+
+// TagSync controls which way tags are synced. Note content is always synced in
+// the direction of the sync itself.
+type TagSync string
+
+const (
+	// TagSyncBoth syncs tags along with content in both directions
+	TagSyncBoth TagSync = "both"
+	// TagSyncFromNotion copies Notion's tags to notes but never pushes them
+	TagSyncFromNotion TagSync = "from-notion"
+	// TagSyncToNotion pushes notes' tags to Notion but never imports Notion's
+	TagSyncToNotion TagSync = "to-notion"
+	// TagSyncNone leaves tags alone on both sides
+	TagSyncNone TagSync = "none"
+)
+
+// TagSyncModes lists the valid TagSync values
+var TagSyncModes = []TagSync{TagSyncBoth, TagSyncFromNotion, TagSyncToNotion, TagSyncNone}
+
+// URLSigner signs storage object names for reading, such as *storage.Client.
+type URLSigner interface {
+	GetSignedURLsWithExpiry(ctx context.Context, objectNames []string, expiry time.Duration) (map[string]string, error)
+}
+
+// ObjectDeleter deletes storage objects, such as *storage.Client.
+type ObjectDeleter interface {
+	DeleteImage(ctx context.Context, objectName string) error
+}
+
+// Options configures a Syncer.
+type Options struct {
+	// DryRun reports what a sync would create, update, and archive without
+	// writing to the database or to Notion.
+	DryRun bool
+
+	// ImgixDomain serves note images pushed to Notion from imgix. Those URLs
+	// don't expire, so it is preferred over Signer.
+	ImgixDomain string
+
+	// Signer signs image URLs when ImgixDomain is unset. Signed URLs expire
+	// after storage.SignedURLDuration, after which the images stop loading in
+	// Notion until the note is pushed again.
+	Signer URLSigner
+
+	// ToNotionSince limits pushes to Notion to notes modified at or after
+	// this time. Zero pushes every note that changed since it was last synced.
+	ToNotionSince time.Time
+
+	// MaxContentLength is the longest note content, in characters, pushed to
+	// Notion. Longer notes, such as ones written before the limit existed, are
+	// skipped and counted as errors rather than truncated, since a bidirectional
+	// sync would pull the truncated page back over the full note. Zero uses
+	// models.DefaultMaxContentLength.
+	MaxContentLength int
+
+	// ToNotionPageSize is how many notes are loaded at a time when pushing to
+	// Notion. Zero uses syncdb.DefaultToNotionPageSize.
+	ToNotionPageSize int
+
+	// IncrementalBuffer is subtracted from the last sync time when fetching
+	// edited pages, to cover clock skew between Notion and this job and edits
+	// that finished saving after the last sync started. A larger buffer
+	// re-fetches and compares more unchanged pages on every run; a smaller
+	// one risks missing edits. Zero uses DefaultIncrementalBuffer.
+	IncrementalBuffer time.Duration
+
+	// Prune deletes local notes linked to Notion pages that a full sync
+	// didn't see, because they were archived or deleted in Notion. It only
+	// applies to full syncs that list every page in one run, never to
+	// incremental or resumed ones, and never when Notion returns no pages.
+	// Notes are deleted outright, as DeleteNote does, rather than hidden: a
+	// page restored from Notion's trash comes back as a new note on the next
+	// full sync, and MaxPruneFraction and DryRun guard against bad listings.
+	Prune bool
+
+	// Storage deletes the images and audio files of pruned notes. When nil
+	// they are left in storage and logged.
+	Storage ObjectDeleter
+
+	// MaxPruneFraction is the largest share, from 0 to 1, of a user's
+	// Notion-linked notes a prune may delete. A prune that would delete more
+	// is refused and counted as an error, since a listing that silently came
+	// back short would otherwise wipe out notes that still exist in Notion.
+	// Zero uses DefaultMaxPruneFraction.
+	MaxPruneFraction float64
+
+	// SyncTags limits which way tags are synced, for users who curate tags on
+	// one side only. Empty uses TagSyncBoth.
+	SyncTags TagSync
+}
+
+// tagsFromNotion reports whether Notion's tags replace notes' tags
+func (o Options) tagsFromNotion() bool {
+	return o.SyncTags == "" || o.SyncTags == TagSyncBoth || o.SyncTags == TagSyncFromNotion
+}
+
+// tagsToNotion reports whether notes' tags replace Notion's tags
+func (o Options) tagsToNotion() bool {
+	return o.SyncTags == "" || o.SyncTags == TagSyncBoth || o.SyncTags == TagSyncToNotion
+}
+
+// store is the subset of *syncdb.DB used by Syncer.
+type store interface {
+	GetNotesByNotionUUIDs(userID string, notionUUIDs []string) (map[string]*syncdb.Note, error)
+	UpsertNoteFromNotion(userID, notionUUID, pageID, content string, tagNames []string, updateTags bool, createdAt, updatedAt time.Time) (*syncdb.Note, bool, error)
+	GetNoteTags(noteID string) ([]string, error)
+	GetLastSyncTime(userID string) (*time.Time, error)
+	UpdateLastSyncTime(userID string, syncTime time.Time) error
+	GetFullSyncCursor(userID string) (string, error)
+	SaveFullSyncCursor(userID, cursor string) error
+	GetNotesNeedingSyncToNotion(userID string, page syncdb.ToNotionPage) ([]syncdb.Note, error)
+	MarkNoteSyncedToNotion(noteID, pageID, notionUUID string) error
+	UpdateNoteNotionSyncTime(noteID string) error
+	GetArchivedNotePageIDs(userID string) ([]string, error)
+	GetNotionLinkedNotes(userID string) ([]syncdb.Note, error)
+	DeleteNotes(userID string, noteIDs []string) (int64, []string, error)
+}
+
+// notionAPI is the subset of *notion.Client used by Syncer.
+type notionAPI interface {
+	ListPostsPage(ctx context.Context, cursor string) ([]*notion.Post, string, error)
+	ListPostsSince(ctx context.Context, since time.Time) ([]*notion.Post, error)
+	CreatePost(ctx context.Context, id, content string, tags []string, imageURLs []string) (string, error)
+	UpdatePost(ctx context.Context, pageID, content string, tags []string, updateTags bool, imageURLs []string) error
+	ArchivePost(ctx context.Context, pageID string) error
+}
+
 // Syncer handles syncing between Notion and PostgreSQL.
 type Syncer struct {
-	db     *syncdb.DB
-	notion *notion.Client
+	db     store
+	notion notionAPI
 	log    *slog.Logger
+	opts   Options
 }
 
 // NewSyncer creates a new Syncer instance.
-func NewSyncer(database *syncdb.DB, notionClient *notion.Client) *Syncer {
+func NewSyncer(database *syncdb.DB, notionClient *notion.Client, opts Options) *Syncer {
 	return &Syncer{
 		db:     database,
 		notion: notionClient,
 		log:    slog.Default(),
+		opts:   opts,
 	}
 }
 
@@ -31,6 +172,7 @@ type SyncResult struct {
 	Created   int
 	Updated   int
 	Unchanged int
+	Pruned    int // Notes deleted because their Notion page is gone
 	Errors    int
 	Duration  time.Duration
 }
@@ -46,16 +188,32 @@ type SyncToNotionResult struct {
 
 // SyncUser syncs all Notion posts for a specific user to the database.
 // If fullSync is true, it fetches all posts; otherwise it only fetches posts modified since last sync.
+// A full sync that was interrupted is resumed from its saved cursor, even if
+// fullSync is false.
 func (s *Syncer) SyncUser(ctx context.Context, userID string, fullSync bool) (*SyncResult, error) {
 	start := time.Now()
 	result := &SyncResult{}
 
-	var posts []*notion.Post
-	var err error
+	cursor, err := s.db.GetFullSyncCursor(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get full sync cursor: %w", err)
+	}
 
-	if fullSync {
-		posts, err = s.notion.ListAllPosts(ctx)
-	} else {
+	// Notion UUIDs listed by a full sync that started from the first page,
+	// for pruning. Nil when not pruning or when the listing is partial.
+	var seen map[string]bool
+	if s.opts.Prune {
+		seen = make(map[string]bool)
+	}
+
+	switch {
+	case cursor != "":
+		s.log.Info("resuming interrupted full sync", "user_id", userID)
+		seen = nil // Pages before the cursor were listed by an earlier run
+		err = s.syncAllPages(ctx, userID, cursor, result, nil)
+	case fullSync:
+		err = s.syncAllPages(ctx, userID, "", result, seen)
+	default:
 		lastSync, syncErr := s.db.GetLastSyncTime(userID)
 		if syncErr != nil {
 			return nil, fmt.Errorf("failed to get last sync time: %w", syncErr)
@@ -63,12 +221,17 @@ func (s *Syncer) SyncUser(ctx context.Context, userID string, fullSync bool) (*S
 
 		if lastSync == nil {
 			s.log.Info("no previous sync found, performing full sync", "user_id", userID)
-			posts, err = s.notion.ListAllPosts(ctx)
+			err = s.syncAllPages(ctx, userID, "", result, seen)
 		} else {
-			// Add a small buffer to avoid missing posts due to timing
-			since := lastSync.Add(-5 * time.Minute)
+			seen = nil
+			since := s.incrementalSince(*lastSync)
 			s.log.Info("starting incremental sync", "user_id", userID, "since", since.Format(time.RFC3339))
+			var posts []*notion.Post
 			posts, err = s.notion.ListPostsSince(ctx, since)
+			if err == nil {
+				s.log.Info("fetched posts from Notion", "user_id", userID, "count", len(posts))
+				s.syncPosts(userID, posts, result)
+			}
 		}
 	}
 
@@ -76,14 +239,159 @@ func (s *Syncer) SyncUser(ctx context.Context, userID string, fullSync bool) (*S
 		return nil, fmt.Errorf("failed to fetch posts from Notion: %w", err)
 	}
 
-	s.log.Info("fetched posts from Notion", "user_id", userID, "count", len(posts))
+	if seen != nil {
+		s.pruneNotes(ctx, userID, seen, result)
+	}
+
+	// Update last sync time
+	if s.opts.DryRun {
+		s.log.Info("dry run: not updating last sync time", "user_id", userID)
+	} else if err := s.db.UpdateLastSyncTime(userID, time.Now()); err != nil {
+		s.log.Warn("failed to update last sync time", "user_id", userID, "error", err)
+	}
+
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// syncAllPages syncs every post one Notion page at a time, saving the cursor
+// after each page so an interrupted run can resume from it. A stored cursor
+// Notion no longer accepts restarts the listing from the first page. The
+// UUID of each listed post is added to seen, if it isn't nil.
+func (s *Syncer) syncAllPages(ctx context.Context, userID, cursor string, result *SyncResult, seen map[string]bool) error {
+	for {
+		posts, next, err := s.notion.ListPostsPage(ctx, cursor)
+		if errors.Is(err, notion.ErrInvalidCursor) {
+			s.log.Warn("full sync cursor rejected, restarting from the first page", "user_id", userID, "error", err)
+			cursor = ""
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		s.log.Info("fetched posts from Notion", "user_id", userID, "count", len(posts))
+		s.syncPosts(userID, posts, result)
+		if seen != nil {
+			for _, post := range posts {
+				seen[post.ID] = true
+			}
+		}
+
+		if next == "" {
+			return nil
+		}
+		cursor = next
+		if s.opts.DryRun {
+			continue
+		}
+		if err := s.db.SaveFullSyncCursor(userID, cursor); err != nil {
+			s.log.Warn("failed to save full sync cursor", "user_id", userID, "error", err)
+		}
+	}
+}
+
+// pruneNotes deletes the user's Notion-linked notes whose UUID isn't in seen,
+// the UUIDs of every post a complete full sync listed.
+func (s *Syncer) pruneNotes(ctx context.Context, userID string, seen map[string]bool, result *SyncResult) {
+	if len(seen) == 0 {
+		s.log.Warn("full sync listed no Notion posts, not pruning", "user_id", userID)
+		return
+	}
+
+	linked, err := s.db.GetNotionLinkedNotes(userID)
+	if err != nil {
+		s.log.Error("error loading Notion-linked notes for pruning", "user_id", userID, "error", err)
+		result.Errors++
+		return
+	}
+
+	stale := notesMissingFromNotion(linked, seen)
+	if len(stale) == 0 {
+		return
+	}
+	if limit := s.maxPruneFraction(); float64(len(stale)) > limit*float64(len(linked)) {
+		s.log.Error("too many notes missing from Notion, not pruning", "user_id", userID, "missing", len(stale), "linked", len(linked), "max_prune_fraction", limit)
+		result.Errors++
+		return
+	}
+	if s.opts.DryRun {
+		for _, noteID := range stale {
+			s.log.Info("dry run: would delete note missing from Notion", "user_id", userID, "note_id", noteID)
+		}
+		result.Pruned += len(stale)
+		return
+	}
+
+	deleted, objectNames, err := s.db.DeleteNotes(userID, stale)
+	if err != nil {
+		s.log.Error("error pruning notes missing from Notion", "user_id", userID, "count", len(stale), "error", err)
+		result.Errors++
+		return
+	}
+	s.log.Info("pruned notes missing from Notion", "user_id", userID, "count", deleted)
+	result.Pruned += int(deleted)
+
+	// Clean up the pruned notes' media, as DeleteNote does
+	for _, name := range objectNames {
+		if s.opts.Storage == nil {
+			s.log.Warn("storage not configured, leaving pruned note media", "user_id", userID, "object_name", name)
+			continue
+		}
+		if err := s.opts.Storage.DeleteImage(ctx, name); err != nil {
+			s.log.Error("failed to delete pruned note media from storage", "user_id", userID, "object_name", name, "error", err)
+		}
+	}
+}
+
+// notesMissingFromNotion returns the IDs of notes whose Notion UUID isn't in
+// seen. Notes without a Notion UUID are never returned.
+func notesMissingFromNotion(notes []syncdb.Note, seen map[string]bool) []string {
+	var missing []string
+	for _, note := range notes {
+		if note.NotionUUID == nil || *note.NotionUUID == "" {
+			continue
+		}
+		if !seen[*note.NotionUUID] {
+			missing = append(missing, note.ID)
+		}
+	}
+	return missing
+}
+
+// syncPosts writes posts to the database and adds the outcome to result.
+// Existing notes for the whole batch are loaded up front, so deciding whether
+// a post changed takes no extra queries.
+func (s *Syncer) syncPosts(userID string, posts []*notion.Post, result *SyncResult) {
+	uuids := make([]string, len(posts))
+	for i, post := range posts {
+		uuids[i] = post.ID
+	}
+	existingNotes, err := s.db.GetNotesByNotionUUIDs(userID, uuids)
+	if err != nil {
+		s.log.Error("error checking existing notes", "user_id", userID, "count", len(posts), "error", err)
+		result.Errors += len(posts)
+		return
+	}
 
 	for _, post := range posts {
-		// Get existing note to check if it changed
-		existing, getErr := s.db.GetNoteByNotionUUID(userID, post.ID)
-		if getErr != nil {
-			s.log.Error("error checking existing note", "notion_uuid", post.ID, "error", getErr)
-			result.Errors++
+		existing := existingNotes[post.ID]
+
+		// Compare before writing, since the upsert replaces the note's tags
+		changed := existing != nil && (existing.Content != post.Text ||
+			(s.opts.tagsFromNotion() && tagsChanged(existing.Tags, post.Tags)))
+
+		if s.opts.DryRun {
+			switch {
+			case existing == nil:
+				result.Created++
+				s.log.Info("dry run: would create note", "notion_uuid", post.ID)
+			case changed:
+				result.Updated++
+				s.log.Info("dry run: would update note", "notion_uuid", post.ID, "note_id", existing.ID)
+			default:
+				result.Unchanged++
+			}
 			continue
 		}
 
@@ -94,6 +402,7 @@ func (s *Syncer) SyncUser(ctx context.Context, userID string, fullSync bool) (*S
 			post.PageID, // Notion page ID
 			post.Text,
 			post.Tags,
+			s.opts.tagsFromNotion(),
 			post.CreatedAt,
 			post.ModifiedAt,
 		)
@@ -105,36 +414,23 @@ func (s *Syncer) SyncUser(ctx context.Context, userID string, fullSync bool) (*S
 
 		if isNew {
 			result.Created++
-		} else if existing != nil && (existing.Content != post.Text || !s.tagsChanged(existing.ID, post.Tags)) {
+		} else if changed {
 			result.Updated++
 		} else {
 			result.Unchanged++
 		}
 	}
-
-	// Update last sync time
-	if err := s.db.UpdateLastSyncTime(userID, time.Now()); err != nil {
-		s.log.Warn("failed to update last sync time", "user_id", userID, "error", err)
-	}
-
-	result.Duration = time.Since(start)
-	return result, nil
 }
 
-// tagsChanged checks if tags have changed for a note
-func (s *Syncer) tagsChanged(noteID string, newTags []string) bool {
-	existingTags, err := s.db.GetNoteTags(noteID)
-	if err != nil {
-		return true // Assume changed if we can't check
-	}
-
+// tagsChanged reports whether a note's tags differ from newTags
+func tagsChanged(existingTags []syncdb.Tag, newTags []string) bool {
 	if len(existingTags) != len(newTags) {
 		return true
 	}
 
 	tagMap := make(map[string]bool)
 	for _, t := range existingTags {
-		tagMap[t] = true
+		tagMap[t.Name] = true
 	}
 	for _, t := range newTags {
 		if !tagMap[t] {
@@ -151,59 +447,23 @@ func (s *Syncer) SyncUserToNotion(ctx context.Context, userID string) (*SyncToNo
 	start := time.Now()
 	result := &SyncToNotionResult{}
 
-	// Get notes that need to be synced to Notion
-	notes, err := s.db.GetNotesNeedingSyncToNotion(userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get notes needing sync: %w", err)
-	}
-
-	s.log.Info("syncing notes to Notion", "user_id", userID, "count", len(notes))
-
-	for _, note := range notes {
-		// Get tags for this note
-		tags, tagErr := s.db.GetNoteTags(note.ID)
-		if tagErr != nil {
-			s.log.Error("error getting tags for note", "note_id", note.ID, "error", tagErr)
-			result.Errors++
-			continue
+	// Page through notes that need to be synced to Notion, so a first sync of
+	// a large account doesn't load every note at once
+	page := syncdb.ToNotionPage{Since: s.opts.ToNotionSince, Limit: s.opts.ToNotionPageSize}
+	for {
+		notes, err := s.db.GetNotesNeedingSyncToNotion(userID, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get notes needing sync: %w", err)
+		}
+		if len(notes) == 0 {
+			break
 		}
 
-		if note.ExternalID == nil || *note.ExternalID == "" {
-			// Note doesn't exist in Notion yet - create it
-			pageID, createErr := s.notion.CreatePost(ctx, note.ID, note.Content, tags)
-			if createErr != nil {
-				s.log.Error("error creating Notion page", "note_id", note.ID, "error", createErr)
-				result.Errors++
-				continue
-			}
-
-			// Update the note with the new Notion page ID
-			if markErr := s.db.MarkNoteSyncedToNotion(note.ID, pageID, note.ID); markErr != nil {
-				s.log.Error("error marking note as synced", "note_id", note.ID, "error", markErr)
-				result.Errors++
-				continue
-			}
-
-			result.Created++
-			s.log.Info("created Notion page", "note_id", note.ID, "page_id", pageID)
-		} else {
-			// Note exists in Notion - update it
-			if updateErr := s.notion.UpdatePost(ctx, *note.ExternalID, note.Content, tags); updateErr != nil {
-				s.log.Error("error updating Notion page", "note_id", note.ID, "page_id", *note.ExternalID, "error", updateErr)
-				result.Errors++
-				continue
-			}
-
-			// Update the sync timestamp
-			if markErr := s.db.UpdateNoteNotionSyncTime(note.ID); markErr != nil {
-				s.log.Error("error updating sync time", "note_id", note.ID, "error", markErr)
-				result.Errors++
-				continue
-			}
-
-			result.Updated++
-			s.log.Info("updated Notion page", "note_id", note.ID, "page_id", *note.ExternalID)
+		s.log.Info("syncing notes to Notion", "user_id", userID, "count", len(notes))
+		for _, note := range notes {
+			s.pushNote(ctx, note, result)
 		}
+		page.AfterID = notes[len(notes)-1].ID
 	}
 
 	// Handle archived/deleted notes (archive them in Notion)
@@ -212,6 +472,11 @@ func (s *Syncer) SyncUserToNotion(ctx context.Context, userID string) (*SyncToNo
 		s.log.Warn("failed to get archived notes", "user_id", userID, "error", err)
 	} else {
 		for _, pageID := range archivedPageIDs {
+			if s.opts.DryRun {
+				result.Archived++
+				s.log.Info("dry run: would archive Notion page", "page_id", pageID)
+				continue
+			}
 			if archiveErr := s.notion.ArchivePost(ctx, pageID); archiveErr != nil {
 				s.log.Error("error archiving Notion page", "page_id", pageID, "error", archiveErr)
 				result.Errors++
@@ -226,6 +491,144 @@ func (s *Syncer) SyncUserToNotion(ctx context.Context, userID string) (*SyncToNo
 	return result, nil
 }
 
+// pushNote creates or updates note's Notion page and adds the outcome to
+// result.
+func (s *Syncer) pushNote(ctx context.Context, note syncdb.Note, result *SyncToNotionResult) {
+	if limit := s.maxContentLength(); utf8.RuneCountInString(note.Content) > limit {
+		s.log.Error("note content too long to push to Notion, skipping", "note_id", note.ID, "limit", limit)
+		result.Errors++
+		return
+	}
+
+	// Get tags for this note, unless they stay local
+	var tags []string
+	if s.opts.tagsToNotion() {
+		var tagErr error
+		tags, tagErr = s.db.GetNoteTags(note.ID)
+		if tagErr != nil {
+			s.log.Error("error getting tags for note", "note_id", note.ID, "error", tagErr)
+			result.Errors++
+			return
+		}
+	}
+
+	imageURLs := s.imageURLs(ctx, note.Images)
+
+	if s.opts.DryRun {
+		if note.ExternalID == nil || *note.ExternalID == "" {
+			result.Created++
+			s.log.Info("dry run: would create Notion page", "note_id", note.ID)
+		} else {
+			result.Updated++
+			s.log.Info("dry run: would update Notion page", "note_id", note.ID, "page_id", *note.ExternalID)
+		}
+		return
+	}
+
+	if note.ExternalID == nil || *note.ExternalID == "" {
+		// Note doesn't exist in Notion yet - create it
+		pageID, createErr := s.notion.CreatePost(ctx, note.ID, note.Content, tags, imageURLs)
+		if createErr != nil {
+			s.log.Error("error creating Notion page", "note_id", note.ID, "error", createErr)
+			result.Errors++
+			return
+		}
+
+		// Update the note with the new Notion page ID
+		if markErr := s.db.MarkNoteSyncedToNotion(note.ID, pageID, note.ID); markErr != nil {
+			s.log.Error("error marking note as synced", "note_id", note.ID, "error", markErr)
+			result.Errors++
+			return
+		}
+
+		result.Created++
+		s.log.Info("created Notion page", "note_id", note.ID, "page_id", pageID)
+		return
+	}
+
+	// Note exists in Notion - update it
+	if updateErr := s.notion.UpdatePost(ctx, *note.ExternalID, note.Content, tags, s.opts.tagsToNotion(), imageURLs); updateErr != nil {
+		s.log.Error("error updating Notion page", "note_id", note.ID, "page_id", *note.ExternalID, "error", updateErr)
+		result.Errors++
+		return
+	}
+
+	// Update the sync timestamp
+	if markErr := s.db.UpdateNoteNotionSyncTime(note.ID); markErr != nil {
+		s.log.Error("error updating sync time", "note_id", note.ID, "error", markErr)
+		result.Errors++
+		return
+	}
+
+	result.Updated++
+	s.log.Info("updated Notion page", "note_id", note.ID, "page_id", *note.ExternalID)
+}
+
+// incrementalSince returns the time an incremental sync fetches edits from,
+// backed off from lastSync by the configured buffer to avoid missing posts
+// due to timing.
+func (s *Syncer) incrementalSince(lastSync time.Time) time.Time {
+	buffer := s.opts.IncrementalBuffer
+	if buffer <= 0 {
+		buffer = DefaultIncrementalBuffer
+	}
+	return lastSync.Add(-buffer)
+}
+
+// maxPruneFraction returns the configured MaxPruneFraction or its default
+func (s *Syncer) maxPruneFraction() float64 {
+	if s.opts.MaxPruneFraction <= 0 {
+		return DefaultMaxPruneFraction
+	}
+	return s.opts.MaxPruneFraction
+}
+
+// maxContentLength returns the configured MaxContentLength or its default
+func (s *Syncer) maxContentLength() int {
+	if s.opts.MaxContentLength <= 0 {
+		return models.DefaultMaxContentLength
+	}
+	return s.opts.MaxContentLength
+}
+
+// imageURLs returns the URLs Notion should load a note's images from, in
+// order. Images fall back to their stored upload URL when neither imgix nor
+// signing is available.
+func (s *Syncer) imageURLs(ctx context.Context, images []syncdb.NoteImage) []string {
+	if len(images) == 0 {
+		return nil
+	}
+
+	var signed map[string]string
+	if s.opts.ImgixDomain == "" && s.opts.Signer != nil {
+		names := make([]string, 0, len(images))
+		for _, img := range images {
+			if img.GCSObjectName != "" {
+				names = append(names, img.GCSObjectName)
+			}
+		}
+		var err error
+		signed, err = s.opts.Signer.GetSignedURLsWithExpiry(ctx, names, storage.SignedURLDuration)
+		if err != nil {
+			// Partial results are still usable
+			s.log.Warn("failed to sign some image URLs, using stored URLs", "error", err)
+		}
+	}
+
+	urls := make([]string, 0, len(images))
+	for _, img := range images {
+		switch {
+		case s.opts.ImgixDomain != "" && img.GCSObjectName != "":
+			urls = append(urls, fmt.Sprintf("https://%s/%s", s.opts.ImgixDomain, img.GCSObjectName))
+		case signed[img.GCSObjectName] != "":
+			urls = append(urls, signed[img.GCSObjectName])
+		default:
+			urls = append(urls, img.URL)
+		}
+	}
+	return urls
+}
+
 // SyncUserBidirectional performs a full bidirectional sync for a user.
 // It first syncs from Notion to the local DB, then syncs local changes back to Notion.
 func (s *Syncer) SyncUserBidirectional(ctx context.Context, userID string, fullSync bool) (*SyncResult, *SyncToNotionResult, error) {
@@ -0,0 +1,575 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/icco/etu-backend/internal/notion"
+	"github.com/icco/etu-backend/internal/storage"
+	"github.com/icco/etu-backend/internal/syncdb"
+)
+
+// fakeStore is an in-memory store for a single user. notes holds existing
+// notes by Notion UUID, and pending holds notes waiting to be pushed to Notion
+// in ID order.
+type fakeStore struct {
+	notes        map[string]*syncdb.Note
+	pending      []syncdb.Note
+	pages        []syncdb.ToNotionPage
+	lookups      int
+	cursor       string
+	savedCursors []string
+	lastSync     *time.Time
+	upserted     []string
+	tagUpdates   []bool
+	tags         map[string][]string
+	tagLookups   int
+	deleted      []string
+	media        map[string][]string // object names by note ID
+}
+
+func (f *fakeStore) GetNotesByNotionUUIDs(userID string, notionUUIDs []string) (map[string]*syncdb.Note, error) {
+	f.lookups++
+	found := map[string]*syncdb.Note{}
+	for _, id := range notionUUIDs {
+		if note, ok := f.notes[id]; ok {
+			found[id] = note
+		}
+	}
+	return found, nil
+}
+
+func (f *fakeStore) UpsertNoteFromNotion(userID, notionUUID, pageID, content string, tagNames []string, updateTags bool, createdAt, updatedAt time.Time) (*syncdb.Note, bool, error) {
+	f.upserted = append(f.upserted, notionUUID)
+	f.tagUpdates = append(f.tagUpdates, updateTags)
+	if note, ok := f.notes[notionUUID]; ok {
+		return note, false, nil
+	}
+	return &syncdb.Note{ID: "note-" + notionUUID}, true, nil
+}
+
+func (f *fakeStore) GetNoteTags(noteID string) ([]string, error) {
+	f.tagLookups++
+	return f.tags[noteID], nil
+}
+
+func (f *fakeStore) GetLastSyncTime(userID string) (*time.Time, error) { return f.lastSync, nil }
+
+func (f *fakeStore) UpdateLastSyncTime(userID string, syncTime time.Time) error {
+	f.lastSync = &syncTime
+	f.cursor = ""
+	return nil
+}
+
+func (f *fakeStore) GetFullSyncCursor(userID string) (string, error) { return f.cursor, nil }
+
+func (f *fakeStore) SaveFullSyncCursor(userID, cursor string) error {
+	f.cursor = cursor
+	f.savedCursors = append(f.savedCursors, cursor)
+	return nil
+}
+
+func (f *fakeStore) GetNotesNeedingSyncToNotion(userID string, page syncdb.ToNotionPage) ([]syncdb.Note, error) {
+	f.pages = append(f.pages, page)
+	var notes []syncdb.Note
+	for _, note := range f.pending {
+		if note.ID > page.AfterID && len(notes) < page.Limit {
+			notes = append(notes, note)
+		}
+	}
+	return notes, nil
+}
+
+func (f *fakeStore) MarkNoteSyncedToNotion(noteID, pageID, notionUUID string) error { return nil }
+
+func (f *fakeStore) UpdateNoteNotionSyncTime(noteID string) error { return nil }
+
+func (f *fakeStore) GetArchivedNotePageIDs(userID string) ([]string, error) { return nil, nil }
+
+func (f *fakeStore) GetNotionLinkedNotes(userID string) ([]syncdb.Note, error) {
+	var notes []syncdb.Note
+	for _, note := range f.notes {
+		notes = append(notes, *note)
+	}
+	slices.SortFunc(notes, func(a, b syncdb.Note) int { return strings.Compare(a.ID, b.ID) })
+	return notes, nil
+}
+
+func (f *fakeStore) DeleteNotes(userID string, noteIDs []string) (int64, []string, error) {
+	f.deleted = append(f.deleted, noteIDs...)
+	var objectNames []string
+	for _, id := range noteIDs {
+		objectNames = append(objectNames, f.media[id]...)
+	}
+	return int64(len(noteIDs)), objectNames, nil
+}
+
+// fakeNotion serves pages of posts keyed by start cursor. Requests for cursors
+// in fail error out, and expired cursors are rejected as invalid.
+type fakeNotion struct {
+	pages   map[string][]*notion.Post
+	next    map[string]string
+	fail    map[string]bool
+	expired map[string]bool
+	queried []string
+	since   []time.Time
+	created []string
+	pushed  []pushedTags
+}
+
+// pushedTags records the tags sent with a create or update of a Notion page
+type pushedTags struct {
+	Tags       []string
+	UpdateTags bool
+}
+
+func (f *fakeNotion) ListPostsPage(ctx context.Context, cursor string) ([]*notion.Post, string, error) {
+	f.queried = append(f.queried, cursor)
+	if f.expired[cursor] {
+		return nil, "", notion.ErrInvalidCursor
+	}
+	if f.fail[cursor] {
+		return nil, "", errors.New("connection reset")
+	}
+	return f.pages[cursor], f.next[cursor], nil
+}
+
+func (f *fakeNotion) ListPostsSince(ctx context.Context, since time.Time) ([]*notion.Post, error) {
+	f.since = append(f.since, since)
+	return nil, nil
+}
+
+func (f *fakeNotion) CreatePost(ctx context.Context, id, content string, tags []string, imageURLs []string) (string, error) {
+	f.created = append(f.created, content)
+	f.pushed = append(f.pushed, pushedTags{Tags: tags, UpdateTags: true})
+	return "page-" + id, nil
+}
+
+func (f *fakeNotion) UpdatePost(ctx context.Context, pageID, content string, tags []string, updateTags bool, imageURLs []string) error {
+	f.pushed = append(f.pushed, pushedTags{Tags: tags, UpdateTags: updateTags})
+	return nil
+}
+
+func (f *fakeNotion) ArchivePost(ctx context.Context, pageID string) error { return nil }
+
+// threePages returns a fake Notion database with one post per page.
+func threePages() *fakeNotion {
+	return &fakeNotion{
+		pages: map[string][]*notion.Post{
+			"":   {{ID: "p1"}},
+			"c2": {{ID: "p2"}},
+			"c3": {{ID: "p3"}},
+		},
+		next: map[string]string{"": "c2", "c2": "c3"},
+	}
+}
+
+func TestSyncUser_ResumesFullSync(t *testing.T) {
+	db := &fakeStore{}
+	api := threePages()
+	api.fail = map[string]bool{"c3": true}
+	s := &Syncer{db: db, notion: api, log: slog.Default()}
+
+	// The first run dies on the last page, leaving its cursor saved
+	if _, err := s.SyncUser(context.Background(), "user1", true); err == nil {
+		t.Fatal("expected the interrupted sync to fail")
+	}
+	if db.cursor != "c3" || db.lastSync != nil {
+		t.Fatalf("after interruption: cursor=%q lastSync=%v, want c3 and nil", db.cursor, db.lastSync)
+	}
+
+	// An incremental run picks the full sync back up at the saved cursor
+	api.fail = nil
+	api.queried = nil
+	result, err := s.SyncUser(context.Background(), "user1", false)
+	if err != nil {
+		t.Fatalf("SyncUser: %v", err)
+	}
+	if diff := cmp.Diff([]string{"c3"}, api.queried); diff != "" {
+		t.Errorf("queried cursors mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"p1", "p2", "p3"}, db.upserted); diff != "" {
+		t.Errorf("upserted posts mismatch (-want +got):\n%s", diff)
+	}
+	if result.Created != 1 {
+		t.Errorf("resumed run created %d notes, want 1", result.Created)
+	}
+	if db.cursor != "" || db.lastSync == nil {
+		t.Errorf("after completion: cursor=%q lastSync=%v, want cleared cursor and a sync time", db.cursor, db.lastSync)
+	}
+}
+
+func TestSyncUser_InvalidCursorRestarts(t *testing.T) {
+	db := &fakeStore{cursor: "stale"}
+	api := threePages()
+	api.expired = map[string]bool{"stale": true}
+	s := &Syncer{db: db, notion: api, log: slog.Default()}
+
+	if _, err := s.SyncUser(context.Background(), "user1", false); err != nil {
+		t.Fatalf("SyncUser: %v", err)
+	}
+	if diff := cmp.Diff([]string{"stale", "", "c2", "c3"}, api.queried); diff != "" {
+		t.Errorf("queried cursors mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"c2", "c3"}, db.savedCursors); diff != "" {
+		t.Errorf("saved cursors mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSyncUser_DryRunDoesNotSaveCursor(t *testing.T) {
+	db := &fakeStore{}
+	s := &Syncer{db: db, notion: threePages(), log: slog.Default(), opts: Options{DryRun: true}}
+
+	result, err := s.SyncUser(context.Background(), "user1", true)
+	if err != nil {
+		t.Fatalf("SyncUser: %v", err)
+	}
+	if result.Created != 3 {
+		t.Errorf("dry run reported %d creates, want 3", result.Created)
+	}
+	if len(db.savedCursors) != 0 || len(db.upserted) != 0 || db.lastSync != nil {
+		t.Errorf("dry run wrote state: cursors=%v upserts=%v lastSync=%v", db.savedCursors, db.upserted, db.lastSync)
+	}
+}
+
+func TestSyncUser_IncrementalBuffer(t *testing.T) {
+	lastSync := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		buffer time.Duration
+		want   time.Time
+	}{
+		{name: "default", buffer: 0, want: lastSync.Add(-DefaultIncrementalBuffer)},
+		{name: "configured", buffer: 30 * time.Minute, want: lastSync.Add(-30 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeStore{lastSync: &lastSync}
+			api := &fakeNotion{}
+			s := &Syncer{db: db, notion: api, log: slog.Default(), opts: Options{IncrementalBuffer: tt.buffer}}
+
+			if _, err := s.SyncUser(context.Background(), "user-1", false); err != nil {
+				t.Fatalf("SyncUser: %v", err)
+			}
+			if len(api.since) != 1 || !api.since[0].Equal(tt.want) {
+				t.Errorf("ListPostsSince called with %v, want [%v]", api.since, tt.want)
+			}
+		})
+	}
+}
+
+// linkedNotes returns fake notes linked to the given Notion UUIDs, with note
+// IDs of "note-<uuid>"
+func linkedNotes(uuids ...string) map[string]*syncdb.Note {
+	notes := make(map[string]*syncdb.Note, len(uuids))
+	for _, uuid := range uuids {
+		notes[uuid] = &syncdb.Note{ID: "note-" + uuid, NotionUUID: &uuid}
+	}
+	return notes
+}
+
+func TestNotesMissingFromNotion(t *testing.T) {
+	uuid := func(s string) *string { return &s }
+	notes := []syncdb.Note{
+		{ID: "n1", NotionUUID: uuid("p1")},
+		{ID: "n2", NotionUUID: uuid("p2")},
+		{ID: "n3", NotionUUID: uuid("")},
+		{ID: "n4"},
+		{ID: "n5", NotionUUID: uuid("p5")},
+	}
+
+	tests := []struct {
+		name string
+		seen map[string]bool
+		want []string
+	}{
+		{name: "all seen", seen: map[string]bool{"p1": true, "p2": true, "p5": true}, want: nil},
+		{name: "some missing", seen: map[string]bool{"p2": true, "p9": true}, want: []string{"n1", "n5"}},
+		{name: "none seen", seen: map[string]bool{}, want: []string{"n1", "n2", "n5"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, notesMissingFromNotion(notes, tt.seen)); diff != "" {
+				t.Errorf("notesMissingFromNotion mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSyncUser_Prune(t *testing.T) {
+	lastSync := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		opts        Options
+		fullSync    bool
+		cursor      string
+		lastSync    *time.Time
+		api         *fakeNotion
+		wantPruned  int
+		wantErrors  int
+		wantDeleted []string
+	}{
+		{name: "full sync", opts: Options{Prune: true}, fullSync: true, api: threePages(), wantPruned: 2, wantDeleted: []string{"note-gone1", "note-gone2"}},
+		{name: "first sync", opts: Options{Prune: true}, api: threePages(), wantPruned: 2, wantDeleted: []string{"note-gone1", "note-gone2"}},
+		{name: "dry run", opts: Options{Prune: true, DryRun: true}, fullSync: true, api: threePages(), wantPruned: 2},
+		{name: "prune off", fullSync: true, api: threePages()},
+		{name: "incremental", opts: Options{Prune: true}, lastSync: &lastSync, api: threePages()},
+		{name: "resumed", opts: Options{Prune: true}, fullSync: true, cursor: "c2", api: threePages()},
+		{name: "empty listing", opts: Options{Prune: true}, fullSync: true, api: &fakeNotion{}},
+		{name: "over max fraction", opts: Options{Prune: true, MaxPruneFraction: 0.25}, fullSync: true, api: threePages(), wantErrors: 1},
+		{name: "over max fraction dry run", opts: Options{Prune: true, DryRun: true, MaxPruneFraction: 0.25}, fullSync: true, api: threePages(), wantErrors: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := &fakeStore{notes: linkedNotes("p1", "p2", "gone1", "gone2"), cursor: tt.cursor, lastSync: tt.lastSync}
+			s := &Syncer{db: db, notion: tt.api, log: slog.Default(), opts: tt.opts}
+
+			result, err := s.SyncUser(context.Background(), "user1", tt.fullSync)
+			if err != nil {
+				t.Fatalf("SyncUser: %v", err)
+			}
+			if result.Pruned != tt.wantPruned {
+				t.Errorf("pruned = %d, want %d", result.Pruned, tt.wantPruned)
+			}
+			if result.Errors != tt.wantErrors {
+				t.Errorf("errors = %d, want %d", result.Errors, tt.wantErrors)
+			}
+			if diff := cmp.Diff(tt.wantDeleted, db.deleted); diff != "" {
+				t.Errorf("deleted notes mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSyncUser_PruneDeletesMedia(t *testing.T) {
+	ctx := context.Background()
+	blobs := storage.NewMemory()
+	for _, name := range []string{"notes/note-p1/img", "notes/note-gone1/img", "notes/note-gone1/aud"} {
+		if _, err := blobs.UploadImage(ctx, name, []byte("x"), "image/png"); err != nil {
+			t.Fatalf("UploadImage: %v", err)
+		}
+	}
+
+	db := &fakeStore{
+		notes: linkedNotes("p1", "p2", "gone1", "gone2"),
+		media: map[string][]string{
+			"note-p1":    {"notes/note-p1/img"},
+			"note-gone1": {"notes/note-gone1/img", "notes/note-gone1/aud"},
+		},
+	}
+	s := &Syncer{db: db, notion: threePages(), log: slog.Default(), opts: Options{Prune: true, Storage: blobs}}
+
+	if _, err := s.SyncUser(ctx, "user1", true); err != nil {
+		t.Fatalf("SyncUser: %v", err)
+	}
+	if diff := cmp.Diff([]string{"notes/note-p1/img"}, blobs.Objects()); diff != "" {
+		t.Errorf("stored objects mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSyncPosts_ComparesPrefetchedNotes(t *testing.T) {
+	db := &fakeStore{notes: map[string]*syncdb.Note{
+		"same":     {ID: "n1", Content: "hello", Tags: []syncdb.Tag{{Name: "a"}, {Name: "b"}}},
+		"edited":   {ID: "n2", Content: "old", Tags: []syncdb.Tag{{Name: "a"}}},
+		"retagged": {ID: "n3", Content: "hello", Tags: []syncdb.Tag{{Name: "a"}}},
+	}}
+	s := &Syncer{db: db, log: slog.Default()}
+
+	result := &SyncResult{}
+	s.syncPosts("user1", []*notion.Post{
+		{ID: "same", Text: "hello", Tags: []string{"b", "a"}},
+		{ID: "edited", Text: "new", Tags: []string{"a"}},
+		{ID: "retagged", Text: "hello", Tags: []string{"c"}},
+		{ID: "fresh", Text: "hi"},
+	}, result)
+
+	if db.lookups != 1 {
+		t.Errorf("looked up existing notes %d times, want once per batch", db.lookups)
+	}
+	if diff := cmp.Diff(SyncResult{Created: 1, Updated: 2, Unchanged: 1}, *result); diff != "" {
+		t.Errorf("result mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSyncPosts_TagSync(t *testing.T) {
+	tests := []struct {
+		mode           TagSync
+		wantResult     SyncResult
+		wantTagUpdates []bool
+	}{
+		{mode: "", wantResult: SyncResult{Updated: 2}, wantTagUpdates: []bool{true, true}},
+		{mode: TagSyncFromNotion, wantResult: SyncResult{Updated: 2}, wantTagUpdates: []bool{true, true}},
+		{mode: TagSyncToNotion, wantResult: SyncResult{Updated: 1, Unchanged: 1}, wantTagUpdates: []bool{false, false}},
+		{mode: TagSyncNone, wantResult: SyncResult{Updated: 1, Unchanged: 1}, wantTagUpdates: []bool{false, false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			db := &fakeStore{notes: map[string]*syncdb.Note{
+				"edited":   {ID: "n1", Content: "old", Tags: []syncdb.Tag{{Name: "local"}}},
+				"retagged": {ID: "n2", Content: "hello", Tags: []syncdb.Tag{{Name: "local"}}},
+			}}
+			s := &Syncer{db: db, log: slog.Default(), opts: Options{SyncTags: tt.mode}}
+
+			result := &SyncResult{}
+			s.syncPosts("user1", []*notion.Post{
+				{ID: "edited", Text: "new", Tags: []string{"notion"}},
+				{ID: "retagged", Text: "hello", Tags: []string{"notion"}},
+			}, result)
+
+			if diff := cmp.Diff(tt.wantResult, *result); diff != "" {
+				t.Errorf("result mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantTagUpdates, db.tagUpdates); diff != "" {
+				t.Errorf("upsert updateTags mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSyncUserToNotion_TagSync(t *testing.T) {
+	pageID := "page-n2"
+	tests := []struct {
+		mode       TagSync
+		wantPushed []pushedTags
+	}{
+		{mode: "", wantPushed: []pushedTags{{Tags: []string{"a"}, UpdateTags: true}, {Tags: []string{"b"}, UpdateTags: true}}},
+		{mode: TagSyncToNotion, wantPushed: []pushedTags{{Tags: []string{"a"}, UpdateTags: true}, {Tags: []string{"b"}, UpdateTags: true}}},
+		{mode: TagSyncFromNotion, wantPushed: []pushedTags{{UpdateTags: true}, {UpdateTags: false}}},
+		{mode: TagSyncNone, wantPushed: []pushedTags{{UpdateTags: true}, {UpdateTags: false}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			db := &fakeStore{
+				pending: []syncdb.Note{{ID: "n1"}, {ID: "n2", ExternalID: &pageID}},
+				tags:    map[string][]string{"n1": {"a"}, "n2": {"b"}},
+			}
+			api := &fakeNotion{}
+			s := &Syncer{db: db, notion: api, log: slog.Default(), opts: Options{SyncTags: tt.mode, ToNotionPageSize: 10}}
+
+			if _, err := s.SyncUserToNotion(context.Background(), "user1"); err != nil {
+				t.Fatalf("SyncUserToNotion: %v", err)
+			}
+			if diff := cmp.Diff(tt.wantPushed, api.pushed); diff != "" {
+				t.Errorf("pushed tags mismatch (-want +got):\n%s", diff)
+			}
+			if !s.opts.tagsToNotion() && db.tagLookups != 0 {
+				t.Errorf("looked up local tags %d times, want none", db.tagLookups)
+			}
+		})
+	}
+}
+
+func TestSyncUserToNotion_Pages(t *testing.T) {
+	db := &fakeStore{pending: []syncdb.Note{{ID: "n1"}, {ID: "n2"}, {ID: "n3"}}}
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	s := &Syncer{db: db, notion: &fakeNotion{}, log: slog.Default(), opts: Options{ToNotionSince: since, ToNotionPageSize: 2}}
+
+	result, err := s.SyncUserToNotion(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("SyncUserToNotion: %v", err)
+	}
+	if result.Created != 3 {
+		t.Errorf("created %d Notion pages, want 3", result.Created)
+	}
+	want := []syncdb.ToNotionPage{
+		{Since: since, Limit: 2},
+		{AfterID: "n2", Since: since, Limit: 2},
+		{AfterID: "n3", Since: since, Limit: 2},
+	}
+	if diff := cmp.Diff(want, db.pages); diff != "" {
+		t.Errorf("requested pages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSyncUserToNotion_SkipsOversizedContent(t *testing.T) {
+	db := &fakeStore{pending: []syncdb.Note{{ID: "n1", Content: "héllo world"}, {ID: "n2", Content: "héllo"}}}
+	api := &fakeNotion{}
+	s := &Syncer{db: db, notion: api, log: slog.Default(), opts: Options{MaxContentLength: 5, ToNotionPageSize: 10}}
+
+	result, err := s.SyncUserToNotion(context.Background(), "user1")
+	if err != nil {
+		t.Fatalf("SyncUserToNotion: %v", err)
+	}
+	// A truncated page would be pulled back over the full note, so n1 is
+	// left out of Notion entirely
+	if diff := cmp.Diff([]string{"héllo"}, api.created); diff != "" {
+		t.Errorf("pushed content mismatch (-want +got):\n%s", diff)
+	}
+	if result.Created != 1 || result.Errors != 1 {
+		t.Errorf("created %d with %d errors, want 1 and 1", result.Created, result.Errors)
+	}
+}
+
+// fakeSigner signs every object except those listed in fail.
+type fakeSigner struct {
+	fail map[string]bool
+}
+
+func (f *fakeSigner) GetSignedURLsWithExpiry(ctx context.Context, objectNames []string, expiry time.Duration) (map[string]string, error) {
+	urls := map[string]string{}
+	var err error
+	for _, name := range objectNames {
+		if f.fail[name] {
+			err = errors.New("signing failed")
+			continue
+		}
+		urls[name] = "https://signed.example/" + name
+	}
+	return urls, err
+}
+
+func TestImageURLs(t *testing.T) {
+	images := []syncdb.NoteImage{
+		{GCSObjectName: "notes/n1/a", URL: "https://stored.example/a"},
+		{GCSObjectName: "notes/n1/b", URL: "https://stored.example/b"},
+	}
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{
+			name: "imgix",
+			opts: Options{ImgixDomain: "etu.imgix.net", Signer: &fakeSigner{}},
+			want: []string{"https://etu.imgix.net/notes/n1/a", "https://etu.imgix.net/notes/n1/b"},
+		},
+		{
+			name: "signed",
+			opts: Options{Signer: &fakeSigner{}},
+			want: []string{"https://signed.example/notes/n1/a", "https://signed.example/notes/n1/b"},
+		},
+		{
+			name: "signing failure falls back to stored URL",
+			opts: Options{Signer: &fakeSigner{fail: map[string]bool{"notes/n1/b": true}}},
+			want: []string{"https://signed.example/notes/n1/a", "https://stored.example/b"},
+		},
+		{
+			name: "no imgix or signer",
+			want: []string{"https://stored.example/a", "https://stored.example/b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Syncer{log: slog.Default(), opts: tt.opts}
+			got := s.imageURLs(context.Background(), images)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("imageURLs mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
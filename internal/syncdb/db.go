@@ -2,17 +2,19 @@ package syncdb
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log/slog"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/icco/etu-backend/internal/crypto"
+	"github.com/icco/etu-backend/internal/db"
 	"github.com/icco/etu-backend/internal/logger"
 	"github.com/icco/etu-backend/internal/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	gormlogger "gorm.io/gorm/logger"
 )
 
@@ -26,6 +28,7 @@ type DB struct {
 type Note = models.Note
 type Tag = models.Tag
 type NoteTag = models.NoteTag
+type NoteImage = models.NoteImage
 type User = models.User
 type SyncState = models.SyncState
 
@@ -66,10 +69,32 @@ func New() (*DB, error) {
 		return nil, fmt.Errorf("failed to get underlying sql.DB: %w", err)
 	}
 
-	sqlDB.SetMaxOpenConns(25)
-	sqlDB.SetMaxIdleConns(5)
-	sqlDB.SetConnMaxLifetime(5 * time.Minute)
+	poolConfig, err := db.PoolConfigFromEnv()
+	if err != nil {
+		return nil, fmt.Errorf("invalid connection pool settings: %w", err)
+	}
+	poolConfig.Apply(sqlDB)
+
+	log := logger.New()
+	log.Info("database connection pool configured",
+		"max_open_conns", poolConfig.MaxOpenConns,
+		"max_idle_conns", poolConfig.MaxIdleConns,
+		"conn_max_lifetime", poolConfig.ConnMaxLifetime.String())
+
+	return &DB{
+		conn: conn,
+		log:  log,
+	}, nil
+}
 
+// NewFromConn creates a DB from an existing *sql.DB (e.g. from sqlmock for testing).
+func NewFromConn(sqlDB *sql.DB) (*DB, error) {
+	conn, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
 	return &DB{
 		conn: conn,
 		log:  logger.New(),
@@ -128,8 +153,88 @@ func (db *DB) GetNoteByNotionUUID(userID, notionUUID string) (*Note, error) {
 	return &note, nil
 }
 
-// UpsertNoteFromNotion creates or updates a note from Notion data
-func (db *DB) UpsertNoteFromNotion(userID, notionUUID, pageID, content string, tagNames []string, createdAt, updatedAt time.Time) (*Note, bool, error) {
+// GetNotesByNotionUUIDs finds the user's notes with the given Notion UUIDs in
+// one query, keyed by UUID, with their tags loaded. UUIDs with no note are
+// absent from the map.
+func (db *DB) GetNotesByNotionUUIDs(userID string, notionUUIDs []string) (map[string]*Note, error) {
+	notes := make(map[string]*Note, len(notionUUIDs))
+	if len(notionUUIDs) == 0 {
+		return notes, nil
+	}
+
+	var found []Note
+	err := db.conn.
+		Preload("Tags").
+		Where(`"userId" = ? AND "notionUuid" IN ?`, userID, notionUUIDs).
+		Find(&found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range found {
+		if found[i].NotionUUID != nil {
+			notes[*found[i].NotionUUID] = &found[i]
+		}
+	}
+	return notes, nil
+}
+
+// GetNotionLinkedNotes returns the ID and Notion UUID of every note of the
+// user's that came from or was pushed to Notion
+func (db *DB) GetNotionLinkedNotes(userID string) ([]Note, error) {
+	var notes []Note
+	err := db.conn.
+		Select("id", "notionUuid").
+		Where(`"userId" = ? AND "notionUuid" IS NOT NULL AND "notionUuid" != ''`, userID).
+		Find(&notes).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Notion-linked notes: %w", err)
+	}
+	return notes, nil
+}
+
+// DeleteNotes deletes the given notes of the user's. It returns how many were
+// deleted and the storage object names of their images and audio files, which
+// the caller should delete from storage.
+func (db *DB) DeleteNotes(userID string, noteIDs []string) (int64, []string, error) {
+	if len(noteIDs) == 0 {
+		return 0, nil, nil
+	}
+
+	var deleted int64
+	var objectNames []string
+	err := db.conn.Transaction(func(tx *gorm.DB) error {
+		owned := tx.Model(&Note{}).Select("id").Where(`"userId" = ? AND id IN ?`, userID, noteIDs)
+		for _, model := range []interface{}{&NoteImage{}, &models.NoteAudio{}} {
+			var names []string
+			if err := tx.Model(model).Where(`"noteId" IN (?)`, owned).Pluck(`"gcsObjectName"`, &names).Error; err != nil {
+				return fmt.Errorf("failed to get media of notes: %w", err)
+			}
+			objectNames = append(objectNames, names...)
+		}
+
+		result := tx.Where(`"userId" = ? AND id IN ?`, userID, noteIDs).Delete(&Note{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete notes: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+	return deleted, objectNames, nil
+}
+
+// countWords is the API server's word counter, so synced notes store the same
+// wordCount as notes written through the API. It is bound here because the
+// *DB receivers below shadow the db package.
+var countWords = db.CountWords
+
+// UpsertNoteFromNotion creates or updates a note from Notion data. When
+// updateTags is false, tagNames is ignored and the note's tags are left as they
+// are; a new note is created without tags.
+func (db *DB) UpsertNoteFromNotion(userID, notionUUID, pageID, content string, tagNames []string, updateTags bool, createdAt, updatedAt time.Time) (*Note, bool, error) {
 	var note Note
 	var isNew bool
 
@@ -147,6 +252,7 @@ func (db *DB) UpsertNoteFromNotion(userID, notionUUID, pageID, content string, t
 			note = Note{
 				ID:         models.GenerateCUID(),
 				Content:    content,
+				WordCount:  int(countWords(content)),
 				CreatedAt:  createdAt,
 				UpdatedAt:  updatedAt,
 				UserID:     userID,
@@ -162,6 +268,7 @@ func (db *DB) UpsertNoteFromNotion(userID, notionUUID, pageID, content string, t
 			// Update existing note
 			isNew = false
 			note.Content = content
+			note.WordCount = int(countWords(content))
 			note.UpdatedAt = updatedAt
 			note.ExternalID = &pageID
 			note.NotionUUID = &notionUUID
@@ -170,33 +277,20 @@ func (db *DB) UpsertNoteFromNotion(userID, notionUUID, pageID, content string, t
 			}
 		}
 
+		if !updateTags {
+			return nil
+		}
+
 		// Clear existing tag associations
 		if err := tx.Where(`"noteId" = ?`, note.ID).Delete(&NoteTag{}).Error; err != nil {
 			return fmt.Errorf("failed to clear tag associations: %w", err)
 		}
 
 		// Create/find tags and associate them
-		for _, tagName := range tagNames {
-			tagName = strings.ToLower(strings.TrimSpace(tagName))
-			if tagName == "" {
-				continue
-			}
-
-			var tag Tag
-			result := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, tagName).First(&tag)
-			if result.Error == gorm.ErrRecordNotFound {
-				// Create new tag
-				tag = Tag{
-					ID:        models.GenerateCUID(),
-					Name:      tagName,
-					CreatedAt: time.Now(),
-					UserID:    userID,
-				}
-				if err := tx.Create(&tag).Error; err != nil {
-					return fmt.Errorf("failed to create tag: %w", err)
-				}
-			} else if result.Error != nil {
-				return result.Error
+		for _, tagName := range models.NormalizeTags(tagNames) {
+			tag, err := findOrCreateTag(tx, userID, tagName)
+			if err != nil {
+				return err
 			}
 
 			// Create association
@@ -219,7 +313,43 @@ func (db *DB) UpsertNoteFromNotion(userID, notionUUID, pageID, content string, t
 	return &note, isNew, nil
 }
 
-// GetLastSyncTime returns the last sync time for a user
+// findOrCreateTag returns the user's tag with the given normalized name,
+// creating it if needed. A concurrent insert of the same tag hits the unique
+// index on ("userId", lower(name)), so the insert is skipped and the existing
+// row returned.
+func findOrCreateTag(tx *gorm.DB, userID, name string) (Tag, error) {
+	var tag Tag
+	result := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, name).First(&tag)
+	if result.Error == nil {
+		return tag, nil
+	}
+	if result.Error != gorm.ErrRecordNotFound {
+		return tag, fmt.Errorf("failed to find tag: %w", result.Error)
+	}
+
+	tag = Tag{
+		ID:        models.GenerateCUID(),
+		Name:      name,
+		CreatedAt: time.Now(),
+		UserID:    userID,
+	}
+	result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tag)
+	if result.Error != nil {
+		return tag, fmt.Errorf("failed to create tag: %w", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		return tag, nil
+	}
+
+	tag = Tag{}
+	if err := tx.Where(`"userId" = ? AND LOWER(name) = ?`, userID, name).First(&tag).Error; err != nil {
+		return tag, fmt.Errorf("failed to find existing tag: %w", err)
+	}
+	return tag, nil
+}
+
+// GetLastSyncTime returns the last sync time for a user, or nil if no sync
+// has completed yet
 func (db *DB) GetLastSyncTime(userID string) (*time.Time, error) {
 	var state SyncState
 	result := db.conn.Where(`"userId" = ?`, userID).First(&state)
@@ -229,10 +359,46 @@ func (db *DB) GetLastSyncTime(userID string) (*time.Time, error) {
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	// A state row saved only for a full-sync cursor has no sync time yet
+	if state.LastSyncedAt.IsZero() {
+		return nil, nil
+	}
 	return &state.LastSyncedAt, nil
 }
 
-// UpdateLastSyncTime updates the last sync time for a user
+// GetFullSyncCursor returns the Notion cursor an interrupted full sync
+// stopped at, or "" if there is nothing to resume
+func (db *DB) GetFullSyncCursor(userID string) (string, error) {
+	var state SyncState
+	result := db.conn.Where(`"userId" = ?`, userID).First(&state)
+	if result.Error == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	if result.Error != nil {
+		return "", result.Error
+	}
+	if state.FullSyncCursor == nil {
+		return "", nil
+	}
+	return *state.FullSyncCursor, nil
+}
+
+// SaveFullSyncCursor records how far a full sync has got so it can resume
+// there. An empty cursor clears it. The last sync time is left alone.
+func (db *DB) SaveFullSyncCursor(userID, cursor string) error {
+	var value *string
+	if cursor != "" {
+		value = &cursor
+	}
+	state := SyncState{UserID: userID, FullSyncCursor: value}
+	return db.conn.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "userId"}},
+		DoUpdates: clause.AssignmentColumns([]string{"fullSyncCursor"}),
+	}).Create(&state).Error
+}
+
+// UpdateLastSyncTime updates the last sync time for a user and clears any
+// full-sync cursor, since the sync that just finished supersedes it
 func (db *DB) UpdateLastSyncTime(userID string, syncTime time.Time) error {
 	state := SyncState{
 		UserID:       userID,
@@ -241,6 +407,20 @@ func (db *DB) UpdateLastSyncTime(userID string, syncTime time.Time) error {
 	return db.conn.Save(&state).Error
 }
 
+// DeleteStaleSyncState removes the sync state of users who no longer have
+// a Notion key, or no longer exist, and returns how many rows were removed.
+// Otherwise an old watermark would make a re-added key sync incrementally
+// from where the old one stopped instead of fully.
+func (db *DB) DeleteStaleSyncState(ctx context.Context) (int64, error) {
+	result := db.conn.WithContext(ctx).
+		Where(`"userId" NOT IN (SELECT id FROM "User" WHERE "notionKey" IS NOT NULL AND "notionKey" != '')`).
+		Delete(&SyncState{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete stale sync state: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
 // GetNoteTags returns the tag names for a note
 func (db *DB) GetNoteTags(noteID string) ([]string, error) {
 	var tags []Tag
@@ -259,17 +439,45 @@ func (db *DB) GetNoteTags(noteID string) ([]string, error) {
 	return names, nil
 }
 
-// GetNotesNeedingSyncToNotion returns notes that have been modified locally
-// and need to be synced back to Notion.
+// DefaultToNotionPageSize is how many notes GetNotesNeedingSyncToNotion
+// returns when ToNotionPage.Limit is unset.
+const DefaultToNotionPageSize = 100
+
+// ToNotionPage selects one page of GetNotesNeedingSyncToNotion results.
+// Pages are keyed by note ID rather than offset because pushing a note to
+// Notion removes it from the result set, which would shift offsets.
+type ToNotionPage struct {
+	AfterID string    // Only notes with IDs after this one; the last ID of the previous page
+	Since   time.Time // Only notes updated at or after this time, if set
+	Limit   int       // Page size, DefaultToNotionPageSize if 0
+}
+
+// GetNotesNeedingSyncToNotion returns a page of notes, ordered by ID, that
+// have been modified locally and need to be synced back to Notion.
 // This includes:
 // - Notes without an ExternalID (never synced to Notion)
 // - Notes where UpdatedAt > LastSyncedToNotion (modified since last sync)
-func (db *DB) GetNotesNeedingSyncToNotion(userID string) ([]Note, error) {
+// Each note's images are loaded in display order.
+func (db *DB) GetNotesNeedingSyncToNotion(userID string, page ToNotionPage) ([]Note, error) {
+	limit := page.Limit
+	if limit <= 0 {
+		limit = DefaultToNotionPageSize
+	}
+
+	query := db.conn.
+		Preload("Images", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order(`position ASC, "createdAt" ASC`)
+		}).
+		Where(`"userId" = ? AND ("externalId" IS NULL OR "lastSyncedToNotion" IS NULL OR "updatedAt" > "lastSyncedToNotion")`, userID)
+	if page.AfterID != "" {
+		query = query.Where(`id > ?`, page.AfterID)
+	}
+	if !page.Since.IsZero() {
+		query = query.Where(`"updatedAt" >= ?`, page.Since)
+	}
+
 	var notes []Note
-	err := db.conn.
-		Where(`"userId" = ? AND ("externalId" IS NULL OR "lastSyncedToNotion" IS NULL OR "updatedAt" > "lastSyncedToNotion")`, userID).
-		Find(&notes).Error
-	if err != nil {
+	if err := query.Order("id ASC").Limit(limit).Find(&notes).Error; err != nil {
 		return nil, err
 	}
 	return notes, nil
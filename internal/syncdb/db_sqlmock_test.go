@@ -0,0 +1,192 @@
+package syncdb
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGetNotesNeedingSyncToNotion_DefaultLimit(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	// No notes come back, so GORM skips the Images preload
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE (.+) ORDER BY id ASC LIMIT \$2`).
+		WithArgs("user-1", DefaultToNotionPageSize).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := db.GetNotesNeedingSyncToNotion("user-1", ToNotionPage{}); err != nil {
+		t.Fatalf("GetNotesNeedingSyncToNotion: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestGetNotesNeedingSyncToNotion_Page(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	since := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	now := time.Now().UTC()
+
+	mock.ExpectQuery(`SELECT (.+) FROM "Note" WHERE (.+) AND id > \$2 AND "updatedAt" >= \$3 ORDER BY id ASC LIMIT \$4`).
+		WithArgs("user-1", "note-1", since, 25).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note-2", "hello", now, now, "user-1"))
+	mock.ExpectQuery(`SELECT (.+) FROM "NoteImage" WHERE "NoteImage"."noteId" = \$1 ORDER BY position ASC, "createdAt" ASC`).
+		WithArgs("note-2").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "noteId"}))
+
+	notes, err := db.GetNotesNeedingSyncToNotion("user-1", ToNotionPage{AfterID: "note-1", Since: since, Limit: 25})
+	if err != nil {
+		t.Fatalf("GetNotesNeedingSyncToNotion: %v", err)
+	}
+	if len(notes) != 1 || notes[0].ID != "note-2" {
+		t.Errorf("notes = %+v, want only note-2", notes)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestDeleteStaleSyncState(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM "SyncState" WHERE "userId" NOT IN \(SELECT id FROM "User" WHERE "notionKey" IS NOT NULL AND "notionKey" != ''\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	removed, err := db.DeleteStaleSyncState(context.Background())
+	if err != nil {
+		t.Fatalf("DeleteStaleSyncState: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestDeleteNotes(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	mock.ExpectQuery(`SELECT "id","notionUuid" FROM "Note" WHERE "userId" = \$1 AND "notionUuid" IS NOT NULL AND "notionUuid" != ''`).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "notionUuid"}).AddRow("note-1", "p1").AddRow("note-2", "p2"))
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT "gcsObjectName" FROM "NoteImage" WHERE "noteId" IN \(SELECT "id" FROM "Note" WHERE "userId" = \$1 AND id IN \(\$2,\$3\)\)`).
+		WithArgs("user-1", "note-1", "note-2").
+		WillReturnRows(sqlmock.NewRows([]string{"gcsObjectName"}).AddRow("notes/note-1/img"))
+	mock.ExpectQuery(`SELECT "gcsObjectName" FROM "NoteAudio" WHERE "noteId" IN \(SELECT "id" FROM "Note"`).
+		WithArgs("user-1", "note-1", "note-2").
+		WillReturnRows(sqlmock.NewRows([]string{"gcsObjectName"}).AddRow("notes/note-2/aud"))
+	mock.ExpectExec(`DELETE FROM "Note" WHERE "userId" = \$1 AND id IN \(\$2,\$3\)`).
+		WithArgs("user-1", "note-1", "note-2").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	notes, err := db.GetNotionLinkedNotes("user-1")
+	if err != nil {
+		t.Fatalf("GetNotionLinkedNotes: %v", err)
+	}
+	ids := make([]string, len(notes))
+	for i, note := range notes {
+		ids[i] = note.ID
+	}
+	deleted, objectNames, err := db.DeleteNotes("user-1", ids)
+	if err != nil {
+		t.Fatalf("DeleteNotes: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted)
+	}
+	if diff := cmp.Diff([]string{"notes/note-1/img", "notes/note-2/aud"}, objectNames); diff != "" {
+		t.Errorf("object names mismatch (-want +got):\n%s", diff)
+	}
+
+	// Nothing to delete makes no query
+	if deleted, objectNames, err := db.DeleteNotes("user-1", nil); err != nil || deleted != 0 || objectNames != nil {
+		t.Errorf("DeleteNotes(nil) = %d, %v, %v, want 0, nil, nil", deleted, objectNames, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
+
+func TestUpsertNoteFromNotion_KeepsTags(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("sqlmock.New: %v", err)
+	}
+	defer func() { _ = sqlDB.Close() }()
+
+	db, err := NewFromConn(sqlDB)
+	if err != nil {
+		t.Fatalf("NewFromConn: %v", err)
+	}
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT \* FROM "Note" WHERE "userId" = \$1 AND "notionUuid" = \$2`).
+		WithArgs("user-1", "uuid-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "content", "createdAt", "updatedAt", "userId"}).
+			AddRow("note-1", "old", now, now, "user-1"))
+	mock.ExpectExec(`UPDATE "Note" SET`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// No DELETE FROM "NoteTag" or tag lookups: the note's tags are kept
+	mock.ExpectCommit()
+
+	note, isNew, err := db.UpsertNoteFromNotion("user-1", "uuid-1", "page-1", "new", []string{"notion"}, false, now, now)
+	if err != nil {
+		t.Fatalf("UpsertNoteFromNotion: %v", err)
+	}
+	if isNew || note.Content != "new" {
+		t.Errorf("got isNew=%v content=%q, want an updated note with new content", isNew, note.Content)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled mock expectations: %v", err)
+	}
+}
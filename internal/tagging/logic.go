@@ -2,7 +2,8 @@ package tagging
 
 import (
 	"regexp"
-	"strings"
+
+	"github.com/icco/etu-backend/internal/models"
 )
 
 var hashtagRegex = regexp.MustCompile(`(?:^|\s)#([a-zA-Z][a-zA-Z0-9]*)`)
@@ -12,8 +13,8 @@ func BuildExistingTagContext(tags []string) (map[string]bool, []string) {
 	existingTagNames := make(map[string]bool, len(tags))
 	existingTagList := make([]string, 0, len(tags))
 	for _, tag := range tags {
-		lowerName := strings.ToLower(strings.TrimSpace(tag))
-		if lowerName == "" || existingTagNames[lowerName] {
+		lowerName, ok := models.NormalizeTag(tag)
+		if !ok || existingTagNames[lowerName] {
 			continue
 		}
 		existingTagNames[lowerName] = true
@@ -26,8 +27,8 @@ func BuildExistingTagContext(tags []string) (map[string]bool, []string) {
 func BuildExistingTagSet(tags []string) map[string]bool {
 	existing := make(map[string]bool, len(tags))
 	for _, tag := range tags {
-		name := strings.ToLower(strings.TrimSpace(tag))
-		if name == "" {
+		name, ok := models.NormalizeTag(tag)
+		if !ok {
 			continue
 		}
 		existing[name] = true
@@ -44,8 +45,8 @@ func ExtractHashtags(content string) []string {
 		if len(match) < 2 {
 			continue
 		}
-		tag := strings.ToLower(match[1])
-		if seen[tag] {
+		tag, ok := models.NormalizeTag(match[1])
+		if !ok || seen[tag] {
 			continue
 		}
 		seen[tag] = true
@@ -85,8 +86,8 @@ func SelectGeneratedTags(generatedTags []string, existingNoteTagNames map[string
 	otherTags := make([]string, 0, len(generatedTags))
 
 	for _, tag := range generatedTags {
-		normalized := strings.ToLower(strings.TrimSpace(tag))
-		if normalized == "" || existingNoteTagNames[normalized] {
+		normalized, ok := models.NormalizeTag(tag)
+		if !ok || existingNoteTagNames[normalized] {
 			continue
 		}
 		existingNoteTagNames[normalized] = true
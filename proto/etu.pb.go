@@ -87,13 +87,77 @@ func (DisabledReason) EnumDescriptor() ([]byte, []int) {
 	return file_proto_etu_proto_rawDescGZIP(), []int{0}
 }
 
+// NoteEventType identifies what happened to a note.
+type NoteEventType int32
+
+const (
+	// NOTE_EVENT_TYPE_UNSPECIFIED is never sent.
+	NoteEventType_NOTE_EVENT_TYPE_UNSPECIFIED NoteEventType = 0
+	// NOTE_EVENT_TYPE_CREATED indicates a new note.
+	NoteEventType_NOTE_EVENT_TYPE_CREATED NoteEventType = 1
+	// NOTE_EVENT_TYPE_UPDATED indicates a change to a note's content or media.
+	NoteEventType_NOTE_EVENT_TYPE_UPDATED NoteEventType = 2
+	// NOTE_EVENT_TYPE_DELETED indicates the note was deleted or merged away.
+	NoteEventType_NOTE_EVENT_TYPE_DELETED NoteEventType = 3
+	// NOTE_EVENT_TYPE_TAGS_CHANGED indicates the note's tags were replaced, so
+	// cached tag lists and counts may be stale.
+	NoteEventType_NOTE_EVENT_TYPE_TAGS_CHANGED NoteEventType = 4
+)
+
+// Enum value maps for NoteEventType.
+var (
+	NoteEventType_name = map[int32]string{
+		0: "NOTE_EVENT_TYPE_UNSPECIFIED",
+		1: "NOTE_EVENT_TYPE_CREATED",
+		2: "NOTE_EVENT_TYPE_UPDATED",
+		3: "NOTE_EVENT_TYPE_DELETED",
+		4: "NOTE_EVENT_TYPE_TAGS_CHANGED",
+	}
+	NoteEventType_value = map[string]int32{
+		"NOTE_EVENT_TYPE_UNSPECIFIED":  0,
+		"NOTE_EVENT_TYPE_CREATED":      1,
+		"NOTE_EVENT_TYPE_UPDATED":      2,
+		"NOTE_EVENT_TYPE_DELETED":      3,
+		"NOTE_EVENT_TYPE_TAGS_CHANGED": 4,
+	}
+)
+
+func (x NoteEventType) Enum() *NoteEventType {
+	p := new(NoteEventType)
+	*p = x
+	return p
+}
+
+func (x NoteEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NoteEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_etu_proto_enumTypes[1].Descriptor()
+}
+
+func (NoteEventType) Type() protoreflect.EnumType {
+	return &file_proto_etu_proto_enumTypes[1]
+}
+
+func (x NoteEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NoteEventType.Descriptor instead.
+func (NoteEventType) EnumDescriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{1}
+}
+
 // ImageUpload contains raw image bytes provided by the client for upload.
 type ImageUpload struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// data is the raw binary payload of the image file.
 	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
 	// mime_type is the image media type, for example "image/jpeg".
-	MimeType      string `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	MimeType string `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
+	// caption is optional human-written alt text describing the image.
+	Caption       string `protobuf:"bytes,3,opt,name=caption,proto3" json:"caption,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -142,6 +206,13 @@ func (x *ImageUpload) GetMimeType() string {
 	return ""
 }
 
+func (x *ImageUpload) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
 // AudioUpload contains raw audio bytes provided by the client for upload.
 type AudioUpload struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -209,7 +280,13 @@ type NoteImage struct {
 	// mime_type is the media type of the stored image.
 	MimeType string `protobuf:"bytes,4,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"`
 	// created_at is when the image attachment was created.
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// caption is human-written alt text describing the image.
+	Caption string `protobuf:"bytes,6,opt,name=caption,proto3" json:"caption,omitempty"`
+	// width is the image width in pixels, or 0 if unknown.
+	Width int32 `protobuf:"varint,7,opt,name=width,proto3" json:"width,omitempty"`
+	// height is the image height in pixels, or 0 if unknown.
+	Height        int32 `protobuf:"varint,8,opt,name=height,proto3" json:"height,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -279,6 +356,27 @@ func (x *NoteImage) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *NoteImage) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
+func (x *NoteImage) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *NoteImage) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
 // NoteAudio represents an audio attachment associated with a note.
 type NoteAudio struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -377,7 +475,21 @@ type Note struct {
 	// images lists image attachments associated with the note.
 	Images []*NoteImage `protobuf:"bytes,6,rep,name=images,proto3" json:"images,omitempty"`
 	// audios lists audio attachments associated with the note.
-	Audios        []*NoteAudio `protobuf:"bytes,7,rep,name=audios,proto3" json:"audios,omitempty"`
+	Audios []*NoteAudio `protobuf:"bytes,7,rep,name=audios,proto3" json:"audios,omitempty"`
+	// word_count is the number of whitespace-separated words in content.
+	WordCount int64 `protobuf:"varint,8,opt,name=word_count,json=wordCount,proto3" json:"word_count,omitempty"`
+	// char_count is the number of characters (Unicode code points) in content.
+	CharCount int64 `protobuf:"varint,9,opt,name=char_count,json=charCount,proto3" json:"char_count,omitempty"`
+	// pinned marks the note to be listed before unpinned notes.
+	Pinned bool `protobuf:"varint,10,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	// color is the label color assigned to the note, or empty for none.
+	Color string `protobuf:"bytes,11,opt,name=color,proto3" json:"color,omitempty"`
+	// summary is a one-line AI summary of a long note, or empty if none has
+	// been generated yet.
+	Summary string `protobuf:"bytes,12,opt,name=summary,proto3" json:"summary,omitempty"`
+	// mood is the AI-detected mood of the note, such as "happy" or "anxious",
+	// or empty if it hasn't been analyzed yet.
+	Mood          string `protobuf:"bytes,13,opt,name=mood,proto3" json:"mood,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -461,6 +573,48 @@ func (x *Note) GetAudios() []*NoteAudio {
 	return nil
 }
 
+func (x *Note) GetWordCount() int64 {
+	if x != nil {
+		return x.WordCount
+	}
+	return 0
+}
+
+func (x *Note) GetCharCount() int64 {
+	if x != nil {
+		return x.CharCount
+	}
+	return 0
+}
+
+func (x *Note) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+func (x *Note) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Note) GetSummary() string {
+	if x != nil {
+		return x.Summary
+	}
+	return ""
+}
+
+func (x *Note) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
 // Tag represents a user tag and optional usage count in list responses.
 type Tag struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -563,8 +717,17 @@ type User struct {
 	DisabledReason *DisabledReason `protobuf:"varint,13,opt,name=disabled_reason,json=disabledReason,proto3,enum=etu.DisabledReason,oneof" json:"disabled_reason,omitempty"`
 	// notion_database_name is the Notion database used for sync.
 	NotionDatabaseName *string `protobuf:"bytes,14,opt,name=notion_database_name,json=notionDatabaseName,proto3,oneof" json:"notion_database_name,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// sync_interval_minutes is the minimum time between Notion syncs for this
+	// user. Unset means the sync job's own interval.
+	SyncIntervalMinutes *int32 `protobuf:"varint,15,opt,name=sync_interval_minutes,json=syncIntervalMinutes,proto3,oneof" json:"sync_interval_minutes,omitempty"`
+	// is_premium reports whether subscription_status is a premium tier
+	// ("active" or "trialing"), regardless of subscription_end.
+	IsPremium bool `protobuf:"varint,16,opt,name=is_premium,json=isPremium,proto3" json:"is_premium,omitempty"`
+	// is_active reports whether the user is premium and subscription_end has
+	// not passed. Use this to gate premium features.
+	IsActive      bool `protobuf:"varint,17,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *User) Reset() {
@@ -688,6 +851,27 @@ func (x *User) GetNotionDatabaseName() string {
 	return ""
 }
 
+func (x *User) GetSyncIntervalMinutes() int32 {
+	if x != nil && x.SyncIntervalMinutes != nil {
+		return *x.SyncIntervalMinutes
+	}
+	return 0
+}
+
+func (x *User) GetIsPremium() bool {
+	if x != nil {
+		return x.IsPremium
+	}
+	return false
+}
+
+func (x *User) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
 // ApiKey represents API key metadata returned to clients.
 type ApiKey struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -700,7 +884,10 @@ type ApiKey struct {
 	// created_at is when the API key was created.
 	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	// last_used is when the API key was most recently used.
-	LastUsed      *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_used,json=lastUsed,proto3,oneof" json:"last_used,omitempty"`
+	LastUsed *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_used,json=lastUsed,proto3,oneof" json:"last_used,omitempty"`
+	// usage_count is how many requests the key has authenticated. Counts from
+	// the last few seconds may not be included yet.
+	UsageCount    int64 `protobuf:"varint,6,opt,name=usage_count,json=usageCount,proto3" json:"usage_count,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -770,6 +957,104 @@ func (x *ApiKey) GetLastUsed() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *ApiKey) GetUsageCount() int64 {
+	if x != nil {
+		return x.UsageCount
+	}
+	return 0
+}
+
+// LoginEvent is an audit record of one authentication attempt.
+type LoginEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is the unique identifier of the event.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// success is true when the attempt authenticated.
+	Success bool `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	// auth_type is how the caller authenticated: "password" or "apikey".
+	AuthType string `protobuf:"bytes,3,opt,name=auth_type,json=authType,proto3" json:"auth_type,omitempty"`
+	// ip_address is the client address, empty when unknown.
+	IpAddress string `protobuf:"bytes,4,opt,name=ip_address,json=ipAddress,proto3" json:"ip_address,omitempty"`
+	// user_agent is the client user agent, empty when unknown.
+	UserAgent string `protobuf:"bytes,5,opt,name=user_agent,json=userAgent,proto3" json:"user_agent,omitempty"`
+	// created_at is when the attempt happened.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LoginEvent) Reset() {
+	*x = LoginEvent{}
+	mi := &file_proto_etu_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LoginEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LoginEvent) ProtoMessage() {}
+
+func (x *LoginEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LoginEvent.ProtoReflect.Descriptor instead.
+func (*LoginEvent) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LoginEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *LoginEvent) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *LoginEvent) GetAuthType() string {
+	if x != nil {
+		return x.AuthType
+	}
+	return ""
+}
+
+func (x *LoginEvent) GetIpAddress() string {
+	if x != nil {
+		return x.IpAddress
+	}
+	return ""
+}
+
+func (x *LoginEvent) GetUserAgent() string {
+	if x != nil {
+		return x.UserAgent
+	}
+	return ""
+}
+
+func (x *LoginEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 // ListNotesRequest defines filters and pagination for listing notes.
 //
 // user_id is currently accepted for compatibility but is expected to match the
@@ -789,14 +1074,33 @@ type ListNotesRequest struct {
 	// limit is the maximum number of results to return.
 	Limit int32 `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
 	// offset is the number of results to skip before returning rows.
-	Offset        int32 `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	Offset int32 `protobuf:"varint,7,opt,name=offset,proto3" json:"offset,omitempty"`
+	// search_captions also matches search text against image captions.
+	SearchCaptions bool `protobuf:"varint,8,opt,name=search_captions,json=searchCaptions,proto3" json:"search_captions,omitempty"`
+	// search_media also matches search text against image OCR text and audio transcripts.
+	SearchMedia bool `protobuf:"varint,9,opt,name=search_media,json=searchMedia,proto3" json:"search_media,omitempty"`
+	// color limits results to notes with this label color when set.
+	Color string `protobuf:"bytes,10,opt,name=color,proto3" json:"color,omitempty"`
+	// sort_by orders results by "created_at" (default), "updated_at", or
+	// "word_count". Pinned notes always come first.
+	SortBy string `protobuf:"bytes,11,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`
+	// sort_dir is "desc" (default) or "asc".
+	SortDir string `protobuf:"bytes,12,opt,name=sort_dir,json=sortDir,proto3" json:"sort_dir,omitempty"`
+	// include_tags loads the notes' tags. Defaults to true; set false to skip
+	// the query when tags aren't shown.
+	IncludeTags *bool `protobuf:"varint,13,opt,name=include_tags,json=includeTags,proto3,oneof" json:"include_tags,omitempty"`
+	// include_images loads the notes' images. Defaults to true; set false to
+	// skip the query and URL signing, e.g. for text-only previews.
+	IncludeImages *bool `protobuf:"varint,14,opt,name=include_images,json=includeImages,proto3,oneof" json:"include_images,omitempty"`
+	// include_audios loads the notes' audio files. Defaults to true.
+	IncludeAudios *bool `protobuf:"varint,15,opt,name=include_audios,json=includeAudios,proto3,oneof" json:"include_audios,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListNotesRequest) Reset() {
 	*x = ListNotesRequest{}
-	mi := &file_proto_etu_proto_msgTypes[8]
+	mi := &file_proto_etu_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -808,7 +1112,7 @@ func (x *ListNotesRequest) String() string {
 func (*ListNotesRequest) ProtoMessage() {}
 
 func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[8]
+	mi := &file_proto_etu_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -821,7 +1125,7 @@ func (x *ListNotesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListNotesRequest.ProtoReflect.Descriptor instead.
 func (*ListNotesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{8}
+	return file_proto_etu_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ListNotesRequest) GetUserId() string {
@@ -873,6 +1177,62 @@ func (x *ListNotesRequest) GetOffset() int32 {
 	return 0
 }
 
+func (x *ListNotesRequest) GetSearchCaptions() bool {
+	if x != nil {
+		return x.SearchCaptions
+	}
+	return false
+}
+
+func (x *ListNotesRequest) GetSearchMedia() bool {
+	if x != nil {
+		return x.SearchMedia
+	}
+	return false
+}
+
+func (x *ListNotesRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *ListNotesRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *ListNotesRequest) GetSortDir() string {
+	if x != nil {
+		return x.SortDir
+	}
+	return ""
+}
+
+func (x *ListNotesRequest) GetIncludeTags() bool {
+	if x != nil && x.IncludeTags != nil {
+		return *x.IncludeTags
+	}
+	return false
+}
+
+func (x *ListNotesRequest) GetIncludeImages() bool {
+	if x != nil && x.IncludeImages != nil {
+		return *x.IncludeImages
+	}
+	return false
+}
+
+func (x *ListNotesRequest) GetIncludeAudios() bool {
+	if x != nil && x.IncludeAudios != nil {
+		return *x.IncludeAudios
+	}
+	return false
+}
+
 // ListNotesResponse returns a page of notes and paging metadata.
 type ListNotesResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -883,14 +1243,17 @@ type ListNotesResponse struct {
 	// limit echoes the effective page size.
 	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
 	// offset echoes the page offset.
-	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	Offset int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	// total_unfiltered is the user's total note count ignoring filters. It
+	// equals total when no filters are set.
+	TotalUnfiltered int32 `protobuf:"varint,5,opt,name=total_unfiltered,json=totalUnfiltered,proto3" json:"total_unfiltered,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ListNotesResponse) Reset() {
 	*x = ListNotesResponse{}
-	mi := &file_proto_etu_proto_msgTypes[9]
+	mi := &file_proto_etu_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -902,7 +1265,7 @@ func (x *ListNotesResponse) String() string {
 func (*ListNotesResponse) ProtoMessage() {}
 
 func (x *ListNotesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[9]
+	mi := &file_proto_etu_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -915,7 +1278,7 @@ func (x *ListNotesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListNotesResponse.ProtoReflect.Descriptor instead.
 func (*ListNotesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{9}
+	return file_proto_etu_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ListNotesResponse) GetNotes() []*Note {
@@ -946,38 +1309,51 @@ func (x *ListNotesResponse) GetOffset() int32 {
 	return 0
 }
 
-// CreateNoteRequest defines payload required to create a note.
-type CreateNoteRequest struct {
+func (x *ListNotesResponse) GetTotalUnfiltered() int32 {
+	if x != nil {
+		return x.TotalUnfiltered
+	}
+	return 0
+}
+
+// CountNotesRequest filters the notes to count, like ListNotesRequest.
+type CountNotesRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// user_id is the target user identifier.
 	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	// content is the note body text to store.
-	Content string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	// tags are initial tag names to associate with the note.
+	// search is free-text search input and may include tag: filters.
+	Search string `protobuf:"bytes,2,opt,name=search,proto3" json:"search,omitempty"`
+	// tags are additional tag names to filter by.
 	Tags []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
-	// images are image files to attach during note creation.
-	Images []*ImageUpload `protobuf:"bytes,4,rep,name=images,proto3" json:"images,omitempty"`
-	// audios are audio files to attach during note creation.
-	Audios        []*AudioUpload `protobuf:"bytes,5,rep,name=audios,proto3" json:"audios,omitempty"`
+	// start_date is an inclusive lower bound timestamp in ISO 8601 format.
+	StartDate string `protobuf:"bytes,4,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is an inclusive upper bound timestamp in ISO 8601 format.
+	EndDate string `protobuf:"bytes,5,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	// search_captions also matches search text against image captions.
+	SearchCaptions bool `protobuf:"varint,6,opt,name=search_captions,json=searchCaptions,proto3" json:"search_captions,omitempty"`
+	// search_media also matches search text against image OCR text and audio transcripts.
+	SearchMedia bool `protobuf:"varint,7,opt,name=search_media,json=searchMedia,proto3" json:"search_media,omitempty"`
+	// color limits the count to notes with this label color when set.
+	Color         string `protobuf:"bytes,8,opt,name=color,proto3" json:"color,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateNoteRequest) Reset() {
-	*x = CreateNoteRequest{}
-	mi := &file_proto_etu_proto_msgTypes[10]
+func (x *CountNotesRequest) Reset() {
+	*x = CountNotesRequest{}
+	mi := &file_proto_etu_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateNoteRequest) String() string {
+func (x *CountNotesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateNoteRequest) ProtoMessage() {}
+func (*CountNotesRequest) ProtoMessage() {}
 
-func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[10]
+func (x *CountNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -988,37 +1364,200 @@ func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
-func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use CountNotesRequest.ProtoReflect.Descriptor instead.
+func (*CountNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *CreateNoteRequest) GetUserId() string {
+func (x *CountNotesRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *CreateNoteRequest) GetContent() string {
+func (x *CountNotesRequest) GetSearch() string {
 	if x != nil {
-		return x.Content
+		return x.Search
 	}
 	return ""
 }
 
-func (x *CreateNoteRequest) GetTags() []string {
+func (x *CountNotesRequest) GetTags() []string {
 	if x != nil {
 		return x.Tags
 	}
 	return nil
 }
 
-func (x *CreateNoteRequest) GetImages() []*ImageUpload {
+func (x *CountNotesRequest) GetStartDate() string {
 	if x != nil {
-		return x.Images
+		return x.StartDate
 	}
-	return nil
+	return ""
+}
+
+func (x *CountNotesRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+func (x *CountNotesRequest) GetSearchCaptions() bool {
+	if x != nil {
+		return x.SearchCaptions
+	}
+	return false
+}
+
+func (x *CountNotesRequest) GetSearchMedia() bool {
+	if x != nil {
+		return x.SearchMedia
+	}
+	return false
+}
+
+func (x *CountNotesRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+// CountNotesResponse returns the number of matching notes.
+type CountNotesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// total is the number of notes matching the filters.
+	Total         int32 `protobuf:"varint,1,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountNotesResponse) Reset() {
+	*x = CountNotesResponse{}
+	mi := &file_proto_etu_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountNotesResponse) ProtoMessage() {}
+
+func (x *CountNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountNotesResponse.ProtoReflect.Descriptor instead.
+func (*CountNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CountNotesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// CreateNoteRequest defines payload required to create a note.
+type CreateNoteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// content is the note body text to store.
+	Content string `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// tags are initial tag names to associate with the note.
+	Tags []string `protobuf:"bytes,3,rep,name=tags,proto3" json:"tags,omitempty"`
+	// images are image files to attach during note creation.
+	Images []*ImageUpload `protobuf:"bytes,4,rep,name=images,proto3" json:"images,omitempty"`
+	// audios are audio files to attach during note creation.
+	Audios []*AudioUpload `protobuf:"bytes,5,rep,name=audios,proto3" json:"audios,omitempty"`
+	// extract_text_sync runs OCR on attached images before returning instead of
+	// leaving it to the background job. Ignored when AI is not configured.
+	ExtractTextSync bool `protobuf:"varint,6,opt,name=extract_text_sync,json=extractTextSync,proto3" json:"extract_text_sync,omitempty"`
+	// idempotency_key makes retries safe: if a note was already created with
+	// this key in the last 24 hours, that note is returned instead of creating
+	// another. Leave empty for a non-idempotent create.
+	IdempotencyKey string `protobuf:"bytes,7,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// color is the label color to assign: red, orange, yellow, green, teal,
+	// blue, purple, pink, or gray. Empty assigns none.
+	Color string `protobuf:"bytes,8,opt,name=color,proto3" json:"color,omitempty"`
+	// created_at backdates the note, e.g. when importing old entries. Unset
+	// means now. It may be at most a few minutes in the future to allow for
+	// clock skew, and cannot be combined with idempotency_key.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateNoteRequest) Reset() {
+	*x = CreateNoteRequest{}
+	mi := &file_proto_etu_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateNoteRequest) ProtoMessage() {}
+
+func (x *CreateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateNoteRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateNoteRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateNoteRequest) GetImages() []*ImageUpload {
+	if x != nil {
+		return x.Images
+	}
+	return nil
 }
 
 func (x *CreateNoteRequest) GetAudios() []*AudioUpload {
@@ -1028,6 +1567,34 @@ func (x *CreateNoteRequest) GetAudios() []*AudioUpload {
 	return nil
 }
 
+func (x *CreateNoteRequest) GetExtractTextSync() bool {
+	if x != nil {
+		return x.ExtractTextSync
+	}
+	return false
+}
+
+func (x *CreateNoteRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *CreateNoteRequest) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
 // CreateNoteResponse returns the created note.
 type CreateNoteResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1038,7 +1605,7 @@ type CreateNoteResponse struct {
 
 func (x *CreateNoteResponse) Reset() {
 	*x = CreateNoteResponse{}
-	mi := &file_proto_etu_proto_msgTypes[11]
+	mi := &file_proto_etu_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1050,7 +1617,7 @@ func (x *CreateNoteResponse) String() string {
 func (*CreateNoteResponse) ProtoMessage() {}
 
 func (x *CreateNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[11]
+	mi := &file_proto_etu_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1063,7 +1630,7 @@ func (x *CreateNoteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateNoteResponse.ProtoReflect.Descriptor instead.
 func (*CreateNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{11}
+	return file_proto_etu_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *CreateNoteResponse) GetNote() *Note {
@@ -1079,14 +1646,28 @@ type GetNoteRequest struct {
 	// user_id is the target user identifier.
 	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	// id is the unique identifier of the note to fetch.
-	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// url_expiry_seconds sets how long signed media URLs stay valid. Zero uses
+	// the server default; values are capped at 7 days.
+	UrlExpirySeconds int32 `protobuf:"varint,3,opt,name=url_expiry_seconds,json=urlExpirySeconds,proto3" json:"url_expiry_seconds,omitempty"`
+	// count_image_text adds the images' extracted OCR text to the note's
+	// word_count and char_count. It requires images to be included.
+	CountImageText bool `protobuf:"varint,4,opt,name=count_image_text,json=countImageText,proto3" json:"count_image_text,omitempty"`
+	// include_tags loads the note's tags. Defaults to true; set false to skip
+	// the query when tags aren't shown.
+	IncludeTags *bool `protobuf:"varint,5,opt,name=include_tags,json=includeTags,proto3,oneof" json:"include_tags,omitempty"`
+	// include_images loads the note's images. Defaults to true; set false to
+	// skip the query and URL signing, e.g. for text-only previews.
+	IncludeImages *bool `protobuf:"varint,6,opt,name=include_images,json=includeImages,proto3,oneof" json:"include_images,omitempty"`
+	// include_audios loads the note's audio files. Defaults to true.
+	IncludeAudios *bool `protobuf:"varint,7,opt,name=include_audios,json=includeAudios,proto3,oneof" json:"include_audios,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetNoteRequest) Reset() {
 	*x = GetNoteRequest{}
-	mi := &file_proto_etu_proto_msgTypes[12]
+	mi := &file_proto_etu_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1098,7 +1679,7 @@ func (x *GetNoteRequest) String() string {
 func (*GetNoteRequest) ProtoMessage() {}
 
 func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[12]
+	mi := &file_proto_etu_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1111,7 +1692,7 @@ func (x *GetNoteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetNoteRequest.ProtoReflect.Descriptor instead.
 func (*GetNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{12}
+	return file_proto_etu_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetNoteRequest) GetUserId() string {
@@ -1128,6 +1709,41 @@ func (x *GetNoteRequest) GetId() string {
 	return ""
 }
 
+func (x *GetNoteRequest) GetUrlExpirySeconds() int32 {
+	if x != nil {
+		return x.UrlExpirySeconds
+	}
+	return 0
+}
+
+func (x *GetNoteRequest) GetCountImageText() bool {
+	if x != nil {
+		return x.CountImageText
+	}
+	return false
+}
+
+func (x *GetNoteRequest) GetIncludeTags() bool {
+	if x != nil && x.IncludeTags != nil {
+		return *x.IncludeTags
+	}
+	return false
+}
+
+func (x *GetNoteRequest) GetIncludeImages() bool {
+	if x != nil && x.IncludeImages != nil {
+		return *x.IncludeImages
+	}
+	return false
+}
+
+func (x *GetNoteRequest) GetIncludeAudios() bool {
+	if x != nil && x.IncludeAudios != nil {
+		return *x.IncludeAudios
+	}
+	return false
+}
+
 // GetNoteResponse returns the requested note when found.
 type GetNoteResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1138,7 +1754,7 @@ type GetNoteResponse struct {
 
 func (x *GetNoteResponse) Reset() {
 	*x = GetNoteResponse{}
-	mi := &file_proto_etu_proto_msgTypes[13]
+	mi := &file_proto_etu_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1150,7 +1766,7 @@ func (x *GetNoteResponse) String() string {
 func (*GetNoteResponse) ProtoMessage() {}
 
 func (x *GetNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[13]
+	mi := &file_proto_etu_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1163,7 +1779,7 @@ func (x *GetNoteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetNoteResponse.ProtoReflect.Descriptor instead.
 func (*GetNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{13}
+	return file_proto_etu_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *GetNoteResponse) GetNote() *Note {
@@ -1189,14 +1805,19 @@ type UpdateNoteRequest struct {
 	// add_images appends new image attachments to the note.
 	AddImages []*ImageUpload `protobuf:"bytes,6,rep,name=add_images,json=addImages,proto3" json:"add_images,omitempty"`
 	// add_audios appends new audio attachments to the note.
-	AddAudios     []*AudioUpload `protobuf:"bytes,7,rep,name=add_audios,json=addAudios,proto3" json:"add_audios,omitempty"`
+	AddAudios []*AudioUpload `protobuf:"bytes,7,rep,name=add_audios,json=addAudios,proto3" json:"add_audios,omitempty"`
+	// extract_text_sync runs OCR on add_images before returning instead of
+	// leaving it to the background job. Ignored when AI is not configured.
+	ExtractTextSync bool `protobuf:"varint,8,opt,name=extract_text_sync,json=extractTextSync,proto3" json:"extract_text_sync,omitempty"`
+	// color updates the label color when provided; empty clears it.
+	Color         *string `protobuf:"bytes,9,opt,name=color,proto3,oneof" json:"color,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateNoteRequest) Reset() {
 	*x = UpdateNoteRequest{}
-	mi := &file_proto_etu_proto_msgTypes[14]
+	mi := &file_proto_etu_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1208,7 +1829,7 @@ func (x *UpdateNoteRequest) String() string {
 func (*UpdateNoteRequest) ProtoMessage() {}
 
 func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[14]
+	mi := &file_proto_etu_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1221,7 +1842,7 @@ func (x *UpdateNoteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateNoteRequest.ProtoReflect.Descriptor instead.
 func (*UpdateNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{14}
+	return file_proto_etu_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *UpdateNoteRequest) GetUserId() string {
@@ -1273,6 +1894,20 @@ func (x *UpdateNoteRequest) GetAddAudios() []*AudioUpload {
 	return nil
 }
 
+func (x *UpdateNoteRequest) GetExtractTextSync() bool {
+	if x != nil {
+		return x.ExtractTextSync
+	}
+	return false
+}
+
+func (x *UpdateNoteRequest) GetColor() string {
+	if x != nil && x.Color != nil {
+		return *x.Color
+	}
+	return ""
+}
+
 // UpdateNoteResponse returns the updated note.
 type UpdateNoteResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -1283,7 +1918,7 @@ type UpdateNoteResponse struct {
 
 func (x *UpdateNoteResponse) Reset() {
 	*x = UpdateNoteResponse{}
-	mi := &file_proto_etu_proto_msgTypes[15]
+	mi := &file_proto_etu_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1295,7 +1930,7 @@ func (x *UpdateNoteResponse) String() string {
 func (*UpdateNoteResponse) ProtoMessage() {}
 
 func (x *UpdateNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[15]
+	mi := &file_proto_etu_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1308,7 +1943,7 @@ func (x *UpdateNoteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateNoteResponse.ProtoReflect.Descriptor instead.
 func (*UpdateNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{15}
+	return file_proto_etu_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *UpdateNoteResponse) GetNote() *Note {
@@ -1331,7 +1966,7 @@ type DeleteNoteRequest struct {
 
 func (x *DeleteNoteRequest) Reset() {
 	*x = DeleteNoteRequest{}
-	mi := &file_proto_etu_proto_msgTypes[16]
+	mi := &file_proto_etu_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1343,7 +1978,7 @@ func (x *DeleteNoteRequest) String() string {
 func (*DeleteNoteRequest) ProtoMessage() {}
 
 func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[16]
+	mi := &file_proto_etu_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1356,7 +1991,7 @@ func (x *DeleteNoteRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteNoteRequest.ProtoReflect.Descriptor instead.
 func (*DeleteNoteRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{16}
+	return file_proto_etu_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *DeleteNoteRequest) GetUserId() string {
@@ -1383,7 +2018,7 @@ type DeleteNoteResponse struct {
 
 func (x *DeleteNoteResponse) Reset() {
 	*x = DeleteNoteResponse{}
-	mi := &file_proto_etu_proto_msgTypes[17]
+	mi := &file_proto_etu_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1395,7 +2030,7 @@ func (x *DeleteNoteResponse) String() string {
 func (*DeleteNoteResponse) ProtoMessage() {}
 
 func (x *DeleteNoteResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[17]
+	mi := &file_proto_etu_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1408,7 +2043,7 @@ func (x *DeleteNoteResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeleteNoteResponse.ProtoReflect.Descriptor instead.
 func (*DeleteNoteResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{17}
+	return file_proto_etu_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *DeleteNoteResponse) GetSuccess() bool {
@@ -1431,7 +2066,7 @@ type GetRandomNotesRequest struct {
 
 func (x *GetRandomNotesRequest) Reset() {
 	*x = GetRandomNotesRequest{}
-	mi := &file_proto_etu_proto_msgTypes[18]
+	mi := &file_proto_etu_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1443,7 +2078,7 @@ func (x *GetRandomNotesRequest) String() string {
 func (*GetRandomNotesRequest) ProtoMessage() {}
 
 func (x *GetRandomNotesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[18]
+	mi := &file_proto_etu_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1456,7 +2091,7 @@ func (x *GetRandomNotesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRandomNotesRequest.ProtoReflect.Descriptor instead.
 func (*GetRandomNotesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{18}
+	return file_proto_etu_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetRandomNotesRequest) GetUserId() string {
@@ -1483,7 +2118,7 @@ type GetRandomNotesResponse struct {
 
 func (x *GetRandomNotesResponse) Reset() {
 	*x = GetRandomNotesResponse{}
-	mi := &file_proto_etu_proto_msgTypes[19]
+	mi := &file_proto_etu_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1495,7 +2130,7 @@ func (x *GetRandomNotesResponse) String() string {
 func (*GetRandomNotesResponse) ProtoMessage() {}
 
 func (x *GetRandomNotesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[19]
+	mi := &file_proto_etu_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1508,7 +2143,7 @@ func (x *GetRandomNotesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRandomNotesResponse.ProtoReflect.Descriptor instead.
 func (*GetRandomNotesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{19}
+	return file_proto_etu_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *GetRandomNotesResponse) GetNotes() []*Note {
@@ -1518,30 +2153,39 @@ func (x *GetRandomNotesResponse) GetNotes() []*Note {
 	return nil
 }
 
-// ListTagsRequest requests all tags for a user.
-type ListTagsRequest struct {
+// ListModifiedSinceRequest requests notes changed since a watermark, for
+// incremental export.
+type ListModifiedSinceRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// user_id is the target user identifier.
-	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// since returns notes updated at or after this time; unset starts from the
+	// beginning.
+	Since *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=since,proto3" json:"since,omitempty"`
+	// limit is the maximum number of notes returned (default 50, max 100).
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// cursor is a previous response's next_cursor. When set, listing resumes
+	// right after the last note that response returned and since is ignored.
+	Cursor        string `protobuf:"bytes,4,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTagsRequest) Reset() {
-	*x = ListTagsRequest{}
-	mi := &file_proto_etu_proto_msgTypes[20]
+func (x *ListModifiedSinceRequest) Reset() {
+	*x = ListModifiedSinceRequest{}
+	mi := &file_proto_etu_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTagsRequest) String() string {
+func (x *ListModifiedSinceRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTagsRequest) ProtoMessage() {}
+func (*ListModifiedSinceRequest) ProtoMessage() {}
 
-func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[20]
+func (x *ListModifiedSinceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1552,41 +2196,71 @@ func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
-func (*ListTagsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ListModifiedSinceRequest.ProtoReflect.Descriptor instead.
+func (*ListModifiedSinceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{23}
 }
 
-func (x *ListTagsRequest) GetUserId() string {
+func (x *ListModifiedSinceRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-// ListTagsResponse returns all tags for a user.
-type ListTagsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Tags          []*Tag                 `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+func (x *ListModifiedSinceRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListModifiedSinceRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListModifiedSinceRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+// ListModifiedSinceResponse returns changed notes, oldest update first.
+type ListModifiedSinceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Notes []*Note                `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	// next_since is the updated_at of the last note returned, or the request's
+	// since when nothing changed. Notes updated exactly at next_since are
+	// returned again when it is passed as since; prefer next_cursor. Deleted
+	// notes are not reported.
+	NextSince *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=next_since,json=nextSince,proto3" json:"next_since,omitempty"`
+	// next_cursor is an opaque token for the next call's cursor. It resumes
+	// after the last note returned without repeating notes that share its
+	// updated_at, or at the same position when nothing changed.
+	NextCursor    string `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListTagsResponse) Reset() {
-	*x = ListTagsResponse{}
-	mi := &file_proto_etu_proto_msgTypes[21]
+func (x *ListModifiedSinceResponse) Reset() {
+	*x = ListModifiedSinceResponse{}
+	mi := &file_proto_etu_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListTagsResponse) String() string {
+func (x *ListModifiedSinceResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListTagsResponse) ProtoMessage() {}
+func (*ListModifiedSinceResponse) ProtoMessage() {}
 
-func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[21]
+func (x *ListModifiedSinceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1597,44 +2271,63 @@ func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListTagsResponse.ProtoReflect.Descriptor instead.
-func (*ListTagsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use ListModifiedSinceResponse.ProtoReflect.Descriptor instead.
+func (*ListModifiedSinceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *ListTagsResponse) GetTags() []*Tag {
+func (x *ListModifiedSinceResponse) GetNotes() []*Note {
 	if x != nil {
-		return x.Tags
+		return x.Notes
 	}
 	return nil
 }
 
-// RegisterRequest contains account registration credentials.
-type RegisterRequest struct {
+func (x *ListModifiedSinceResponse) GetNextSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextSince
+	}
+	return nil
+}
+
+func (x *ListModifiedSinceResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+// SuggestTagsRequest asks for AI tag suggestions for a saved note or for
+// unsaved content. Exactly one of id and content must be set.
+type SuggestTagsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// email is the unique email for the new account.
-	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	// password is the plaintext password supplied at registration.
-	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the note to suggest tags for; tags already on it are not suggested.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// content is note text to suggest tags for, e.g. while composing a note.
+	Content string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// max_tags is the maximum number of suggestions (default 3, max 10).
+	MaxTags       int32 `protobuf:"varint,4,opt,name=max_tags,json=maxTags,proto3" json:"max_tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterRequest) Reset() {
-	*x = RegisterRequest{}
-	mi := &file_proto_etu_proto_msgTypes[22]
+func (x *SuggestTagsRequest) Reset() {
+	*x = SuggestTagsRequest{}
+	mi := &file_proto_etu_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterRequest) String() string {
+func (x *SuggestTagsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterRequest) ProtoMessage() {}
+func (*SuggestTagsRequest) ProtoMessage() {}
 
-func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[22]
+func (x *SuggestTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1645,48 +2338,63 @@ func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
-func (*RegisterRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use SuggestTagsRequest.ProtoReflect.Descriptor instead.
+func (*SuggestTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{25}
 }
 
-func (x *RegisterRequest) GetEmail() string {
+func (x *SuggestTagsRequest) GetUserId() string {
 	if x != nil {
-		return x.Email
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *RegisterRequest) GetPassword() string {
+func (x *SuggestTagsRequest) GetId() string {
 	if x != nil {
-		return x.Password
+		return x.Id
 	}
 	return ""
 }
 
-// RegisterResponse returns the created user record.
-type RegisterResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+func (x *SuggestTagsRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SuggestTagsRequest) GetMaxTags() int32 {
+	if x != nil {
+		return x.MaxTags
+	}
+	return 0
+}
+
+// SuggestTagsResponse returns suggested tags without applying them.
+type SuggestTagsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tags holds normalized tag names, tags the user already uses first.
+	Tags          []string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RegisterResponse) Reset() {
-	*x = RegisterResponse{}
-	mi := &file_proto_etu_proto_msgTypes[23]
+func (x *SuggestTagsResponse) Reset() {
+	*x = SuggestTagsResponse{}
+	mi := &file_proto_etu_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *RegisterResponse) String() string {
+func (x *SuggestTagsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*RegisterResponse) ProtoMessage() {}
+func (*SuggestTagsResponse) ProtoMessage() {}
 
-func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[23]
+func (x *SuggestTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1697,44 +2405,47 @@ func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
-func (*RegisterResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use SuggestTagsResponse.ProtoReflect.Descriptor instead.
+func (*SuggestTagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *RegisterResponse) GetUser() *User {
+func (x *SuggestTagsResponse) GetTags() []string {
 	if x != nil {
-		return x.User
+		return x.Tags
 	}
 	return nil
 }
 
-// AuthenticateRequest contains login credentials.
-type AuthenticateRequest struct {
+// ReprocessNoteRequest identifies a note whose AI-derived data should be regenerated.
+type ReprocessNoteRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// email is the account email used for authentication.
-	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
-	// password is the plaintext password supplied for verification.
-	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the unique identifier of the note to reprocess.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// tasks selects what to regenerate: "ocr" clears image extracted text,
+	// "transcribe" clears audio transcriptions, and "tags" removes the note's tags.
+	Tasks         []string `protobuf:"bytes,3,rep,name=tasks,proto3" json:"tasks,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AuthenticateRequest) Reset() {
-	*x = AuthenticateRequest{}
-	mi := &file_proto_etu_proto_msgTypes[24]
+func (x *ReprocessNoteRequest) Reset() {
+	*x = ReprocessNoteRequest{}
+	mi := &file_proto_etu_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuthenticateRequest) String() string {
+func (x *ReprocessNoteRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticateRequest) ProtoMessage() {}
+func (*ReprocessNoteRequest) ProtoMessage() {}
 
-func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[24]
+func (x *ReprocessNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1745,51 +2456,55 @@ func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticateRequest.ProtoReflect.Descriptor instead.
-func (*AuthenticateRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{24}
+// Deprecated: Use ReprocessNoteRequest.ProtoReflect.Descriptor instead.
+func (*ReprocessNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{27}
 }
 
-func (x *AuthenticateRequest) GetEmail() string {
+func (x *ReprocessNoteRequest) GetUserId() string {
 	if x != nil {
-		return x.Email
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *AuthenticateRequest) GetPassword() string {
+func (x *ReprocessNoteRequest) GetId() string {
 	if x != nil {
-		return x.Password
+		return x.Id
 	}
 	return ""
 }
 
-// AuthenticateResponse reports authentication status and user context.
-type AuthenticateResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// success indicates whether authentication succeeded.
-	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	// user is set when authentication succeeds and user data is returned.
-	User          *User `protobuf:"bytes,2,opt,name=user,proto3,oneof" json:"user,omitempty"`
+func (x *ReprocessNoteRequest) GetTasks() []string {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// ReprocessNoteResponse returns the note after its AI-derived data was cleared.
+type ReprocessNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AuthenticateResponse) Reset() {
-	*x = AuthenticateResponse{}
-	mi := &file_proto_etu_proto_msgTypes[25]
+func (x *ReprocessNoteResponse) Reset() {
+	*x = ReprocessNoteResponse{}
+	mi := &file_proto_etu_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AuthenticateResponse) String() string {
+func (x *ReprocessNoteResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AuthenticateResponse) ProtoMessage() {}
+func (*ReprocessNoteResponse) ProtoMessage() {}
 
-func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[25]
+func (x *ReprocessNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1800,48 +2515,46 @@ func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AuthenticateResponse.ProtoReflect.Descriptor instead.
-func (*AuthenticateResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{25}
-}
-
-func (x *AuthenticateResponse) GetSuccess() bool {
-	if x != nil {
-		return x.Success
-	}
-	return false
+// Deprecated: Use ReprocessNoteResponse.ProtoReflect.Descriptor instead.
+func (*ReprocessNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *AuthenticateResponse) GetUser() *User {
+func (x *ReprocessNoteResponse) GetNote() *Note {
 	if x != nil {
-		return x.User
+		return x.Note
 	}
 	return nil
 }
 
-// GetUserRequest fetches a user by id.
-type GetUserRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+// ReorderImagesRequest sets the display order of a note's images.
+type ReorderImagesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// note_id is the unique identifier of the note whose images are reordered.
+	NoteId string `protobuf:"bytes,2,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`
+	// image_ids lists every image on the note exactly once, in the desired order.
+	ImageIds      []string `protobuf:"bytes,3,rep,name=image_ids,json=imageIds,proto3" json:"image_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserRequest) Reset() {
-	*x = GetUserRequest{}
-	mi := &file_proto_etu_proto_msgTypes[26]
+func (x *ReorderImagesRequest) Reset() {
+	*x = ReorderImagesRequest{}
+	mi := &file_proto_etu_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserRequest) String() string {
+func (x *ReorderImagesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserRequest) ProtoMessage() {}
+func (*ReorderImagesRequest) ProtoMessage() {}
 
-func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[26]
+func (x *ReorderImagesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1852,41 +2565,55 @@ func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
-func (*GetUserRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use ReorderImagesRequest.ProtoReflect.Descriptor instead.
+func (*ReorderImagesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{29}
 }
 
-func (x *GetUserRequest) GetUserId() string {
+func (x *ReorderImagesRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-// GetUserResponse returns a user record.
-type GetUserResponse struct {
+func (x *ReorderImagesRequest) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *ReorderImagesRequest) GetImageIds() []string {
+	if x != nil {
+		return x.ImageIds
+	}
+	return nil
+}
+
+// ReorderImagesResponse returns the note with its images in the new order.
+type ReorderImagesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserResponse) Reset() {
-	*x = GetUserResponse{}
-	mi := &file_proto_etu_proto_msgTypes[27]
+func (x *ReorderImagesResponse) Reset() {
+	*x = ReorderImagesResponse{}
+	mi := &file_proto_etu_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserResponse) String() string {
+func (x *ReorderImagesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserResponse) ProtoMessage() {}
+func (*ReorderImagesResponse) ProtoMessage() {}
 
-func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[27]
+func (x *ReorderImagesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1897,41 +2624,42 @@ func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
-func (*GetUserResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use ReorderImagesResponse.ProtoReflect.Descriptor instead.
+func (*ReorderImagesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *GetUserResponse) GetUser() *User {
+func (x *ReorderImagesResponse) GetNote() *Note {
 	if x != nil {
-		return x.User
+		return x.Note
 	}
 	return nil
 }
 
-// GetUserByStripeCustomerIdRequest fetches a user by Stripe customer id.
-type GetUserByStripeCustomerIdRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	StripeCustomerId string                 `protobuf:"bytes,1,opt,name=stripe_customer_id,json=stripeCustomerId,proto3" json:"stripe_customer_id,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+// FindDuplicatesRequest requests groups of notes with matching content.
+type FindDuplicatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserByStripeCustomerIdRequest) Reset() {
-	*x = GetUserByStripeCustomerIdRequest{}
-	mi := &file_proto_etu_proto_msgTypes[28]
+func (x *FindDuplicatesRequest) Reset() {
+	*x = FindDuplicatesRequest{}
+	mi := &file_proto_etu_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserByStripeCustomerIdRequest) String() string {
+func (x *FindDuplicatesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserByStripeCustomerIdRequest) ProtoMessage() {}
+func (*FindDuplicatesRequest) ProtoMessage() {}
 
-func (x *GetUserByStripeCustomerIdRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[28]
+func (x *FindDuplicatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1942,41 +2670,3015 @@ func (x *GetUserByStripeCustomerIdRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserByStripeCustomerIdRequest.ProtoReflect.Descriptor instead.
-func (*GetUserByStripeCustomerIdRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use FindDuplicatesRequest.ProtoReflect.Descriptor instead.
+func (*FindDuplicatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *GetUserByStripeCustomerIdRequest) GetStripeCustomerId() string {
+func (x *FindDuplicatesRequest) GetUserId() string {
 	if x != nil {
-		return x.StripeCustomerId
+		return x.UserId
 	}
 	return ""
 }
 
-// GetUserByStripeCustomerIdResponse returns the matching user when found.
-type GetUserByStripeCustomerIdResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3,oneof" json:"user,omitempty"`
-	unknownFields protoimpl.UnknownFields
+// DuplicateNote identifies one note in a duplicate group.
+type DuplicateNote struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is the unique identifier of the note.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// created_at is when the note was created.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateNote) Reset() {
+	*x = DuplicateNote{}
+	mi := &file_proto_etu_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateNote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateNote) ProtoMessage() {}
+
+func (x *DuplicateNote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateNote.ProtoReflect.Descriptor instead.
+func (*DuplicateNote) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *DuplicateNote) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DuplicateNote) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// DuplicateGroup lists notes whose content matches after whitespace normalization.
+type DuplicateGroup struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// notes are the matching notes, oldest first.
+	Notes         []*DuplicateNote `protobuf:"bytes,1,rep,name=notes,proto3" json:"notes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateGroup) Reset() {
+	*x = DuplicateGroup{}
+	mi := &file_proto_etu_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateGroup) ProtoMessage() {}
+
+func (x *DuplicateGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateGroup.ProtoReflect.Descriptor instead.
+func (*DuplicateGroup) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *DuplicateGroup) GetNotes() []*DuplicateNote {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+// FindDuplicatesResponse returns every group of duplicate notes.
+type FindDuplicatesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// groups contains one entry per set of notes sharing the same content.
+	Groups        []*DuplicateGroup `protobuf:"bytes,1,rep,name=groups,proto3" json:"groups,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FindDuplicatesResponse) Reset() {
+	*x = FindDuplicatesResponse{}
+	mi := &file_proto_etu_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FindDuplicatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FindDuplicatesResponse) ProtoMessage() {}
+
+func (x *FindDuplicatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FindDuplicatesResponse.ProtoReflect.Descriptor instead.
+func (*FindDuplicatesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *FindDuplicatesResponse) GetGroups() []*DuplicateGroup {
+	if x != nil {
+		return x.Groups
+	}
+	return nil
+}
+
+// MergeNotesRequest folds one or more notes into a target note.
+type MergeNotesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// target_id is the note that receives the merged content, media, and tags.
+	TargetId string `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	// source_ids are the notes merged into the target and then deleted.
+	SourceIds     []string `protobuf:"bytes,3,rep,name=source_ids,json=sourceIds,proto3" json:"source_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeNotesRequest) Reset() {
+	*x = MergeNotesRequest{}
+	mi := &file_proto_etu_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeNotesRequest) ProtoMessage() {}
+
+func (x *MergeNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeNotesRequest.ProtoReflect.Descriptor instead.
+func (*MergeNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *MergeNotesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MergeNotesRequest) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *MergeNotesRequest) GetSourceIds() []string {
+	if x != nil {
+		return x.SourceIds
+	}
+	return nil
+}
+
+// MergeNotesResponse returns the merged target note.
+type MergeNotesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MergeNotesResponse) Reset() {
+	*x = MergeNotesResponse{}
+	mi := &file_proto_etu_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MergeNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MergeNotesResponse) ProtoMessage() {}
+
+func (x *MergeNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MergeNotesResponse.ProtoReflect.Descriptor instead.
+func (*MergeNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *MergeNotesResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// DuplicateNoteRequest copies a note.
+type DuplicateNoteRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the unique identifier of the note to copy.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateNoteRequest) Reset() {
+	*x = DuplicateNoteRequest{}
+	mi := &file_proto_etu_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateNoteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateNoteRequest) ProtoMessage() {}
+
+func (x *DuplicateNoteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateNoteRequest.ProtoReflect.Descriptor instead.
+func (*DuplicateNoteRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DuplicateNoteRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DuplicateNoteRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DuplicateNoteResponse returns the new copy.
+type DuplicateNoteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DuplicateNoteResponse) Reset() {
+	*x = DuplicateNoteResponse{}
+	mi := &file_proto_etu_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DuplicateNoteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DuplicateNoteResponse) ProtoMessage() {}
+
+func (x *DuplicateNoteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DuplicateNoteResponse.ProtoReflect.Descriptor instead.
+func (*DuplicateNoteResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DuplicateNoteResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// SetNotePinnedRequest pins or unpins a note.
+type SetNotePinnedRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the unique identifier of the note to update.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// pinned lists the note first in ListNotes when true.
+	Pinned        bool `protobuf:"varint,3,opt,name=pinned,proto3" json:"pinned,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNotePinnedRequest) Reset() {
+	*x = SetNotePinnedRequest{}
+	mi := &file_proto_etu_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNotePinnedRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNotePinnedRequest) ProtoMessage() {}
+
+func (x *SetNotePinnedRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNotePinnedRequest.ProtoReflect.Descriptor instead.
+func (*SetNotePinnedRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *SetNotePinnedRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetNotePinnedRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SetNotePinnedRequest) GetPinned() bool {
+	if x != nil {
+		return x.Pinned
+	}
+	return false
+}
+
+// SetNotePinnedResponse returns the updated note.
+type SetNotePinnedResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetNotePinnedResponse) Reset() {
+	*x = SetNotePinnedResponse{}
+	mi := &file_proto_etu_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetNotePinnedResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetNotePinnedResponse) ProtoMessage() {}
+
+func (x *SetNotePinnedResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetNotePinnedResponse.ProtoReflect.Descriptor instead.
+func (*SetNotePinnedResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *SetNotePinnedResponse) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+// UpdateImageCaptionRequest sets the caption of a single note image.
+type UpdateImageCaptionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// image_id is the unique identifier of the image to update.
+	ImageId string `protobuf:"bytes,2,opt,name=image_id,json=imageId,proto3" json:"image_id,omitempty"`
+	// caption is the new caption; empty clears it.
+	Caption       string `protobuf:"bytes,3,opt,name=caption,proto3" json:"caption,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateImageCaptionRequest) Reset() {
+	*x = UpdateImageCaptionRequest{}
+	mi := &file_proto_etu_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateImageCaptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateImageCaptionRequest) ProtoMessage() {}
+
+func (x *UpdateImageCaptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateImageCaptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateImageCaptionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *UpdateImageCaptionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateImageCaptionRequest) GetImageId() string {
+	if x != nil {
+		return x.ImageId
+	}
+	return ""
+}
+
+func (x *UpdateImageCaptionRequest) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
+// UpdateImageCaptionResponse returns the updated image.
+type UpdateImageCaptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Image         *NoteImage             `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateImageCaptionResponse) Reset() {
+	*x = UpdateImageCaptionResponse{}
+	mi := &file_proto_etu_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateImageCaptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateImageCaptionResponse) ProtoMessage() {}
+
+func (x *UpdateImageCaptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateImageCaptionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateImageCaptionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *UpdateImageCaptionResponse) GetImage() *NoteImage {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+// WatchNotesRequest subscribes to changes to a user's notes.
+type WatchNotesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id identifies the user whose note changes are streamed.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchNotesRequest) Reset() {
+	*x = WatchNotesRequest{}
+	mi := &file_proto_etu_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNotesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNotesRequest) ProtoMessage() {}
+
+func (x *WatchNotesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchNotesRequest.ProtoReflect.Descriptor instead.
+func (*WatchNotesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *WatchNotesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// NoteEvent describes a single change to one of the user's notes.
+type NoteEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// type is what happened to the note.
+	Type NoteEventType `protobuf:"varint,1,opt,name=type,proto3,enum=etu.NoteEventType" json:"type,omitempty"`
+	// note_id identifies the changed note.
+	NoteId string `protobuf:"bytes,2,opt,name=note_id,json=noteId,proto3" json:"note_id,omitempty"`
+	// note is the note after the change. It is unset for deletions.
+	Note *Note `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	// tags is the note's new tag list for NOTE_EVENT_TYPE_TAGS_CHANGED.
+	Tags []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	// occurred_at is when the server published the event.
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NoteEvent) Reset() {
+	*x = NoteEvent{}
+	mi := &file_proto_etu_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NoteEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NoteEvent) ProtoMessage() {}
+
+func (x *NoteEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NoteEvent.ProtoReflect.Descriptor instead.
+func (*NoteEvent) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *NoteEvent) GetType() NoteEventType {
+	if x != nil {
+		return x.Type
+	}
+	return NoteEventType_NOTE_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *NoteEvent) GetNoteId() string {
+	if x != nil {
+		return x.NoteId
+	}
+	return ""
+}
+
+func (x *NoteEvent) GetNote() *Note {
+	if x != nil {
+		return x.Note
+	}
+	return nil
+}
+
+func (x *NoteEvent) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *NoteEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+// WatchNotesResponse carries one change event on a WatchNotes stream.
+type WatchNotesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// event is the change that occurred.
+	Event         *NoteEvent `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchNotesResponse) Reset() {
+	*x = WatchNotesResponse{}
+	mi := &file_proto_etu_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNotesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNotesResponse) ProtoMessage() {}
+
+func (x *WatchNotesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchNotesResponse.ProtoReflect.Descriptor instead.
+func (*WatchNotesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *WatchNotesResponse) GetEvent() *NoteEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// ListTagsRequest requests all tags for a user.
+type ListTagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsRequest) Reset() {
+	*x = ListTagsRequest{}
+	mi := &file_proto_etu_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsRequest) ProtoMessage() {}
+
+func (x *ListTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsRequest.ProtoReflect.Descriptor instead.
+func (*ListTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *ListTagsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// ListTagsResponse returns all tags for a user.
+type ListTagsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tags          []*Tag                 `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTagsResponse) Reset() {
+	*x = ListTagsResponse{}
+	mi := &file_proto_etu_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTagsResponse) ProtoMessage() {}
+
+func (x *ListTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTagsResponse.ProtoReflect.Descriptor instead.
+func (*ListTagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListTagsResponse) GetTags() []*Tag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// GetTagCountsRequest requests tag usage counts within a date range.
+type GetTagCountsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// start_date is an inclusive lower bound on note creation time in ISO 8601 format.
+	StartDate string `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is an inclusive upper bound on note creation time in ISO 8601 format.
+	EndDate       string `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagCountsRequest) Reset() {
+	*x = GetTagCountsRequest{}
+	mi := &file_proto_etu_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagCountsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagCountsRequest) ProtoMessage() {}
+
+func (x *GetTagCountsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagCountsRequest.ProtoReflect.Descriptor instead.
+func (*GetTagCountsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetTagCountsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetTagCountsRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetTagCountsRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+// GetTagCountsResponse returns tags used in the range, most used first.
+type GetTagCountsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tags holds each tag with count set to its notes in the range.
+	Tags          []*Tag `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagCountsResponse) Reset() {
+	*x = GetTagCountsResponse{}
+	mi := &file_proto_etu_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagCountsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagCountsResponse) ProtoMessage() {}
+
+func (x *GetTagCountsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagCountsResponse.ProtoReflect.Descriptor instead.
+func (*GetTagCountsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetTagCountsResponse) GetTags() []*Tag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// GetRelatedTagsRequest requests tags that are often used alongside a tag.
+type GetRelatedTagsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// tag_name is the tag to find companions for; it is normalized like stored
+	// tags.
+	TagName string `protobuf:"bytes,2,opt,name=tag_name,json=tagName,proto3" json:"tag_name,omitempty"`
+	// limit is the maximum number of tags returned (default 10, max 50).
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRelatedTagsRequest) Reset() {
+	*x = GetRelatedTagsRequest{}
+	mi := &file_proto_etu_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRelatedTagsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelatedTagsRequest) ProtoMessage() {}
+
+func (x *GetRelatedTagsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelatedTagsRequest.ProtoReflect.Descriptor instead.
+func (*GetRelatedTagsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *GetRelatedTagsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetRelatedTagsRequest) GetTagName() string {
+	if x != nil {
+		return x.TagName
+	}
+	return ""
+}
+
+func (x *GetRelatedTagsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// GetRelatedTagsResponse returns co-occurring tags, most shared first.
+type GetRelatedTagsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tags holds each related tag with count set to the number of notes it
+	// shares with tag_name.
+	Tags          []*Tag `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRelatedTagsResponse) Reset() {
+	*x = GetRelatedTagsResponse{}
+	mi := &file_proto_etu_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRelatedTagsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRelatedTagsResponse) ProtoMessage() {}
+
+func (x *GetRelatedTagsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRelatedTagsResponse.ProtoReflect.Descriptor instead.
+func (*GetRelatedTagsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *GetRelatedTagsResponse) GetTags() []*Tag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// GetTagRequest requests a single tag and the first page of its notes.
+type GetTagRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the unique identifier of the tag.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// limit is the maximum number of notes to embed.
+	Limit         int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagRequest) Reset() {
+	*x = GetTagRequest{}
+	mi := &file_proto_etu_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagRequest) ProtoMessage() {}
+
+func (x *GetTagRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagRequest.ProtoReflect.Descriptor instead.
+func (*GetTagRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetTagRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetTagRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetTagRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// GetTagResponse returns a tag and the newest notes that use it.
+type GetTagResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tag is the requested tag with its usage count.
+	Tag *Tag `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	// notes is the first page of notes with the tag, newest first.
+	Notes []*Note `protobuf:"bytes,2,rep,name=notes,proto3" json:"notes,omitempty"`
+	// total is the number of notes with the tag; page further with ListNotes.
+	Total         int32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTagResponse) Reset() {
+	*x = GetTagResponse{}
+	mi := &file_proto_etu_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTagResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTagResponse) ProtoMessage() {}
+
+func (x *GetTagResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTagResponse.ProtoReflect.Descriptor instead.
+func (*GetTagResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *GetTagResponse) GetTag() *Tag {
+	if x != nil {
+		return x.Tag
+	}
+	return nil
+}
+
+func (x *GetTagResponse) GetNotes() []*Note {
+	if x != nil {
+		return x.Notes
+	}
+	return nil
+}
+
+func (x *GetTagResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+// RegisterRequest contains account registration credentials.
+type RegisterRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// email is the unique email for the new account.
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// password is the plaintext password supplied at registration.
+	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterRequest) Reset() {
+	*x = RegisterRequest{}
+	mi := &file_proto_etu_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterRequest) ProtoMessage() {}
+
+func (x *RegisterRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterRequest.ProtoReflect.Descriptor instead.
+func (*RegisterRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *RegisterRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *RegisterRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// RegisterResponse returns the created user record.
+type RegisterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegisterResponse) Reset() {
+	*x = RegisterResponse{}
+	mi := &file_proto_etu_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegisterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegisterResponse) ProtoMessage() {}
+
+func (x *RegisterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegisterResponse.ProtoReflect.Descriptor instead.
+func (*RegisterResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *RegisterResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// AuthenticateRequest contains login credentials.
+type AuthenticateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// email is the account email used for authentication.
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// password is the plaintext password supplied for verification.
+	Password      string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateRequest) Reset() {
+	*x = AuthenticateRequest{}
+	mi := &file_proto_etu_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateRequest) ProtoMessage() {}
+
+func (x *AuthenticateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateRequest.ProtoReflect.Descriptor instead.
+func (*AuthenticateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *AuthenticateRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *AuthenticateRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+// AuthenticateResponse reports authentication status and user context.
+type AuthenticateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// success indicates whether authentication succeeded.
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// user is set when authentication succeeds and user data is returned.
+	User          *User `protobuf:"bytes,2,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthenticateResponse) Reset() {
+	*x = AuthenticateResponse{}
+	mi := &file_proto_etu_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthenticateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthenticateResponse) ProtoMessage() {}
+
+func (x *AuthenticateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthenticateResponse.ProtoReflect.Descriptor instead.
+func (*AuthenticateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *AuthenticateResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AuthenticateResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// GetUserRequest fetches a user by id.
+type GetUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserRequest) Reset() {
+	*x = GetUserRequest{}
+	mi := &file_proto_etu_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserRequest) ProtoMessage() {}
+
+func (x *GetUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserRequest.ProtoReflect.Descriptor instead.
+func (*GetUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// GetUserResponse returns a user record.
+type GetUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserResponse) Reset() {
+	*x = GetUserResponse{}
+	mi := &file_proto_etu_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserResponse) ProtoMessage() {}
+
+func (x *GetUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserResponse.ProtoReflect.Descriptor instead.
+func (*GetUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// GetSubscriptionStatusRequest fetches a user's subscription state.
+type GetSubscriptionStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionStatusRequest) Reset() {
+	*x = GetSubscriptionStatusRequest{}
+	mi := &file_proto_etu_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionStatusRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *GetSubscriptionStatusRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// GetSubscriptionStatusResponse returns only the subscription fields of a
+// user. The flags are derived the same way as on User.
+type GetSubscriptionStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// subscription_status is the billing status, such as "free" or "active".
+	SubscriptionStatus string `protobuf:"bytes,1,opt,name=subscription_status,json=subscriptionStatus,proto3" json:"subscription_status,omitempty"`
+	// subscription_end is when the current subscription period ends.
+	SubscriptionEnd *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=subscription_end,json=subscriptionEnd,proto3,oneof" json:"subscription_end,omitempty"`
+	// is_premium reports whether subscription_status is a premium tier.
+	IsPremium bool `protobuf:"varint,3,opt,name=is_premium,json=isPremium,proto3" json:"is_premium,omitempty"`
+	// is_active reports whether the user is premium and subscription_end has
+	// not passed.
+	IsActive      bool `protobuf:"varint,4,opt,name=is_active,json=isActive,proto3" json:"is_active,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionStatusResponse) Reset() {
+	*x = GetSubscriptionStatusResponse{}
+	mi := &file_proto_etu_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionStatusResponse) ProtoMessage() {}
+
+func (x *GetSubscriptionStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetSubscriptionStatusResponse) GetSubscriptionStatus() string {
+	if x != nil {
+		return x.SubscriptionStatus
+	}
+	return ""
+}
+
+func (x *GetSubscriptionStatusResponse) GetSubscriptionEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubscriptionEnd
+	}
+	return nil
+}
+
+func (x *GetSubscriptionStatusResponse) GetIsPremium() bool {
+	if x != nil {
+		return x.IsPremium
+	}
+	return false
+}
+
+func (x *GetSubscriptionStatusResponse) GetIsActive() bool {
+	if x != nil {
+		return x.IsActive
+	}
+	return false
+}
+
+// AdminListUsersRequest pages through all users. Requires M2M authentication.
+type AdminListUsersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// email limits results to users whose email contains this text,
+	// case-insensitively.
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// limit is the maximum number of results to return.
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// offset is the number of results to skip before returning rows.
+	Offset        int32 `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminListUsersRequest) Reset() {
+	*x = AdminListUsersRequest{}
+	mi := &file_proto_etu_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminListUsersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminListUsersRequest) ProtoMessage() {}
+
+func (x *AdminListUsersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminListUsersRequest.ProtoReflect.Descriptor instead.
+func (*AdminListUsersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *AdminListUsersRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *AdminListUsersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *AdminListUsersRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// AdminListUsersResponse returns a page of users, newest first.
+type AdminListUsersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// users is the requested page of users. notion_key is never set.
+	Users []*User `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	// total is the total result count across all pages.
+	Total int32 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// limit echoes the effective page size.
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// offset echoes the page offset.
+	Offset        int32 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminListUsersResponse) Reset() {
+	*x = AdminListUsersResponse{}
+	mi := &file_proto_etu_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminListUsersResponse) ProtoMessage() {}
+
+func (x *AdminListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminListUsersResponse.ProtoReflect.Descriptor instead.
+func (*AdminListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *AdminListUsersResponse) GetUsers() []*User {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+func (x *AdminListUsersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *AdminListUsersResponse) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *AdminListUsersResponse) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+// AdminDisableUserRequest disables a user's account. Requires M2M
+// authentication.
+type AdminDisableUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the account to disable.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// reason records why the account was disabled.
+	Reason        DisabledReason `protobuf:"varint,2,opt,name=reason,proto3,enum=etu.DisabledReason" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminDisableUserRequest) Reset() {
+	*x = AdminDisableUserRequest{}
+	mi := &file_proto_etu_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminDisableUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminDisableUserRequest) ProtoMessage() {}
+
+func (x *AdminDisableUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminDisableUserRequest.ProtoReflect.Descriptor instead.
+func (*AdminDisableUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *AdminDisableUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AdminDisableUserRequest) GetReason() DisabledReason {
+	if x != nil {
+		return x.Reason
+	}
+	return DisabledReason_UNSPECIFIED
+}
+
+// AdminDisableUserResponse returns the disabled user.
+type AdminDisableUserResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user is the updated user. notion_key is never set.
+	User          *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminDisableUserResponse) Reset() {
+	*x = AdminDisableUserResponse{}
+	mi := &file_proto_etu_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminDisableUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminDisableUserResponse) ProtoMessage() {}
+
+func (x *AdminDisableUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminDisableUserResponse.ProtoReflect.Descriptor instead.
+func (*AdminDisableUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *AdminDisableUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// AdminEnableUserRequest re-enables a disabled account. Requires M2M
+// authentication.
+type AdminEnableUserRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the account to re-enable.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminEnableUserRequest) Reset() {
+	*x = AdminEnableUserRequest{}
+	mi := &file_proto_etu_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminEnableUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminEnableUserRequest) ProtoMessage() {}
+
+func (x *AdminEnableUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminEnableUserRequest.ProtoReflect.Descriptor instead.
+func (*AdminEnableUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *AdminEnableUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// AdminEnableUserResponse returns the re-enabled user.
+type AdminEnableUserResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user is the updated user. notion_key is never set.
+	User          *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminEnableUserResponse) Reset() {
+	*x = AdminEnableUserResponse{}
+	mi := &file_proto_etu_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminEnableUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminEnableUserResponse) ProtoMessage() {}
+
+func (x *AdminEnableUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminEnableUserResponse.ProtoReflect.Descriptor instead.
+func (*AdminEnableUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *AdminEnableUserResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// AdminUnlockAccountRequest clears a user's failed login lockout. Requires
+// M2M authentication.
+type AdminUnlockAccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the account to unlock.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminUnlockAccountRequest) Reset() {
+	*x = AdminUnlockAccountRequest{}
+	mi := &file_proto_etu_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminUnlockAccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUnlockAccountRequest) ProtoMessage() {}
+
+func (x *AdminUnlockAccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUnlockAccountRequest.ProtoReflect.Descriptor instead.
+func (*AdminUnlockAccountRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *AdminUnlockAccountRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// AdminUnlockAccountResponse returns the unlocked user.
+type AdminUnlockAccountResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user is the updated user. notion_key is never set.
+	User          *User `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminUnlockAccountResponse) Reset() {
+	*x = AdminUnlockAccountResponse{}
+	mi := &file_proto_etu_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminUnlockAccountResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUnlockAccountResponse) ProtoMessage() {}
+
+func (x *AdminUnlockAccountResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUnlockAccountResponse.ProtoReflect.Descriptor instead.
+func (*AdminUnlockAccountResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *AdminUnlockAccountResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// GetLoginHistoryRequest lists a user's recent authentication attempts.
+type GetLoginHistoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the user whose history to return.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// limit is the maximum number of events to return.
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLoginHistoryRequest) Reset() {
+	*x = GetLoginHistoryRequest{}
+	mi := &file_proto_etu_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLoginHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLoginHistoryRequest) ProtoMessage() {}
+
+func (x *GetLoginHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLoginHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetLoginHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *GetLoginHistoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetLoginHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// GetLoginHistoryResponse returns login events, newest first.
+type GetLoginHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// events are the user's most recent authentication attempts.
+	Events        []*LoginEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLoginHistoryResponse) Reset() {
+	*x = GetLoginHistoryResponse{}
+	mi := &file_proto_etu_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLoginHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLoginHistoryResponse) ProtoMessage() {}
+
+func (x *GetLoginHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLoginHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetLoginHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetLoginHistoryResponse) GetEvents() []*LoginEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// GetUserByStripeCustomerIdRequest fetches a user by Stripe customer id.
+type GetUserByStripeCustomerIdRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	StripeCustomerId string                 `protobuf:"bytes,1,opt,name=stripe_customer_id,json=stripeCustomerId,proto3" json:"stripe_customer_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetUserByStripeCustomerIdRequest) Reset() {
+	*x = GetUserByStripeCustomerIdRequest{}
+	mi := &file_proto_etu_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserByStripeCustomerIdRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserByStripeCustomerIdRequest) ProtoMessage() {}
+
+func (x *GetUserByStripeCustomerIdRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserByStripeCustomerIdRequest.ProtoReflect.Descriptor instead.
+func (*GetUserByStripeCustomerIdRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetUserByStripeCustomerIdRequest) GetStripeCustomerId() string {
+	if x != nil {
+		return x.StripeCustomerId
+	}
+	return ""
+}
+
+// GetUserByStripeCustomerIdResponse returns the matching user when found.
+type GetUserByStripeCustomerIdResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserByStripeCustomerIdResponse) Reset() {
+	*x = GetUserByStripeCustomerIdResponse{}
+	mi := &file_proto_etu_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserByStripeCustomerIdResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserByStripeCustomerIdResponse) ProtoMessage() {}
+
+func (x *GetUserByStripeCustomerIdResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserByStripeCustomerIdResponse.ProtoReflect.Descriptor instead.
+func (*GetUserByStripeCustomerIdResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetUserByStripeCustomerIdResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// UpdateUserSubscriptionRequest updates subscription billing fields.
+type UpdateUserSubscriptionRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	UserId             string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SubscriptionStatus string                 `protobuf:"bytes,2,opt,name=subscription_status,json=subscriptionStatus,proto3" json:"subscription_status,omitempty"`
+	StripeCustomerId   *string                `protobuf:"bytes,3,opt,name=stripe_customer_id,json=stripeCustomerId,proto3,oneof" json:"stripe_customer_id,omitempty"`
+	SubscriptionEnd    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=subscription_end,json=subscriptionEnd,proto3,oneof" json:"subscription_end,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *UpdateUserSubscriptionRequest) Reset() {
+	*x = UpdateUserSubscriptionRequest{}
+	mi := &file_proto_etu_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateUserSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *UpdateUserSubscriptionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateUserSubscriptionRequest) GetSubscriptionStatus() string {
+	if x != nil {
+		return x.SubscriptionStatus
+	}
+	return ""
+}
+
+func (x *UpdateUserSubscriptionRequest) GetStripeCustomerId() string {
+	if x != nil && x.StripeCustomerId != nil {
+		return *x.StripeCustomerId
+	}
+	return ""
+}
+
+func (x *UpdateUserSubscriptionRequest) GetSubscriptionEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SubscriptionEnd
+	}
+	return nil
+}
+
+// UpdateUserSubscriptionResponse returns the updated user record.
+type UpdateUserSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserSubscriptionResponse) Reset() {
+	*x = UpdateUserSubscriptionResponse{}
+	mi := &file_proto_etu_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserSubscriptionResponse) ProtoMessage() {}
+
+func (x *UpdateUserSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *UpdateUserSubscriptionResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// CreateApiKeyRequest creates a named API key for a user.
+type CreateApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateApiKeyRequest) Reset() {
+	*x = CreateApiKeyRequest{}
+	mi := &file_proto_etu_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyRequest) ProtoMessage() {}
+
+func (x *CreateApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *CreateApiKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateApiKeyRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// CreateApiKeyResponse returns key metadata and a one-time raw key value.
+type CreateApiKeyResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ApiKey *ApiKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// raw_key is only returned at creation time and should be stored securely.
+	RawKey        string `protobuf:"bytes,2,opt,name=raw_key,json=rawKey,proto3" json:"raw_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateApiKeyResponse) Reset() {
+	*x = CreateApiKeyResponse{}
+	mi := &file_proto_etu_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateApiKeyResponse) ProtoMessage() {}
+
+func (x *CreateApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*CreateApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *CreateApiKeyResponse) GetApiKey() *ApiKey {
+	if x != nil {
+		return x.ApiKey
+	}
+	return nil
+}
+
+func (x *CreateApiKeyResponse) GetRawKey() string {
+	if x != nil {
+		return x.RawKey
+	}
+	return ""
+}
+
+// ListApiKeysRequest requests API keys for a user.
+type ListApiKeysRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysRequest) Reset() {
+	*x = ListApiKeysRequest{}
+	mi := &file_proto_etu_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysRequest) ProtoMessage() {}
+
+func (x *ListApiKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysRequest.ProtoReflect.Descriptor instead.
+func (*ListApiKeysRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *ListApiKeysRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// ListApiKeysResponse returns all API key metadata for a user.
+type ListApiKeysResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ApiKeys       []*ApiKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListApiKeysResponse) Reset() {
+	*x = ListApiKeysResponse{}
+	mi := &file_proto_etu_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListApiKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListApiKeysResponse) ProtoMessage() {}
+
+func (x *ListApiKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListApiKeysResponse.ProtoReflect.Descriptor instead.
+func (*ListApiKeysResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *ListApiKeysResponse) GetApiKeys() []*ApiKey {
+	if x != nil {
+		return x.ApiKeys
+	}
+	return nil
+}
+
+// DeleteApiKeyRequest identifies an API key to revoke.
+type DeleteApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteApiKeyRequest) Reset() {
+	*x = DeleteApiKeyRequest{}
+	mi := &file_proto_etu_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteApiKeyRequest) ProtoMessage() {}
+
+func (x *DeleteApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*DeleteApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *DeleteApiKeyRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *DeleteApiKeyRequest) GetKeyId() string {
+	if x != nil {
+		return x.KeyId
+	}
+	return ""
+}
+
+// DeleteApiKeyResponse reports whether an API key deletion occurred.
+type DeleteApiKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteApiKeyResponse) Reset() {
+	*x = DeleteApiKeyResponse{}
+	mi := &file_proto_etu_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteApiKeyResponse) ProtoMessage() {}
+
+func (x *DeleteApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*DeleteApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *DeleteApiKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+// VerifyApiKeyRequest verifies a raw API key value.
+type VerifyApiKeyRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RawKey        string                 `protobuf:"bytes,1,opt,name=raw_key,json=rawKey,proto3" json:"raw_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyApiKeyRequest) Reset() {
+	*x = VerifyApiKeyRequest{}
+	mi := &file_proto_etu_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyApiKeyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyApiKeyRequest) ProtoMessage() {}
+
+func (x *VerifyApiKeyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyApiKeyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyApiKeyRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *VerifyApiKeyRequest) GetRawKey() string {
+	if x != nil {
+		return x.RawKey
+	}
+	return ""
+}
+
+// VerifyApiKeyResponse reports verification status and associated user.
+type VerifyApiKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	UserId        *string                `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyApiKeyResponse) Reset() {
+	*x = VerifyApiKeyResponse{}
+	mi := &file_proto_etu_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyApiKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyApiKeyResponse) ProtoMessage() {}
+
+func (x *VerifyApiKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyApiKeyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyApiKeyResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *VerifyApiKeyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyApiKeyResponse) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+// GetUserSettingsRequest fetches mutable user settings fields.
+type GetUserSettingsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserSettingsRequest) Reset() {
+	*x = GetUserSettingsRequest{}
+	mi := &file_proto_etu_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSettingsRequest) ProtoMessage() {}
+
+func (x *GetUserSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetUserSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *GetUserSettingsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// GetUserSettingsResponse returns settings on the User message.
+type GetUserSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUserSettingsResponse) Reset() {
+	*x = GetUserSettingsResponse{}
+	mi := &file_proto_etu_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUserSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUserSettingsResponse) ProtoMessage() {}
+
+func (x *GetUserSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUserSettingsResponse.ProtoReflect.Descriptor instead.
+func (*GetUserSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *GetUserSettingsResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// UpdateUserSettingsRequest updates profile and integration settings.
+type UpdateUserSettingsRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	UserId    string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	NotionKey *string                `protobuf:"bytes,2,opt,name=notion_key,json=notionKey,proto3,oneof" json:"notion_key,omitempty"`
+	Name      *string                `protobuf:"bytes,4,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// password must meet the same policy as Register; a weak one is rejected
+	// with INVALID_ARGUMENT.
+	Password           *string      `protobuf:"bytes,6,opt,name=password,proto3,oneof" json:"password,omitempty"`
+	NotionDatabaseName *string      `protobuf:"bytes,7,opt,name=notion_database_name,json=notionDatabaseName,proto3,oneof" json:"notion_database_name,omitempty"`
+	ProfileImageUpload *ImageUpload `protobuf:"bytes,8,opt,name=profile_image_upload,json=profileImageUpload,proto3,oneof" json:"profile_image_upload,omitempty"`
+	ClearProfileImage  *bool        `protobuf:"varint,9,opt,name=clear_profile_image,json=clearProfileImage,proto3,oneof" json:"clear_profile_image,omitempty"`
+	// sync_interval_minutes sets the minimum time between Notion syncs; 0
+	// clears the override.
+	SyncIntervalMinutes *int32 `protobuf:"varint,10,opt,name=sync_interval_minutes,json=syncIntervalMinutes,proto3,oneof" json:"sync_interval_minutes,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UpdateUserSettingsRequest) Reset() {
+	*x = UpdateUserSettingsRequest{}
+	mi := &file_proto_etu_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserSettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserSettingsRequest) ProtoMessage() {}
+
+func (x *UpdateUserSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserSettingsRequest.ProtoReflect.Descriptor instead.
+func (*UpdateUserSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *UpdateUserSettingsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateUserSettingsRequest) GetNotionKey() string {
+	if x != nil && x.NotionKey != nil {
+		return *x.NotionKey
+	}
+	return ""
+}
+
+func (x *UpdateUserSettingsRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateUserSettingsRequest) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *UpdateUserSettingsRequest) GetNotionDatabaseName() string {
+	if x != nil && x.NotionDatabaseName != nil {
+		return *x.NotionDatabaseName
+	}
+	return ""
+}
+
+func (x *UpdateUserSettingsRequest) GetProfileImageUpload() *ImageUpload {
+	if x != nil {
+		return x.ProfileImageUpload
+	}
+	return nil
+}
+
+func (x *UpdateUserSettingsRequest) GetClearProfileImage() bool {
+	if x != nil && x.ClearProfileImage != nil {
+		return *x.ClearProfileImage
+	}
+	return false
+}
+
+func (x *UpdateUserSettingsRequest) GetSyncIntervalMinutes() int32 {
+	if x != nil && x.SyncIntervalMinutes != nil {
+		return *x.SyncIntervalMinutes
+	}
+	return 0
+}
+
+// UpdateUserSettingsResponse returns the updated user settings view.
+type UpdateUserSettingsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateUserSettingsResponse) Reset() {
+	*x = UpdateUserSettingsResponse{}
+	mi := &file_proto_etu_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateUserSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateUserSettingsResponse) ProtoMessage() {}
+
+func (x *UpdateUserSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateUserSettingsResponse.ProtoReflect.Descriptor instead.
+func (*UpdateUserSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *UpdateUserSettingsResponse) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+// GetStatsRequest requests aggregate statistics.
+type GetStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id scopes stats to a single user when provided.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserByStripeCustomerIdResponse) Reset() {
-	*x = GetUserByStripeCustomerIdResponse{}
-	mi := &file_proto_etu_proto_msgTypes[29]
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_proto_etu_proto_msgTypes[88]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserByStripeCustomerIdResponse) String() string {
+func (x *GetStatsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserByStripeCustomerIdResponse) ProtoMessage() {}
+func (*GetStatsRequest) ProtoMessage() {}
 
-func (x *GetUserByStripeCustomerIdResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[29]
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[88]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1987,44 +5689,320 @@ func (x *GetUserByStripeCustomerIdResponse) ProtoReflect() protoreflect.Message
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserByStripeCustomerIdResponse.ProtoReflect.Descriptor instead.
-func (*GetUserByStripeCustomerIdResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{88}
 }
 
-func (x *GetUserByStripeCustomerIdResponse) GetUser() *User {
+func (x *GetStatsRequest) GetUserId() string {
 	if x != nil {
-		return x.User
+		return x.UserId
+	}
+	return ""
+}
+
+// GetStatsResponse contains aggregate note and tag metrics.
+type GetStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// total_blips is the total number of notes.
+	TotalBlips int64 `protobuf:"varint,1,opt,name=total_blips,json=totalBlips,proto3" json:"total_blips,omitempty"`
+	// unique_tags is the number of distinct tags.
+	UniqueTags int64 `protobuf:"varint,2,opt,name=unique_tags,json=uniqueTags,proto3" json:"unique_tags,omitempty"`
+	// words_written is the total word count across matching notes.
+	WordsWritten  int64 `protobuf:"varint,3,opt,name=words_written,json=wordsWritten,proto3" json:"words_written,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsResponse) Reset() {
+	*x = GetStatsResponse{}
+	mi := &file_proto_etu_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsResponse) ProtoMessage() {}
+
+func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *GetStatsResponse) GetTotalBlips() int64 {
+	if x != nil {
+		return x.TotalBlips
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetUniqueTags() int64 {
+	if x != nil {
+		return x.UniqueTags
+	}
+	return 0
+}
+
+func (x *GetStatsResponse) GetWordsWritten() int64 {
+	if x != nil {
+		return x.WordsWritten
+	}
+	return 0
+}
+
+// GetMoodBreakdownRequest requests how many of a user's notes have each mood.
+type GetMoodBreakdownRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// start_date is an inclusive lower bound on note creation time in ISO 8601 format.
+	StartDate string `protobuf:"bytes,2,opt,name=start_date,json=startDate,proto3" json:"start_date,omitempty"`
+	// end_date is an inclusive upper bound on note creation time in ISO 8601 format.
+	EndDate       string `protobuf:"bytes,3,opt,name=end_date,json=endDate,proto3" json:"end_date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMoodBreakdownRequest) Reset() {
+	*x = GetMoodBreakdownRequest{}
+	mi := &file_proto_etu_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMoodBreakdownRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMoodBreakdownRequest) ProtoMessage() {}
+
+func (x *GetMoodBreakdownRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMoodBreakdownRequest.ProtoReflect.Descriptor instead.
+func (*GetMoodBreakdownRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *GetMoodBreakdownRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetMoodBreakdownRequest) GetStartDate() string {
+	if x != nil {
+		return x.StartDate
+	}
+	return ""
+}
+
+func (x *GetMoodBreakdownRequest) GetEndDate() string {
+	if x != nil {
+		return x.EndDate
+	}
+	return ""
+}
+
+// MoodCount is the number of notes with one mood.
+type MoodCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// mood is the mood label.
+	Mood string `protobuf:"bytes,1,opt,name=mood,proto3" json:"mood,omitempty"`
+	// count is the number of notes with the mood.
+	Count         int64 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MoodCount) Reset() {
+	*x = MoodCount{}
+	mi := &file_proto_etu_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MoodCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MoodCount) ProtoMessage() {}
+
+func (x *MoodCount) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MoodCount.ProtoReflect.Descriptor instead.
+func (*MoodCount) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *MoodCount) GetMood() string {
+	if x != nil {
+		return x.Mood
+	}
+	return ""
+}
+
+func (x *MoodCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// GetMoodBreakdownResponse returns mood counts, most common first.
+type GetMoodBreakdownResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// moods holds each mood found in the range. Notes not yet analyzed are
+	// left out.
+	Moods         []*MoodCount `protobuf:"bytes,1,rep,name=moods,proto3" json:"moods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMoodBreakdownResponse) Reset() {
+	*x = GetMoodBreakdownResponse{}
+	mi := &file_proto_etu_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMoodBreakdownResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMoodBreakdownResponse) ProtoMessage() {}
+
+func (x *GetMoodBreakdownResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMoodBreakdownResponse.ProtoReflect.Descriptor instead.
+func (*GetMoodBreakdownResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *GetMoodBreakdownResponse) GetMoods() []*MoodCount {
+	if x != nil {
+		return x.Moods
 	}
 	return nil
 }
 
-// UpdateUserSubscriptionRequest updates subscription billing fields.
-type UpdateUserSubscriptionRequest struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	UserId             string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	SubscriptionStatus string                 `protobuf:"bytes,2,opt,name=subscription_status,json=subscriptionStatus,proto3" json:"subscription_status,omitempty"`
-	StripeCustomerId   *string                `protobuf:"bytes,3,opt,name=stripe_customer_id,json=stripeCustomerId,proto3,oneof" json:"stripe_customer_id,omitempty"`
-	SubscriptionEnd    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=subscription_end,json=subscriptionEnd,proto3,oneof" json:"subscription_end,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+// GetStorageUsageRequest requests media storage usage for a user.
+type GetStorageUsageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserSubscriptionRequest) Reset() {
-	*x = UpdateUserSubscriptionRequest{}
-	mi := &file_proto_etu_proto_msgTypes[30]
+func (x *GetStorageUsageRequest) Reset() {
+	*x = GetStorageUsageRequest{}
+	mi := &file_proto_etu_proto_msgTypes[93]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserSubscriptionRequest) String() string {
+func (x *GetStorageUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStorageUsageRequest) ProtoMessage() {}
+
+func (x *GetStorageUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStorageUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetStorageUsageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *GetStorageUsageRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// GetStorageUsageResponse contains media storage totals for a user.
+type GetStorageUsageResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// total_bytes is the combined size of the user's images and audio files.
+	TotalBytes int64 `protobuf:"varint,1,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	// object_count is the number of stored images and audio files.
+	ObjectCount   int64 `protobuf:"varint,2,opt,name=object_count,json=objectCount,proto3" json:"object_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStorageUsageResponse) Reset() {
+	*x = GetStorageUsageResponse{}
+	mi := &file_proto_etu_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStorageUsageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserSubscriptionRequest) ProtoMessage() {}
+func (*GetStorageUsageResponse) ProtoMessage() {}
 
-func (x *UpdateUserSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[30]
+func (x *GetStorageUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[94]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2035,62 +6013,59 @@ func (x *UpdateUserSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{30}
-}
-
-func (x *UpdateUserSubscriptionRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use GetStorageUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetStorageUsageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{94}
 }
 
-func (x *UpdateUserSubscriptionRequest) GetSubscriptionStatus() string {
+func (x *GetStorageUsageResponse) GetTotalBytes() int64 {
 	if x != nil {
-		return x.SubscriptionStatus
-	}
-	return ""
-}
-
-func (x *UpdateUserSubscriptionRequest) GetStripeCustomerId() string {
-	if x != nil && x.StripeCustomerId != nil {
-		return *x.StripeCustomerId
+		return x.TotalBytes
 	}
-	return ""
+	return 0
 }
 
-func (x *UpdateUserSubscriptionRequest) GetSubscriptionEnd() *timestamppb.Timestamp {
+func (x *GetStorageUsageResponse) GetObjectCount() int64 {
 	if x != nil {
-		return x.SubscriptionEnd
+		return x.ObjectCount
 	}
-	return nil
+	return 0
 }
 
-// UpdateUserSubscriptionResponse returns the updated user record.
-type UpdateUserSubscriptionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+// NoteTemplate is a user's reusable starting point for new notes.
+type NoteTemplate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is the unique identifier of the template.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name is the label the user picks the template by.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// content is copied into notes created from the template.
+	Content string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// tags are normalized tag names applied to notes created from the template.
+	Tags []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
+	// created_at is when the template was created.
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// updated_at is when the template was last modified.
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserSubscriptionResponse) Reset() {
-	*x = UpdateUserSubscriptionResponse{}
-	mi := &file_proto_etu_proto_msgTypes[31]
+func (x *NoteTemplate) Reset() {
+	*x = NoteTemplate{}
+	mi := &file_proto_etu_proto_msgTypes[95]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserSubscriptionResponse) String() string {
+func (x *NoteTemplate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserSubscriptionResponse) ProtoMessage() {}
+func (*NoteTemplate) ProtoMessage() {}
 
-func (x *UpdateUserSubscriptionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[31]
+func (x *NoteTemplate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[95]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2101,42 +6076,83 @@ func (x *UpdateUserSubscriptionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserSubscriptionResponse.ProtoReflect.Descriptor instead.
-func (*UpdateUserSubscriptionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{31}
+// Deprecated: Use NoteTemplate.ProtoReflect.Descriptor instead.
+func (*NoteTemplate) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{95}
 }
 
-func (x *UpdateUserSubscriptionResponse) GetUser() *User {
+func (x *NoteTemplate) GetId() string {
 	if x != nil {
-		return x.User
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NoteTemplate) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NoteTemplate) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *NoteTemplate) GetTags() []string {
+	if x != nil {
+		return x.Tags
 	}
 	return nil
 }
 
-// CreateApiKeyRequest creates a named API key for a user.
-type CreateApiKeyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+func (x *NoteTemplate) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *NoteTemplate) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// CreateTemplateRequest saves a new note template.
+type CreateTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// name is the template label.
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// content is the template body.
+	Content string `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// tags are default tag names for notes created from the template.
+	Tags          []string `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateApiKeyRequest) Reset() {
-	*x = CreateApiKeyRequest{}
-	mi := &file_proto_etu_proto_msgTypes[32]
+func (x *CreateTemplateRequest) Reset() {
+	*x = CreateTemplateRequest{}
+	mi := &file_proto_etu_proto_msgTypes[96]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateApiKeyRequest) String() string {
+func (x *CreateTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateApiKeyRequest) ProtoMessage() {}
+func (*CreateTemplateRequest) ProtoMessage() {}
 
-func (x *CreateApiKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[32]
+func (x *CreateTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[96]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2147,50 +6163,62 @@ func (x *CreateApiKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateApiKeyRequest.ProtoReflect.Descriptor instead.
-func (*CreateApiKeyRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{32}
+// Deprecated: Use CreateTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CreateTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{96}
 }
 
-func (x *CreateApiKeyRequest) GetUserId() string {
+func (x *CreateTemplateRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *CreateApiKeyRequest) GetName() string {
+func (x *CreateTemplateRequest) GetName() string {
 	if x != nil {
 		return x.Name
 	}
 	return ""
 }
 
-// CreateApiKeyResponse returns key metadata and a one-time raw key value.
-type CreateApiKeyResponse struct {
-	state  protoimpl.MessageState `protogen:"open.v1"`
-	ApiKey *ApiKey                `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-	// raw_key is only returned at creation time and should be stored securely.
-	RawKey        string `protobuf:"bytes,2,opt,name=raw_key,json=rawKey,proto3" json:"raw_key,omitempty"`
+func (x *CreateTemplateRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *CreateTemplateRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+// CreateTemplateResponse returns the saved template.
+type CreateTemplateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Template      *NoteTemplate          `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateApiKeyResponse) Reset() {
-	*x = CreateApiKeyResponse{}
-	mi := &file_proto_etu_proto_msgTypes[33]
+func (x *CreateTemplateResponse) Reset() {
+	*x = CreateTemplateResponse{}
+	mi := &file_proto_etu_proto_msgTypes[97]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateApiKeyResponse) String() string {
+func (x *CreateTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateApiKeyResponse) ProtoMessage() {}
+func (*CreateTemplateResponse) ProtoMessage() {}
 
-func (x *CreateApiKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[33]
+func (x *CreateTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[97]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2201,48 +6229,42 @@ func (x *CreateApiKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateApiKeyResponse.ProtoReflect.Descriptor instead.
-func (*CreateApiKeyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use CreateTemplateResponse.ProtoReflect.Descriptor instead.
+func (*CreateTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{97}
 }
 
-func (x *CreateApiKeyResponse) GetApiKey() *ApiKey {
+func (x *CreateTemplateResponse) GetTemplate() *NoteTemplate {
 	if x != nil {
-		return x.ApiKey
+		return x.Template
 	}
 	return nil
 }
 
-func (x *CreateApiKeyResponse) GetRawKey() string {
-	if x != nil {
-		return x.RawKey
-	}
-	return ""
-}
-
-// ListApiKeysRequest requests API keys for a user.
-type ListApiKeysRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+// ListTemplatesRequest lists a user's templates.
+type ListTemplatesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListApiKeysRequest) Reset() {
-	*x = ListApiKeysRequest{}
-	mi := &file_proto_etu_proto_msgTypes[34]
+func (x *ListTemplatesRequest) Reset() {
+	*x = ListTemplatesRequest{}
+	mi := &file_proto_etu_proto_msgTypes[98]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListApiKeysRequest) String() string {
+func (x *ListTemplatesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListApiKeysRequest) ProtoMessage() {}
+func (*ListTemplatesRequest) ProtoMessage() {}
 
-func (x *ListApiKeysRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[34]
+func (x *ListTemplatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[98]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2253,41 +6275,41 @@ func (x *ListApiKeysRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListApiKeysRequest.ProtoReflect.Descriptor instead.
-func (*ListApiKeysRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{34}
+// Deprecated: Use ListTemplatesRequest.ProtoReflect.Descriptor instead.
+func (*ListTemplatesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{98}
 }
 
-func (x *ListApiKeysRequest) GetUserId() string {
+func (x *ListTemplatesRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-// ListApiKeysResponse returns all API key metadata for a user.
-type ListApiKeysResponse struct {
+// ListTemplatesResponse returns templates ordered by name.
+type ListTemplatesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ApiKeys       []*ApiKey              `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	Templates     []*NoteTemplate        `protobuf:"bytes,1,rep,name=templates,proto3" json:"templates,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListApiKeysResponse) Reset() {
-	*x = ListApiKeysResponse{}
-	mi := &file_proto_etu_proto_msgTypes[35]
+func (x *ListTemplatesResponse) Reset() {
+	*x = ListTemplatesResponse{}
+	mi := &file_proto_etu_proto_msgTypes[99]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListApiKeysResponse) String() string {
+func (x *ListTemplatesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListApiKeysResponse) ProtoMessage() {}
+func (*ListTemplatesResponse) ProtoMessage() {}
 
-func (x *ListApiKeysResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[35]
+func (x *ListTemplatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[99]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2298,42 +6320,44 @@ func (x *ListApiKeysResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListApiKeysResponse.ProtoReflect.Descriptor instead.
-func (*ListApiKeysResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use ListTemplatesResponse.ProtoReflect.Descriptor instead.
+func (*ListTemplatesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{99}
 }
 
-func (x *ListApiKeysResponse) GetApiKeys() []*ApiKey {
+func (x *ListTemplatesResponse) GetTemplates() []*NoteTemplate {
 	if x != nil {
-		return x.ApiKeys
+		return x.Templates
 	}
 	return nil
 }
 
-// DeleteApiKeyRequest identifies an API key to revoke.
-type DeleteApiKeyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	KeyId         string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId,proto3" json:"key_id,omitempty"`
+// GetTemplateRequest identifies a template to fetch.
+type GetTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the unique identifier of the template.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteApiKeyRequest) Reset() {
-	*x = DeleteApiKeyRequest{}
-	mi := &file_proto_etu_proto_msgTypes[36]
+func (x *GetTemplateRequest) Reset() {
+	*x = GetTemplateRequest{}
+	mi := &file_proto_etu_proto_msgTypes[100]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteApiKeyRequest) String() string {
+func (x *GetTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteApiKeyRequest) ProtoMessage() {}
+func (*GetTemplateRequest) ProtoMessage() {}
 
-func (x *DeleteApiKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[36]
+func (x *GetTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[100]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2344,48 +6368,48 @@ func (x *DeleteApiKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteApiKeyRequest.ProtoReflect.Descriptor instead.
-func (*DeleteApiKeyRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{36}
+// Deprecated: Use GetTemplateRequest.ProtoReflect.Descriptor instead.
+func (*GetTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{100}
 }
 
-func (x *DeleteApiKeyRequest) GetUserId() string {
+func (x *GetTemplateRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *DeleteApiKeyRequest) GetKeyId() string {
+func (x *GetTemplateRequest) GetId() string {
 	if x != nil {
-		return x.KeyId
+		return x.Id
 	}
 	return ""
 }
 
-// DeleteApiKeyResponse reports whether an API key deletion occurred.
-type DeleteApiKeyResponse struct {
+// GetTemplateResponse returns the requested template.
+type GetTemplateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Template      *NoteTemplate          `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteApiKeyResponse) Reset() {
-	*x = DeleteApiKeyResponse{}
-	mi := &file_proto_etu_proto_msgTypes[37]
+func (x *GetTemplateResponse) Reset() {
+	*x = GetTemplateResponse{}
+	mi := &file_proto_etu_proto_msgTypes[101]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteApiKeyResponse) String() string {
+func (x *GetTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteApiKeyResponse) ProtoMessage() {}
+func (*GetTemplateResponse) ProtoMessage() {}
 
-func (x *DeleteApiKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[37]
+func (x *GetTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[101]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2396,41 +6420,52 @@ func (x *DeleteApiKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteApiKeyResponse.ProtoReflect.Descriptor instead.
-func (*DeleteApiKeyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{37}
+// Deprecated: Use GetTemplateResponse.ProtoReflect.Descriptor instead.
+func (*GetTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{101}
 }
 
-func (x *DeleteApiKeyResponse) GetSuccess() bool {
+func (x *GetTemplateResponse) GetTemplate() *NoteTemplate {
 	if x != nil {
-		return x.Success
+		return x.Template
 	}
-	return false
+	return nil
 }
 
-// VerifyApiKeyRequest verifies a raw API key value.
-type VerifyApiKeyRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	RawKey        string                 `protobuf:"bytes,1,opt,name=raw_key,json=rawKey,proto3" json:"raw_key,omitempty"`
+// UpdateTemplateRequest changes fields of a template.
+type UpdateTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the unique identifier of the template to update.
+	Id string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	// name updates the template label when provided.
+	Name *string `protobuf:"bytes,3,opt,name=name,proto3,oneof" json:"name,omitempty"`
+	// content updates the template body when provided.
+	Content *string `protobuf:"bytes,4,opt,name=content,proto3,oneof" json:"content,omitempty"`
+	// tags is the tag list used when update_tags is true.
+	Tags []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	// update_tags controls whether the default tags are replaced from tags.
+	UpdateTags    bool `protobuf:"varint,6,opt,name=update_tags,json=updateTags,proto3" json:"update_tags,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *VerifyApiKeyRequest) Reset() {
-	*x = VerifyApiKeyRequest{}
-	mi := &file_proto_etu_proto_msgTypes[38]
+func (x *UpdateTemplateRequest) Reset() {
+	*x = UpdateTemplateRequest{}
+	mi := &file_proto_etu_proto_msgTypes[102]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *VerifyApiKeyRequest) String() string {
+func (x *UpdateTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VerifyApiKeyRequest) ProtoMessage() {}
+func (*UpdateTemplateRequest) ProtoMessage() {}
 
-func (x *VerifyApiKeyRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[38]
+func (x *UpdateTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[102]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2441,42 +6476,76 @@ func (x *VerifyApiKeyRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VerifyApiKeyRequest.ProtoReflect.Descriptor instead.
-func (*VerifyApiKeyRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{38}
+// Deprecated: Use UpdateTemplateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *UpdateTemplateRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateTemplateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateTemplateRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *UpdateTemplateRequest) GetContent() string {
+	if x != nil && x.Content != nil {
+		return *x.Content
+	}
+	return ""
+}
+
+func (x *UpdateTemplateRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
 }
 
-func (x *VerifyApiKeyRequest) GetRawKey() string {
+func (x *UpdateTemplateRequest) GetUpdateTags() bool {
 	if x != nil {
-		return x.RawKey
+		return x.UpdateTags
 	}
-	return ""
+	return false
 }
 
-// VerifyApiKeyResponse reports verification status and associated user.
-type VerifyApiKeyResponse struct {
+// UpdateTemplateResponse returns the updated template.
+type UpdateTemplateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Valid         bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
-	UserId        *string                `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`
+	Template      *NoteTemplate          `protobuf:"bytes,1,opt,name=template,proto3" json:"template,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *VerifyApiKeyResponse) Reset() {
-	*x = VerifyApiKeyResponse{}
-	mi := &file_proto_etu_proto_msgTypes[39]
+func (x *UpdateTemplateResponse) Reset() {
+	*x = UpdateTemplateResponse{}
+	mi := &file_proto_etu_proto_msgTypes[103]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *VerifyApiKeyResponse) String() string {
+func (x *UpdateTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*VerifyApiKeyResponse) ProtoMessage() {}
+func (*UpdateTemplateResponse) ProtoMessage() {}
 
-func (x *VerifyApiKeyResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[39]
+func (x *UpdateTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[103]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2487,48 +6556,44 @@ func (x *VerifyApiKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use VerifyApiKeyResponse.ProtoReflect.Descriptor instead.
-func (*VerifyApiKeyResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{39}
+// Deprecated: Use UpdateTemplateResponse.ProtoReflect.Descriptor instead.
+func (*UpdateTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{103}
 }
 
-func (x *VerifyApiKeyResponse) GetValid() bool {
+func (x *UpdateTemplateResponse) GetTemplate() *NoteTemplate {
 	if x != nil {
-		return x.Valid
-	}
-	return false
-}
-
-func (x *VerifyApiKeyResponse) GetUserId() string {
-	if x != nil && x.UserId != nil {
-		return *x.UserId
+		return x.Template
 	}
-	return ""
+	return nil
 }
 
-// GetUserSettingsRequest fetches mutable user settings fields.
-type GetUserSettingsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+// DeleteTemplateRequest identifies a template to delete.
+type DeleteTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// id is the unique identifier of the template to delete.
+	Id            string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserSettingsRequest) Reset() {
-	*x = GetUserSettingsRequest{}
-	mi := &file_proto_etu_proto_msgTypes[40]
+func (x *DeleteTemplateRequest) Reset() {
+	*x = DeleteTemplateRequest{}
+	mi := &file_proto_etu_proto_msgTypes[104]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserSettingsRequest) String() string {
+func (x *DeleteTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserSettingsRequest) ProtoMessage() {}
+func (*DeleteTemplateRequest) ProtoMessage() {}
 
-func (x *GetUserSettingsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[40]
+func (x *DeleteTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[104]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2539,41 +6604,48 @@ func (x *GetUserSettingsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserSettingsRequest.ProtoReflect.Descriptor instead.
-func (*GetUserSettingsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{40}
+// Deprecated: Use DeleteTemplateRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{104}
 }
 
-func (x *GetUserSettingsRequest) GetUserId() string {
+func (x *DeleteTemplateRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-// GetUserSettingsResponse returns settings on the User message.
-type GetUserSettingsResponse struct {
+func (x *DeleteTemplateRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// DeleteTemplateResponse reports whether a template was deleted.
+type DeleteTemplateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetUserSettingsResponse) Reset() {
-	*x = GetUserSettingsResponse{}
-	mi := &file_proto_etu_proto_msgTypes[41]
+func (x *DeleteTemplateResponse) Reset() {
+	*x = DeleteTemplateResponse{}
+	mi := &file_proto_etu_proto_msgTypes[105]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetUserSettingsResponse) String() string {
+func (x *DeleteTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetUserSettingsResponse) ProtoMessage() {}
+func (*DeleteTemplateResponse) ProtoMessage() {}
 
-func (x *GetUserSettingsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[41]
+func (x *DeleteTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[105]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2584,47 +6656,44 @@ func (x *GetUserSettingsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetUserSettingsResponse.ProtoReflect.Descriptor instead.
-func (*GetUserSettingsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{41}
+// Deprecated: Use DeleteTemplateResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{105}
 }
 
-func (x *GetUserSettingsResponse) GetUser() *User {
+func (x *DeleteTemplateResponse) GetSuccess() bool {
 	if x != nil {
-		return x.User
+		return x.Success
 	}
-	return nil
+	return false
 }
 
-// UpdateUserSettingsRequest updates profile and integration settings.
-type UpdateUserSettingsRequest struct {
-	state              protoimpl.MessageState `protogen:"open.v1"`
-	UserId             string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	NotionKey          *string                `protobuf:"bytes,2,opt,name=notion_key,json=notionKey,proto3,oneof" json:"notion_key,omitempty"`
-	Name               *string                `protobuf:"bytes,4,opt,name=name,proto3,oneof" json:"name,omitempty"`
-	Password           *string                `protobuf:"bytes,6,opt,name=password,proto3,oneof" json:"password,omitempty"`
-	NotionDatabaseName *string                `protobuf:"bytes,7,opt,name=notion_database_name,json=notionDatabaseName,proto3,oneof" json:"notion_database_name,omitempty"`
-	ProfileImageUpload *ImageUpload           `protobuf:"bytes,8,opt,name=profile_image_upload,json=profileImageUpload,proto3,oneof" json:"profile_image_upload,omitempty"`
-	ClearProfileImage  *bool                  `protobuf:"varint,9,opt,name=clear_profile_image,json=clearProfileImage,proto3,oneof" json:"clear_profile_image,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+// CreateNoteFromTemplateRequest creates a note from a template.
+type CreateNoteFromTemplateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user_id is the target user identifier.
+	UserId string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// template_id is the template to copy content and tags from.
+	TemplateId    string `protobuf:"bytes,2,opt,name=template_id,json=templateId,proto3" json:"template_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserSettingsRequest) Reset() {
-	*x = UpdateUserSettingsRequest{}
-	mi := &file_proto_etu_proto_msgTypes[42]
+func (x *CreateNoteFromTemplateRequest) Reset() {
+	*x = CreateNoteFromTemplateRequest{}
+	mi := &file_proto_etu_proto_msgTypes[106]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserSettingsRequest) String() string {
+func (x *CreateNoteFromTemplateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserSettingsRequest) ProtoMessage() {}
+func (*CreateNoteFromTemplateRequest) ProtoMessage() {}
 
-func (x *UpdateUserSettingsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[42]
+func (x *CreateNoteFromTemplateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[106]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2635,83 +6704,48 @@ func (x *UpdateUserSettingsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserSettingsRequest.ProtoReflect.Descriptor instead.
-func (*UpdateUserSettingsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{42}
+// Deprecated: Use CreateNoteFromTemplateRequest.ProtoReflect.Descriptor instead.
+func (*CreateNoteFromTemplateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{106}
 }
 
-func (x *UpdateUserSettingsRequest) GetUserId() string {
+func (x *CreateNoteFromTemplateRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-func (x *UpdateUserSettingsRequest) GetNotionKey() string {
-	if x != nil && x.NotionKey != nil {
-		return *x.NotionKey
-	}
-	return ""
-}
-
-func (x *UpdateUserSettingsRequest) GetName() string {
-	if x != nil && x.Name != nil {
-		return *x.Name
-	}
-	return ""
-}
-
-func (x *UpdateUserSettingsRequest) GetPassword() string {
-	if x != nil && x.Password != nil {
-		return *x.Password
-	}
-	return ""
-}
-
-func (x *UpdateUserSettingsRequest) GetNotionDatabaseName() string {
-	if x != nil && x.NotionDatabaseName != nil {
-		return *x.NotionDatabaseName
-	}
-	return ""
-}
-
-func (x *UpdateUserSettingsRequest) GetProfileImageUpload() *ImageUpload {
+func (x *CreateNoteFromTemplateRequest) GetTemplateId() string {
 	if x != nil {
-		return x.ProfileImageUpload
-	}
-	return nil
-}
-
-func (x *UpdateUserSettingsRequest) GetClearProfileImage() bool {
-	if x != nil && x.ClearProfileImage != nil {
-		return *x.ClearProfileImage
+		return x.TemplateId
 	}
-	return false
+	return ""
 }
 
-// UpdateUserSettingsResponse returns the updated user settings view.
-type UpdateUserSettingsResponse struct {
+// CreateNoteFromTemplateResponse returns the new note.
+type CreateNoteFromTemplateResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	User          *User                  `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	Note          *Note                  `protobuf:"bytes,1,opt,name=note,proto3" json:"note,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateUserSettingsResponse) Reset() {
-	*x = UpdateUserSettingsResponse{}
-	mi := &file_proto_etu_proto_msgTypes[43]
+func (x *CreateNoteFromTemplateResponse) Reset() {
+	*x = CreateNoteFromTemplateResponse{}
+	mi := &file_proto_etu_proto_msgTypes[107]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateUserSettingsResponse) String() string {
+func (x *CreateNoteFromTemplateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateUserSettingsResponse) ProtoMessage() {}
+func (*CreateNoteFromTemplateResponse) ProtoMessage() {}
 
-func (x *UpdateUserSettingsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[43]
+func (x *CreateNoteFromTemplateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[107]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2722,42 +6756,41 @@ func (x *UpdateUserSettingsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateUserSettingsResponse.ProtoReflect.Descriptor instead.
-func (*UpdateUserSettingsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{43}
+// Deprecated: Use CreateNoteFromTemplateResponse.ProtoReflect.Descriptor instead.
+func (*CreateNoteFromTemplateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{107}
 }
 
-func (x *UpdateUserSettingsResponse) GetUser() *User {
+func (x *CreateNoteFromTemplateResponse) GetNote() *Note {
 	if x != nil {
-		return x.User
+		return x.Note
 	}
 	return nil
 }
 
-// GetStatsRequest requests aggregate statistics.
-type GetStatsRequest struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// user_id scopes stats to a single user when provided.
-	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+// ResetSyncStateRequest forgets a user's Notion sync progress.
+type ResetSyncStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsRequest) Reset() {
-	*x = GetStatsRequest{}
-	mi := &file_proto_etu_proto_msgTypes[44]
+func (x *ResetSyncStateRequest) Reset() {
+	*x = ResetSyncStateRequest{}
+	mi := &file_proto_etu_proto_msgTypes[108]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsRequest) String() string {
+func (x *ResetSyncStateRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsRequest) ProtoMessage() {}
+func (*ResetSyncStateRequest) ProtoMessage() {}
 
-func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[44]
+func (x *ResetSyncStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[108]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2768,46 +6801,42 @@ func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
-func (*GetStatsRequest) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{44}
+// Deprecated: Use ResetSyncStateRequest.ProtoReflect.Descriptor instead.
+func (*ResetSyncStateRequest) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{108}
 }
 
-func (x *GetStatsRequest) GetUserId() string {
+func (x *ResetSyncStateRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-// GetStatsResponse contains aggregate note and tag metrics.
-type GetStatsResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// total_blips is the total number of notes.
-	TotalBlips int64 `protobuf:"varint,1,opt,name=total_blips,json=totalBlips,proto3" json:"total_blips,omitempty"`
-	// unique_tags is the number of distinct tags.
-	UniqueTags int64 `protobuf:"varint,2,opt,name=unique_tags,json=uniqueTags,proto3" json:"unique_tags,omitempty"`
-	// words_written is the total word count across matching notes.
-	WordsWritten  int64 `protobuf:"varint,3,opt,name=words_written,json=wordsWritten,proto3" json:"words_written,omitempty"`
+// ResetSyncStateResponse reports the reset. success is true even if the user
+// had never synced.
+type ResetSyncStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetStatsResponse) Reset() {
-	*x = GetStatsResponse{}
-	mi := &file_proto_etu_proto_msgTypes[45]
+func (x *ResetSyncStateResponse) Reset() {
+	*x = ResetSyncStateResponse{}
+	mi := &file_proto_etu_proto_msgTypes[109]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetStatsResponse) String() string {
+func (x *ResetSyncStateResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetStatsResponse) ProtoMessage() {}
+func (*ResetSyncStateResponse) ProtoMessage() {}
 
-func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_etu_proto_msgTypes[45]
+func (x *ResetSyncStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_etu_proto_msgTypes[109]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2818,57 +6847,47 @@ func (x *GetStatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetStatsResponse.ProtoReflect.Descriptor instead.
-func (*GetStatsResponse) Descriptor() ([]byte, []int) {
-	return file_proto_etu_proto_rawDescGZIP(), []int{45}
-}
-
-func (x *GetStatsResponse) GetTotalBlips() int64 {
-	if x != nil {
-		return x.TotalBlips
-	}
-	return 0
-}
-
-func (x *GetStatsResponse) GetUniqueTags() int64 {
-	if x != nil {
-		return x.UniqueTags
-	}
-	return 0
+// Deprecated: Use ResetSyncStateResponse.ProtoReflect.Descriptor instead.
+func (*ResetSyncStateResponse) Descriptor() ([]byte, []int) {
+	return file_proto_etu_proto_rawDescGZIP(), []int{109}
 }
 
-func (x *GetStatsResponse) GetWordsWritten() int64 {
+func (x *ResetSyncStateResponse) GetSuccess() bool {
 	if x != nil {
-		return x.WordsWritten
+		return x.Success
 	}
-	return 0
+	return false
 }
 
 var File_proto_etu_proto protoreflect.FileDescriptor
 
 const file_proto_etu_proto_rawDesc = "" +
 	"\n" +
-	"\x0fproto/etu.proto\x12\x03etu\x1a\x1fgoogle/protobuf/timestamp.proto\">\n" +
+	"\x0fproto/etu.proto\x12\x03etu\x1a\x1fgoogle/protobuf/timestamp.proto\"X\n" +
 	"\vImageUpload\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1b\n" +
-	"\tmime_type\x18\x02 \x01(\tR\bmimeType\">\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x18\n" +
+	"\acaption\x18\x03 \x01(\tR\acaption\">\n" +
 	"\vAudioUpload\x12\x12\n" +
 	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1b\n" +
-	"\tmime_type\x18\x02 \x01(\tR\bmimeType\"\xac\x01\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\"\xf4\x01\n" +
 	"\tNoteImage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03url\x18\x02 \x01(\tR\x03url\x12%\n" +
 	"\x0eextracted_text\x18\x03 \x01(\tR\rextractedText\x12\x1b\n" +
 	"\tmime_type\x18\x04 \x01(\tR\bmimeType\x129\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb0\x01\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x18\n" +
+	"\acaption\x18\x06 \x01(\tR\acaption\x12\x14\n" +
+	"\x05width\x18\a \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\b \x01(\x05R\x06height\"\xb0\x01\n" +
 	"\tNoteAudio\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x10\n" +
 	"\x03url\x18\x02 \x01(\tR\x03url\x12)\n" +
 	"\x10transcribed_text\x18\x03 \x01(\tR\x0ftranscribedText\x12\x1b\n" +
 	"\tmime_type\x18\x04 \x01(\tR\bmimeType\x129\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8a\x02\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xa4\x03\n" +
 	"\x04Note\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x12\n" +
@@ -2878,13 +6897,22 @@ const file_proto_etu_proto_rawDesc = "" +
 	"\n" +
 	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12&\n" +
 	"\x06images\x18\x06 \x03(\v2\x0e.etu.NoteImageR\x06images\x12&\n" +
-	"\x06audios\x18\a \x03(\v2\x0e.etu.NoteAudioR\x06audios\"z\n" +
+	"\x06audios\x18\a \x03(\v2\x0e.etu.NoteAudioR\x06audios\x12\x1d\n" +
+	"\n" +
+	"word_count\x18\b \x01(\x03R\twordCount\x12\x1d\n" +
+	"\n" +
+	"char_count\x18\t \x01(\x03R\tcharCount\x12\x16\n" +
+	"\x06pinned\x18\n" +
+	" \x01(\bR\x06pinned\x12\x14\n" +
+	"\x05color\x18\v \x01(\tR\x05color\x12\x18\n" +
+	"\asummary\x18\f \x01(\tR\asummary\x12\x12\n" +
+	"\x04mood\x18\r \x01(\tR\x04mood\"z\n" +
 	"\x03Tag\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
 	"\x05count\x18\x03 \x01(\x05R\x05count\x129\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc1\x05\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xd0\x06\n" +
 	"\x04User\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
 	"\x05email\x18\x02 \x01(\tR\x05email\x12\x17\n" +
@@ -2901,15 +6929,20 @@ const file_proto_etu_proto_rawDesc = "" +
 	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1a\n" +
 	"\bdisabled\x18\f \x01(\bR\bdisabled\x12A\n" +
 	"\x0fdisabled_reason\x18\r \x01(\x0e2\x13.etu.DisabledReasonH\x05R\x0edisabledReason\x88\x01\x01\x125\n" +
-	"\x14notion_database_name\x18\x0e \x01(\tH\x06R\x12notionDatabaseName\x88\x01\x01B\a\n" +
+	"\x14notion_database_name\x18\x0e \x01(\tH\x06R\x12notionDatabaseName\x88\x01\x01\x127\n" +
+	"\x15sync_interval_minutes\x18\x0f \x01(\x05H\aR\x13syncIntervalMinutes\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"is_premium\x18\x10 \x01(\bR\tisPremium\x12\x1b\n" +
+	"\tis_active\x18\x11 \x01(\bR\bisActiveB\a\n" +
 	"\x05_nameB\b\n" +
 	"\x06_imageB\x13\n" +
 	"\x11_subscription_endB\x15\n" +
 	"\x13_stripe_customer_idB\r\n" +
 	"\v_notion_keyB\x12\n" +
 	"\x10_disabled_reasonB\x17\n" +
-	"\x15_notion_database_nameJ\x04\b\n" +
-	"\x10\v\"\xd2\x01\n" +
+	"\x15_notion_database_nameB\x18\n" +
+	"\x16_sync_interval_minutesJ\x04\b\n" +
+	"\x10\v\"\xf3\x01\n" +
 	"\x06ApiKey\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
@@ -2917,9 +6950,22 @@ const file_proto_etu_proto_rawDesc = "" +
 	"key_prefix\x18\x03 \x01(\tR\tkeyPrefix\x129\n" +
 	"\n" +
 	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12<\n" +
-	"\tlast_used\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\blastUsed\x88\x01\x01B\f\n" +
+	"\tlast_used\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\blastUsed\x88\x01\x01\x12\x1f\n" +
+	"\vusage_count\x18\x06 \x01(\x03R\n" +
+	"usageCountB\f\n" +
+	"\n" +
+	"_last_used\"\xcc\x01\n" +
+	"\n" +
+	"LoginEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x1b\n" +
+	"\tauth_type\x18\x03 \x01(\tR\bauthType\x12\x1d\n" +
+	"\n" +
+	"ip_address\x18\x04 \x01(\tR\tipAddress\x12\x1d\n" +
 	"\n" +
-	"_last_used\"\xbf\x01\n" +
+	"user_agent\x18\x05 \x01(\tR\tuserAgent\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x8c\x04\n" +
 	"\x10ListNotesRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06search\x18\x02 \x01(\tR\x06search\x12\x12\n" +
@@ -2928,25 +6974,63 @@ const file_proto_etu_proto_rawDesc = "" +
 	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x19\n" +
 	"\bend_date\x18\x05 \x01(\tR\aendDate\x12\x14\n" +
 	"\x05limit\x18\x06 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\a \x01(\x05R\x06offset\"x\n" +
+	"\x06offset\x18\a \x01(\x05R\x06offset\x12'\n" +
+	"\x0fsearch_captions\x18\b \x01(\bR\x0esearchCaptions\x12!\n" +
+	"\fsearch_media\x18\t \x01(\bR\vsearchMedia\x12\x14\n" +
+	"\x05color\x18\n" +
+	" \x01(\tR\x05color\x12\x17\n" +
+	"\asort_by\x18\v \x01(\tR\x06sortBy\x12\x19\n" +
+	"\bsort_dir\x18\f \x01(\tR\asortDir\x12&\n" +
+	"\finclude_tags\x18\r \x01(\bH\x00R\vincludeTags\x88\x01\x01\x12*\n" +
+	"\x0einclude_images\x18\x0e \x01(\bH\x01R\rincludeImages\x88\x01\x01\x12*\n" +
+	"\x0einclude_audios\x18\x0f \x01(\bH\x02R\rincludeAudios\x88\x01\x01B\x0f\n" +
+	"\r_include_tagsB\x11\n" +
+	"\x0f_include_imagesB\x11\n" +
+	"\x0f_include_audios\"\xa3\x01\n" +
 	"\x11ListNotesResponse\x12\x1f\n" +
 	"\x05notes\x18\x01 \x03(\v2\t.etu.NoteR\x05notes\x12\x14\n" +
 	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
 	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\xae\x01\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\x12)\n" +
+	"\x10total_unfiltered\x18\x05 \x01(\x05R\x0ftotalUnfiltered\"\xf4\x01\n" +
+	"\x11CountNotesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06search\x18\x02 \x01(\tR\x06search\x12\x12\n" +
+	"\x04tags\x18\x03 \x03(\tR\x04tags\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x04 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x05 \x01(\tR\aendDate\x12'\n" +
+	"\x0fsearch_captions\x18\x06 \x01(\bR\x0esearchCaptions\x12!\n" +
+	"\fsearch_media\x18\a \x01(\bR\vsearchMedia\x12\x14\n" +
+	"\x05color\x18\b \x01(\tR\x05color\"*\n" +
+	"\x12CountNotesResponse\x12\x14\n" +
+	"\x05total\x18\x01 \x01(\x05R\x05total\"\xd4\x02\n" +
 	"\x11CreateNoteRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x12\n" +
 	"\x04tags\x18\x03 \x03(\tR\x04tags\x12(\n" +
 	"\x06images\x18\x04 \x03(\v2\x10.etu.ImageUploadR\x06images\x12(\n" +
-	"\x06audios\x18\x05 \x03(\v2\x10.etu.AudioUploadR\x06audios\"3\n" +
+	"\x06audios\x18\x05 \x03(\v2\x10.etu.AudioUploadR\x06audios\x12*\n" +
+	"\x11extract_text_sync\x18\x06 \x01(\bR\x0fextractTextSync\x12'\n" +
+	"\x0fidempotency_key\x18\a \x01(\tR\x0eidempotencyKey\x12\x14\n" +
+	"\x05color\x18\b \x01(\tR\x05color\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"3\n" +
 	"\x12CreateNoteResponse\x12\x1d\n" +
-	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"9\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"\xc8\x02\n" +
 	"\x0eGetNoteRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
-	"\x02id\x18\x02 \x01(\tR\x02id\"0\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12,\n" +
+	"\x12url_expiry_seconds\x18\x03 \x01(\x05R\x10urlExpirySeconds\x12(\n" +
+	"\x10count_image_text\x18\x04 \x01(\bR\x0ecountImageText\x12&\n" +
+	"\finclude_tags\x18\x05 \x01(\bH\x00R\vincludeTags\x88\x01\x01\x12*\n" +
+	"\x0einclude_images\x18\x06 \x01(\bH\x01R\rincludeImages\x88\x01\x01\x12*\n" +
+	"\x0einclude_audios\x18\a \x01(\bH\x02R\rincludeAudios\x88\x01\x01B\x0f\n" +
+	"\r_include_tagsB\x11\n" +
+	"\x0f_include_imagesB\x11\n" +
+	"\x0f_include_audios\"0\n" +
 	"\x0fGetNoteResponse\x12\x1d\n" +
-	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"\xfe\x01\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"\xcf\x02\n" +
 	"\x11UpdateNoteRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
 	"\x02id\x18\x02 \x01(\tR\x02id\x12\x1d\n" +
@@ -2957,9 +7041,12 @@ const file_proto_etu_proto_rawDesc = "" +
 	"\n" +
 	"add_images\x18\x06 \x03(\v2\x10.etu.ImageUploadR\taddImages\x12/\n" +
 	"\n" +
-	"add_audios\x18\a \x03(\v2\x10.etu.AudioUploadR\taddAudiosB\n" +
+	"add_audios\x18\a \x03(\v2\x10.etu.AudioUploadR\taddAudios\x12*\n" +
+	"\x11extract_text_sync\x18\b \x01(\bR\x0fextractTextSync\x12\x19\n" +
+	"\x05color\x18\t \x01(\tH\x01R\x05color\x88\x01\x01B\n" +
 	"\n" +
-	"\b_content\"3\n" +
+	"\b_contentB\b\n" +
+	"\x06_color\"3\n" +
 	"\x12UpdateNoteResponse\x12\x1d\n" +
 	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"<\n" +
 	"\x11DeleteNoteRequest\x12\x17\n" +
@@ -2971,11 +7058,107 @@ const file_proto_etu_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05count\x18\x02 \x01(\x05R\x05count\"9\n" +
 	"\x16GetRandomNotesResponse\x12\x1f\n" +
-	"\x05notes\x18\x01 \x03(\v2\t.etu.NoteR\x05notes\"*\n" +
+	"\x05notes\x18\x01 \x03(\v2\t.etu.NoteR\x05notes\"\x93\x01\n" +
+	"\x18ListModifiedSinceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x120\n" +
+	"\x05since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x05since\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x04 \x01(\tR\x06cursor\"\x98\x01\n" +
+	"\x19ListModifiedSinceResponse\x12\x1f\n" +
+	"\x05notes\x18\x01 \x03(\v2\t.etu.NoteR\x05notes\x129\n" +
+	"\n" +
+	"next_since\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tnextSince\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
+	"nextCursor\"r\n" +
+	"\x12SuggestTagsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x19\n" +
+	"\bmax_tags\x18\x04 \x01(\x05R\amaxTags\")\n" +
+	"\x13SuggestTagsResponse\x12\x12\n" +
+	"\x04tags\x18\x01 \x03(\tR\x04tags\"U\n" +
+	"\x14ReprocessNoteRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05tasks\x18\x03 \x03(\tR\x05tasks\"6\n" +
+	"\x15ReprocessNoteResponse\x12\x1d\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"e\n" +
+	"\x14ReorderImagesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\anote_id\x18\x02 \x01(\tR\x06noteId\x12\x1b\n" +
+	"\timage_ids\x18\x03 \x03(\tR\bimageIds\"6\n" +
+	"\x15ReorderImagesResponse\x12\x1d\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"0\n" +
+	"\x15FindDuplicatesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"Z\n" +
+	"\rDuplicateNote\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\":\n" +
+	"\x0eDuplicateGroup\x12(\n" +
+	"\x05notes\x18\x01 \x03(\v2\x12.etu.DuplicateNoteR\x05notes\"E\n" +
+	"\x16FindDuplicatesResponse\x12+\n" +
+	"\x06groups\x18\x01 \x03(\v2\x13.etu.DuplicateGroupR\x06groups\"h\n" +
+	"\x11MergeNotesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\tR\btargetId\x12\x1d\n" +
+	"\n" +
+	"source_ids\x18\x03 \x03(\tR\tsourceIds\"3\n" +
+	"\x12MergeNotesResponse\x12\x1d\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"?\n" +
+	"\x14DuplicateNoteRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"6\n" +
+	"\x15DuplicateNoteResponse\x12\x1d\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"W\n" +
+	"\x14SetNotePinnedRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x16\n" +
+	"\x06pinned\x18\x03 \x01(\bR\x06pinned\"6\n" +
+	"\x15SetNotePinnedResponse\x12\x1d\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"i\n" +
+	"\x19UpdateImageCaptionRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bimage_id\x18\x02 \x01(\tR\aimageId\x12\x18\n" +
+	"\acaption\x18\x03 \x01(\tR\acaption\"B\n" +
+	"\x1aUpdateImageCaptionResponse\x12$\n" +
+	"\x05image\x18\x01 \x01(\v2\x0e.etu.NoteImageR\x05image\",\n" +
+	"\x11WatchNotesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xbc\x01\n" +
+	"\tNoteEvent\x12&\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x12.etu.NoteEventTypeR\x04type\x12\x17\n" +
+	"\anote_id\x18\x02 \x01(\tR\x06noteId\x12\x1d\n" +
+	"\x04note\x18\x03 \x01(\v2\t.etu.NoteR\x04note\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\":\n" +
+	"\x12WatchNotesResponse\x12$\n" +
+	"\x05event\x18\x01 \x01(\v2\x0e.etu.NoteEventR\x05event\"*\n" +
 	"\x0fListTagsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"0\n" +
 	"\x10ListTagsResponse\x12\x1c\n" +
-	"\x04tags\x18\x01 \x03(\v2\b.etu.TagR\x04tags\"C\n" +
+	"\x04tags\x18\x01 \x03(\v2\b.etu.TagR\x04tags\"h\n" +
+	"\x13GetTagCountsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\"4\n" +
+	"\x14GetTagCountsResponse\x12\x1c\n" +
+	"\x04tags\x18\x01 \x03(\v2\b.etu.TagR\x04tags\"a\n" +
+	"\x15GetRelatedTagsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\btag_name\x18\x02 \x01(\tR\atagName\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"6\n" +
+	"\x16GetRelatedTagsResponse\x12\x1c\n" +
+	"\x04tags\x18\x01 \x03(\v2\b.etu.TagR\x04tags\"N\n" +
+	"\rGetTagRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"c\n" +
+	"\x0eGetTagResponse\x12\x1a\n" +
+	"\x03tag\x18\x01 \x01(\v2\b.etu.TagR\x03tag\x12\x1f\n" +
+	"\x05notes\x18\x02 \x03(\v2\t.etu.NoteR\x05notes\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\"C\n" +
 	"\x0fRegisterRequest\x12\x14\n" +
 	"\x05email\x18\x01 \x01(\tR\x05email\x12\x1a\n" +
 	"\bpassword\x18\x02 \x01(\tR\bpassword\"1\n" +
@@ -2991,7 +7174,43 @@ const file_proto_etu_proto_rawDesc = "" +
 	"\x0eGetUserRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"0\n" +
 	"\x0fGetUserResponse\x12\x1d\n" +
-	"\x04user\x18\x01 \x01(\v2\t.etu.UserR\x04user\"P\n" +
+	"\x04user\x18\x01 \x01(\v2\t.etu.UserR\x04user\"7\n" +
+	"\x1cGetSubscriptionStatusRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xed\x01\n" +
+	"\x1dGetSubscriptionStatusResponse\x12/\n" +
+	"\x13subscription_status\x18\x01 \x01(\tR\x12subscriptionStatus\x12J\n" +
+	"\x10subscription_end\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampH\x00R\x0fsubscriptionEnd\x88\x01\x01\x12\x1d\n" +
+	"\n" +
+	"is_premium\x18\x03 \x01(\bR\tisPremium\x12\x1b\n" +
+	"\tis_active\x18\x04 \x01(\bR\bisActiveB\x13\n" +
+	"\x11_subscription_end\"[\n" +
+	"\x15AdminListUsersRequest\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"}\n" +
+	"\x16AdminListUsersResponse\x12\x1f\n" +
+	"\x05users\x18\x01 \x03(\v2\t.etu.UserR\x05users\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"_\n" +
+	"\x17AdminDisableUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12+\n" +
+	"\x06reason\x18\x02 \x01(\x0e2\x13.etu.DisabledReasonR\x06reason\"9\n" +
+	"\x18AdminDisableUserResponse\x12\x1d\n" +
+	"\x04user\x18\x01 \x01(\v2\t.etu.UserR\x04user\"1\n" +
+	"\x16AdminEnableUserRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"8\n" +
+	"\x17AdminEnableUserResponse\x12\x1d\n" +
+	"\x04user\x18\x01 \x01(\v2\t.etu.UserR\x04user\"4\n" +
+	"\x19AdminUnlockAccountRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\";\n" +
+	"\x1aAdminUnlockAccountResponse\x12\x1d\n" +
+	"\x04user\x18\x01 \x01(\v2\t.etu.UserR\x04user\"G\n" +
+	"\x16GetLoginHistoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"B\n" +
+	"\x17GetLoginHistoryResponse\x12'\n" +
+	"\x06events\x18\x01 \x03(\v2\x0f.etu.LoginEventR\x06events\"P\n" +
 	" GetUserByStripeCustomerIdRequest\x12,\n" +
 	"\x12stripe_customer_id\x18\x01 \x01(\tR\x10stripeCustomerId\"P\n" +
 	"!GetUserByStripeCustomerIdResponse\x12\"\n" +
@@ -3031,7 +7250,7 @@ const file_proto_etu_proto_rawDesc = "" +
 	"\x16GetUserSettingsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\">\n" +
 	"\x17GetUserSettingsResponse\x12\x1d\n" +
-	"\x04user\x18\x02 \x01(\v2\t.etu.UserR\x04userJ\x04\b\x01\x10\x02\"\xc2\x03\n" +
+	"\x04user\x18\x02 \x01(\v2\t.etu.UserR\x04userJ\x04\b\x01\x10\x02\"\x95\x04\n" +
 	"\x19UpdateUserSettingsRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\"\n" +
 	"\n" +
@@ -3040,13 +7259,16 @@ const file_proto_etu_proto_rawDesc = "" +
 	"\bpassword\x18\x06 \x01(\tH\x02R\bpassword\x88\x01\x01\x125\n" +
 	"\x14notion_database_name\x18\a \x01(\tH\x03R\x12notionDatabaseName\x88\x01\x01\x12G\n" +
 	"\x14profile_image_upload\x18\b \x01(\v2\x10.etu.ImageUploadH\x04R\x12profileImageUpload\x88\x01\x01\x123\n" +
-	"\x13clear_profile_image\x18\t \x01(\bH\x05R\x11clearProfileImage\x88\x01\x01B\r\n" +
+	"\x13clear_profile_image\x18\t \x01(\bH\x05R\x11clearProfileImage\x88\x01\x01\x127\n" +
+	"\x15sync_interval_minutes\x18\n" +
+	" \x01(\x05H\x06R\x13syncIntervalMinutes\x88\x01\x01B\r\n" +
 	"\v_notion_keyB\a\n" +
 	"\x05_nameB\v\n" +
 	"\t_passwordB\x17\n" +
 	"\x15_notion_database_nameB\x17\n" +
 	"\x15_profile_image_uploadB\x16\n" +
-	"\x14_clear_profile_imageJ\x04\b\x03\x10\x04J\x04\b\x05\x10\x06\"A\n" +
+	"\x14_clear_profile_imageB\x18\n" +
+	"\x16_sync_interval_minutesJ\x04\b\x03\x10\x04J\x04\b\x05\x10\x06\"A\n" +
 	"\x1aUpdateUserSettingsResponse\x12\x1d\n" +
 	"\x04user\x18\x02 \x01(\v2\t.etu.UserR\x04userJ\x04\b\x01\x10\x02\"*\n" +
 	"\x0fGetStatsRequest\x12\x17\n" +
@@ -3056,32 +7278,130 @@ const file_proto_etu_proto_rawDesc = "" +
 	"totalBlips\x12\x1f\n" +
 	"\vunique_tags\x18\x02 \x01(\x03R\n" +
 	"uniqueTags\x12#\n" +
-	"\rwords_written\x18\x03 \x01(\x03R\fwordsWritten*|\n" +
+	"\rwords_written\x18\x03 \x01(\x03R\fwordsWritten\"l\n" +
+	"\x17GetMoodBreakdownRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"start_date\x18\x02 \x01(\tR\tstartDate\x12\x19\n" +
+	"\bend_date\x18\x03 \x01(\tR\aendDate\"5\n" +
+	"\tMoodCount\x12\x12\n" +
+	"\x04mood\x18\x01 \x01(\tR\x04mood\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"@\n" +
+	"\x18GetMoodBreakdownResponse\x12$\n" +
+	"\x05moods\x18\x01 \x03(\v2\x0e.etu.MoodCountR\x05moods\"1\n" +
+	"\x16GetStorageUsageRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"]\n" +
+	"\x17GetStorageUsageResponse\x12\x1f\n" +
+	"\vtotal_bytes\x18\x01 \x01(\x03R\n" +
+	"totalBytes\x12!\n" +
+	"\fobject_count\x18\x02 \x01(\x03R\vobjectCount\"\xd6\x01\n" +
+	"\fNoteTemplate\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"r\n" +
+	"\x15CreateTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\"G\n" +
+	"\x16CreateTemplateResponse\x12-\n" +
+	"\btemplate\x18\x01 \x01(\v2\x11.etu.NoteTemplateR\btemplate\"/\n" +
+	"\x14ListTemplatesRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"H\n" +
+	"\x15ListTemplatesResponse\x12/\n" +
+	"\ttemplates\x18\x01 \x03(\v2\x11.etu.NoteTemplateR\ttemplates\"=\n" +
+	"\x12GetTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"D\n" +
+	"\x13GetTemplateResponse\x12-\n" +
+	"\btemplate\x18\x01 \x01(\v2\x11.etu.NoteTemplateR\btemplate\"\xc2\x01\n" +
+	"\x15UpdateTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x17\n" +
+	"\x04name\x18\x03 \x01(\tH\x00R\x04name\x88\x01\x01\x12\x1d\n" +
+	"\acontent\x18\x04 \x01(\tH\x01R\acontent\x88\x01\x01\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\x12\x1f\n" +
+	"\vupdate_tags\x18\x06 \x01(\bR\n" +
+	"updateTagsB\a\n" +
+	"\x05_nameB\n" +
+	"\n" +
+	"\b_content\"G\n" +
+	"\x16UpdateTemplateResponse\x12-\n" +
+	"\btemplate\x18\x01 \x01(\v2\x11.etu.NoteTemplateR\btemplate\"@\n" +
+	"\x15DeleteTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"2\n" +
+	"\x16DeleteTemplateResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"Y\n" +
+	"\x1dCreateNoteFromTemplateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1f\n" +
+	"\vtemplate_id\x18\x02 \x01(\tR\n" +
+	"templateId\"?\n" +
+	"\x1eCreateNoteFromTemplateResponse\x12\x1d\n" +
+	"\x04note\x18\x01 \x01(\v2\t.etu.NoteR\x04note\"0\n" +
+	"\x15ResetSyncStateRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"2\n" +
+	"\x16ResetSyncStateResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess*|\n" +
 	"\x0eDisabledReason\x12\x0f\n" +
 	"\vUNSPECIFIED\x10\x00\x12\x13\n" +
 	"\x0fTERMS_VIOLATION\x10\x01\x12\x14\n" +
 	"\x10SECURITY_CONCERN\x10\x02\x12\x10\n" +
 	"\fUSER_REQUEST\x10\x03\x12\x11\n" +
 	"\rPAYMENT_ISSUE\x10\x04\x12\t\n" +
-	"\x05OTHER\x10\x052\x88\x03\n" +
+	"\x05OTHER\x10\x05*\xa9\x01\n" +
+	"\rNoteEventType\x12\x1f\n" +
+	"\x1bNOTE_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17NOTE_EVENT_TYPE_CREATED\x10\x01\x12\x1b\n" +
+	"\x17NOTE_EVENT_TYPE_UPDATED\x10\x02\x12\x1b\n" +
+	"\x17NOTE_EVENT_TYPE_DELETED\x10\x03\x12 \n" +
+	"\x1cNOTE_EVENT_TYPE_TAGS_CHANGED\x10\x042\x9f\t\n" +
 	"\fNotesService\x12:\n" +
 	"\tListNotes\x12\x15.etu.ListNotesRequest\x1a\x16.etu.ListNotesResponse\x12=\n" +
 	"\n" +
+	"CountNotes\x12\x16.etu.CountNotesRequest\x1a\x17.etu.CountNotesResponse\x12=\n" +
+	"\n" +
 	"CreateNote\x12\x16.etu.CreateNoteRequest\x1a\x17.etu.CreateNoteResponse\x124\n" +
 	"\aGetNote\x12\x13.etu.GetNoteRequest\x1a\x14.etu.GetNoteResponse\x12=\n" +
 	"\n" +
 	"UpdateNote\x12\x16.etu.UpdateNoteRequest\x1a\x17.etu.UpdateNoteResponse\x12=\n" +
 	"\n" +
 	"DeleteNote\x12\x16.etu.DeleteNoteRequest\x1a\x17.etu.DeleteNoteResponse\x12I\n" +
-	"\x0eGetRandomNotes\x12\x1a.etu.GetRandomNotesRequest\x1a\x1b.etu.GetRandomNotesResponse2F\n" +
+	"\x0eGetRandomNotes\x12\x1a.etu.GetRandomNotesRequest\x1a\x1b.etu.GetRandomNotesResponse\x12R\n" +
+	"\x11ListModifiedSince\x12\x1d.etu.ListModifiedSinceRequest\x1a\x1e.etu.ListModifiedSinceResponse\x12@\n" +
+	"\vSuggestTags\x12\x17.etu.SuggestTagsRequest\x1a\x18.etu.SuggestTagsResponse\x12F\n" +
+	"\rReprocessNote\x12\x19.etu.ReprocessNoteRequest\x1a\x1a.etu.ReprocessNoteResponse\x12F\n" +
+	"\rReorderImages\x12\x19.etu.ReorderImagesRequest\x1a\x1a.etu.ReorderImagesResponse\x12U\n" +
+	"\x12UpdateImageCaption\x12\x1e.etu.UpdateImageCaptionRequest\x1a\x1f.etu.UpdateImageCaptionResponse\x12I\n" +
+	"\x0eFindDuplicates\x12\x1a.etu.FindDuplicatesRequest\x1a\x1b.etu.FindDuplicatesResponse\x12=\n" +
+	"\n" +
+	"MergeNotes\x12\x16.etu.MergeNotesRequest\x1a\x17.etu.MergeNotesResponse\x12F\n" +
+	"\rDuplicateNote\x12\x19.etu.DuplicateNoteRequest\x1a\x1a.etu.DuplicateNoteResponse\x12F\n" +
+	"\rSetNotePinned\x12\x19.etu.SetNotePinnedRequest\x1a\x1a.etu.SetNotePinnedResponse\x12?\n" +
+	"\n" +
+	"WatchNotes\x12\x16.etu.WatchNotesRequest\x1a\x17.etu.WatchNotesResponse0\x012\x89\x02\n" +
 	"\vTagsService\x127\n" +
-	"\bListTags\x12\x14.etu.ListTagsRequest\x1a\x15.etu.ListTagsResponse2\x90\x03\n" +
+	"\bListTags\x12\x14.etu.ListTagsRequest\x1a\x15.etu.ListTagsResponse\x121\n" +
+	"\x06GetTag\x12\x12.etu.GetTagRequest\x1a\x13.etu.GetTagResponse\x12C\n" +
+	"\fGetTagCounts\x12\x18.etu.GetTagCountsRequest\x1a\x19.etu.GetTagCountsResponse\x12I\n" +
+	"\x0eGetRelatedTags\x12\x1a.etu.GetRelatedTagsRequest\x1a\x1b.etu.GetRelatedTagsResponse2\xff\x06\n" +
 	"\vAuthService\x127\n" +
 	"\bRegister\x12\x14.etu.RegisterRequest\x1a\x15.etu.RegisterResponse\x12C\n" +
 	"\fAuthenticate\x12\x18.etu.AuthenticateRequest\x1a\x19.etu.AuthenticateResponse\x124\n" +
-	"\aGetUser\x12\x13.etu.GetUserRequest\x1a\x14.etu.GetUserResponse\x12j\n" +
+	"\aGetUser\x12\x13.etu.GetUserRequest\x1a\x14.etu.GetUserResponse\x12^\n" +
+	"\x15GetSubscriptionStatus\x12!.etu.GetSubscriptionStatusRequest\x1a\".etu.GetSubscriptionStatusResponse\x12L\n" +
+	"\x0fGetLoginHistory\x12\x1b.etu.GetLoginHistoryRequest\x1a\x1c.etu.GetLoginHistoryResponse\x12j\n" +
 	"\x19GetUserByStripeCustomerId\x12%.etu.GetUserByStripeCustomerIdRequest\x1a&.etu.GetUserByStripeCustomerIdResponse\x12a\n" +
-	"\x16UpdateUserSubscription\x12\".etu.UpdateUserSubscriptionRequest\x1a#.etu.UpdateUserSubscriptionResponse2\xa1\x02\n" +
+	"\x16UpdateUserSubscription\x12\".etu.UpdateUserSubscriptionRequest\x1a#.etu.UpdateUserSubscriptionResponse\x12I\n" +
+	"\x0eAdminListUsers\x12\x1a.etu.AdminListUsersRequest\x1a\x1b.etu.AdminListUsersResponse\x12O\n" +
+	"\x10AdminDisableUser\x12\x1c.etu.AdminDisableUserRequest\x1a\x1d.etu.AdminDisableUserResponse\x12L\n" +
+	"\x0fAdminEnableUser\x12\x1b.etu.AdminEnableUserRequest\x1a\x1c.etu.AdminEnableUserResponse\x12U\n" +
+	"\x12AdminUnlockAccount\x12\x1e.etu.AdminUnlockAccountRequest\x1a\x1f.etu.AdminUnlockAccountResponse2\xa1\x02\n" +
 	"\x0eApiKeysService\x12C\n" +
 	"\fCreateApiKey\x12\x18.etu.CreateApiKeyRequest\x1a\x19.etu.CreateApiKeyResponse\x12@\n" +
 	"\vListApiKeys\x12\x17.etu.ListApiKeysRequest\x1a\x18.etu.ListApiKeysResponse\x12C\n" +
@@ -3089,9 +7409,20 @@ const file_proto_etu_proto_rawDesc = "" +
 	"\fVerifyApiKey\x12\x18.etu.VerifyApiKeyRequest\x1a\x19.etu.VerifyApiKeyResponse2\xba\x01\n" +
 	"\x13UserSettingsService\x12L\n" +
 	"\x0fGetUserSettings\x12\x1b.etu.GetUserSettingsRequest\x1a\x1c.etu.GetUserSettingsResponse\x12U\n" +
-	"\x12UpdateUserSettings\x12\x1e.etu.UpdateUserSettingsRequest\x1a\x1f.etu.UpdateUserSettingsResponse2G\n" +
+	"\x12UpdateUserSettings\x12\x1e.etu.UpdateUserSettingsRequest\x1a\x1f.etu.UpdateUserSettingsResponse2\xe6\x01\n" +
 	"\fStatsService\x127\n" +
-	"\bGetStats\x12\x14.etu.GetStatsRequest\x1a\x15.etu.GetStatsResponseB#Z!github.com/icco/etu-backend/protob\x06proto3"
+	"\bGetStats\x12\x14.etu.GetStatsRequest\x1a\x15.etu.GetStatsResponse\x12L\n" +
+	"\x0fGetStorageUsage\x12\x1b.etu.GetStorageUsageRequest\x1a\x1c.etu.GetStorageUsageResponse\x12O\n" +
+	"\x10GetMoodBreakdown\x12\x1c.etu.GetMoodBreakdownRequest\x1a\x1d.etu.GetMoodBreakdownResponse2\xe0\x03\n" +
+	"\x10TemplatesService\x12I\n" +
+	"\x0eCreateTemplate\x12\x1a.etu.CreateTemplateRequest\x1a\x1b.etu.CreateTemplateResponse\x12F\n" +
+	"\rListTemplates\x12\x19.etu.ListTemplatesRequest\x1a\x1a.etu.ListTemplatesResponse\x12@\n" +
+	"\vGetTemplate\x12\x17.etu.GetTemplateRequest\x1a\x18.etu.GetTemplateResponse\x12I\n" +
+	"\x0eUpdateTemplate\x12\x1a.etu.UpdateTemplateRequest\x1a\x1b.etu.UpdateTemplateResponse\x12I\n" +
+	"\x0eDeleteTemplate\x12\x1a.etu.DeleteTemplateRequest\x1a\x1b.etu.DeleteTemplateResponse\x12a\n" +
+	"\x16CreateNoteFromTemplate\x12\".etu.CreateNoteFromTemplateRequest\x1a#.etu.CreateNoteFromTemplateResponse2X\n" +
+	"\vSyncService\x12I\n" +
+	"\x0eResetSyncState\x12\x1a.etu.ResetSyncStateRequest\x1a\x1b.etu.ResetSyncStateResponseB#Z!github.com/icco/etu-backend/protob\x06proto3"
 
 var (
 	file_proto_etu_proto_rawDescOnce sync.Once
@@ -3105,136 +7436,296 @@ func file_proto_etu_proto_rawDescGZIP() []byte {
 	return file_proto_etu_proto_rawDescData
 }
 
-var file_proto_etu_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_etu_proto_msgTypes = make([]protoimpl.MessageInfo, 46)
+var file_proto_etu_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_etu_proto_msgTypes = make([]protoimpl.MessageInfo, 110)
 var file_proto_etu_proto_goTypes = []any{
 	(DisabledReason)(0),                       // 0: etu.DisabledReason
-	(*ImageUpload)(nil),                       // 1: etu.ImageUpload
-	(*AudioUpload)(nil),                       // 2: etu.AudioUpload
-	(*NoteImage)(nil),                         // 3: etu.NoteImage
-	(*NoteAudio)(nil),                         // 4: etu.NoteAudio
-	(*Note)(nil),                              // 5: etu.Note
-	(*Tag)(nil),                               // 6: etu.Tag
-	(*User)(nil),                              // 7: etu.User
-	(*ApiKey)(nil),                            // 8: etu.ApiKey
-	(*ListNotesRequest)(nil),                  // 9: etu.ListNotesRequest
-	(*ListNotesResponse)(nil),                 // 10: etu.ListNotesResponse
-	(*CreateNoteRequest)(nil),                 // 11: etu.CreateNoteRequest
-	(*CreateNoteResponse)(nil),                // 12: etu.CreateNoteResponse
-	(*GetNoteRequest)(nil),                    // 13: etu.GetNoteRequest
-	(*GetNoteResponse)(nil),                   // 14: etu.GetNoteResponse
-	(*UpdateNoteRequest)(nil),                 // 15: etu.UpdateNoteRequest
-	(*UpdateNoteResponse)(nil),                // 16: etu.UpdateNoteResponse
-	(*DeleteNoteRequest)(nil),                 // 17: etu.DeleteNoteRequest
-	(*DeleteNoteResponse)(nil),                // 18: etu.DeleteNoteResponse
-	(*GetRandomNotesRequest)(nil),             // 19: etu.GetRandomNotesRequest
-	(*GetRandomNotesResponse)(nil),            // 20: etu.GetRandomNotesResponse
-	(*ListTagsRequest)(nil),                   // 21: etu.ListTagsRequest
-	(*ListTagsResponse)(nil),                  // 22: etu.ListTagsResponse
-	(*RegisterRequest)(nil),                   // 23: etu.RegisterRequest
-	(*RegisterResponse)(nil),                  // 24: etu.RegisterResponse
-	(*AuthenticateRequest)(nil),               // 25: etu.AuthenticateRequest
-	(*AuthenticateResponse)(nil),              // 26: etu.AuthenticateResponse
-	(*GetUserRequest)(nil),                    // 27: etu.GetUserRequest
-	(*GetUserResponse)(nil),                   // 28: etu.GetUserResponse
-	(*GetUserByStripeCustomerIdRequest)(nil),  // 29: etu.GetUserByStripeCustomerIdRequest
-	(*GetUserByStripeCustomerIdResponse)(nil), // 30: etu.GetUserByStripeCustomerIdResponse
-	(*UpdateUserSubscriptionRequest)(nil),     // 31: etu.UpdateUserSubscriptionRequest
-	(*UpdateUserSubscriptionResponse)(nil),    // 32: etu.UpdateUserSubscriptionResponse
-	(*CreateApiKeyRequest)(nil),               // 33: etu.CreateApiKeyRequest
-	(*CreateApiKeyResponse)(nil),              // 34: etu.CreateApiKeyResponse
-	(*ListApiKeysRequest)(nil),                // 35: etu.ListApiKeysRequest
-	(*ListApiKeysResponse)(nil),               // 36: etu.ListApiKeysResponse
-	(*DeleteApiKeyRequest)(nil),               // 37: etu.DeleteApiKeyRequest
-	(*DeleteApiKeyResponse)(nil),              // 38: etu.DeleteApiKeyResponse
-	(*VerifyApiKeyRequest)(nil),               // 39: etu.VerifyApiKeyRequest
-	(*VerifyApiKeyResponse)(nil),              // 40: etu.VerifyApiKeyResponse
-	(*GetUserSettingsRequest)(nil),            // 41: etu.GetUserSettingsRequest
-	(*GetUserSettingsResponse)(nil),           // 42: etu.GetUserSettingsResponse
-	(*UpdateUserSettingsRequest)(nil),         // 43: etu.UpdateUserSettingsRequest
-	(*UpdateUserSettingsResponse)(nil),        // 44: etu.UpdateUserSettingsResponse
-	(*GetStatsRequest)(nil),                   // 45: etu.GetStatsRequest
-	(*GetStatsResponse)(nil),                  // 46: etu.GetStatsResponse
-	(*timestamppb.Timestamp)(nil),             // 47: google.protobuf.Timestamp
+	(NoteEventType)(0),                        // 1: etu.NoteEventType
+	(*ImageUpload)(nil),                       // 2: etu.ImageUpload
+	(*AudioUpload)(nil),                       // 3: etu.AudioUpload
+	(*NoteImage)(nil),                         // 4: etu.NoteImage
+	(*NoteAudio)(nil),                         // 5: etu.NoteAudio
+	(*Note)(nil),                              // 6: etu.Note
+	(*Tag)(nil),                               // 7: etu.Tag
+	(*User)(nil),                              // 8: etu.User
+	(*ApiKey)(nil),                            // 9: etu.ApiKey
+	(*LoginEvent)(nil),                        // 10: etu.LoginEvent
+	(*ListNotesRequest)(nil),                  // 11: etu.ListNotesRequest
+	(*ListNotesResponse)(nil),                 // 12: etu.ListNotesResponse
+	(*CountNotesRequest)(nil),                 // 13: etu.CountNotesRequest
+	(*CountNotesResponse)(nil),                // 14: etu.CountNotesResponse
+	(*CreateNoteRequest)(nil),                 // 15: etu.CreateNoteRequest
+	(*CreateNoteResponse)(nil),                // 16: etu.CreateNoteResponse
+	(*GetNoteRequest)(nil),                    // 17: etu.GetNoteRequest
+	(*GetNoteResponse)(nil),                   // 18: etu.GetNoteResponse
+	(*UpdateNoteRequest)(nil),                 // 19: etu.UpdateNoteRequest
+	(*UpdateNoteResponse)(nil),                // 20: etu.UpdateNoteResponse
+	(*DeleteNoteRequest)(nil),                 // 21: etu.DeleteNoteRequest
+	(*DeleteNoteResponse)(nil),                // 22: etu.DeleteNoteResponse
+	(*GetRandomNotesRequest)(nil),             // 23: etu.GetRandomNotesRequest
+	(*GetRandomNotesResponse)(nil),            // 24: etu.GetRandomNotesResponse
+	(*ListModifiedSinceRequest)(nil),          // 25: etu.ListModifiedSinceRequest
+	(*ListModifiedSinceResponse)(nil),         // 26: etu.ListModifiedSinceResponse
+	(*SuggestTagsRequest)(nil),                // 27: etu.SuggestTagsRequest
+	(*SuggestTagsResponse)(nil),               // 28: etu.SuggestTagsResponse
+	(*ReprocessNoteRequest)(nil),              // 29: etu.ReprocessNoteRequest
+	(*ReprocessNoteResponse)(nil),             // 30: etu.ReprocessNoteResponse
+	(*ReorderImagesRequest)(nil),              // 31: etu.ReorderImagesRequest
+	(*ReorderImagesResponse)(nil),             // 32: etu.ReorderImagesResponse
+	(*FindDuplicatesRequest)(nil),             // 33: etu.FindDuplicatesRequest
+	(*DuplicateNote)(nil),                     // 34: etu.DuplicateNote
+	(*DuplicateGroup)(nil),                    // 35: etu.DuplicateGroup
+	(*FindDuplicatesResponse)(nil),            // 36: etu.FindDuplicatesResponse
+	(*MergeNotesRequest)(nil),                 // 37: etu.MergeNotesRequest
+	(*MergeNotesResponse)(nil),                // 38: etu.MergeNotesResponse
+	(*DuplicateNoteRequest)(nil),              // 39: etu.DuplicateNoteRequest
+	(*DuplicateNoteResponse)(nil),             // 40: etu.DuplicateNoteResponse
+	(*SetNotePinnedRequest)(nil),              // 41: etu.SetNotePinnedRequest
+	(*SetNotePinnedResponse)(nil),             // 42: etu.SetNotePinnedResponse
+	(*UpdateImageCaptionRequest)(nil),         // 43: etu.UpdateImageCaptionRequest
+	(*UpdateImageCaptionResponse)(nil),        // 44: etu.UpdateImageCaptionResponse
+	(*WatchNotesRequest)(nil),                 // 45: etu.WatchNotesRequest
+	(*NoteEvent)(nil),                         // 46: etu.NoteEvent
+	(*WatchNotesResponse)(nil),                // 47: etu.WatchNotesResponse
+	(*ListTagsRequest)(nil),                   // 48: etu.ListTagsRequest
+	(*ListTagsResponse)(nil),                  // 49: etu.ListTagsResponse
+	(*GetTagCountsRequest)(nil),               // 50: etu.GetTagCountsRequest
+	(*GetTagCountsResponse)(nil),              // 51: etu.GetTagCountsResponse
+	(*GetRelatedTagsRequest)(nil),             // 52: etu.GetRelatedTagsRequest
+	(*GetRelatedTagsResponse)(nil),            // 53: etu.GetRelatedTagsResponse
+	(*GetTagRequest)(nil),                     // 54: etu.GetTagRequest
+	(*GetTagResponse)(nil),                    // 55: etu.GetTagResponse
+	(*RegisterRequest)(nil),                   // 56: etu.RegisterRequest
+	(*RegisterResponse)(nil),                  // 57: etu.RegisterResponse
+	(*AuthenticateRequest)(nil),               // 58: etu.AuthenticateRequest
+	(*AuthenticateResponse)(nil),              // 59: etu.AuthenticateResponse
+	(*GetUserRequest)(nil),                    // 60: etu.GetUserRequest
+	(*GetUserResponse)(nil),                   // 61: etu.GetUserResponse
+	(*GetSubscriptionStatusRequest)(nil),      // 62: etu.GetSubscriptionStatusRequest
+	(*GetSubscriptionStatusResponse)(nil),     // 63: etu.GetSubscriptionStatusResponse
+	(*AdminListUsersRequest)(nil),             // 64: etu.AdminListUsersRequest
+	(*AdminListUsersResponse)(nil),            // 65: etu.AdminListUsersResponse
+	(*AdminDisableUserRequest)(nil),           // 66: etu.AdminDisableUserRequest
+	(*AdminDisableUserResponse)(nil),          // 67: etu.AdminDisableUserResponse
+	(*AdminEnableUserRequest)(nil),            // 68: etu.AdminEnableUserRequest
+	(*AdminEnableUserResponse)(nil),           // 69: etu.AdminEnableUserResponse
+	(*AdminUnlockAccountRequest)(nil),         // 70: etu.AdminUnlockAccountRequest
+	(*AdminUnlockAccountResponse)(nil),        // 71: etu.AdminUnlockAccountResponse
+	(*GetLoginHistoryRequest)(nil),            // 72: etu.GetLoginHistoryRequest
+	(*GetLoginHistoryResponse)(nil),           // 73: etu.GetLoginHistoryResponse
+	(*GetUserByStripeCustomerIdRequest)(nil),  // 74: etu.GetUserByStripeCustomerIdRequest
+	(*GetUserByStripeCustomerIdResponse)(nil), // 75: etu.GetUserByStripeCustomerIdResponse
+	(*UpdateUserSubscriptionRequest)(nil),     // 76: etu.UpdateUserSubscriptionRequest
+	(*UpdateUserSubscriptionResponse)(nil),    // 77: etu.UpdateUserSubscriptionResponse
+	(*CreateApiKeyRequest)(nil),               // 78: etu.CreateApiKeyRequest
+	(*CreateApiKeyResponse)(nil),              // 79: etu.CreateApiKeyResponse
+	(*ListApiKeysRequest)(nil),                // 80: etu.ListApiKeysRequest
+	(*ListApiKeysResponse)(nil),               // 81: etu.ListApiKeysResponse
+	(*DeleteApiKeyRequest)(nil),               // 82: etu.DeleteApiKeyRequest
+	(*DeleteApiKeyResponse)(nil),              // 83: etu.DeleteApiKeyResponse
+	(*VerifyApiKeyRequest)(nil),               // 84: etu.VerifyApiKeyRequest
+	(*VerifyApiKeyResponse)(nil),              // 85: etu.VerifyApiKeyResponse
+	(*GetUserSettingsRequest)(nil),            // 86: etu.GetUserSettingsRequest
+	(*GetUserSettingsResponse)(nil),           // 87: etu.GetUserSettingsResponse
+	(*UpdateUserSettingsRequest)(nil),         // 88: etu.UpdateUserSettingsRequest
+	(*UpdateUserSettingsResponse)(nil),        // 89: etu.UpdateUserSettingsResponse
+	(*GetStatsRequest)(nil),                   // 90: etu.GetStatsRequest
+	(*GetStatsResponse)(nil),                  // 91: etu.GetStatsResponse
+	(*GetMoodBreakdownRequest)(nil),           // 92: etu.GetMoodBreakdownRequest
+	(*MoodCount)(nil),                         // 93: etu.MoodCount
+	(*GetMoodBreakdownResponse)(nil),          // 94: etu.GetMoodBreakdownResponse
+	(*GetStorageUsageRequest)(nil),            // 95: etu.GetStorageUsageRequest
+	(*GetStorageUsageResponse)(nil),           // 96: etu.GetStorageUsageResponse
+	(*NoteTemplate)(nil),                      // 97: etu.NoteTemplate
+	(*CreateTemplateRequest)(nil),             // 98: etu.CreateTemplateRequest
+	(*CreateTemplateResponse)(nil),            // 99: etu.CreateTemplateResponse
+	(*ListTemplatesRequest)(nil),              // 100: etu.ListTemplatesRequest
+	(*ListTemplatesResponse)(nil),             // 101: etu.ListTemplatesResponse
+	(*GetTemplateRequest)(nil),                // 102: etu.GetTemplateRequest
+	(*GetTemplateResponse)(nil),               // 103: etu.GetTemplateResponse
+	(*UpdateTemplateRequest)(nil),             // 104: etu.UpdateTemplateRequest
+	(*UpdateTemplateResponse)(nil),            // 105: etu.UpdateTemplateResponse
+	(*DeleteTemplateRequest)(nil),             // 106: etu.DeleteTemplateRequest
+	(*DeleteTemplateResponse)(nil),            // 107: etu.DeleteTemplateResponse
+	(*CreateNoteFromTemplateRequest)(nil),     // 108: etu.CreateNoteFromTemplateRequest
+	(*CreateNoteFromTemplateResponse)(nil),    // 109: etu.CreateNoteFromTemplateResponse
+	(*ResetSyncStateRequest)(nil),             // 110: etu.ResetSyncStateRequest
+	(*ResetSyncStateResponse)(nil),            // 111: etu.ResetSyncStateResponse
+	(*timestamppb.Timestamp)(nil),             // 112: google.protobuf.Timestamp
 }
 var file_proto_etu_proto_depIdxs = []int32{
-	47, // 0: etu.NoteImage.created_at:type_name -> google.protobuf.Timestamp
-	47, // 1: etu.NoteAudio.created_at:type_name -> google.protobuf.Timestamp
-	47, // 2: etu.Note.created_at:type_name -> google.protobuf.Timestamp
-	47, // 3: etu.Note.updated_at:type_name -> google.protobuf.Timestamp
-	3,  // 4: etu.Note.images:type_name -> etu.NoteImage
-	4,  // 5: etu.Note.audios:type_name -> etu.NoteAudio
-	47, // 6: etu.Tag.created_at:type_name -> google.protobuf.Timestamp
-	47, // 7: etu.User.subscription_end:type_name -> google.protobuf.Timestamp
-	47, // 8: etu.User.created_at:type_name -> google.protobuf.Timestamp
-	47, // 9: etu.User.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 10: etu.User.disabled_reason:type_name -> etu.DisabledReason
-	47, // 11: etu.ApiKey.created_at:type_name -> google.protobuf.Timestamp
-	47, // 12: etu.ApiKey.last_used:type_name -> google.protobuf.Timestamp
-	5,  // 13: etu.ListNotesResponse.notes:type_name -> etu.Note
-	1,  // 14: etu.CreateNoteRequest.images:type_name -> etu.ImageUpload
-	2,  // 15: etu.CreateNoteRequest.audios:type_name -> etu.AudioUpload
-	5,  // 16: etu.CreateNoteResponse.note:type_name -> etu.Note
-	5,  // 17: etu.GetNoteResponse.note:type_name -> etu.Note
-	1,  // 18: etu.UpdateNoteRequest.add_images:type_name -> etu.ImageUpload
-	2,  // 19: etu.UpdateNoteRequest.add_audios:type_name -> etu.AudioUpload
-	5,  // 20: etu.UpdateNoteResponse.note:type_name -> etu.Note
-	5,  // 21: etu.GetRandomNotesResponse.notes:type_name -> etu.Note
-	6,  // 22: etu.ListTagsResponse.tags:type_name -> etu.Tag
-	7,  // 23: etu.RegisterResponse.user:type_name -> etu.User
-	7,  // 24: etu.AuthenticateResponse.user:type_name -> etu.User
-	7,  // 25: etu.GetUserResponse.user:type_name -> etu.User
-	7,  // 26: etu.GetUserByStripeCustomerIdResponse.user:type_name -> etu.User
-	47, // 27: etu.UpdateUserSubscriptionRequest.subscription_end:type_name -> google.protobuf.Timestamp
-	7,  // 28: etu.UpdateUserSubscriptionResponse.user:type_name -> etu.User
-	8,  // 29: etu.CreateApiKeyResponse.api_key:type_name -> etu.ApiKey
-	8,  // 30: etu.ListApiKeysResponse.api_keys:type_name -> etu.ApiKey
-	7,  // 31: etu.GetUserSettingsResponse.user:type_name -> etu.User
-	1,  // 32: etu.UpdateUserSettingsRequest.profile_image_upload:type_name -> etu.ImageUpload
-	7,  // 33: etu.UpdateUserSettingsResponse.user:type_name -> etu.User
-	9,  // 34: etu.NotesService.ListNotes:input_type -> etu.ListNotesRequest
-	11, // 35: etu.NotesService.CreateNote:input_type -> etu.CreateNoteRequest
-	13, // 36: etu.NotesService.GetNote:input_type -> etu.GetNoteRequest
-	15, // 37: etu.NotesService.UpdateNote:input_type -> etu.UpdateNoteRequest
-	17, // 38: etu.NotesService.DeleteNote:input_type -> etu.DeleteNoteRequest
-	19, // 39: etu.NotesService.GetRandomNotes:input_type -> etu.GetRandomNotesRequest
-	21, // 40: etu.TagsService.ListTags:input_type -> etu.ListTagsRequest
-	23, // 41: etu.AuthService.Register:input_type -> etu.RegisterRequest
-	25, // 42: etu.AuthService.Authenticate:input_type -> etu.AuthenticateRequest
-	27, // 43: etu.AuthService.GetUser:input_type -> etu.GetUserRequest
-	29, // 44: etu.AuthService.GetUserByStripeCustomerId:input_type -> etu.GetUserByStripeCustomerIdRequest
-	31, // 45: etu.AuthService.UpdateUserSubscription:input_type -> etu.UpdateUserSubscriptionRequest
-	33, // 46: etu.ApiKeysService.CreateApiKey:input_type -> etu.CreateApiKeyRequest
-	35, // 47: etu.ApiKeysService.ListApiKeys:input_type -> etu.ListApiKeysRequest
-	37, // 48: etu.ApiKeysService.DeleteApiKey:input_type -> etu.DeleteApiKeyRequest
-	39, // 49: etu.ApiKeysService.VerifyApiKey:input_type -> etu.VerifyApiKeyRequest
-	41, // 50: etu.UserSettingsService.GetUserSettings:input_type -> etu.GetUserSettingsRequest
-	43, // 51: etu.UserSettingsService.UpdateUserSettings:input_type -> etu.UpdateUserSettingsRequest
-	45, // 52: etu.StatsService.GetStats:input_type -> etu.GetStatsRequest
-	10, // 53: etu.NotesService.ListNotes:output_type -> etu.ListNotesResponse
-	12, // 54: etu.NotesService.CreateNote:output_type -> etu.CreateNoteResponse
-	14, // 55: etu.NotesService.GetNote:output_type -> etu.GetNoteResponse
-	16, // 56: etu.NotesService.UpdateNote:output_type -> etu.UpdateNoteResponse
-	18, // 57: etu.NotesService.DeleteNote:output_type -> etu.DeleteNoteResponse
-	20, // 58: etu.NotesService.GetRandomNotes:output_type -> etu.GetRandomNotesResponse
-	22, // 59: etu.TagsService.ListTags:output_type -> etu.ListTagsResponse
-	24, // 60: etu.AuthService.Register:output_type -> etu.RegisterResponse
-	26, // 61: etu.AuthService.Authenticate:output_type -> etu.AuthenticateResponse
-	28, // 62: etu.AuthService.GetUser:output_type -> etu.GetUserResponse
-	30, // 63: etu.AuthService.GetUserByStripeCustomerId:output_type -> etu.GetUserByStripeCustomerIdResponse
-	32, // 64: etu.AuthService.UpdateUserSubscription:output_type -> etu.UpdateUserSubscriptionResponse
-	34, // 65: etu.ApiKeysService.CreateApiKey:output_type -> etu.CreateApiKeyResponse
-	36, // 66: etu.ApiKeysService.ListApiKeys:output_type -> etu.ListApiKeysResponse
-	38, // 67: etu.ApiKeysService.DeleteApiKey:output_type -> etu.DeleteApiKeyResponse
-	40, // 68: etu.ApiKeysService.VerifyApiKey:output_type -> etu.VerifyApiKeyResponse
-	42, // 69: etu.UserSettingsService.GetUserSettings:output_type -> etu.GetUserSettingsResponse
-	44, // 70: etu.UserSettingsService.UpdateUserSettings:output_type -> etu.UpdateUserSettingsResponse
-	46, // 71: etu.StatsService.GetStats:output_type -> etu.GetStatsResponse
-	53, // [53:72] is the sub-list for method output_type
-	34, // [34:53] is the sub-list for method input_type
-	34, // [34:34] is the sub-list for extension type_name
-	34, // [34:34] is the sub-list for extension extendee
-	0,  // [0:34] is the sub-list for field type_name
+	112, // 0: etu.NoteImage.created_at:type_name -> google.protobuf.Timestamp
+	112, // 1: etu.NoteAudio.created_at:type_name -> google.protobuf.Timestamp
+	112, // 2: etu.Note.created_at:type_name -> google.protobuf.Timestamp
+	112, // 3: etu.Note.updated_at:type_name -> google.protobuf.Timestamp
+	4,   // 4: etu.Note.images:type_name -> etu.NoteImage
+	5,   // 5: etu.Note.audios:type_name -> etu.NoteAudio
+	112, // 6: etu.Tag.created_at:type_name -> google.protobuf.Timestamp
+	112, // 7: etu.User.subscription_end:type_name -> google.protobuf.Timestamp
+	112, // 8: etu.User.created_at:type_name -> google.protobuf.Timestamp
+	112, // 9: etu.User.updated_at:type_name -> google.protobuf.Timestamp
+	0,   // 10: etu.User.disabled_reason:type_name -> etu.DisabledReason
+	112, // 11: etu.ApiKey.created_at:type_name -> google.protobuf.Timestamp
+	112, // 12: etu.ApiKey.last_used:type_name -> google.protobuf.Timestamp
+	112, // 13: etu.LoginEvent.created_at:type_name -> google.protobuf.Timestamp
+	6,   // 14: etu.ListNotesResponse.notes:type_name -> etu.Note
+	2,   // 15: etu.CreateNoteRequest.images:type_name -> etu.ImageUpload
+	3,   // 16: etu.CreateNoteRequest.audios:type_name -> etu.AudioUpload
+	112, // 17: etu.CreateNoteRequest.created_at:type_name -> google.protobuf.Timestamp
+	6,   // 18: etu.CreateNoteResponse.note:type_name -> etu.Note
+	6,   // 19: etu.GetNoteResponse.note:type_name -> etu.Note
+	2,   // 20: etu.UpdateNoteRequest.add_images:type_name -> etu.ImageUpload
+	3,   // 21: etu.UpdateNoteRequest.add_audios:type_name -> etu.AudioUpload
+	6,   // 22: etu.UpdateNoteResponse.note:type_name -> etu.Note
+	6,   // 23: etu.GetRandomNotesResponse.notes:type_name -> etu.Note
+	112, // 24: etu.ListModifiedSinceRequest.since:type_name -> google.protobuf.Timestamp
+	6,   // 25: etu.ListModifiedSinceResponse.notes:type_name -> etu.Note
+	112, // 26: etu.ListModifiedSinceResponse.next_since:type_name -> google.protobuf.Timestamp
+	6,   // 27: etu.ReprocessNoteResponse.note:type_name -> etu.Note
+	6,   // 28: etu.ReorderImagesResponse.note:type_name -> etu.Note
+	112, // 29: etu.DuplicateNote.created_at:type_name -> google.protobuf.Timestamp
+	34,  // 30: etu.DuplicateGroup.notes:type_name -> etu.DuplicateNote
+	35,  // 31: etu.FindDuplicatesResponse.groups:type_name -> etu.DuplicateGroup
+	6,   // 32: etu.MergeNotesResponse.note:type_name -> etu.Note
+	6,   // 33: etu.DuplicateNoteResponse.note:type_name -> etu.Note
+	6,   // 34: etu.SetNotePinnedResponse.note:type_name -> etu.Note
+	4,   // 35: etu.UpdateImageCaptionResponse.image:type_name -> etu.NoteImage
+	1,   // 36: etu.NoteEvent.type:type_name -> etu.NoteEventType
+	6,   // 37: etu.NoteEvent.note:type_name -> etu.Note
+	112, // 38: etu.NoteEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	46,  // 39: etu.WatchNotesResponse.event:type_name -> etu.NoteEvent
+	7,   // 40: etu.ListTagsResponse.tags:type_name -> etu.Tag
+	7,   // 41: etu.GetTagCountsResponse.tags:type_name -> etu.Tag
+	7,   // 42: etu.GetRelatedTagsResponse.tags:type_name -> etu.Tag
+	7,   // 43: etu.GetTagResponse.tag:type_name -> etu.Tag
+	6,   // 44: etu.GetTagResponse.notes:type_name -> etu.Note
+	8,   // 45: etu.RegisterResponse.user:type_name -> etu.User
+	8,   // 46: etu.AuthenticateResponse.user:type_name -> etu.User
+	8,   // 47: etu.GetUserResponse.user:type_name -> etu.User
+	112, // 48: etu.GetSubscriptionStatusResponse.subscription_end:type_name -> google.protobuf.Timestamp
+	8,   // 49: etu.AdminListUsersResponse.users:type_name -> etu.User
+	0,   // 50: etu.AdminDisableUserRequest.reason:type_name -> etu.DisabledReason
+	8,   // 51: etu.AdminDisableUserResponse.user:type_name -> etu.User
+	8,   // 52: etu.AdminEnableUserResponse.user:type_name -> etu.User
+	8,   // 53: etu.AdminUnlockAccountResponse.user:type_name -> etu.User
+	10,  // 54: etu.GetLoginHistoryResponse.events:type_name -> etu.LoginEvent
+	8,   // 55: etu.GetUserByStripeCustomerIdResponse.user:type_name -> etu.User
+	112, // 56: etu.UpdateUserSubscriptionRequest.subscription_end:type_name -> google.protobuf.Timestamp
+	8,   // 57: etu.UpdateUserSubscriptionResponse.user:type_name -> etu.User
+	9,   // 58: etu.CreateApiKeyResponse.api_key:type_name -> etu.ApiKey
+	9,   // 59: etu.ListApiKeysResponse.api_keys:type_name -> etu.ApiKey
+	8,   // 60: etu.GetUserSettingsResponse.user:type_name -> etu.User
+	2,   // 61: etu.UpdateUserSettingsRequest.profile_image_upload:type_name -> etu.ImageUpload
+	8,   // 62: etu.UpdateUserSettingsResponse.user:type_name -> etu.User
+	93,  // 63: etu.GetMoodBreakdownResponse.moods:type_name -> etu.MoodCount
+	112, // 64: etu.NoteTemplate.created_at:type_name -> google.protobuf.Timestamp
+	112, // 65: etu.NoteTemplate.updated_at:type_name -> google.protobuf.Timestamp
+	97,  // 66: etu.CreateTemplateResponse.template:type_name -> etu.NoteTemplate
+	97,  // 67: etu.ListTemplatesResponse.templates:type_name -> etu.NoteTemplate
+	97,  // 68: etu.GetTemplateResponse.template:type_name -> etu.NoteTemplate
+	97,  // 69: etu.UpdateTemplateResponse.template:type_name -> etu.NoteTemplate
+	6,   // 70: etu.CreateNoteFromTemplateResponse.note:type_name -> etu.Note
+	11,  // 71: etu.NotesService.ListNotes:input_type -> etu.ListNotesRequest
+	13,  // 72: etu.NotesService.CountNotes:input_type -> etu.CountNotesRequest
+	15,  // 73: etu.NotesService.CreateNote:input_type -> etu.CreateNoteRequest
+	17,  // 74: etu.NotesService.GetNote:input_type -> etu.GetNoteRequest
+	19,  // 75: etu.NotesService.UpdateNote:input_type -> etu.UpdateNoteRequest
+	21,  // 76: etu.NotesService.DeleteNote:input_type -> etu.DeleteNoteRequest
+	23,  // 77: etu.NotesService.GetRandomNotes:input_type -> etu.GetRandomNotesRequest
+	25,  // 78: etu.NotesService.ListModifiedSince:input_type -> etu.ListModifiedSinceRequest
+	27,  // 79: etu.NotesService.SuggestTags:input_type -> etu.SuggestTagsRequest
+	29,  // 80: etu.NotesService.ReprocessNote:input_type -> etu.ReprocessNoteRequest
+	31,  // 81: etu.NotesService.ReorderImages:input_type -> etu.ReorderImagesRequest
+	43,  // 82: etu.NotesService.UpdateImageCaption:input_type -> etu.UpdateImageCaptionRequest
+	33,  // 83: etu.NotesService.FindDuplicates:input_type -> etu.FindDuplicatesRequest
+	37,  // 84: etu.NotesService.MergeNotes:input_type -> etu.MergeNotesRequest
+	39,  // 85: etu.NotesService.DuplicateNote:input_type -> etu.DuplicateNoteRequest
+	41,  // 86: etu.NotesService.SetNotePinned:input_type -> etu.SetNotePinnedRequest
+	45,  // 87: etu.NotesService.WatchNotes:input_type -> etu.WatchNotesRequest
+	48,  // 88: etu.TagsService.ListTags:input_type -> etu.ListTagsRequest
+	54,  // 89: etu.TagsService.GetTag:input_type -> etu.GetTagRequest
+	50,  // 90: etu.TagsService.GetTagCounts:input_type -> etu.GetTagCountsRequest
+	52,  // 91: etu.TagsService.GetRelatedTags:input_type -> etu.GetRelatedTagsRequest
+	56,  // 92: etu.AuthService.Register:input_type -> etu.RegisterRequest
+	58,  // 93: etu.AuthService.Authenticate:input_type -> etu.AuthenticateRequest
+	60,  // 94: etu.AuthService.GetUser:input_type -> etu.GetUserRequest
+	62,  // 95: etu.AuthService.GetSubscriptionStatus:input_type -> etu.GetSubscriptionStatusRequest
+	72,  // 96: etu.AuthService.GetLoginHistory:input_type -> etu.GetLoginHistoryRequest
+	74,  // 97: etu.AuthService.GetUserByStripeCustomerId:input_type -> etu.GetUserByStripeCustomerIdRequest
+	76,  // 98: etu.AuthService.UpdateUserSubscription:input_type -> etu.UpdateUserSubscriptionRequest
+	64,  // 99: etu.AuthService.AdminListUsers:input_type -> etu.AdminListUsersRequest
+	66,  // 100: etu.AuthService.AdminDisableUser:input_type -> etu.AdminDisableUserRequest
+	68,  // 101: etu.AuthService.AdminEnableUser:input_type -> etu.AdminEnableUserRequest
+	70,  // 102: etu.AuthService.AdminUnlockAccount:input_type -> etu.AdminUnlockAccountRequest
+	78,  // 103: etu.ApiKeysService.CreateApiKey:input_type -> etu.CreateApiKeyRequest
+	80,  // 104: etu.ApiKeysService.ListApiKeys:input_type -> etu.ListApiKeysRequest
+	82,  // 105: etu.ApiKeysService.DeleteApiKey:input_type -> etu.DeleteApiKeyRequest
+	84,  // 106: etu.ApiKeysService.VerifyApiKey:input_type -> etu.VerifyApiKeyRequest
+	86,  // 107: etu.UserSettingsService.GetUserSettings:input_type -> etu.GetUserSettingsRequest
+	88,  // 108: etu.UserSettingsService.UpdateUserSettings:input_type -> etu.UpdateUserSettingsRequest
+	90,  // 109: etu.StatsService.GetStats:input_type -> etu.GetStatsRequest
+	95,  // 110: etu.StatsService.GetStorageUsage:input_type -> etu.GetStorageUsageRequest
+	92,  // 111: etu.StatsService.GetMoodBreakdown:input_type -> etu.GetMoodBreakdownRequest
+	98,  // 112: etu.TemplatesService.CreateTemplate:input_type -> etu.CreateTemplateRequest
+	100, // 113: etu.TemplatesService.ListTemplates:input_type -> etu.ListTemplatesRequest
+	102, // 114: etu.TemplatesService.GetTemplate:input_type -> etu.GetTemplateRequest
+	104, // 115: etu.TemplatesService.UpdateTemplate:input_type -> etu.UpdateTemplateRequest
+	106, // 116: etu.TemplatesService.DeleteTemplate:input_type -> etu.DeleteTemplateRequest
+	108, // 117: etu.TemplatesService.CreateNoteFromTemplate:input_type -> etu.CreateNoteFromTemplateRequest
+	110, // 118: etu.SyncService.ResetSyncState:input_type -> etu.ResetSyncStateRequest
+	12,  // 119: etu.NotesService.ListNotes:output_type -> etu.ListNotesResponse
+	14,  // 120: etu.NotesService.CountNotes:output_type -> etu.CountNotesResponse
+	16,  // 121: etu.NotesService.CreateNote:output_type -> etu.CreateNoteResponse
+	18,  // 122: etu.NotesService.GetNote:output_type -> etu.GetNoteResponse
+	20,  // 123: etu.NotesService.UpdateNote:output_type -> etu.UpdateNoteResponse
+	22,  // 124: etu.NotesService.DeleteNote:output_type -> etu.DeleteNoteResponse
+	24,  // 125: etu.NotesService.GetRandomNotes:output_type -> etu.GetRandomNotesResponse
+	26,  // 126: etu.NotesService.ListModifiedSince:output_type -> etu.ListModifiedSinceResponse
+	28,  // 127: etu.NotesService.SuggestTags:output_type -> etu.SuggestTagsResponse
+	30,  // 128: etu.NotesService.ReprocessNote:output_type -> etu.ReprocessNoteResponse
+	32,  // 129: etu.NotesService.ReorderImages:output_type -> etu.ReorderImagesResponse
+	44,  // 130: etu.NotesService.UpdateImageCaption:output_type -> etu.UpdateImageCaptionResponse
+	36,  // 131: etu.NotesService.FindDuplicates:output_type -> etu.FindDuplicatesResponse
+	38,  // 132: etu.NotesService.MergeNotes:output_type -> etu.MergeNotesResponse
+	40,  // 133: etu.NotesService.DuplicateNote:output_type -> etu.DuplicateNoteResponse
+	42,  // 134: etu.NotesService.SetNotePinned:output_type -> etu.SetNotePinnedResponse
+	47,  // 135: etu.NotesService.WatchNotes:output_type -> etu.WatchNotesResponse
+	49,  // 136: etu.TagsService.ListTags:output_type -> etu.ListTagsResponse
+	55,  // 137: etu.TagsService.GetTag:output_type -> etu.GetTagResponse
+	51,  // 138: etu.TagsService.GetTagCounts:output_type -> etu.GetTagCountsResponse
+	53,  // 139: etu.TagsService.GetRelatedTags:output_type -> etu.GetRelatedTagsResponse
+	57,  // 140: etu.AuthService.Register:output_type -> etu.RegisterResponse
+	59,  // 141: etu.AuthService.Authenticate:output_type -> etu.AuthenticateResponse
+	61,  // 142: etu.AuthService.GetUser:output_type -> etu.GetUserResponse
+	63,  // 143: etu.AuthService.GetSubscriptionStatus:output_type -> etu.GetSubscriptionStatusResponse
+	73,  // 144: etu.AuthService.GetLoginHistory:output_type -> etu.GetLoginHistoryResponse
+	75,  // 145: etu.AuthService.GetUserByStripeCustomerId:output_type -> etu.GetUserByStripeCustomerIdResponse
+	77,  // 146: etu.AuthService.UpdateUserSubscription:output_type -> etu.UpdateUserSubscriptionResponse
+	65,  // 147: etu.AuthService.AdminListUsers:output_type -> etu.AdminListUsersResponse
+	67,  // 148: etu.AuthService.AdminDisableUser:output_type -> etu.AdminDisableUserResponse
+	69,  // 149: etu.AuthService.AdminEnableUser:output_type -> etu.AdminEnableUserResponse
+	71,  // 150: etu.AuthService.AdminUnlockAccount:output_type -> etu.AdminUnlockAccountResponse
+	79,  // 151: etu.ApiKeysService.CreateApiKey:output_type -> etu.CreateApiKeyResponse
+	81,  // 152: etu.ApiKeysService.ListApiKeys:output_type -> etu.ListApiKeysResponse
+	83,  // 153: etu.ApiKeysService.DeleteApiKey:output_type -> etu.DeleteApiKeyResponse
+	85,  // 154: etu.ApiKeysService.VerifyApiKey:output_type -> etu.VerifyApiKeyResponse
+	87,  // 155: etu.UserSettingsService.GetUserSettings:output_type -> etu.GetUserSettingsResponse
+	89,  // 156: etu.UserSettingsService.UpdateUserSettings:output_type -> etu.UpdateUserSettingsResponse
+	91,  // 157: etu.StatsService.GetStats:output_type -> etu.GetStatsResponse
+	96,  // 158: etu.StatsService.GetStorageUsage:output_type -> etu.GetStorageUsageResponse
+	94,  // 159: etu.StatsService.GetMoodBreakdown:output_type -> etu.GetMoodBreakdownResponse
+	99,  // 160: etu.TemplatesService.CreateTemplate:output_type -> etu.CreateTemplateResponse
+	101, // 161: etu.TemplatesService.ListTemplates:output_type -> etu.ListTemplatesResponse
+	103, // 162: etu.TemplatesService.GetTemplate:output_type -> etu.GetTemplateResponse
+	105, // 163: etu.TemplatesService.UpdateTemplate:output_type -> etu.UpdateTemplateResponse
+	107, // 164: etu.TemplatesService.DeleteTemplate:output_type -> etu.DeleteTemplateResponse
+	109, // 165: etu.TemplatesService.CreateNoteFromTemplate:output_type -> etu.CreateNoteFromTemplateResponse
+	111, // 166: etu.SyncService.ResetSyncState:output_type -> etu.ResetSyncStateResponse
+	119, // [119:167] is the sub-list for method output_type
+	71,  // [71:119] is the sub-list for method input_type
+	71,  // [71:71] is the sub-list for extension type_name
+	71,  // [71:71] is the sub-list for extension extendee
+	0,   // [0:71] is the sub-list for field type_name
 }
 
 func init() { file_proto_etu_proto_init() }
@@ -3244,21 +7735,25 @@ func file_proto_etu_proto_init() {
 	}
 	file_proto_etu_proto_msgTypes[6].OneofWrappers = []any{}
 	file_proto_etu_proto_msgTypes[7].OneofWrappers = []any{}
-	file_proto_etu_proto_msgTypes[14].OneofWrappers = []any{}
-	file_proto_etu_proto_msgTypes[25].OneofWrappers = []any{}
-	file_proto_etu_proto_msgTypes[29].OneofWrappers = []any{}
-	file_proto_etu_proto_msgTypes[30].OneofWrappers = []any{}
-	file_proto_etu_proto_msgTypes[39].OneofWrappers = []any{}
-	file_proto_etu_proto_msgTypes[42].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[9].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[15].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[17].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[57].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[61].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[73].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[74].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[83].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[86].OneofWrappers = []any{}
+	file_proto_etu_proto_msgTypes[102].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_etu_proto_rawDesc), len(file_proto_etu_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   46,
+			NumEnums:      2,
+			NumMessages:   110,
 			NumExtensions: 0,
-			NumServices:   6,
+			NumServices:   8,
 		},
 		GoTypes:           file_proto_etu_proto_goTypes,
 		DependencyIndexes: file_proto_etu_proto_depIdxs,
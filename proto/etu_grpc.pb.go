@@ -19,12 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	NotesService_ListNotes_FullMethodName      = "/etu.NotesService/ListNotes"
-	NotesService_CreateNote_FullMethodName     = "/etu.NotesService/CreateNote"
-	NotesService_GetNote_FullMethodName        = "/etu.NotesService/GetNote"
-	NotesService_UpdateNote_FullMethodName     = "/etu.NotesService/UpdateNote"
-	NotesService_DeleteNote_FullMethodName     = "/etu.NotesService/DeleteNote"
-	NotesService_GetRandomNotes_FullMethodName = "/etu.NotesService/GetRandomNotes"
+	NotesService_ListNotes_FullMethodName          = "/etu.NotesService/ListNotes"
+	NotesService_CountNotes_FullMethodName         = "/etu.NotesService/CountNotes"
+	NotesService_CreateNote_FullMethodName         = "/etu.NotesService/CreateNote"
+	NotesService_GetNote_FullMethodName            = "/etu.NotesService/GetNote"
+	NotesService_UpdateNote_FullMethodName         = "/etu.NotesService/UpdateNote"
+	NotesService_DeleteNote_FullMethodName         = "/etu.NotesService/DeleteNote"
+	NotesService_GetRandomNotes_FullMethodName     = "/etu.NotesService/GetRandomNotes"
+	NotesService_ListModifiedSince_FullMethodName  = "/etu.NotesService/ListModifiedSince"
+	NotesService_SuggestTags_FullMethodName        = "/etu.NotesService/SuggestTags"
+	NotesService_ReprocessNote_FullMethodName      = "/etu.NotesService/ReprocessNote"
+	NotesService_ReorderImages_FullMethodName      = "/etu.NotesService/ReorderImages"
+	NotesService_UpdateImageCaption_FullMethodName = "/etu.NotesService/UpdateImageCaption"
+	NotesService_FindDuplicates_FullMethodName     = "/etu.NotesService/FindDuplicates"
+	NotesService_MergeNotes_FullMethodName         = "/etu.NotesService/MergeNotes"
+	NotesService_DuplicateNote_FullMethodName      = "/etu.NotesService/DuplicateNote"
+	NotesService_SetNotePinned_FullMethodName      = "/etu.NotesService/SetNotePinned"
+	NotesService_WatchNotes_FullMethodName         = "/etu.NotesService/WatchNotes"
 )
 
 // NotesServiceClient is the client API for NotesService service.
@@ -35,6 +46,8 @@ const (
 type NotesServiceClient interface {
 	// ListNotes returns notes matching filters and pagination options.
 	ListNotes(ctx context.Context, in *ListNotesRequest, opts ...grpc.CallOption) (*ListNotesResponse, error)
+	// CountNotes returns how many notes match the ListNotes filters.
+	CountNotes(ctx context.Context, in *CountNotesRequest, opts ...grpc.CallOption) (*CountNotesResponse, error)
 	// CreateNote creates a new note with optional tags and attachments.
 	CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*CreateNoteResponse, error)
 	// GetNote returns one note by id.
@@ -45,6 +58,36 @@ type NotesServiceClient interface {
 	DeleteNote(ctx context.Context, in *DeleteNoteRequest, opts ...grpc.CallOption) (*DeleteNoteResponse, error)
 	// GetRandomNotes returns a random sample of notes.
 	GetRandomNotes(ctx context.Context, in *GetRandomNotesRequest, opts ...grpc.CallOption) (*GetRandomNotesResponse, error)
+	// ListModifiedSince returns notes updated since a watermark, for incremental
+	// export.
+	ListModifiedSince(ctx context.Context, in *ListModifiedSinceRequest, opts ...grpc.CallOption) (*ListModifiedSinceResponse, error)
+	// SuggestTags returns AI tag suggestions for a note without applying them.
+	// Requires the server to be configured with a Gemini API key.
+	SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*SuggestTagsResponse, error)
+	// ReprocessNote clears AI-derived data so background processing regenerates it.
+	ReprocessNote(ctx context.Context, in *ReprocessNoteRequest, opts ...grpc.CallOption) (*ReprocessNoteResponse, error)
+	// ReorderImages sets the display order of a note's images.
+	ReorderImages(ctx context.Context, in *ReorderImagesRequest, opts ...grpc.CallOption) (*ReorderImagesResponse, error)
+	// UpdateImageCaption sets the caption of a note image.
+	UpdateImageCaption(ctx context.Context, in *UpdateImageCaptionRequest, opts ...grpc.CallOption) (*UpdateImageCaptionResponse, error)
+	// FindDuplicates returns groups of notes with the same normalized content.
+	FindDuplicates(ctx context.Context, in *FindDuplicatesRequest, opts ...grpc.CallOption) (*FindDuplicatesResponse, error)
+	// MergeNotes merges notes into a target note and deletes the merged notes.
+	// Fails with INVALID_ARGUMENT if the merged content would be longer than
+	// MAX_CONTENT_LENGTH, and with FAILED_PRECONDITION if a source note is
+	// linked to a Notion page, which a later sync would bring back.
+	MergeNotes(ctx context.Context, in *MergeNotesRequest, opts ...grpc.CallOption) (*MergeNotesResponse, error)
+	// DuplicateNote copies a note with its tags and attachments. Attachments are
+	// stored again for the copy, so deleting either note leaves the other intact.
+	DuplicateNote(ctx context.Context, in *DuplicateNoteRequest, opts ...grpc.CallOption) (*DuplicateNoteResponse, error)
+	// SetNotePinned pins a note to the top of ListNotes or unpins it.
+	SetNotePinned(ctx context.Context, in *SetNotePinnedRequest, opts ...grpc.CallOption) (*SetNotePinnedResponse, error)
+	// WatchNotes streams create, update, delete, and tag change events for the
+	// user's notes as they happen on this server. Events from other server
+	// instances are not delivered. A watcher that falls too far behind is
+	// disconnected with ABORTED and should re-list its notes before watching
+	// again.
+	WatchNotes(ctx context.Context, in *WatchNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchNotesResponse], error)
 }
 
 type notesServiceClient struct {
@@ -65,6 +108,16 @@ func (c *notesServiceClient) ListNotes(ctx context.Context, in *ListNotesRequest
 	return out, nil
 }
 
+func (c *notesServiceClient) CountNotes(ctx context.Context, in *CountNotesRequest, opts ...grpc.CallOption) (*CountNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_CountNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *notesServiceClient) CreateNote(ctx context.Context, in *CreateNoteRequest, opts ...grpc.CallOption) (*CreateNoteResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateNoteResponse)
@@ -115,6 +168,115 @@ func (c *notesServiceClient) GetRandomNotes(ctx context.Context, in *GetRandomNo
 	return out, nil
 }
 
+func (c *notesServiceClient) ListModifiedSince(ctx context.Context, in *ListModifiedSinceRequest, opts ...grpc.CallOption) (*ListModifiedSinceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListModifiedSinceResponse)
+	err := c.cc.Invoke(ctx, NotesService_ListModifiedSince_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) SuggestTags(ctx context.Context, in *SuggestTagsRequest, opts ...grpc.CallOption) (*SuggestTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SuggestTagsResponse)
+	err := c.cc.Invoke(ctx, NotesService_SuggestTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ReprocessNote(ctx context.Context, in *ReprocessNoteRequest, opts ...grpc.CallOption) (*ReprocessNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReprocessNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_ReprocessNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) ReorderImages(ctx context.Context, in *ReorderImagesRequest, opts ...grpc.CallOption) (*ReorderImagesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReorderImagesResponse)
+	err := c.cc.Invoke(ctx, NotesService_ReorderImages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) UpdateImageCaption(ctx context.Context, in *UpdateImageCaptionRequest, opts ...grpc.CallOption) (*UpdateImageCaptionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateImageCaptionResponse)
+	err := c.cc.Invoke(ctx, NotesService_UpdateImageCaption_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) FindDuplicates(ctx context.Context, in *FindDuplicatesRequest, opts ...grpc.CallOption) (*FindDuplicatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FindDuplicatesResponse)
+	err := c.cc.Invoke(ctx, NotesService_FindDuplicates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) MergeNotes(ctx context.Context, in *MergeNotesRequest, opts ...grpc.CallOption) (*MergeNotesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MergeNotesResponse)
+	err := c.cc.Invoke(ctx, NotesService_MergeNotes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) DuplicateNote(ctx context.Context, in *DuplicateNoteRequest, opts ...grpc.CallOption) (*DuplicateNoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DuplicateNoteResponse)
+	err := c.cc.Invoke(ctx, NotesService_DuplicateNote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) SetNotePinned(ctx context.Context, in *SetNotePinnedRequest, opts ...grpc.CallOption) (*SetNotePinnedResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNotePinnedResponse)
+	err := c.cc.Invoke(ctx, NotesService_SetNotePinned_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *notesServiceClient) WatchNotes(ctx context.Context, in *WatchNotesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchNotesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &NotesService_ServiceDesc.Streams[0], NotesService_WatchNotes_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchNotesRequest, WatchNotesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_WatchNotesClient = grpc.ServerStreamingClient[WatchNotesResponse]
+
 // NotesServiceServer is the server API for NotesService service.
 // All implementations must embed UnimplementedNotesServiceServer
 // for forward compatibility.
@@ -123,6 +285,8 @@ func (c *notesServiceClient) GetRandomNotes(ctx context.Context, in *GetRandomNo
 type NotesServiceServer interface {
 	// ListNotes returns notes matching filters and pagination options.
 	ListNotes(context.Context, *ListNotesRequest) (*ListNotesResponse, error)
+	// CountNotes returns how many notes match the ListNotes filters.
+	CountNotes(context.Context, *CountNotesRequest) (*CountNotesResponse, error)
 	// CreateNote creates a new note with optional tags and attachments.
 	CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error)
 	// GetNote returns one note by id.
@@ -133,6 +297,36 @@ type NotesServiceServer interface {
 	DeleteNote(context.Context, *DeleteNoteRequest) (*DeleteNoteResponse, error)
 	// GetRandomNotes returns a random sample of notes.
 	GetRandomNotes(context.Context, *GetRandomNotesRequest) (*GetRandomNotesResponse, error)
+	// ListModifiedSince returns notes updated since a watermark, for incremental
+	// export.
+	ListModifiedSince(context.Context, *ListModifiedSinceRequest) (*ListModifiedSinceResponse, error)
+	// SuggestTags returns AI tag suggestions for a note without applying them.
+	// Requires the server to be configured with a Gemini API key.
+	SuggestTags(context.Context, *SuggestTagsRequest) (*SuggestTagsResponse, error)
+	// ReprocessNote clears AI-derived data so background processing regenerates it.
+	ReprocessNote(context.Context, *ReprocessNoteRequest) (*ReprocessNoteResponse, error)
+	// ReorderImages sets the display order of a note's images.
+	ReorderImages(context.Context, *ReorderImagesRequest) (*ReorderImagesResponse, error)
+	// UpdateImageCaption sets the caption of a note image.
+	UpdateImageCaption(context.Context, *UpdateImageCaptionRequest) (*UpdateImageCaptionResponse, error)
+	// FindDuplicates returns groups of notes with the same normalized content.
+	FindDuplicates(context.Context, *FindDuplicatesRequest) (*FindDuplicatesResponse, error)
+	// MergeNotes merges notes into a target note and deletes the merged notes.
+	// Fails with INVALID_ARGUMENT if the merged content would be longer than
+	// MAX_CONTENT_LENGTH, and with FAILED_PRECONDITION if a source note is
+	// linked to a Notion page, which a later sync would bring back.
+	MergeNotes(context.Context, *MergeNotesRequest) (*MergeNotesResponse, error)
+	// DuplicateNote copies a note with its tags and attachments. Attachments are
+	// stored again for the copy, so deleting either note leaves the other intact.
+	DuplicateNote(context.Context, *DuplicateNoteRequest) (*DuplicateNoteResponse, error)
+	// SetNotePinned pins a note to the top of ListNotes or unpins it.
+	SetNotePinned(context.Context, *SetNotePinnedRequest) (*SetNotePinnedResponse, error)
+	// WatchNotes streams create, update, delete, and tag change events for the
+	// user's notes as they happen on this server. Events from other server
+	// instances are not delivered. A watcher that falls too far behind is
+	// disconnected with ABORTED and should re-list its notes before watching
+	// again.
+	WatchNotes(*WatchNotesRequest, grpc.ServerStreamingServer[WatchNotesResponse]) error
 	mustEmbedUnimplementedNotesServiceServer()
 }
 
@@ -146,6 +340,9 @@ type UnimplementedNotesServiceServer struct{}
 func (UnimplementedNotesServiceServer) ListNotes(context.Context, *ListNotesRequest) (*ListNotesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListNotes not implemented")
 }
+func (UnimplementedNotesServiceServer) CountNotes(context.Context, *CountNotesRequest) (*CountNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CountNotes not implemented")
+}
 func (UnimplementedNotesServiceServer) CreateNote(context.Context, *CreateNoteRequest) (*CreateNoteResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateNote not implemented")
 }
@@ -161,6 +358,36 @@ func (UnimplementedNotesServiceServer) DeleteNote(context.Context, *DeleteNoteRe
 func (UnimplementedNotesServiceServer) GetRandomNotes(context.Context, *GetRandomNotesRequest) (*GetRandomNotesResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetRandomNotes not implemented")
 }
+func (UnimplementedNotesServiceServer) ListModifiedSince(context.Context, *ListModifiedSinceRequest) (*ListModifiedSinceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListModifiedSince not implemented")
+}
+func (UnimplementedNotesServiceServer) SuggestTags(context.Context, *SuggestTagsRequest) (*SuggestTagsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SuggestTags not implemented")
+}
+func (UnimplementedNotesServiceServer) ReprocessNote(context.Context, *ReprocessNoteRequest) (*ReprocessNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReprocessNote not implemented")
+}
+func (UnimplementedNotesServiceServer) ReorderImages(context.Context, *ReorderImagesRequest) (*ReorderImagesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReorderImages not implemented")
+}
+func (UnimplementedNotesServiceServer) UpdateImageCaption(context.Context, *UpdateImageCaptionRequest) (*UpdateImageCaptionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateImageCaption not implemented")
+}
+func (UnimplementedNotesServiceServer) FindDuplicates(context.Context, *FindDuplicatesRequest) (*FindDuplicatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method FindDuplicates not implemented")
+}
+func (UnimplementedNotesServiceServer) MergeNotes(context.Context, *MergeNotesRequest) (*MergeNotesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method MergeNotes not implemented")
+}
+func (UnimplementedNotesServiceServer) DuplicateNote(context.Context, *DuplicateNoteRequest) (*DuplicateNoteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DuplicateNote not implemented")
+}
+func (UnimplementedNotesServiceServer) SetNotePinned(context.Context, *SetNotePinnedRequest) (*SetNotePinnedResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetNotePinned not implemented")
+}
+func (UnimplementedNotesServiceServer) WatchNotes(*WatchNotesRequest, grpc.ServerStreamingServer[WatchNotesResponse]) error {
+	return status.Error(codes.Unimplemented, "method WatchNotes not implemented")
+}
 func (UnimplementedNotesServiceServer) mustEmbedUnimplementedNotesServiceServer() {}
 func (UnimplementedNotesServiceServer) testEmbeddedByValue()                      {}
 
@@ -200,6 +427,24 @@ func _NotesService_ListNotes_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NotesService_CountNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).CountNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_CountNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).CountNotes(ctx, req.(*CountNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _NotesService_CreateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CreateNoteRequest)
 	if err := dec(in); err != nil {
@@ -290,6 +535,179 @@ func _NotesService_GetRandomNotes_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _NotesService_ListModifiedSince_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListModifiedSinceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ListModifiedSince(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ListModifiedSince_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ListModifiedSince(ctx, req.(*ListModifiedSinceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_SuggestTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SuggestTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).SuggestTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_SuggestTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).SuggestTags(ctx, req.(*SuggestTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ReprocessNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReprocessNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ReprocessNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ReprocessNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ReprocessNote(ctx, req.(*ReprocessNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_ReorderImages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReorderImagesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).ReorderImages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_ReorderImages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).ReorderImages(ctx, req.(*ReorderImagesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_UpdateImageCaption_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateImageCaptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).UpdateImageCaption(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_UpdateImageCaption_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).UpdateImageCaption(ctx, req.(*UpdateImageCaptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_FindDuplicates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindDuplicatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).FindDuplicates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_FindDuplicates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).FindDuplicates(ctx, req.(*FindDuplicatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_MergeNotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeNotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).MergeNotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_MergeNotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).MergeNotes(ctx, req.(*MergeNotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_DuplicateNote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DuplicateNoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).DuplicateNote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_DuplicateNote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).DuplicateNote(ctx, req.(*DuplicateNoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_SetNotePinned_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNotePinnedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NotesServiceServer).SetNotePinned(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: NotesService_SetNotePinned_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NotesServiceServer).SetNotePinned(ctx, req.(*SetNotePinnedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _NotesService_WatchNotes_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchNotesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NotesServiceServer).WatchNotes(m, &grpc.GenericServerStream[WatchNotesRequest, WatchNotesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type NotesService_WatchNotesServer = grpc.ServerStreamingServer[WatchNotesResponse]
+
 // NotesService_ServiceDesc is the grpc.ServiceDesc for NotesService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -301,6 +719,10 @@ var NotesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListNotes",
 			Handler:    _NotesService_ListNotes_Handler,
 		},
+		{
+			MethodName: "CountNotes",
+			Handler:    _NotesService_CountNotes_Handler,
+		},
 		{
 			MethodName: "CreateNote",
 			Handler:    _NotesService_CreateNote_Handler,
@@ -321,13 +743,58 @@ var NotesService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRandomNotes",
 			Handler:    _NotesService_GetRandomNotes_Handler,
 		},
+		{
+			MethodName: "ListModifiedSince",
+			Handler:    _NotesService_ListModifiedSince_Handler,
+		},
+		{
+			MethodName: "SuggestTags",
+			Handler:    _NotesService_SuggestTags_Handler,
+		},
+		{
+			MethodName: "ReprocessNote",
+			Handler:    _NotesService_ReprocessNote_Handler,
+		},
+		{
+			MethodName: "ReorderImages",
+			Handler:    _NotesService_ReorderImages_Handler,
+		},
+		{
+			MethodName: "UpdateImageCaption",
+			Handler:    _NotesService_UpdateImageCaption_Handler,
+		},
+		{
+			MethodName: "FindDuplicates",
+			Handler:    _NotesService_FindDuplicates_Handler,
+		},
+		{
+			MethodName: "MergeNotes",
+			Handler:    _NotesService_MergeNotes_Handler,
+		},
+		{
+			MethodName: "DuplicateNote",
+			Handler:    _NotesService_DuplicateNote_Handler,
+		},
+		{
+			MethodName: "SetNotePinned",
+			Handler:    _NotesService_SetNotePinned_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchNotes",
+			Handler:       _NotesService_WatchNotes_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/etu.proto",
 }
 
 const (
-	TagsService_ListTags_FullMethodName = "/etu.TagsService/ListTags"
+	TagsService_ListTags_FullMethodName       = "/etu.TagsService/ListTags"
+	TagsService_GetTag_FullMethodName         = "/etu.TagsService/GetTag"
+	TagsService_GetTagCounts_FullMethodName   = "/etu.TagsService/GetTagCounts"
+	TagsService_GetRelatedTags_FullMethodName = "/etu.TagsService/GetRelatedTags"
 )
 
 // TagsServiceClient is the client API for TagsService service.
@@ -338,6 +805,13 @@ const (
 type TagsServiceClient interface {
 	// ListTags returns all tags for a user.
 	ListTags(ctx context.Context, in *ListTagsRequest, opts ...grpc.CallOption) (*ListTagsResponse, error)
+	// GetTag returns one tag with the first page of its notes.
+	GetTag(ctx context.Context, in *GetTagRequest, opts ...grpc.CallOption) (*GetTagResponse, error)
+	// GetTagCounts returns tag usage counts for notes created in a date range.
+	GetTagCounts(ctx context.Context, in *GetTagCountsRequest, opts ...grpc.CallOption) (*GetTagCountsResponse, error)
+	// GetRelatedTags returns tags that frequently appear on the same notes as a
+	// tag, for tag suggestions.
+	GetRelatedTags(ctx context.Context, in *GetRelatedTagsRequest, opts ...grpc.CallOption) (*GetRelatedTagsResponse, error)
 }
 
 type tagsServiceClient struct {
@@ -358,14 +832,51 @@ func (c *tagsServiceClient) ListTags(ctx context.Context, in *ListTagsRequest, o
 	return out, nil
 }
 
-// TagsServiceServer is the server API for TagsService service.
-// All implementations must embed UnimplementedTagsServiceServer
-// for forward compatibility.
-//
-// TagsService provides tag listing for notes.
-type TagsServiceServer interface {
+func (c *tagsServiceClient) GetTag(ctx context.Context, in *GetTagRequest, opts ...grpc.CallOption) (*GetTagResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTagResponse)
+	err := c.cc.Invoke(ctx, TagsService_GetTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagsServiceClient) GetTagCounts(ctx context.Context, in *GetTagCountsRequest, opts ...grpc.CallOption) (*GetTagCountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTagCountsResponse)
+	err := c.cc.Invoke(ctx, TagsService_GetTagCounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *tagsServiceClient) GetRelatedTags(ctx context.Context, in *GetRelatedTagsRequest, opts ...grpc.CallOption) (*GetRelatedTagsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRelatedTagsResponse)
+	err := c.cc.Invoke(ctx, TagsService_GetRelatedTags_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TagsServiceServer is the server API for TagsService service.
+// All implementations must embed UnimplementedTagsServiceServer
+// for forward compatibility.
+//
+// TagsService provides tag listing for notes.
+type TagsServiceServer interface {
 	// ListTags returns all tags for a user.
 	ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error)
+	// GetTag returns one tag with the first page of its notes.
+	GetTag(context.Context, *GetTagRequest) (*GetTagResponse, error)
+	// GetTagCounts returns tag usage counts for notes created in a date range.
+	GetTagCounts(context.Context, *GetTagCountsRequest) (*GetTagCountsResponse, error)
+	// GetRelatedTags returns tags that frequently appear on the same notes as a
+	// tag, for tag suggestions.
+	GetRelatedTags(context.Context, *GetRelatedTagsRequest) (*GetRelatedTagsResponse, error)
 	mustEmbedUnimplementedTagsServiceServer()
 }
 
@@ -379,6 +890,15 @@ type UnimplementedTagsServiceServer struct{}
 func (UnimplementedTagsServiceServer) ListTags(context.Context, *ListTagsRequest) (*ListTagsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListTags not implemented")
 }
+func (UnimplementedTagsServiceServer) GetTag(context.Context, *GetTagRequest) (*GetTagResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTag not implemented")
+}
+func (UnimplementedTagsServiceServer) GetTagCounts(context.Context, *GetTagCountsRequest) (*GetTagCountsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTagCounts not implemented")
+}
+func (UnimplementedTagsServiceServer) GetRelatedTags(context.Context, *GetRelatedTagsRequest) (*GetRelatedTagsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRelatedTags not implemented")
+}
 func (UnimplementedTagsServiceServer) mustEmbedUnimplementedTagsServiceServer() {}
 func (UnimplementedTagsServiceServer) testEmbeddedByValue()                     {}
 
@@ -418,6 +938,60 @@ func _TagsService_ListTags_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TagsService_GetTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagsServiceServer).GetTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagsService_GetTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagsServiceServer).GetTag(ctx, req.(*GetTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagsService_GetTagCounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTagCountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagsServiceServer).GetTagCounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagsService_GetTagCounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagsServiceServer).GetTagCounts(ctx, req.(*GetTagCountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TagsService_GetRelatedTags_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRelatedTagsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TagsServiceServer).GetRelatedTags(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TagsService_GetRelatedTags_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TagsServiceServer).GetRelatedTags(ctx, req.(*GetRelatedTagsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // TagsService_ServiceDesc is the grpc.ServiceDesc for TagsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -429,6 +1003,18 @@ var TagsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListTags",
 			Handler:    _TagsService_ListTags_Handler,
 		},
+		{
+			MethodName: "GetTag",
+			Handler:    _TagsService_GetTag_Handler,
+		},
+		{
+			MethodName: "GetTagCounts",
+			Handler:    _TagsService_GetTagCounts_Handler,
+		},
+		{
+			MethodName: "GetRelatedTags",
+			Handler:    _TagsService_GetRelatedTags_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/etu.proto",
@@ -438,8 +1024,14 @@ const (
 	AuthService_Register_FullMethodName                  = "/etu.AuthService/Register"
 	AuthService_Authenticate_FullMethodName              = "/etu.AuthService/Authenticate"
 	AuthService_GetUser_FullMethodName                   = "/etu.AuthService/GetUser"
+	AuthService_GetSubscriptionStatus_FullMethodName     = "/etu.AuthService/GetSubscriptionStatus"
+	AuthService_GetLoginHistory_FullMethodName           = "/etu.AuthService/GetLoginHistory"
 	AuthService_GetUserByStripeCustomerId_FullMethodName = "/etu.AuthService/GetUserByStripeCustomerId"
 	AuthService_UpdateUserSubscription_FullMethodName    = "/etu.AuthService/UpdateUserSubscription"
+	AuthService_AdminListUsers_FullMethodName            = "/etu.AuthService/AdminListUsers"
+	AuthService_AdminDisableUser_FullMethodName          = "/etu.AuthService/AdminDisableUser"
+	AuthService_AdminEnableUser_FullMethodName           = "/etu.AuthService/AdminEnableUser"
+	AuthService_AdminUnlockAccount_FullMethodName        = "/etu.AuthService/AdminUnlockAccount"
 )
 
 // AuthServiceClient is the client API for AuthService service.
@@ -454,10 +1046,24 @@ type AuthServiceClient interface {
 	Authenticate(ctx context.Context, in *AuthenticateRequest, opts ...grpc.CallOption) (*AuthenticateResponse, error)
 	// GetUser fetches a user by id.
 	GetUser(ctx context.Context, in *GetUserRequest, opts ...grpc.CallOption) (*GetUserResponse, error)
+	// GetSubscriptionStatus fetches a user's subscription state without the
+	// rest of the user record.
+	GetSubscriptionStatus(ctx context.Context, in *GetSubscriptionStatusRequest, opts ...grpc.CallOption) (*GetSubscriptionStatusResponse, error)
+	// GetLoginHistory lists a user's recent authentication attempts.
+	GetLoginHistory(ctx context.Context, in *GetLoginHistoryRequest, opts ...grpc.CallOption) (*GetLoginHistoryResponse, error)
 	// GetUserByStripeCustomerId fetches a user by Stripe customer id.
 	GetUserByStripeCustomerId(ctx context.Context, in *GetUserByStripeCustomerIdRequest, opts ...grpc.CallOption) (*GetUserByStripeCustomerIdResponse, error)
 	// UpdateUserSubscription updates billing-related subscription state.
 	UpdateUserSubscription(ctx context.Context, in *UpdateUserSubscriptionRequest, opts ...grpc.CallOption) (*UpdateUserSubscriptionResponse, error)
+	// AdminListUsers pages through all users. Requires M2M authentication.
+	AdminListUsers(ctx context.Context, in *AdminListUsersRequest, opts ...grpc.CallOption) (*AdminListUsersResponse, error)
+	// AdminDisableUser disables a user's account. Requires M2M authentication.
+	AdminDisableUser(ctx context.Context, in *AdminDisableUserRequest, opts ...grpc.CallOption) (*AdminDisableUserResponse, error)
+	// AdminEnableUser re-enables a user's account. Requires M2M authentication.
+	AdminEnableUser(ctx context.Context, in *AdminEnableUserRequest, opts ...grpc.CallOption) (*AdminEnableUserResponse, error)
+	// AdminUnlockAccount clears a user's failed login lockout. Requires M2M
+	// authentication.
+	AdminUnlockAccount(ctx context.Context, in *AdminUnlockAccountRequest, opts ...grpc.CallOption) (*AdminUnlockAccountResponse, error)
 }
 
 type authServiceClient struct {
@@ -498,6 +1104,26 @@ func (c *authServiceClient) GetUser(ctx context.Context, in *GetUserRequest, opt
 	return out, nil
 }
 
+func (c *authServiceClient) GetSubscriptionStatus(ctx context.Context, in *GetSubscriptionStatusRequest, opts ...grpc.CallOption) (*GetSubscriptionStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSubscriptionStatusResponse)
+	err := c.cc.Invoke(ctx, AuthService_GetSubscriptionStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) GetLoginHistory(ctx context.Context, in *GetLoginHistoryRequest, opts ...grpc.CallOption) (*GetLoginHistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLoginHistoryResponse)
+	err := c.cc.Invoke(ctx, AuthService_GetLoginHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *authServiceClient) GetUserByStripeCustomerId(ctx context.Context, in *GetUserByStripeCustomerIdRequest, opts ...grpc.CallOption) (*GetUserByStripeCustomerIdResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetUserByStripeCustomerIdResponse)
@@ -518,6 +1144,46 @@ func (c *authServiceClient) UpdateUserSubscription(ctx context.Context, in *Upda
 	return out, nil
 }
 
+func (c *authServiceClient) AdminListUsers(ctx context.Context, in *AdminListUsersRequest, opts ...grpc.CallOption) (*AdminListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminListUsersResponse)
+	err := c.cc.Invoke(ctx, AuthService_AdminListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) AdminDisableUser(ctx context.Context, in *AdminDisableUserRequest, opts ...grpc.CallOption) (*AdminDisableUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminDisableUserResponse)
+	err := c.cc.Invoke(ctx, AuthService_AdminDisableUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) AdminEnableUser(ctx context.Context, in *AdminEnableUserRequest, opts ...grpc.CallOption) (*AdminEnableUserResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminEnableUserResponse)
+	err := c.cc.Invoke(ctx, AuthService_AdminEnableUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) AdminUnlockAccount(ctx context.Context, in *AdminUnlockAccountRequest, opts ...grpc.CallOption) (*AdminUnlockAccountResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminUnlockAccountResponse)
+	err := c.cc.Invoke(ctx, AuthService_AdminUnlockAccount_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthServiceServer is the server API for AuthService service.
 // All implementations must embed UnimplementedAuthServiceServer
 // for forward compatibility.
@@ -530,10 +1196,24 @@ type AuthServiceServer interface {
 	Authenticate(context.Context, *AuthenticateRequest) (*AuthenticateResponse, error)
 	// GetUser fetches a user by id.
 	GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error)
+	// GetSubscriptionStatus fetches a user's subscription state without the
+	// rest of the user record.
+	GetSubscriptionStatus(context.Context, *GetSubscriptionStatusRequest) (*GetSubscriptionStatusResponse, error)
+	// GetLoginHistory lists a user's recent authentication attempts.
+	GetLoginHistory(context.Context, *GetLoginHistoryRequest) (*GetLoginHistoryResponse, error)
 	// GetUserByStripeCustomerId fetches a user by Stripe customer id.
 	GetUserByStripeCustomerId(context.Context, *GetUserByStripeCustomerIdRequest) (*GetUserByStripeCustomerIdResponse, error)
 	// UpdateUserSubscription updates billing-related subscription state.
 	UpdateUserSubscription(context.Context, *UpdateUserSubscriptionRequest) (*UpdateUserSubscriptionResponse, error)
+	// AdminListUsers pages through all users. Requires M2M authentication.
+	AdminListUsers(context.Context, *AdminListUsersRequest) (*AdminListUsersResponse, error)
+	// AdminDisableUser disables a user's account. Requires M2M authentication.
+	AdminDisableUser(context.Context, *AdminDisableUserRequest) (*AdminDisableUserResponse, error)
+	// AdminEnableUser re-enables a user's account. Requires M2M authentication.
+	AdminEnableUser(context.Context, *AdminEnableUserRequest) (*AdminEnableUserResponse, error)
+	// AdminUnlockAccount clears a user's failed login lockout. Requires M2M
+	// authentication.
+	AdminUnlockAccount(context.Context, *AdminUnlockAccountRequest) (*AdminUnlockAccountResponse, error)
 	mustEmbedUnimplementedAuthServiceServer()
 }
 
@@ -553,12 +1233,30 @@ func (UnimplementedAuthServiceServer) Authenticate(context.Context, *Authenticat
 func (UnimplementedAuthServiceServer) GetUser(context.Context, *GetUserRequest) (*GetUserResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUser not implemented")
 }
+func (UnimplementedAuthServiceServer) GetSubscriptionStatus(context.Context, *GetSubscriptionStatusRequest) (*GetSubscriptionStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSubscriptionStatus not implemented")
+}
+func (UnimplementedAuthServiceServer) GetLoginHistory(context.Context, *GetLoginHistoryRequest) (*GetLoginHistoryResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetLoginHistory not implemented")
+}
 func (UnimplementedAuthServiceServer) GetUserByStripeCustomerId(context.Context, *GetUserByStripeCustomerIdRequest) (*GetUserByStripeCustomerIdResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetUserByStripeCustomerId not implemented")
 }
 func (UnimplementedAuthServiceServer) UpdateUserSubscription(context.Context, *UpdateUserSubscriptionRequest) (*UpdateUserSubscriptionResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateUserSubscription not implemented")
 }
+func (UnimplementedAuthServiceServer) AdminListUsers(context.Context, *AdminListUsersRequest) (*AdminListUsersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdminListUsers not implemented")
+}
+func (UnimplementedAuthServiceServer) AdminDisableUser(context.Context, *AdminDisableUserRequest) (*AdminDisableUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdminDisableUser not implemented")
+}
+func (UnimplementedAuthServiceServer) AdminEnableUser(context.Context, *AdminEnableUserRequest) (*AdminEnableUserResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdminEnableUser not implemented")
+}
+func (UnimplementedAuthServiceServer) AdminUnlockAccount(context.Context, *AdminUnlockAccountRequest) (*AdminUnlockAccountResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdminUnlockAccount not implemented")
+}
 func (UnimplementedAuthServiceServer) mustEmbedUnimplementedAuthServiceServer() {}
 func (UnimplementedAuthServiceServer) testEmbeddedByValue()                     {}
 
@@ -634,6 +1332,42 @@ func _AuthService_GetUser_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_GetSubscriptionStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).GetSubscriptionStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_GetSubscriptionStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).GetSubscriptionStatus(ctx, req.(*GetSubscriptionStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_GetLoginHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLoginHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).GetLoginHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_GetLoginHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).GetLoginHistory(ctx, req.(*GetLoginHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AuthService_GetUserByStripeCustomerId_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetUserByStripeCustomerIdRequest)
 	if err := dec(in); err != nil {
@@ -670,6 +1404,78 @@ func _AuthService_UpdateUserSubscription_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthService_AdminListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminListUsersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AdminListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_AdminListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AdminListUsers(ctx, req.(*AdminListUsersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_AdminDisableUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminDisableUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AdminDisableUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_AdminDisableUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AdminDisableUser(ctx, req.(*AdminDisableUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_AdminEnableUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminEnableUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AdminEnableUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_AdminEnableUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AdminEnableUser(ctx, req.(*AdminEnableUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_AdminUnlockAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminUnlockAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AdminUnlockAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AuthService_AdminUnlockAccount_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AdminUnlockAccount(ctx, req.(*AdminUnlockAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthService_ServiceDesc is the grpc.ServiceDesc for AuthService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -689,6 +1495,14 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetUser",
 			Handler:    _AuthService_GetUser_Handler,
 		},
+		{
+			MethodName: "GetSubscriptionStatus",
+			Handler:    _AuthService_GetSubscriptionStatus_Handler,
+		},
+		{
+			MethodName: "GetLoginHistory",
+			Handler:    _AuthService_GetLoginHistory_Handler,
+		},
 		{
 			MethodName: "GetUserByStripeCustomerId",
 			Handler:    _AuthService_GetUserByStripeCustomerId_Handler,
@@ -697,6 +1511,22 @@ var AuthService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateUserSubscription",
 			Handler:    _AuthService_UpdateUserSubscription_Handler,
 		},
+		{
+			MethodName: "AdminListUsers",
+			Handler:    _AuthService_AdminListUsers_Handler,
+		},
+		{
+			MethodName: "AdminDisableUser",
+			Handler:    _AuthService_AdminDisableUser_Handler,
+		},
+		{
+			MethodName: "AdminEnableUser",
+			Handler:    _AuthService_AdminEnableUser_Handler,
+		},
+		{
+			MethodName: "AdminUnlockAccount",
+			Handler:    _AuthService_AdminUnlockAccount_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/etu.proto",
@@ -1079,7 +1909,9 @@ var UserSettingsService_ServiceDesc = grpc.ServiceDesc{
 }
 
 const (
-	StatsService_GetStats_FullMethodName = "/etu.StatsService/GetStats"
+	StatsService_GetStats_FullMethodName         = "/etu.StatsService/GetStats"
+	StatsService_GetStorageUsage_FullMethodName  = "/etu.StatsService/GetStorageUsage"
+	StatsService_GetMoodBreakdown_FullMethodName = "/etu.StatsService/GetMoodBreakdown"
 )
 
 // StatsServiceClient is the client API for StatsService service.
@@ -1090,6 +1922,10 @@ const (
 type StatsServiceClient interface {
 	// GetStats returns aggregate note, tag, and word-count statistics.
 	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*GetStatsResponse, error)
+	// GetStorageUsage returns the bytes and object count of a user's stored media.
+	GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*GetStorageUsageResponse, error)
+	// GetMoodBreakdown returns how many of a user's notes have each mood.
+	GetMoodBreakdown(ctx context.Context, in *GetMoodBreakdownRequest, opts ...grpc.CallOption) (*GetMoodBreakdownResponse, error)
 }
 
 type statsServiceClient struct {
@@ -1110,6 +1946,26 @@ func (c *statsServiceClient) GetStats(ctx context.Context, in *GetStatsRequest,
 	return out, nil
 }
 
+func (c *statsServiceClient) GetStorageUsage(ctx context.Context, in *GetStorageUsageRequest, opts ...grpc.CallOption) (*GetStorageUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStorageUsageResponse)
+	err := c.cc.Invoke(ctx, StatsService_GetStorageUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *statsServiceClient) GetMoodBreakdown(ctx context.Context, in *GetMoodBreakdownRequest, opts ...grpc.CallOption) (*GetMoodBreakdownResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetMoodBreakdownResponse)
+	err := c.cc.Invoke(ctx, StatsService_GetMoodBreakdown_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StatsServiceServer is the server API for StatsService service.
 // All implementations must embed UnimplementedStatsServiceServer
 // for forward compatibility.
@@ -1118,6 +1974,10 @@ func (c *statsServiceClient) GetStats(ctx context.Context, in *GetStatsRequest,
 type StatsServiceServer interface {
 	// GetStats returns aggregate note, tag, and word-count statistics.
 	GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error)
+	// GetStorageUsage returns the bytes and object count of a user's stored media.
+	GetStorageUsage(context.Context, *GetStorageUsageRequest) (*GetStorageUsageResponse, error)
+	// GetMoodBreakdown returns how many of a user's notes have each mood.
+	GetMoodBreakdown(context.Context, *GetMoodBreakdownRequest) (*GetMoodBreakdownResponse, error)
 	mustEmbedUnimplementedStatsServiceServer()
 }
 
@@ -1131,6 +1991,12 @@ type UnimplementedStatsServiceServer struct{}
 func (UnimplementedStatsServiceServer) GetStats(context.Context, *GetStatsRequest) (*GetStatsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetStats not implemented")
 }
+func (UnimplementedStatsServiceServer) GetStorageUsage(context.Context, *GetStorageUsageRequest) (*GetStorageUsageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetStorageUsage not implemented")
+}
+func (UnimplementedStatsServiceServer) GetMoodBreakdown(context.Context, *GetMoodBreakdownRequest) (*GetMoodBreakdownResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetMoodBreakdown not implemented")
+}
 func (UnimplementedStatsServiceServer) mustEmbedUnimplementedStatsServiceServer() {}
 func (UnimplementedStatsServiceServer) testEmbeddedByValue()                      {}
 
@@ -1170,6 +2036,42 @@ func _StatsService_GetStats_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _StatsService_GetStorageUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStorageUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).GetStorageUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StatsService_GetStorageUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).GetStorageUsage(ctx, req.(*GetStorageUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StatsService_GetMoodBreakdown_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMoodBreakdownRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StatsServiceServer).GetMoodBreakdown(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StatsService_GetMoodBreakdown_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StatsServiceServer).GetMoodBreakdown(ctx, req.(*GetMoodBreakdownRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StatsService_ServiceDesc is the grpc.ServiceDesc for StatsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1181,6 +2083,432 @@ var StatsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetStats",
 			Handler:    _StatsService_GetStats_Handler,
 		},
+		{
+			MethodName: "GetStorageUsage",
+			Handler:    _StatsService_GetStorageUsage_Handler,
+		},
+		{
+			MethodName: "GetMoodBreakdown",
+			Handler:    _StatsService_GetMoodBreakdown_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/etu.proto",
+}
+
+const (
+	TemplatesService_CreateTemplate_FullMethodName         = "/etu.TemplatesService/CreateTemplate"
+	TemplatesService_ListTemplates_FullMethodName          = "/etu.TemplatesService/ListTemplates"
+	TemplatesService_GetTemplate_FullMethodName            = "/etu.TemplatesService/GetTemplate"
+	TemplatesService_UpdateTemplate_FullMethodName         = "/etu.TemplatesService/UpdateTemplate"
+	TemplatesService_DeleteTemplate_FullMethodName         = "/etu.TemplatesService/DeleteTemplate"
+	TemplatesService_CreateNoteFromTemplate_FullMethodName = "/etu.TemplatesService/CreateNoteFromTemplate"
+)
+
+// TemplatesServiceClient is the client API for TemplatesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TemplatesService manages reusable note templates.
+type TemplatesServiceClient interface {
+	// CreateTemplate saves a new template.
+	CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error)
+	// ListTemplates lists a user's templates by name.
+	ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error)
+	// GetTemplate fetches one template.
+	GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error)
+	// UpdateTemplate changes a template's name, content, or tags.
+	UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error)
+	// DeleteTemplate deletes a template. Notes created from it are unaffected.
+	DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error)
+	// CreateNoteFromTemplate creates a note with the template's content and tags.
+	CreateNoteFromTemplate(ctx context.Context, in *CreateNoteFromTemplateRequest, opts ...grpc.CallOption) (*CreateNoteFromTemplateResponse, error)
+}
+
+type templatesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTemplatesServiceClient(cc grpc.ClientConnInterface) TemplatesServiceClient {
+	return &templatesServiceClient{cc}
+}
+
+func (c *templatesServiceClient) CreateTemplate(ctx context.Context, in *CreateTemplateRequest, opts ...grpc.CallOption) (*CreateTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateTemplateResponse)
+	err := c.cc.Invoke(ctx, TemplatesService_CreateTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *templatesServiceClient) ListTemplates(ctx context.Context, in *ListTemplatesRequest, opts ...grpc.CallOption) (*ListTemplatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTemplatesResponse)
+	err := c.cc.Invoke(ctx, TemplatesService_ListTemplates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *templatesServiceClient) GetTemplate(ctx context.Context, in *GetTemplateRequest, opts ...grpc.CallOption) (*GetTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTemplateResponse)
+	err := c.cc.Invoke(ctx, TemplatesService_GetTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *templatesServiceClient) UpdateTemplate(ctx context.Context, in *UpdateTemplateRequest, opts ...grpc.CallOption) (*UpdateTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateTemplateResponse)
+	err := c.cc.Invoke(ctx, TemplatesService_UpdateTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *templatesServiceClient) DeleteTemplate(ctx context.Context, in *DeleteTemplateRequest, opts ...grpc.CallOption) (*DeleteTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTemplateResponse)
+	err := c.cc.Invoke(ctx, TemplatesService_DeleteTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *templatesServiceClient) CreateNoteFromTemplate(ctx context.Context, in *CreateNoteFromTemplateRequest, opts ...grpc.CallOption) (*CreateNoteFromTemplateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateNoteFromTemplateResponse)
+	err := c.cc.Invoke(ctx, TemplatesService_CreateNoteFromTemplate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TemplatesServiceServer is the server API for TemplatesService service.
+// All implementations must embed UnimplementedTemplatesServiceServer
+// for forward compatibility.
+//
+// TemplatesService manages reusable note templates.
+type TemplatesServiceServer interface {
+	// CreateTemplate saves a new template.
+	CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error)
+	// ListTemplates lists a user's templates by name.
+	ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error)
+	// GetTemplate fetches one template.
+	GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error)
+	// UpdateTemplate changes a template's name, content, or tags.
+	UpdateTemplate(context.Context, *UpdateTemplateRequest) (*UpdateTemplateResponse, error)
+	// DeleteTemplate deletes a template. Notes created from it are unaffected.
+	DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error)
+	// CreateNoteFromTemplate creates a note with the template's content and tags.
+	CreateNoteFromTemplate(context.Context, *CreateNoteFromTemplateRequest) (*CreateNoteFromTemplateResponse, error)
+	mustEmbedUnimplementedTemplatesServiceServer()
+}
+
+// UnimplementedTemplatesServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTemplatesServiceServer struct{}
+
+func (UnimplementedTemplatesServiceServer) CreateTemplate(context.Context, *CreateTemplateRequest) (*CreateTemplateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateTemplate not implemented")
+}
+func (UnimplementedTemplatesServiceServer) ListTemplates(context.Context, *ListTemplatesRequest) (*ListTemplatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTemplates not implemented")
+}
+func (UnimplementedTemplatesServiceServer) GetTemplate(context.Context, *GetTemplateRequest) (*GetTemplateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTemplate not implemented")
+}
+func (UnimplementedTemplatesServiceServer) UpdateTemplate(context.Context, *UpdateTemplateRequest) (*UpdateTemplateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateTemplate not implemented")
+}
+func (UnimplementedTemplatesServiceServer) DeleteTemplate(context.Context, *DeleteTemplateRequest) (*DeleteTemplateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteTemplate not implemented")
+}
+func (UnimplementedTemplatesServiceServer) CreateNoteFromTemplate(context.Context, *CreateNoteFromTemplateRequest) (*CreateNoteFromTemplateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateNoteFromTemplate not implemented")
+}
+func (UnimplementedTemplatesServiceServer) mustEmbedUnimplementedTemplatesServiceServer() {}
+func (UnimplementedTemplatesServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafeTemplatesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TemplatesServiceServer will
+// result in compilation errors.
+type UnsafeTemplatesServiceServer interface {
+	mustEmbedUnimplementedTemplatesServiceServer()
+}
+
+func RegisterTemplatesServiceServer(s grpc.ServiceRegistrar, srv TemplatesServiceServer) {
+	// If the following call panics, it indicates UnimplementedTemplatesServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TemplatesService_ServiceDesc, srv)
+}
+
+func _TemplatesService_CreateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemplatesServiceServer).CreateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemplatesService_CreateTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemplatesServiceServer).CreateTemplate(ctx, req.(*CreateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemplatesService_ListTemplates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTemplatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemplatesServiceServer).ListTemplates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemplatesService_ListTemplates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemplatesServiceServer).ListTemplates(ctx, req.(*ListTemplatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemplatesService_GetTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemplatesServiceServer).GetTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemplatesService_GetTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemplatesServiceServer).GetTemplate(ctx, req.(*GetTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemplatesService_UpdateTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemplatesServiceServer).UpdateTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemplatesService_UpdateTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemplatesServiceServer).UpdateTemplate(ctx, req.(*UpdateTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemplatesService_DeleteTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemplatesServiceServer).DeleteTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemplatesService_DeleteTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemplatesServiceServer).DeleteTemplate(ctx, req.(*DeleteTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TemplatesService_CreateNoteFromTemplate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateNoteFromTemplateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TemplatesServiceServer).CreateNoteFromTemplate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TemplatesService_CreateNoteFromTemplate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TemplatesServiceServer).CreateNoteFromTemplate(ctx, req.(*CreateNoteFromTemplateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TemplatesService_ServiceDesc is the grpc.ServiceDesc for TemplatesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TemplatesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "etu.TemplatesService",
+	HandlerType: (*TemplatesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateTemplate",
+			Handler:    _TemplatesService_CreateTemplate_Handler,
+		},
+		{
+			MethodName: "ListTemplates",
+			Handler:    _TemplatesService_ListTemplates_Handler,
+		},
+		{
+			MethodName: "GetTemplate",
+			Handler:    _TemplatesService_GetTemplate_Handler,
+		},
+		{
+			MethodName: "UpdateTemplate",
+			Handler:    _TemplatesService_UpdateTemplate_Handler,
+		},
+		{
+			MethodName: "DeleteTemplate",
+			Handler:    _TemplatesService_DeleteTemplate_Handler,
+		},
+		{
+			MethodName: "CreateNoteFromTemplate",
+			Handler:    _TemplatesService_CreateNoteFromTemplate_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/etu.proto",
+}
+
+const (
+	SyncService_ResetSyncState_FullMethodName = "/etu.SyncService/ResetSyncState"
+)
+
+// SyncServiceClient is the client API for SyncService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SyncService controls Notion sync for a user.
+type SyncServiceClient interface {
+	// ResetSyncState clears a user's sync watermark and any interrupted full
+	// sync, so the sync job's next run for them is a full sync.
+	ResetSyncState(ctx context.Context, in *ResetSyncStateRequest, opts ...grpc.CallOption) (*ResetSyncStateResponse, error)
+}
+
+type syncServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSyncServiceClient(cc grpc.ClientConnInterface) SyncServiceClient {
+	return &syncServiceClient{cc}
+}
+
+func (c *syncServiceClient) ResetSyncState(ctx context.Context, in *ResetSyncStateRequest, opts ...grpc.CallOption) (*ResetSyncStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResetSyncStateResponse)
+	err := c.cc.Invoke(ctx, SyncService_ResetSyncState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SyncServiceServer is the server API for SyncService service.
+// All implementations must embed UnimplementedSyncServiceServer
+// for forward compatibility.
+//
+// SyncService controls Notion sync for a user.
+type SyncServiceServer interface {
+	// ResetSyncState clears a user's sync watermark and any interrupted full
+	// sync, so the sync job's next run for them is a full sync.
+	ResetSyncState(context.Context, *ResetSyncStateRequest) (*ResetSyncStateResponse, error)
+	mustEmbedUnimplementedSyncServiceServer()
+}
+
+// UnimplementedSyncServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSyncServiceServer struct{}
+
+func (UnimplementedSyncServiceServer) ResetSyncState(context.Context, *ResetSyncStateRequest) (*ResetSyncStateResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResetSyncState not implemented")
+}
+func (UnimplementedSyncServiceServer) mustEmbedUnimplementedSyncServiceServer() {}
+func (UnimplementedSyncServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeSyncServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SyncServiceServer will
+// result in compilation errors.
+type UnsafeSyncServiceServer interface {
+	mustEmbedUnimplementedSyncServiceServer()
+}
+
+func RegisterSyncServiceServer(s grpc.ServiceRegistrar, srv SyncServiceServer) {
+	// If the following call panics, it indicates UnimplementedSyncServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SyncService_ServiceDesc, srv)
+}
+
+func _SyncService_ResetSyncState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetSyncStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SyncServiceServer).ResetSyncState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SyncService_ResetSyncState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SyncServiceServer).ResetSyncState(ctx, req.(*ResetSyncStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SyncService_ServiceDesc is the grpc.ServiceDesc for SyncService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SyncService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "etu.SyncService",
+	HandlerType: (*SyncServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ResetSyncState",
+			Handler:    _SyncService_ResetSyncState_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/etu.proto",